@@ -15,4 +15,4 @@
 #
 ###############################################################################
 #!/bin/bash
-/pharos/pharos-node
+/pharos/pharos-node "$@"
@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/backup provides functionality to handle requests
+// of backing up the node state and restoring it from a backup.
+package backup
+
+import (
+	"api/common"
+	"commons/logger"
+	"commons/url"
+	"controller/backup"
+	"io"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	PUT    string = "PUT"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	IMAGES       string = "images"
+	CONTENT_TYPE string = "application/gzip"
+	FILE_NAME    string = "pharos-node-backup.tar.gz"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	backup(w http.ResponseWriter, req *http.Request)
+	restoreBackup(w http.ResponseWriter, req *http.Request)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var backupExecutor backup.Command
+var router *common.Router
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	backupExecutor = backup.Executor{}
+
+	management := url.Base() + url.Management()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: management + url.Backup(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.backup(w, req)
+		}},
+		common.Route{Method: POST, Template: management + url.RestoreBackup(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.restoreBackup(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of backup APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is backing up and restoring the node state.
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+// Handling requests which is downloading a backup archive of the node state.
+// images of apps are included if 'images' query is true.
+func (innerExecutorImpl) backup(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	archive, e := backupExecutor.Backup(req.URL.Query().Get(IMAGES) == "true")
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+FILE_NAME+"\"")
+	w.WriteHeader(http.StatusOK)
+
+	_, err := io.Copy(w, archive)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
+
+// Handling requests which is restoring the node state from a backup archive
+// in the body of request.
+func (innerExecutorImpl) restoreBackup(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := backupExecutor.Restore(req.Body)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
@@ -0,0 +1,160 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package backup
+
+import (
+	"bytes"
+	"commons/errors"
+	backupmocks "controller/backup/mocks"
+	"github.com/golang/mock/gomock"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testArchive = "test_archive"
+)
+
+var (
+	invalidOperationList = map[string][]string{
+		"/api/v1/management/backup":         []string{PUT, POST, DELETE},
+		"/api/v1/management/restore-backup": []string{GET, PUT, DELETE},
+	}
+	testMap = map[string]interface{}{
+		"apps":       []map[string]interface{}{{"id": "test_app_id"}},
+		"registered": true,
+	}
+)
+
+var backupAPIExecutor Command
+
+func init() {
+	backupAPIExecutor = Executor{}
+}
+
+func TestBackupAPIInvalidOperation(t *testing.T) {
+	for api, invalidMethodList := range invalidOperationList {
+		for _, method := range invalidMethodList {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, api, nil)
+
+			backupAPIExecutor.Handle(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected error : %d, Actual Error : %d", http.StatusMethodNotAllowed, w.Code)
+			}
+		}
+	}
+}
+
+func TestBackupAPI_ExpectArchiveReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupExecutorMockObj := backupmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		backupExecutorMockObj.EXPECT().Backup(true).Return(ioutil.NopCloser(strings.NewReader(testArchive)), nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/backup?images=true", nil)
+
+	backupExecutor = backupExecutorMockObj
+
+	backupAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != CONTENT_TYPE {
+		t.Errorf("Expected content type : %s, Actual content type : %s", CONTENT_TYPE, w.Header().Get("Content-Type"))
+	}
+	if w.Body.String() != testArchive {
+		t.Errorf("Expected body : %s, Actual body : %s", testArchive, w.Body.String())
+	}
+}
+
+func TestBackupAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupExecutorMockObj := backupmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		backupExecutorMockObj.EXPECT().Backup(false).Return(nil, errors.IOError{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/backup", nil)
+
+	backupExecutor = backupExecutorMockObj
+
+	backupAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestRestoreBackupAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupExecutorMockObj := backupmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		backupExecutorMockObj.EXPECT().Restore(gomock.Any()).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/management/restore-backup", bytes.NewReader([]byte(testArchive)))
+
+	backupExecutor = backupExecutorMockObj
+
+	backupAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestRestoreBackupAPIWithInvalidArchive_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backupExecutorMockObj := backupmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		backupExecutorMockObj.EXPECT().Restore(gomock.Any()).Return(nil, errors.InvalidParam{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/management/restore-backup", bytes.NewReader([]byte(testArchive)))
+
+	backupExecutor = backupExecutorMockObj
+
+	backupAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
@@ -37,34 +37,7 @@ type ResponseType map[string]interface{}
 
 // Making non succeed response by error type.
 func MakeErrorResponse(w http.ResponseWriter, err error) {
-	var code int
-
-	switch err.(type) {
-
-	case errors.NotFoundURL:
-		code = http.StatusNotFound
-
-	case errors.InvalidMethod:
-		code = http.StatusMethodNotAllowed
-
-	case errors.InvalidYaml, errors.InvalidAppId,
-		errors.InvalidParam, errors.NotFoundImage,
-		errors.AlreadyAllocatedPort, errors.AlreadyUsedName,
-		errors.InvalidContainerName:
-		code = http.StatusBadRequest
-
-	case errors.IOError:
-		code = http.StatusInternalServerError
-
-	case errors.ConnectionError, errors.NotFound:
-		code = http.StatusServiceUnavailable
-
-	case errors.AlreadyReported:
-		code = http.StatusAlreadyReported
-
-	default:
-		code = http.StatusInternalServerError
-	}
+	code := errors.StatusCode(err)
 
 	logger.Logging(logger.DEBUG, "Send response", strconv.Itoa(code), err.Error())
 
@@ -93,6 +66,13 @@ func MakeResponse(w http.ResponseWriter, data []byte) {
 	WriteSuccess(w, data)
 }
 
+// Making response for a request accepted to be processed in background.
+func MakeAcceptedResponse(w http.ResponseWriter, data []byte) {
+	logger.Logging(logger.DEBUG, "Send response : 202")
+	w.WriteHeader(http.StatusAccepted)
+	WriteSuccess(w, data)
+}
+
 // Setting body of response.
 func WriteSuccess(w http.ResponseWriter, data []byte) {
 	w.Header().Set("Content-Type", "application/json")
@@ -52,6 +52,24 @@ func TestMakeErrorResponseWithInternalDefinedError_ExpectConversionToMatchHttpEr
 		t.Errorf("Unexpected Error code : %d", w.Code)
 	}
 
+	w = httptest.NewRecorder()
+	MakeErrorResponse(w, errors.Unauthorized{})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Unexpected Error code : %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	MakeErrorResponse(w, errors.Forbidden{})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Unexpected Error code : %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	MakeErrorResponse(w, errors.InsufficientResource{})
+	if w.Code != http.StatusConflict {
+		t.Errorf("Unexpected Error code : %d", w.Code)
+	}
+
 	w = httptest.NewRecorder()
 	MakeErrorResponse(w, errors.InvalidYaml{})
 	if w.Code != http.StatusBadRequest {
@@ -109,6 +127,22 @@ func TestMakeResponse_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestMakeAcceptedResponse_ExpectAcceptedCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	data := []byte{'1', '2', '3'}
+	w := httptest.NewRecorder()
+
+	MakeAcceptedResponse(w, data)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Unexpected Error code : %d", w.Code)
+	}
+	if w.Body.String() != string(data) {
+		t.Errorf("Unexpected body : %s", w.Body.String())
+	}
+}
+
 func TestCheckSupportedMethodWithValidMethod_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,242 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package common
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	PARAM_PREFIX     = "{"
+	PARAM_SUFFIX     = "}"
+	PARAM_TYPE_SEP   = ":"
+	STRING_PARAM     = "string"
+	INT_PARAM        = "int"
+	PATH_SEP         = "/"
+	ALLOW_HEADER     = "Allow"
+	ALLOW_HEADER_SEP = ", "
+)
+
+// HandlerFunc handles a request matched with a route.
+// params has values of path parameters in the route's template.
+type HandlerFunc func(w http.ResponseWriter, req *http.Request, params Params)
+
+// Route describes an API with a method and a path template.
+// a segment of template enclosed in braces is a path parameter,
+// and it can be typed with a suffix, e.g. "/apps/{appId}/revisions/{revision:int}".
+// supported types are "string"(default) and "int".
+type Route struct {
+	Method   string
+	Template string
+	Handler  HandlerFunc
+}
+
+// Params is a map of path parameter names to values.
+type Params map[string]string
+
+type segment struct {
+	literal   string
+	param     string
+	paramType string
+}
+
+type entry struct {
+	template string
+	segments []segment
+	methods  []string
+	handlers map[string]HandlerFunc
+}
+
+// Router dispatches requests to routes by method and path template.
+type Router struct {
+	routes  []Route
+	entries []*entry
+}
+
+// Get returns a value of path parameter.
+func (params Params) Get(name string) string {
+	return params[name]
+}
+
+// Int returns a value of path parameter as integer.
+func (params Params) Int(name string) (int, error) {
+	value, err := strconv.Atoi(params[name])
+	if err != nil {
+		return 0, errors.InvalidParam{Msg: "invalid path parameter : " + name}
+	}
+	return value, nil
+}
+
+// NewRouter makes a router with routes.
+// it panics if a template has a parameter of unsupported type.
+func NewRouter(routes ...Route) *Router {
+	router := &Router{}
+	router.Add(routes...)
+	return router
+}
+
+// Add registers routes to the router.
+// a route with the same method and template as registered one replaces it.
+func (router *Router) Add(routes ...Route) {
+	for _, route := range routes {
+		e := router.find(route.Template)
+		if e == nil {
+			e = &entry{
+				template: route.Template,
+				segments: parseTemplate(route.Template),
+				handlers: make(map[string]HandlerFunc),
+			}
+			router.entries = append(router.entries, e)
+		}
+		if _, exists := e.handlers[route.Method]; !exists {
+			e.methods = append(e.methods, route.Method)
+		}
+		e.handlers[route.Method] = route.Handler
+		router.routes = append(router.routes, route)
+	}
+}
+
+// Routes returns all of routes registered to the router.
+func (router *Router) Routes() []Route {
+	return router.routes
+}
+
+// Handle calls a handler of route matched with the request.
+// when several templates match the path, the one which has
+// a literal segment at the first differing position is chosen.
+// if no template matches the path, makes 'not found' response.
+// if the template doesn't support the method, makes 'method not allowed'
+// response with Allow header.
+func (router *Router) Handle(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+
+	var matched *entry
+	var params Params
+	for _, e := range router.entries {
+		values, ok := e.match(path)
+		if !ok {
+			continue
+		}
+		if matched == nil || e.moreSpecificThan(matched) {
+			matched, params = e, values
+		}
+	}
+
+	if matched == nil {
+		logger.Logging(logger.DEBUG, "Unmatched url")
+		MakeErrorResponse(w, errors.NotFoundURL{Msg: path})
+		return
+	}
+
+	handler, exists := matched.handlers[req.Method]
+	if !exists {
+		logger.Logging(logger.DEBUG, "UnSupported method")
+		w.Header().Set(ALLOW_HEADER, strings.Join(matched.methods, ALLOW_HEADER_SEP))
+		MakeErrorResponse(w, errors.InvalidMethod{Msg: req.Method})
+		return
+	}
+	handler(w, req, params)
+}
+
+// Forward makes routes which have the same methods and templates as given routes
+// and pass matched requests to handle as it is.
+func Forward(routes []Route, handle func(w http.ResponseWriter, req *http.Request)) []Route {
+	forwarded := make([]Route, 0)
+	for _, route := range routes {
+		forwarded = append(forwarded, Route{
+			Method:   route.Method,
+			Template: route.Template,
+			Handler: func(w http.ResponseWriter, req *http.Request, _ Params) {
+				handle(w, req)
+			},
+		})
+	}
+	return forwarded
+}
+
+func (router *Router) find(template string) *entry {
+	for _, e := range router.entries {
+		if e.template == template {
+			return e
+		}
+	}
+	return nil
+}
+
+func parseTemplate(template string) []segment {
+	segments := make([]segment, 0)
+	for _, part := range strings.Split(template, PATH_SEP) {
+		if !strings.HasPrefix(part, PARAM_PREFIX) || !strings.HasSuffix(part, PARAM_SUFFIX) {
+			segments = append(segments, segment{literal: part})
+			continue
+		}
+
+		param := strings.TrimSuffix(strings.TrimPrefix(part, PARAM_PREFIX), PARAM_SUFFIX)
+		paramType := STRING_PARAM
+		if idx := strings.Index(param, PARAM_TYPE_SEP); idx != -1 {
+			param, paramType = param[:idx], param[idx+1:]
+		}
+		if paramType != STRING_PARAM && paramType != INT_PARAM {
+			panic("unsupported type of path parameter : " + template)
+		}
+		segments = append(segments, segment{param: param, paramType: paramType})
+	}
+	return segments
+}
+
+func (e *entry) match(path string) (Params, bool) {
+	parts := strings.Split(path, PATH_SEP)
+	if len(parts) != len(e.segments) {
+		return nil, false
+	}
+
+	params := make(Params)
+	for i, seg := range e.segments {
+		if len(seg.param) == 0 {
+			if parts[i] != seg.literal {
+				return nil, false
+			}
+			continue
+		}
+
+		if len(parts[i]) == 0 {
+			return nil, false
+		}
+		if seg.paramType == INT_PARAM {
+			if _, err := strconv.Atoi(parts[i]); err != nil {
+				return nil, false
+			}
+		}
+		params[seg.param] = parts[i]
+	}
+	return params, true
+}
+
+func (e *entry) moreSpecificThan(other *entry) bool {
+	for i := range e.segments {
+		isLiteral := len(e.segments[i].param) == 0
+		isOtherLiteral := len(other.segments[i].param) == 0
+		if isLiteral != isOtherLiteral {
+			return isLiteral
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	APPS_TEMPLATE     = "/api/v1/apps"
+	APP_TEMPLATE      = "/api/v1/apps/{appId}"
+	DEPLOY_TEMPLATE   = "/api/v1/apps/deploy"
+	REVISION_TEMPLATE = "/api/v1/apps/{appId}/revisions/{revision:int}"
+)
+
+type called struct {
+	template string
+	params   Params
+}
+
+func makeTestRouter(result *called) *Router {
+	handler := func(template string) HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request, params Params) {
+			result.template = template
+			result.params = params
+		}
+	}
+	return NewRouter(
+		Route{Method: GET, Template: APPS_TEMPLATE, Handler: handler(APPS_TEMPLATE)},
+		Route{Method: GET, Template: APP_TEMPLATE, Handler: handler(APP_TEMPLATE)},
+		Route{Method: DELETE, Template: APP_TEMPLATE, Handler: handler(APP_TEMPLATE)},
+		Route{Method: POST, Template: DEPLOY_TEMPLATE, Handler: handler(DEPLOY_TEMPLATE)},
+		Route{Method: GET, Template: REVISION_TEMPLATE, Handler: handler(REVISION_TEMPLATE)},
+	)
+}
+
+func TestRouterWithMatchedRequest_ExpectCalledHandlerWithParams(t *testing.T) {
+	result := &called{}
+	router := makeTestRouter(result)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/apps/abc/revisions/3", nil)
+	router.Handle(w, req)
+
+	if result.template != REVISION_TEMPLATE {
+		t.Errorf("Expected template : %s, actual template : %s", REVISION_TEMPLATE, result.template)
+	}
+	if result.params.Get("appId") != "abc" {
+		t.Errorf("Expected appId : %s, actual appId : %s", "abc", result.params.Get("appId"))
+	}
+	if revision, err := result.params.Int("revision"); err != nil || revision != 3 {
+		t.Errorf("Expected revision : %d, actual revision : %d", 3, revision)
+	}
+}
+
+func TestRouterWithLiteralAndParamMatched_ExpectLiteralPreferred(t *testing.T) {
+	result := &called{}
+	router := makeTestRouter(result)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/apps/deploy", nil)
+	router.Handle(w, req)
+
+	if result.template != DEPLOY_TEMPLATE {
+		t.Errorf("Expected template : %s, actual template : %s", DEPLOY_TEMPLATE, result.template)
+	}
+}
+
+func TestRouterWithUnmatchedPath_ExpectNotFound(t *testing.T) {
+	paths := []string{
+		"/api/v1/apps/abc/revisions/latest",
+		"/api/v1/apps/deploy-something/start",
+		"/api/v1/apps/",
+		"/api/v1/apps/abc/",
+		"/api/v2/apps",
+	}
+
+	for _, path := range paths {
+		result := &called{}
+		router := makeTestRouter(result)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, path, nil)
+		router.Handle(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected code : %d, actual code : %d, path : %s", http.StatusNotFound, w.Code, path)
+		}
+		if len(result.template) != 0 {
+			t.Errorf("Unexpected handler called : %s", result.template)
+		}
+	}
+}
+
+func TestRouterWithUnsupportedMethod_ExpectMethodNotAllowedWithAllowHeader(t *testing.T) {
+	result := &called{}
+	router := makeTestRouter(result)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/apps/abc", nil)
+	router.Handle(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusMethodNotAllowed, w.Code)
+	}
+	if allow := w.Header().Get(ALLOW_HEADER); allow != "GET, DELETE" {
+		t.Errorf("Expected Allow header : %s, actual : %s", "GET, DELETE", allow)
+	}
+	if len(result.template) != 0 {
+		t.Errorf("Unexpected handler called : %s", result.template)
+	}
+}
+
+func TestForward_ExpectRequestPassedToHandle(t *testing.T) {
+	result := &called{}
+	forwarded := 0
+	router := NewRouter(Forward(makeTestRouter(result).Routes(), func(w http.ResponseWriter, req *http.Request) {
+		forwarded++
+	})...)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/apps", nil)
+	router.Handle(w, req)
+
+	if forwarded != 1 {
+		t.Errorf("Expected forwarded count : %d, actual : %d", 1, forwarded)
+	}
+	if len(result.template) != 0 {
+		t.Errorf("Unexpected handler called : %s", result.template)
+	}
+}
+
+func TestNewRouterWithUnsupportedParamType_ExpectPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic")
+		}
+	}()
+
+	NewRouter(Route{Method: GET, Template: "/api/v1/apps/{appId:uuid}", Handler: nil})
+}
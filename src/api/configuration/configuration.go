@@ -20,9 +20,9 @@ import (
 	"api/common"
 	"commons/errors"
 	"commons/logger"
+	"commons/url"
 	"controller/configuration"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -45,23 +45,33 @@ type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
 var configurationExecutor configuration.Command
+var router *common.Router
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	configurationExecutor = configuration.Executor{}
+
+	config := url.Base() + url.Management() + url.Device() + url.Configuration()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: config, Handler: handleConfiguration},
+		common.Route{Method: POST, Template: config, Handler: handleConfiguration},
+	)
+}
+
+// Routes returns the route table of configuration APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl, split := req.URL.Path, strings.Split(req.URL.Path, "/"); {
-	case len(split) == 6:
-		apiInnerExecutor.configuration(w, req)
-	default:
-		logger.Logging(logger.DEBUG, "Unmatched url")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-	}
+	router.Handle(w, req)
+}
+
+func handleConfiguration(w http.ResponseWriter, req *http.Request, _ common.Params) {
+	apiInnerExecutor.configuration(w, req)
 }
 
 // configuration handles requests which is used to get/set a node configuration.
@@ -69,10 +79,6 @@ func (innerExecutorImpl) configuration(w http.ResponseWriter, req *http.Request)
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, GET, POST) {
-		return
-	}
-
 	response := make(map[string]interface{})
 	var e error
 	switch req.Method {
@@ -22,7 +22,14 @@ import (
 	"commons/logger"
 	"commons/url"
 	"controller/deployment"
+	"controller/dockercontroller"
+	"controller/job"
+	"encoding/json"
+	"golang.org/x/net/context"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -31,6 +38,14 @@ const (
 	PUT    string = "PUT"
 	POST   string = "POST"
 	DELETE string = "DELETE"
+
+	APP_ID   string = "appId"
+	REVISION string = "revision"
+	SERVICE  string = "service"
+	REPLICAS string = "replicas"
+
+	NDJSON_CONTENT_TYPE string = "application/x-ndjson"
+	RAW_STREAM_TYPE     string = "application/vnd.docker.raw-stream"
 )
 
 type Command interface {
@@ -39,12 +54,27 @@ type Command interface {
 
 type apiInnerCommand interface {
 	deploy(w http.ResponseWriter, req *http.Request)
+	validate(w http.ResponseWriter, req *http.Request)
 	app(w http.ResponseWriter, req *http.Request, appId string)
 	apps(w http.ResponseWriter, req *http.Request)
 	update(w http.ResponseWriter, req *http.Request, appId string)
 	stop(w http.ResponseWriter, req *http.Request, appId string)
 	start(w http.ResponseWriter, req *http.Request, appId string)
+	pause(w http.ResponseWriter, req *http.Request, appId string)
+	unpause(w http.ResponseWriter, req *http.Request, appId string)
 	events(w http.ResponseWriter, req *http.Request, appId string)
+	updatePolicy(w http.ResponseWriter, req *http.Request, appId string)
+	setUpdatePolicy(w http.ResponseWriter, req *http.Request, appId string)
+	revisions(w http.ResponseWriter, req *http.Request, appId string)
+	revision(w http.ResponseWriter, req *http.Request, appId string, revision string)
+	diffRevisions(w http.ResponseWriter, req *http.Request, appId string)
+	redeploy(w http.ResponseWriter, req *http.Request, appId string, revision string)
+	logs(w http.ResponseWriter, req *http.Request, appId string)
+	exec(w http.ResponseWriter, req *http.Request, appId string, service string)
+	startService(w http.ResponseWriter, req *http.Request, appId string, service string)
+	stopService(w http.ResponseWriter, req *http.Request, appId string, service string)
+	restartService(w http.ResponseWriter, req *http.Request, appId string, service string)
+	scaleService(w http.ResponseWriter, req *http.Request, appId string, service string)
 }
 
 type Executor struct{}
@@ -52,10 +82,96 @@ type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
 var deploymentExecutor deployment.Command
+var jobExecutor job.Command
+var router *common.Router
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	deploymentExecutor = deployment.Executor
+	jobExecutor = job.Executor{}
+
+	apps := url.Base() + url.Management() + url.Apps()
+	app := apps + "/{" + APP_ID + "}"
+	revision := app + url.Revisions() + "/{" + REVISION + ":int}"
+	service := app + url.Services() + "/{" + SERVICE + "}"
+
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: apps, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.apps(w, req)
+		}},
+		common.Route{Method: POST, Template: apps + url.Deploy(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.deploy(w, req)
+		}},
+		common.Route{Method: POST, Template: apps + url.Validate(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.validate(w, req)
+		}},
+		common.Route{Method: GET, Template: app, Handler: handleApp},
+		common.Route{Method: POST, Template: app, Handler: handleApp},
+		common.Route{Method: DELETE, Template: app, Handler: handleApp},
+		common.Route{Method: POST, Template: app + url.Update(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.update(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.Stop(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.stop(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.Start(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.start(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.Pause(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.pause(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.Unpause(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.unpause(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.Events(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.events(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: GET, Template: app + url.UpdatePolicy(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.updatePolicy(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: app + url.UpdatePolicy(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.setUpdatePolicy(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: GET, Template: app + url.Logs(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.logs(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: POST, Template: service + url.Start(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.startService(w, req, params.Get(APP_ID), params.Get(SERVICE))
+		}},
+		common.Route{Method: POST, Template: service + url.Stop(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.stopService(w, req, params.Get(APP_ID), params.Get(SERVICE))
+		}},
+		common.Route{Method: POST, Template: service + url.Restart(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.restartService(w, req, params.Get(APP_ID), params.Get(SERVICE))
+		}},
+		common.Route{Method: POST, Template: service + url.Scale(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.scaleService(w, req, params.Get(APP_ID), params.Get(SERVICE))
+		}},
+		common.Route{Method: POST, Template: service + url.Exec(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.exec(w, req, params.Get(APP_ID), params.Get(SERVICE))
+		}},
+		common.Route{Method: GET, Template: app + url.Revisions(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.revisions(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: GET, Template: app + url.Revisions() + url.Diff(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.diffRevisions(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: GET, Template: revision, Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.revision(w, req, params.Get(APP_ID), params.Get(REVISION))
+		}},
+		common.Route{Method: POST, Template: revision + url.Redeploy(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.redeploy(w, req, params.Get(APP_ID), params.Get(REVISION))
+		}},
+	)
+}
+
+func handleApp(w http.ResponseWriter, req *http.Request, params common.Params) {
+	apiInnerExecutor.app(w, req, params.Get(APP_ID))
+}
+
+// Routes returns the route table of deployment APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 // Handling requests which is related to deployment functions.
@@ -63,62 +179,58 @@ func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl, split := req.URL.Path, strings.Split(req.URL.Path, "/"); {
-	case len(split) == 7:
-		switch appId := split[len(split)-2]; {
-		case strings.HasSuffix(reqUrl, url.Start()):
-			apiInnerExecutor.start(w, req, appId)
-
-		case strings.HasSuffix(reqUrl, url.Stop()):
-			apiInnerExecutor.stop(w, req, appId)
-
-		case strings.HasSuffix(reqUrl, url.Update()):
-			apiInnerExecutor.update(w, req, appId)
-
-		case strings.HasSuffix(reqUrl, url.Events()):
-			apiInnerExecutor.events(w, req, appId)
-
-		default:
-			logger.Logging(logger.DEBUG, "Unmatched url")
-			common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-		}
-	case len(split) == 6:
-		if strings.Contains(req.URL.Path, url.Deploy()) {
-			apiInnerExecutor.deploy(w, req)
-		} else {
-			apiInnerExecutor.app(w, req, split[len(split)-1])
-		}
-	case len(split) == 5:
-		apiInnerExecutor.apps(w, req)
-	default:
-		logger.Logging(logger.DEBUG, "Unmatched url")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-	}
+	router.Handle(w, req)
 }
 
 // Handling requests which is deploy(pulling images) app to the target.
+// the app is deployed in background by a job which is returned to be polled.
 func (innerExecutorImpl) deploy(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-
 	bodyStr, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeErrorResponse(w, errors.InvalidYaml{"body is empty"})
 		return
 	}
 
-	response, e := deploymentExecutor.DeployApp(bodyStr, parseQuery(req))
+	query := parseQuery(req)
+	response, e := jobExecutor.Start(job.DEPLOY, func(ctx context.Context, progress *job.Job) (map[string]interface{}, error) {
+		return deploymentExecutor.DeployApp(ctx, bodyStr, query, progress)
+	})
 	if e != nil {
 		common.MakeErrorResponse(w, e)
 		return
 	}
+	makeJobResponse(w, response)
+}
 
-	appId := response["id"].(string)
-	w.Header().Set("Location", url.Base()+url.Management()+url.Apps()+"/"+appId)
+// Making response for a deploy, update or redeploy job started in background.
+// the job can be polled and canceled at the url in Location header.
+func makeJobResponse(w http.ResponseWriter, response map[string]interface{}) {
+	jobId := response[job.ID].(string)
+	w.Header().Set("Location", url.Base()+url.Management()+url.Jobs()+"/"+jobId)
+
+	common.MakeAcceptedResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is validating yaml description of app without deploying it.
+// result is returned with 200 even though the description is invalid.
+func (innerExecutorImpl) validate(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyStr, err := common.GetBodyFromReq(req)
+	if err != nil || len(bodyStr) == 0 {
+		common.MakeErrorResponse(w, errors.InvalidYaml{Msg: "body is empty"})
+		return
+	}
+
+	response, e := deploymentExecutor.ValidateApp(bodyStr)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
 
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
@@ -129,10 +241,6 @@ func (innerExecutorImpl) app(w http.ResponseWriter, req *http.Request, appId str
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, GET, POST, DELETE) {
-		return
-	}
-
 	response := make(map[string]interface{})
 	var e error
 	switch req.Method {
@@ -166,9 +274,6 @@ func (innerExecutorImpl) apps(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, GET) {
-		return
-	}
 	response, e := deploymentExecutor.Apps()
 	if e != nil {
 		common.MakeErrorResponse(w, e)
@@ -179,15 +284,32 @@ func (innerExecutorImpl) apps(w http.ResponseWriter, req *http.Request) {
 }
 
 // Handling requests which is updating image from registry.
+// the app is updated in background by a job which is returned to be polled.
 func (innerExecutorImpl) update(w http.ResponseWriter, req *http.Request, appId string) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
+	query := parseQuery(req)
+	response, e := jobExecutor.Start(job.UPDATE, func(ctx context.Context, progress *job.Job) (map[string]interface{}, error) {
+		err := deploymentExecutor.UpdateApp(ctx, appId, query, progress)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"id": appId}, nil
+	})
+	if e != nil {
+		common.MakeErrorResponse(w, e)
 		return
 	}
+	makeJobResponse(w, response)
+}
+
+// Handling requests which is stop the app.
+func (innerExecutorImpl) stop(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
 
-	e := deploymentExecutor.UpdateApp(appId, parseQuery(req))
+	e := deploymentExecutor.StopApp(appId)
 	if e != nil {
 		common.MakeErrorResponse(w, e)
 		return
@@ -198,15 +320,12 @@ func (innerExecutorImpl) update(w http.ResponseWriter, req *http.Request, appId
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
 
-// Handling requests which is stop the app.
-func (innerExecutorImpl) stop(w http.ResponseWriter, req *http.Request, appId string) {
+// Handling requests which is start the app.
+func (innerExecutorImpl) start(w http.ResponseWriter, req *http.Request, appId string) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-	e := deploymentExecutor.StopApp(appId)
+	e := deploymentExecutor.StartApp(appId)
 	if e != nil {
 		common.MakeErrorResponse(w, e)
 		return
@@ -217,15 +336,28 @@ func (innerExecutorImpl) stop(w http.ResponseWriter, req *http.Request, appId st
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
 
-// Handling requests which is start the app.
-func (innerExecutorImpl) start(w http.ResponseWriter, req *http.Request, appId string) {
+// Handling requests which is pause the app.
+func (innerExecutorImpl) pause(w http.ResponseWriter, req *http.Request, appId string) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
+	e := deploymentExecutor.PauseApp(appId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
 		return
 	}
-	e := deploymentExecutor.StartApp(appId)
+
+	response := make(map[string]interface{})
+	response["result"] = "success"
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is unpause the app.
+func (innerExecutorImpl) unpause(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	e := deploymentExecutor.UnpauseApp(appId)
 	if e != nil {
 		common.MakeErrorResponse(w, e)
 		return
@@ -241,10 +373,6 @@ func (innerExecutorImpl) events(w http.ResponseWriter, req *http.Request, appId
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-
 	var bodyStr string
 	bodyStr, e := common.GetBodyFromReq(req)
 	if e != nil {
@@ -263,6 +391,308 @@ func (innerExecutorImpl) events(w http.ResponseWriter, req *http.Request, appId
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
 
+// Handling requests which is getting update policy of the app.
+func (innerExecutorImpl) updatePolicy(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := deploymentExecutor.UpdatePolicy(appId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is setting update policy of the app.
+func (innerExecutorImpl) setUpdatePolicy(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyStr, e := common.GetBodyFromReq(req)
+	if e != nil {
+		common.MakeErrorResponse(w, errors.InvalidJSON{Msg: "body is empty"})
+		return
+	}
+
+	e = deploymentExecutor.SetUpdatePolicy(appId, bodyStr)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	response := make(map[string]interface{})
+	response["result"] = "success"
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting revision list of the app.
+func (innerExecutorImpl) revisions(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := deploymentExecutor.Revisions(appId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting a revision of the app.
+func (innerExecutorImpl) revision(w http.ResponseWriter, req *http.Request, appId string, revision string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := deploymentExecutor.Revision(appId, revision)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is comparing two revisions of the app.
+// revisions are given by 'from' and 'to' query.
+func (innerExecutorImpl) diffRevisions(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	from, to := req.URL.Query().Get("from"), req.URL.Query().Get("to")
+	if len(from) == 0 || len(to) == 0 {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "from and to query are required"})
+		return
+	}
+
+	response, e := deploymentExecutor.DiffRevisions(appId, from, to)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is redeploying a revision of the app.
+// the app is redeployed in background by a job which is returned to be polled.
+func (innerExecutorImpl) redeploy(w http.ResponseWriter, req *http.Request, appId string, revision string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := jobExecutor.Start(job.REDEPLOY, func(ctx context.Context, progress *job.Job) (map[string]interface{}, error) {
+		err := deploymentExecutor.RedeployRevision(ctx, appId, revision, progress)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"id": appId}, nil
+	})
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	makeJobResponse(w, response)
+}
+
+// Handling requests which is start containers of a service of the app.
+func (innerExecutorImpl) startService(w http.ResponseWriter, req *http.Request, appId string, service string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	makeServiceResponse(w, deploymentExecutor.StartService(appId, service))
+}
+
+// Handling requests which is stop containers of a service of the app.
+func (innerExecutorImpl) stopService(w http.ResponseWriter, req *http.Request, appId string, service string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	makeServiceResponse(w, deploymentExecutor.StopService(appId, service))
+}
+
+// Handling requests which is restart containers of a service of the app.
+func (innerExecutorImpl) restartService(w http.ResponseWriter, req *http.Request, appId string, service string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	makeServiceResponse(w, deploymentExecutor.RestartService(appId, service))
+}
+
+// Handling requests which is scale the number of containers of a service
+// of the app to 'replicas' query.
+func (innerExecutorImpl) scaleService(w http.ResponseWriter, req *http.Request, appId string, service string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	replicas, err := strconv.Atoi(req.URL.Query().Get(REPLICAS))
+	if err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "replicas should be a number"})
+		return
+	}
+
+	makeServiceResponse(w, deploymentExecutor.ScaleService(appId, service, replicas))
+}
+
+func makeServiceResponse(w http.ResponseWriter, e error) {
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	response := make(map[string]interface{})
+	response["result"] = "success"
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting logs of containers of the app.
+// logs can be filtered by 'service', 'tail' and 'since' query.
+// with 'follow=true', logs are streamed as newline delimited json
+// until the client closes the connection.
+func (innerExecutorImpl) logs(w http.ResponseWriter, req *http.Request, appId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	query := req.URL.Query()
+	options := dockercontroller.LogOptions{
+		Tail:   query.Get("tail"),
+		Since:  query.Get("since"),
+		Follow: query.Get("follow") == "true",
+	}
+	for _, value := range query["service"] {
+		for _, service := range strings.Split(value, ",") {
+			if len(service) != 0 {
+				options.Services = append(options.Services, service)
+			}
+		}
+	}
+
+	var flusher http.Flusher
+	if options.Follow {
+		var ok bool
+		if flusher, ok = w.(http.Flusher); !ok {
+			common.MakeErrorResponse(w, errors.Unknown{Msg: "streaming is not supported"})
+			return
+		}
+	}
+
+	entries, e := deploymentExecutor.AppLogs(req.Context(), appId, options)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	if !options.Follow {
+		logs := make([]map[string]interface{}, 0)
+		for entry := range entries {
+			logs = append(logs, convertLogEntry(entry))
+		}
+		// lines of each container are in order, merge them by timestamp.
+		sort.SliceStable(logs, func(i, j int) bool {
+			return logs[i]["timestamp"].(string) < logs[j]["timestamp"].(string)
+		})
+
+		response := make(map[string]interface{})
+		response["logs"] = logs
+		common.MakeResponse(w, common.ChangeToJson(response))
+		return
+	}
+
+	w.Header().Set("Content-Type", NDJSON_CONTENT_TYPE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for entry := range entries {
+		data, err := json.Marshal(convertLogEntry(entry))
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		w.Write(append(data, '\n'))
+		flusher.Flush()
+	}
+}
+
+// Handling requests which is executing a command in a container of the service.
+// on success, the connection is upgraded to a raw stream which carries stdin
+// from the client and output of the command in both directions.
+// output is multiplexed with 8-byte headers per frame unless 'tty' is set.
+func (innerExecutorImpl) exec(w http.ResponseWriter, req *http.Request, appId string, service string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		common.MakeErrorResponse(w, errors.Unknown{Msg: "connection upgrade is not supported"})
+		return
+	}
+
+	bodyStr, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "body is empty"})
+		return
+	}
+
+	var body struct {
+		Cmd []string `json:"cmd"`
+		Tty bool     `json:"tty"`
+	}
+	if err = json.Unmarshal([]byte(bodyStr), &body); err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "invalid exec body: " + err.Error()})
+		return
+	}
+
+	stream, err := deploymentExecutor.ExecService(req.Context(), appId, service, body.Cmd, body.Tty)
+	if err != nil {
+		common.MakeErrorResponse(w, err)
+		return
+	}
+	defer stream.Close()
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer conn.Close()
+
+	buf.WriteString("HTTP/1.1 101 UPGRADED\r\n")
+	buf.WriteString("Content-Type: " + RAW_STREAM_TYPE + "\r\n")
+	buf.WriteString("Connection: Upgrade\r\n")
+	buf.WriteString("Upgrade: tcp\r\n\r\n")
+	if err = buf.Flush(); err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	// stdin of the client may be buffered already by the http server.
+	// EOF of stdin is propagated to the exec process by closing its stdin.
+	go func() {
+		io.Copy(stream, buf)
+		if closer, ok := stream.(writeCloser); ok {
+			closer.CloseWrite()
+		}
+	}()
+	io.Copy(conn, stream)
+}
+
+// writeCloser is a stream of which the writing side can be closed alone.
+type writeCloser interface {
+	CloseWrite() error
+}
+
+func convertLogEntry(entry dockercontroller.LogEntry) map[string]interface{} {
+	return map[string]interface{}{
+		"service":   entry.Service,
+		"container": entry.Container,
+		"stream":    entry.Stream,
+		"timestamp": entry.Timestamp,
+		"log":       entry.Log,
+	}
+}
+
 func parseQuery(req *http.Request) map[string]interface{} {
 	query := make(map[string]interface{})
 
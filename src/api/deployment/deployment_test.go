@@ -17,30 +17,57 @@
 package deployment
 
 import (
+	"bufio"
 	"bytes"
 	"commons/errors"
 	urls "commons/url"
 	deploymentmocks "controller/deployment/mocks"
+	"controller/dockercontroller"
+	"controller/job"
+	jobmocks "controller/job/mocks"
+	"encoding/json"
 	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
-	ID string = "id"
+	ID     string = "id"
+	JOB_ID string = "0000000000002"
 )
 
 var (
 	appId                = "0000000000001"
 	invalidOperationList = map[string][]string{
-		"/api/v1/management/apps":           []string{PUT, POST, DELETE},
-		"/api/v1/management/apps/deploy":    []string{GET, PUT, DELETE},
-		"/api/v1/management/apps/11":        []string{PUT},
-		"/api/v1/management/apps/11/update": []string{GET, PUT, DELETE},
-		"/api/v1/management/apps/11/stop":   []string{GET, PUT, DELETE},
-		"/api/v1/management/apps/11/start":  []string{GET, PUT, DELETE},
+		"/api/v1/management/apps":                         []string{PUT, POST, DELETE},
+		"/api/v1/management/apps/deploy":                  []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/validate":                []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11":                      []string{PUT},
+		"/api/v1/management/apps/11/update":               []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/stop":                 []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/start":                []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/pause":                []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/unpause":              []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/revisions":            []string{PUT, POST, DELETE},
+		"/api/v1/management/apps/11/revisions/1":          []string{PUT, POST, DELETE},
+		"/api/v1/management/apps/11/revisions/diff":       []string{PUT, POST, DELETE},
+		"/api/v1/management/apps/11/revisions/1/redeploy": []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/logs":                 []string{PUT, POST, DELETE},
+		"/api/v1/management/apps/11/updatepolicy":         []string{PUT, DELETE},
+		"/api/v1/management/apps/11/services/web/start":   []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/services/web/stop":    []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/services/web/restart": []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/services/web/scale":   []string{GET, PUT, DELETE},
+		"/api/v1/management/apps/11/services/web/exec":    []string{GET, PUT, DELETE},
 	}
 	testList = []testObj{
 		{"InvalidYamlError", errors.InvalidYaml{}, http.StatusBadRequest},
@@ -58,6 +85,10 @@ var (
 	testMap = map[string]interface{}{
 		"id": appId,
 	}
+	jobMap = map[string]interface{}{
+		"id":    JOB_ID,
+		"state": "running",
+	}
 )
 
 type testObj struct {
@@ -100,35 +131,46 @@ func TestDeployAPI_ExpectSuccess(t *testing.T) {
 	data.Set("name", "test")
 	body := bytes.NewBufferString(data.Encode())
 
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
 	deployedApp := make(map[string]interface{})
 	deployedApp[ID] = appId
 
+	var result map[string]interface{}
 	gomock.InOrder(
-		deploymentExecutorMockObj.EXPECT().DeployApp(gomock.Any(), nil).Return(deployedApp, nil),
+		jobExecutorMockObj.EXPECT().Start(job.DEPLOY, gomock.Any()).DoAndReturn(func(kind string, task job.Task) (map[string]interface{}, error) {
+			result, _ = task(context.Background(), &job.Job{})
+			return jobMap, nil
+		}),
+		deploymentExecutorMockObj.EXPECT().DeployApp(gomock.Any(), gomock.Any(), nil, gomock.Any()).Return(deployedApp, nil),
 	)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+urls.Deploy(), body)
 
 	deploymentExecutor = deploymentExecutorMockObj
+	jobExecutor = jobExecutorMockObj
 
 	deploymentAPIExecutor.Handle(w, req)
 
-	if w.Header().Get("Location") != urls.Base()+urls.Management()+urls.Apps()+"/"+appId ||
-		w.Code != http.StatusOK {
+	if w.Header().Get("Location") != urls.Base()+urls.Management()+urls.Jobs()+"/"+JOB_ID ||
+		w.Code != http.StatusAccepted {
 		t.Error()
 	}
+	if !reflect.DeepEqual(result, deployedApp) {
+		t.Errorf("Expected result of job : %v, Actual result : %v", deployedApp, result)
+	}
 }
 
-func TestDeployAPIWhenControllerFailed_ExpecReturnError(t *testing.T) {
+func TestDeployAPIWhenJobFailedToStart_ExpecReturnError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
 
 	for _, test := range testList {
 		gomock.InOrder(
-			deploymentExecutorMockObj.EXPECT().DeployApp(gomock.Any(), nil).Return(nil, test.err),
+			jobExecutorMockObj.EXPECT().Start(job.DEPLOY, gomock.Any()).Return(nil, test.err),
 		)
 
 		data := url.Values{}
@@ -138,7 +180,7 @@ func TestDeployAPIWhenControllerFailed_ExpecReturnError(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+urls.Deploy(), body)
 
-		deploymentExecutor = deploymentExecutorMockObj
+		jobExecutor = jobExecutorMockObj
 
 		deploymentAPIExecutor.Handle(w, req)
 
@@ -526,14 +568,105 @@ func TestUpdateAPI_ExpectSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
 
+	var result map[string]interface{}
 	gomock.InOrder(
-		deploymentExecutorMockObj.EXPECT().UpdateApp(appId, nil).Return(nil),
+		jobExecutorMockObj.EXPECT().Start(job.UPDATE, gomock.Any()).DoAndReturn(func(kind string, task job.Task) (map[string]interface{}, error) {
+			result, _ = task(context.Background(), &job.Job{})
+			return jobMap, nil
+		}),
+		deploymentExecutorMockObj.EXPECT().UpdateApp(gomock.Any(), appId, nil, gomock.Any()).Return(nil),
 	)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Update(), nil)
 
+	deploymentExecutor = deploymentExecutorMockObj
+	jobExecutor = jobExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Header().Get("Location") != urls.Base()+urls.Management()+urls.Jobs()+"/"+JOB_ID ||
+		w.Code != http.StatusAccepted {
+		t.Errorf("Expected return Accepted, Actual Return : %d", w.Code)
+	}
+	if !reflect.DeepEqual(result, testMap) {
+		t.Errorf("Expected result of job : %v, Actual result : %v", testMap, result)
+	}
+}
+
+func TestUpdateAPIWhenControllerFailed_ExpectJobFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	var err error
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Start(job.UPDATE, gomock.Any()).DoAndReturn(func(kind string, task job.Task) (map[string]interface{}, error) {
+			_, err = task(context.Background(), &job.Job{})
+			return jobMap, nil
+		}),
+		deploymentExecutorMockObj.EXPECT().UpdateApp(gomock.Any(), appId, nil, gomock.Any()).Return(errors.RolledBack{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Update(), nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+	jobExecutor = jobExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected return Accepted, Actual Return : %d", w.Code)
+	}
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err of job : RolledBack, Actual err : %v", err)
+	case errors.RolledBack:
+	}
+}
+
+func TestUpdateAPIWhenJobFailedToStart_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			jobExecutorMockObj.EXPECT().Start(job.UPDATE, gomock.Any()).Return(nil, test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Update(), nil)
+
+		jobExecutor = jobExecutorMockObj
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Expected error code : %d, Actual error code : %d\n", test.expectCode, w.Code)
+		}
+	}
+}
+
+func TestUpdatePolicyAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().UpdatePolicy(appId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.UpdatePolicy(), nil)
+
 	deploymentExecutor = deploymentExecutorMockObj
 
 	deploymentAPIExecutor.Handle(w, req)
@@ -543,7 +676,30 @@ func TestUpdateAPI_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestUpdateAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+func TestSetUpdatePolicyAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := `{"mode":"immediate"}`
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().SetUpdatePolicy(appId, body).Return(nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.UpdatePolicy(), bytes.NewReader([]byte(body)))
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+}
+
+func TestSetUpdatePolicyAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -551,11 +707,57 @@ func TestUpdateAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
 
 	for _, test := range testList {
 		gomock.InOrder(
-			deploymentExecutorMockObj.EXPECT().UpdateApp(appId, nil).Return(test.err),
+			deploymentExecutorMockObj.EXPECT().SetUpdatePolicy(appId, gomock.Any()).Return(test.err),
 		)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Update(), nil)
+		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.UpdatePolicy(), bytes.NewReader([]byte(`{"mode":"manual"}`)))
+
+		deploymentExecutor = deploymentExecutorMockObj
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Expected error code : %d, Actual error code : %d\n", test.expectCode, w.Code)
+		}
+	}
+}
+
+func TestRevisionsAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().Revisions(appId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions(), nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+}
+
+func TestRevisionAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			deploymentExecutorMockObj.EXPECT().Revision(appId, "1").Return(nil, test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions()+"/1", nil)
 
 		deploymentExecutor = deploymentExecutorMockObj
 
@@ -566,3 +768,564 @@ func TestUpdateAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
 		}
 	}
 }
+
+func TestDiffRevisionsAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().DiffRevisions(appId, "1", "2").Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions()+urls.Diff()+"?from=1&to=2", nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+}
+
+func TestDiffRevisionsAPIWithoutQuery_ExpectReturnError(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions()+urls.Diff()+"?from=1", nil)
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected error code : %d, Actual error code : %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRedeployAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	var result map[string]interface{}
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Start(job.REDEPLOY, gomock.Any()).DoAndReturn(func(kind string, task job.Task) (map[string]interface{}, error) {
+			result, _ = task(context.Background(), &job.Job{})
+			return jobMap, nil
+		}),
+		deploymentExecutorMockObj.EXPECT().RedeployRevision(gomock.Any(), appId, "1", gomock.Any()).Return(nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions()+"/1"+urls.Redeploy(), nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+	jobExecutor = jobExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Header().Get("Location") != urls.Base()+urls.Management()+urls.Jobs()+"/"+JOB_ID ||
+		w.Code != http.StatusAccepted {
+		t.Errorf("Expected return Accepted, Actual Return : %d", w.Code)
+	}
+	if !reflect.DeepEqual(result, testMap) {
+		t.Errorf("Expected result of job : %v, Actual result : %v", testMap, result)
+	}
+}
+
+func TestRedeployAPIWhenControllerFailed_ExpectJobFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	var err error
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Start(job.REDEPLOY, gomock.Any()).DoAndReturn(func(kind string, task job.Task) (map[string]interface{}, error) {
+			_, err = task(context.Background(), &job.Job{})
+			return jobMap, nil
+		}),
+		deploymentExecutorMockObj.EXPECT().RedeployRevision(gomock.Any(), appId, "1", gomock.Any()).Return(errors.RolledBack{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Revisions()+"/1"+urls.Redeploy(), nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+	jobExecutor = jobExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected return Accepted, Actual Return : %d", w.Code)
+	}
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err of job : RolledBack, Actual err : %v", err)
+	case errors.RolledBack:
+	}
+}
+
+func makeLogEntries(entries ...dockercontroller.LogEntry) <-chan dockercontroller.LogEntry {
+	result := make(chan dockercontroller.LogEntry, len(entries))
+	for _, entry := range entries {
+		result <- entry
+	}
+	close(result)
+	return result
+}
+
+func TestLogsAPI_ExpectMergedByTimestamp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	options := dockercontroller.LogOptions{Services: []string{"web", "db"}, Tail: "10", Since: "10m"}
+	entries := makeLogEntries(
+		dockercontroller.LogEntry{Service: "web", Container: "app_web_1", Stream: "stdout", Timestamp: "2018-01-01T00:00:02.000000000Z", Log: "second"},
+		dockercontroller.LogEntry{Service: "db", Container: "app_db_1", Stream: "stderr", Timestamp: "2018-01-01T00:00:01.000000000Z", Log: "first"},
+	)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().AppLogs(gomock.Any(), appId, options).Return(entries, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Logs()+"?service=web,db&tail=10&since=10m", nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+
+	response := make(map[string][]map[string]interface{})
+	json.Unmarshal(w.Body.Bytes(), &response)
+	logs := response["logs"]
+	if len(logs) != 2 || logs[0]["log"] != "first" || logs[1]["log"] != "second" {
+		t.Errorf("Unexpected logs : %v", logs)
+	}
+}
+
+func TestLogsAPIWithFollow_ExpectStreamed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	options := dockercontroller.LogOptions{Follow: true}
+	entries := makeLogEntries(
+		dockercontroller.LogEntry{Service: "web", Container: "app_web_1", Stream: "stdout", Timestamp: "2018-01-01T00:00:01.000000000Z", Log: "first"},
+		dockercontroller.LogEntry{Service: "web", Container: "app_web_1", Stream: "stdout", Timestamp: "2018-01-01T00:00:02.000000000Z", Log: "second"},
+	)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().AppLogs(gomock.Any(), appId, options).Return(entries, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Logs()+"?follow=true", nil)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != NDJSON_CONTENT_TYPE {
+		t.Errorf("Unexpected response : %d, %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected lines : %v", lines)
+	}
+	line := make(map[string]interface{})
+	json.Unmarshal([]byte(lines[0]), &line)
+	expected := map[string]interface{}{
+		"service":   "web",
+		"container": "app_web_1",
+		"stream":    "stdout",
+		"timestamp": "2018-01-01T00:00:01.000000000Z",
+		"log":       "first",
+	}
+	if !reflect.DeepEqual(expected, line) {
+		t.Errorf("Expected line : %v, Actual line : %v", expected, line)
+	}
+}
+
+func TestLogsAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			deploymentExecutorMockObj.EXPECT().AppLogs(gomock.Any(), appId, gomock.Any()).Return(nil, test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Logs()+"?follow=true", nil)
+
+		deploymentExecutor = deploymentExecutorMockObj
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Expected error : %d, Actual Error : %d", test.expectCode, w.Code)
+		}
+	}
+}
+
+func execRequest(serverURL string, body string) (net.Conn, *bufio.Reader, *http.Response, error) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	req, _ := http.NewRequest(POST, serverURL+urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Services()+"/web"+urls.Exec(), strings.NewReader(body))
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	return conn, reader, resp, nil
+}
+
+func TestExecAPI_ExpectUpgradedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	server, stream := net.Pipe()
+	defer stream.Close()
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().ExecService(gomock.Any(), appId, "web", []string{"sh"}, true).Return(server, nil),
+	)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	ts := httptest.NewServer(http.HandlerFunc(deploymentAPIExecutor.Handle))
+	defer ts.Close()
+
+	conn, reader, resp, err := execRequest(ts.URL, `{"cmd":["sh"],"tty":true}`)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	defer conn.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected code : %d, Actual code : %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+
+	if resp.Header.Get("Content-Type") != RAW_STREAM_TYPE {
+		t.Errorf("Expected content type : %s, Actual : %s", RAW_STREAM_TYPE, resp.Header.Get("Content-Type"))
+	}
+
+	conn.Write([]byte("ls\n"))
+	input := make([]byte, 3)
+	if _, err = stream.Read(input); err != nil || string(input) != "ls\n" {
+		t.Errorf("Expected stdin : ls, Actual : %q, err : %v", input, err)
+	}
+
+	stream.Write([]byte("output"))
+	stream.Close()
+
+	output, _ := ioutil.ReadAll(reader)
+	if string(output) != "output" {
+		t.Errorf("Expected output : output, Actual : %q", output)
+	}
+}
+
+type halfClosableStream struct {
+	net.Conn
+	writeClosed chan struct{}
+}
+
+func (s *halfClosableStream) CloseWrite() error {
+	close(s.writeClosed)
+	return nil
+}
+
+func TestExecAPIWhenStdinClosed_ExpectStreamWriteClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	server, stream := net.Pipe()
+	defer stream.Close()
+	execStream := &halfClosableStream{Conn: server, writeClosed: make(chan struct{})}
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().ExecService(gomock.Any(), appId, "web", []string{"cat"}, false).Return(execStream, nil),
+	)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	ts := httptest.NewServer(http.HandlerFunc(deploymentAPIExecutor.Handle))
+	defer ts.Close()
+
+	conn, _, resp, err := execRequest(ts.URL, `{"cmd":["cat"]}`)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	defer conn.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected code : %d, Actual code : %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+
+	conn.Write([]byte("input"))
+	conn.(*net.TCPConn).CloseWrite()
+
+	input := make([]byte, 5)
+	if _, err = io.ReadFull(stream, input); err != nil || string(input) != "input" {
+		t.Errorf("Expected stdin : input, Actual : %q, err : %v", input, err)
+	}
+
+	select {
+	case <-execStream.writeClosed:
+	case <-time.After(time.Second):
+		t.Errorf("Expected stdin of exec closed")
+	}
+}
+
+func TestExecAPIWithInvalidBody_ExpectReturnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(deploymentAPIExecutor.Handle))
+	defer ts.Close()
+
+	for _, body := range []string{"", "{invalid", `{"cmd":"ls"}`} {
+		conn, _, resp, err := execRequest(ts.URL, body)
+		if err != nil {
+			t.Fatalf("Unexpected err: %s", err.Error())
+		}
+		conn.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected error : %d, Actual Error : %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	}
+}
+
+func TestExecAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	ts := httptest.NewServer(http.HandlerFunc(deploymentAPIExecutor.Handle))
+	defer ts.Close()
+
+	tests := append(testList, testObj{"Forbidden", errors.Forbidden{}, http.StatusForbidden})
+	for _, test := range tests {
+		gomock.InOrder(
+			deploymentExecutorMockObj.EXPECT().ExecService(gomock.Any(), appId, "web", []string{"ls"}, false).Return(nil, test.err),
+		)
+
+		deploymentExecutor = deploymentExecutorMockObj
+
+		conn, _, resp, err := execRequest(ts.URL, `{"cmd":["ls"]}`)
+		if err != nil {
+			t.Fatalf("Unexpected err: %s", err.Error())
+		}
+		conn.Close()
+
+		if resp.StatusCode != test.expectCode {
+			t.Errorf("Expected error : %d, Actual Error : %d", test.expectCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestExecAPIWithoutHijacker_ExpectReturnError(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+urls.Services()+"/web"+urls.Exec(), bytes.NewReader([]byte(`{"cmd":["ls"]}`)))
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestServiceControlAPIs_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().StartService(appId, "web").Return(nil),
+		deploymentExecutorMockObj.EXPECT().StopService(appId, "web").Return(nil),
+		deploymentExecutorMockObj.EXPECT().RestartService(appId, "web").Return(nil),
+		deploymentExecutorMockObj.EXPECT().ScaleService(appId, "web", 3).Return(nil),
+	)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	service := urls.Base() + urls.Management() + urls.Apps() + "/" + appId + urls.Services() + "/web"
+	for _, path := range []string{urls.Start(), urls.Stop(), urls.Restart(), urls.Scale() + "?replicas=3"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, service+path, nil)
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected return OK for %s, Actual Return : %d", path, w.Code)
+		}
+	}
+}
+
+func TestServiceControlAPIsWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	service := urls.Base() + urls.Management() + urls.Apps() + "/" + appId + urls.Services() + "/web"
+	for _, test := range testList {
+		gomock.InOrder(
+			deploymentExecutorMockObj.EXPECT().StartService(appId, "web").Return(test.err),
+			deploymentExecutorMockObj.EXPECT().StopService(appId, "web").Return(test.err),
+			deploymentExecutorMockObj.EXPECT().RestartService(appId, "web").Return(test.err),
+			deploymentExecutorMockObj.EXPECT().ScaleService(appId, "web", 0).Return(test.err),
+		)
+
+		deploymentExecutor = deploymentExecutorMockObj
+
+		for _, path := range []string{urls.Start(), urls.Stop(), urls.Restart(), urls.Scale() + "?replicas=0"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(POST, service+path, nil)
+
+			deploymentAPIExecutor.Handle(w, req)
+
+			if w.Code != test.expectCode {
+				t.Errorf("Expected error code : %d, Actual error code : %d\n", test.expectCode, w.Code)
+			}
+		}
+	}
+}
+
+func TestScaleServiceAPIWithInvalidReplicas_ExpectReturnError(t *testing.T) {
+	service := urls.Base() + urls.Management() + urls.Apps() + "/" + appId + urls.Services() + "/web"
+	for _, query := range []string{"", "?replicas=", "?replicas=two"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, service+urls.Scale()+query, nil)
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected error code : %d, Actual error code : %d\n", http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestPauseAndUnpauseAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().PauseApp(appId).Return(nil),
+		deploymentExecutorMockObj.EXPECT().UnpauseApp(appId).Return(nil),
+	)
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	for _, path := range []string{urls.Pause(), urls.Unpause()} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+path, nil)
+
+		deploymentAPIExecutor.Handle(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected return OK for %s, Actual Return : %d", path, w.Code)
+		}
+	}
+}
+
+func TestPauseAndUnpauseAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			deploymentExecutorMockObj.EXPECT().PauseApp(appId).Return(test.err),
+			deploymentExecutorMockObj.EXPECT().UnpauseApp(appId).Return(test.err),
+		)
+
+		deploymentExecutor = deploymentExecutorMockObj
+
+		for _, path := range []string{urls.Pause(), urls.Unpause()} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+"/"+appId+path, nil)
+
+			deploymentAPIExecutor.Handle(w, req)
+
+			if w.Code != test.expectCode {
+				t.Errorf("Expected error code : %d, Actual error code : %d\n", test.expectCode, w.Code)
+			}
+		}
+	}
+}
+
+func TestValidateAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+
+	result := map[string]interface{}{
+		"valid":    false,
+		"errors":   []map[string]interface{}{{"service": "web", "field": "image", "message": "image is required"}},
+		"warnings": []map[string]interface{}{},
+	}
+
+	gomock.InOrder(
+		deploymentExecutorMockObj.EXPECT().ValidateApp("body").Return(result, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+urls.Validate(), bytes.NewReader([]byte("body")))
+
+	deploymentExecutor = deploymentExecutorMockObj
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["valid"] != false || len(response["errors"].([]interface{})) != 1 {
+		t.Errorf("Unexpected response : %s", w.Body.String())
+	}
+}
+
+func TestValidateAPIWithEmptyBody_ExpectReturnError(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Apps()+urls.Validate(), nil)
+
+	deploymentAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected error code : %d, Actual error code : %d", http.StatusBadRequest, w.Code)
+	}
+}
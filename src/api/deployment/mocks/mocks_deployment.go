@@ -129,3 +129,19 @@ func (_m *MockapiInnerCommand) events(w http.ResponseWriter, req *http.Request,
 func (_mr *_MockapiInnerCommandRecorder) events(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "events", arg0, arg1, arg2)
 }
+
+func (_m *MockapiInnerCommand) updatePolicy(w http.ResponseWriter, req *http.Request, appId string) {
+	_m.ctrl.Call(_m, "updatePolicy", w, req, appId)
+}
+
+func (_mr *_MockapiInnerCommandRecorder) updatePolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "updatePolicy", arg0, arg1, arg2)
+}
+
+func (_m *MockapiInnerCommand) setUpdatePolicy(w http.ResponseWriter, req *http.Request, appId string) {
+	_m.ctrl.Call(_m, "setUpdatePolicy", w, req, appId)
+}
+
+func (_mr *_MockapiInnerCommandRecorder) setUpdatePolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "setUpdatePolicy", arg0, arg1, arg2)
+}
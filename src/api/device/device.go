@@ -18,12 +18,11 @@ package device
 
 import (
 	"api/common"
-	"commons/errors"
 	"commons/logger"
 	"commons/url"
 	"controller/device"
+	"controller/gc"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -40,6 +39,7 @@ type Command interface {
 type apiInnerCommand interface {
 	reboot(w http.ResponseWriter, req *http.Request)
 	restore(w http.ResponseWriter, req *http.Request)
+	gc(w http.ResponseWriter, req *http.Request)
 }
 
 type Executor struct{}
@@ -47,25 +47,38 @@ type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
 var deviceExecutor device.Command
+var gcExecutor gc.Command
+var router *common.Router
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	deviceExecutor = device.Executor{}
+	gcExecutor = gc.Executor{}
+
+	device := url.Base() + url.Management() + url.Device()
+	router = common.NewRouter(
+		common.Route{Method: POST, Template: device + url.Reboot(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.reboot(w, req)
+		}},
+		common.Route{Method: POST, Template: device + url.Restore(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.restore(w, req)
+		}},
+		common.Route{Method: POST, Template: device + url.GC(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.gc(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of device APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl, _ := req.URL.Path, strings.Split(req.URL.Path, "/"); {
-	case strings.Contains(reqUrl, url.Restore()):
-		apiInnerExecutor.restore(w, req)
-	case strings.Contains(reqUrl, url.Reboot()):
-		apiInnerExecutor.reboot(w, req)
-	default:
-		logger.Logging(logger.DEBUG, "Unmatched url")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-	}
+	router.Handle(w, req)
 }
 
 // reboot handles requests which is used to reboot a device.
@@ -73,10 +86,6 @@ func (innerExecutorImpl) reboot(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-
 	e := deviceExecutor.Reboot()
 	if e != nil {
 		common.MakeErrorResponse(w, e)
@@ -93,10 +102,6 @@ func (innerExecutorImpl) restore(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-
 	e := deviceExecutor.Restore()
 	if e != nil {
 		common.MakeErrorResponse(w, e)
@@ -107,3 +112,18 @@ func (innerExecutorImpl) restore(w http.ResponseWriter, req *http.Request) {
 	response["result"] = "success"
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
+
+// gc handles requests which is used to remove unused images and containers
+// and reclaim disk space of a device.
+func (innerExecutorImpl) gc(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := gcExecutor.Collect()
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
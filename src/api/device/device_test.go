@@ -20,6 +20,7 @@ import (
 	"commons/errors"
 	urls "commons/url"
 	devicemocks "controller/device/mocks"
+	gcmocks "controller/gc/mocks"
 	"github.com/golang/mock/gomock"
 	"net/http"
 	"net/http/httptest"
@@ -30,6 +31,7 @@ var (
 	invalidOperationList = map[string][]string{
 		"/api/v1/management/device/reboot":  []string{GET, PUT, DELETE},
 		"/api/v1/management/device/restore": []string{GET, PUT, DELETE},
+		"/api/v1/management/device/gc":      []string{GET, PUT, DELETE},
 	}
 	testMap = map[string]interface{}{
 		"test": "test",
@@ -169,4 +171,49 @@ func TestRestoreAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
 			t.Errorf("Unexpected error code : %d\n", w.Code)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestGCAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gcExecutorMockObj := gcmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		gcExecutorMockObj.EXPECT().Collect().Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Device()+urls.GC(), nil)
+
+	gcExecutor = gcExecutorMockObj
+
+	deviceAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestGCAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gcExecutorMockObj := gcmocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			gcExecutorMockObj.EXPECT().Collect().Return(nil, test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, urls.Base()+urls.Management()+urls.Device()+urls.GC(), nil)
+
+		gcExecutor = gcExecutorMockObj
+
+		deviceAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Unexpected error code : %d\n", w.Code)
+		}
+	}
+}
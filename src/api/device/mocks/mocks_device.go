@@ -101,3 +101,13 @@ func (m *MockapiInnerCommand) restore(w http.ResponseWriter, req *http.Request)
 func (mr *MockapiInnerCommandMockRecorder) restore(w, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "restore", reflect.TypeOf((*MockapiInnerCommand)(nil).restore), w, req)
 }
+
+// gc mocks base method
+func (m *MockapiInnerCommand) gc(w http.ResponseWriter, req *http.Request) {
+	m.ctrl.Call(m, "gc", w, req)
+}
+
+// gc indicates an expected call of gc
+func (mr *MockapiInnerCommandMockRecorder) gc(w, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "gc", reflect.TypeOf((*MockapiInnerCommand)(nil).gc), w, req)
+}
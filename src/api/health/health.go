@@ -21,9 +21,7 @@ import (
 	"commons/logger"
 	"commons/url"
 	"controller/health"
-
 	"net/http"
-	"strings"
 )
 
 const (
@@ -46,21 +44,33 @@ type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
 var healthExecutor health.Command
+var router *common.Router
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	healthExecutor = health.Executor{}
+
+	router = common.NewRouter(
+		common.Route{Method: POST, Template: url.Base() + url.Management() + url.Unregister(), Handler: handleUnregister},
+		common.Route{Method: POST, Template: url.Base() + url.Management() + url.Nodes() + url.Unregister(), Handler: handleUnregister},
+	)
 }
 
-// Handling requests which is related to deployment functions.
+// Routes returns the route table of health APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is related to health functions.
 func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl := req.URL.Path; {
-	case strings.Contains(reqUrl, url.Unregister()):
-		apiInnerExecutor.unregister(w, req)
-	}
+	router.Handle(w, req)
+}
+
+func handleUnregister(w http.ResponseWriter, req *http.Request, _ common.Params) {
+	apiInnerExecutor.unregister(w, req)
 }
 
 // Handling requests which is to unregister to manager service.
@@ -68,10 +78,6 @@ func (innerExecutorImpl) unregister(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, POST) {
-		return
-	}
-
 	e := healthExecutor.Unregister()
 	if e != nil {
 		common.MakeErrorResponse(w, e)
@@ -0,0 +1,107 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+// Package api/job provides functionality to handle requests
+// of polling and canceling jobs deploying and updating apps.
+package job
+
+import (
+	"api/common"
+	"commons/logger"
+	"commons/url"
+	"controller/job"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	PUT    string = "PUT"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	JOB_ID string = "jobId"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	job(w http.ResponseWriter, req *http.Request, jobId string)
+	cancel(w http.ResponseWriter, req *http.Request, jobId string)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var jobExecutor job.Command
+var router *common.Router
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	jobExecutor = job.Executor{}
+
+	jobs := url.Base() + url.Management() + url.Jobs()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: jobs + "/{" + JOB_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.job(w, req, params.Get(JOB_ID))
+		}},
+		common.Route{Method: DELETE, Template: jobs + "/{" + JOB_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.cancel(w, req, params.Get(JOB_ID))
+		}},
+	)
+}
+
+// Routes returns the route table of job APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is polling and canceling jobs.
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+// Handling requests which is getting phase, progress and result of a job.
+func (innerExecutorImpl) job(w http.ResponseWriter, req *http.Request, jobId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := jobExecutor.Get(jobId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is canceling a job.
+// the job is stopped in background, so it is returned before being canceled.
+func (innerExecutorImpl) cancel(w http.ResponseWriter, req *http.Request, jobId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := jobExecutor.Cancel(jobId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeAcceptedResponse(w, common.ChangeToJson(response))
+}
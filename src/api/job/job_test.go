@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package job
+
+import (
+	"commons/errors"
+	jobmocks "controller/job/mocks"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const (
+	jobId = "0000000000002"
+)
+
+var (
+	invalidOperationList = map[string][]string{
+		"/api/v1/management/jobs/" + jobId: []string{PUT, POST},
+	}
+	testMap = map[string]interface{}{
+		"id":    jobId,
+		"type":  "deploy",
+		"state": "running",
+		"phase": "pulling",
+	}
+)
+
+var jobAPIExecutor Command
+
+func init() {
+	jobAPIExecutor = Executor{}
+}
+
+func TestJobAPIInvalidOperation(t *testing.T) {
+	for api, invalidMethodList := range invalidOperationList {
+		for _, method := range invalidMethodList {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, api, nil)
+
+			jobAPIExecutor.Handle(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected error : %d, Actual Error : %d", http.StatusMethodNotAllowed, w.Code)
+			}
+		}
+	}
+}
+
+func TestJobAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Get(jobId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/jobs/"+jobId, nil)
+
+	jobExecutor = jobExecutorMockObj
+
+	jobAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+
+	response := make(map[string]interface{})
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if !reflect.DeepEqual(response, testMap) {
+		t.Errorf("Expected body : %v, Actual body : %v", testMap, response)
+	}
+}
+
+func TestJobAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Get(jobId).Return(nil, errors.NotFoundURL{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/jobs/"+jobId, nil)
+
+	jobExecutor = jobExecutorMockObj
+
+	jobAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestCancelJobAPI_ExpectAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Cancel(jobId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/management/jobs/"+jobId, nil)
+
+	jobExecutor = jobExecutorMockObj
+
+	jobAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestCancelJobAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobExecutorMockObj := jobmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		jobExecutorMockObj.EXPECT().Cancel(jobId).Return(nil, errors.NotFoundURL{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/management/jobs/"+jobId, nil)
+
+	jobExecutor = jobExecutorMockObj
+
+	jobAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
@@ -0,0 +1,146 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alerts
+
+import (
+	"api/common"
+	"commons/errors"
+	"commons/logger"
+	"commons/url"
+	"controller/monitoring/alerts"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	PUT    string = "PUT"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	ALERT_ID string = "alertId"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	addAlert(w http.ResponseWriter, req *http.Request)
+	alerts(w http.ResponseWriter, req *http.Request)
+	alert(w http.ResponseWriter, req *http.Request, alertId string)
+	deleteAlert(w http.ResponseWriter, req *http.Request, alertId string)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var alertsExecutor alerts.Command
+var router *common.Router
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	alertsExecutor = alerts.Executor{}
+
+	base := url.Base() + url.Monitoring() + url.Alerts()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: base, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.alerts(w, req)
+		}},
+		common.Route{Method: POST, Template: base, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.addAlert(w, req)
+		}},
+		common.Route{Method: GET, Template: base + "/{" + ALERT_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.alert(w, req, params.Get(ALERT_ID))
+		}},
+		common.Route{Method: DELETE, Template: base + "/{" + ALERT_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.deleteAlert(w, req, params.Get(ALERT_ID))
+		}},
+	)
+}
+
+// Routes returns the route table of alert APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is managing alert rules of resources.
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+// Handling requests which is adding a new alert rule.
+func (innerExecutorImpl) addAlert(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyStr, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "body is empty"})
+		return
+	}
+
+	response, e := alertsExecutor.AddRule(bodyStr)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting all of alert rules.
+func (innerExecutorImpl) alerts(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := alertsExecutor.GetRules()
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting an alert rule.
+func (innerExecutorImpl) alert(w http.ResponseWriter, req *http.Request, alertId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := alertsExecutor.GetRule(alertId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is deleting an alert rule.
+func (innerExecutorImpl) deleteAlert(w http.ResponseWriter, req *http.Request, alertId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	e := alertsExecutor.DeleteRule(alertId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, nil)
+}
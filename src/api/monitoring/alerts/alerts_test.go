@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alerts
+
+import (
+	"bytes"
+	"commons/errors"
+	alertsmocks "controller/monitoring/alerts/mocks"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testAlertId    = "test_alert_id"
+	testBodyString = `{"target":"host","metric":"mem","operator":">","threshold":90}`
+)
+
+var (
+	invalidOperationList = map[string][]string{
+		"/api/v1/monitoring/alerts":               []string{PUT, DELETE},
+		"/api/v1/monitoring/alerts/test_alert_id": []string{POST, PUT},
+	}
+	testMap = map[string]interface{}{
+		"id": testAlertId,
+	}
+)
+
+var alertsAPIExecutor Command
+
+func init() {
+	alertsAPIExecutor = Executor{}
+}
+
+func TestAlertsAPIInvalidOperation(t *testing.T) {
+	for api, invalidMethodList := range invalidOperationList {
+		for _, method := range invalidMethodList {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, api, nil)
+
+			alertsAPIExecutor.Handle(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected error : %d, Actual Error : %d", http.StatusMethodNotAllowed, w.Code)
+			}
+		}
+	}
+}
+
+func TestAddAlertAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alertsExecutorMockObj := alertsmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		alertsExecutorMockObj.EXPECT().AddRule(testBodyString).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/monitoring/alerts", bytes.NewReader([]byte(testBodyString)))
+
+	alertsExecutor = alertsExecutorMockObj
+
+	alertsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestAddAlertAPIWithInvalidBody_ExpectBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alertsExecutorMockObj := alertsmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		alertsExecutorMockObj.EXPECT().AddRule(testBodyString).Return(nil, errors.InvalidParam{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/monitoring/alerts", bytes.NewReader([]byte(testBodyString)))
+
+	alertsExecutor = alertsExecutorMockObj
+
+	alertsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetAlertsAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alertsExecutorMockObj := alertsmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		alertsExecutorMockObj.EXPECT().GetRules().Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/monitoring/alerts", nil)
+
+	alertsExecutor = alertsExecutorMockObj
+
+	alertsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestGetAlertAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alertsExecutorMockObj := alertsmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		alertsExecutorMockObj.EXPECT().GetRule(testAlertId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/monitoring/alerts/"+testAlertId, nil)
+
+	alertsExecutor = alertsExecutorMockObj
+
+	alertsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestDeleteAlertAPIWhenAlertNotExist_ExpectErrorResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alertsExecutorMockObj := alertsmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		alertsExecutorMockObj.EXPECT().DeleteRule(testAlertId).Return(errors.NotFoundURL{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/monitoring/alerts/"+testAlertId, nil)
+
+	alertsExecutor = alertsExecutorMockObj
+
+	alertsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusNotFound, w.Code)
+	}
+}
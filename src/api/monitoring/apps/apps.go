@@ -0,0 +1,163 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package apps
+
+import (
+	"api/common"
+	"commons/errors"
+	"commons/logger"
+	"commons/url"
+	"controller/dockercontroller"
+	"controller/monitoring/apps"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	GET    string = "GET"
+	PUT    string = "PUT"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	APPID   string = "appid"
+	SERVICE string = "service"
+	STATUS  string = "status"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	stream(w http.ResponseWriter, req *http.Request)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var appsMonitor apps.Command
+var router *common.Router
+
+// Interval of comment lines which keep idle connections alive.
+var keepAliveInterval = 15 * time.Second
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	appsMonitor = apps.Executor{}
+
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: url.Base() + url.Monitoring() + url.Apps() + url.Events() + url.Stream(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.stream(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of app monitoring APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is related to app monitoring functions.
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+// Handling requests which is streaming container and app state changes
+// as server-sent events until the client closes the connection.
+// events can be filtered by appid, service and status query.
+func (innerExecutorImpl) stream(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		common.MakeErrorResponse(w, errors.Unknown{Msg: "streaming is not supported"})
+		return
+	}
+
+	query := req.URL.Query()
+	filter := apps.EventFilter{
+		AppIDs:   splitQuery(query[APPID]),
+		Services: splitQuery(query[SERVICE]),
+		Statuses: splitQuery(query[STATUS]),
+	}
+
+	id, events := appsMonitor.SubscribeEvents(filter)
+	defer appsMonitor.UnsubscribeEvents(id)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(convertEvent(event))
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func convertEvent(event dockercontroller.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        event.Type,
+		"appid":       event.AppID,
+		"servicename": event.ServiceName,
+		"status":      event.Status,
+		"cid":         event.CID,
+		"timestamp":   event.Timestamp,
+	}
+}
+
+// Query values can be given repeatedly or as a comma separated list.
+func splitQuery(values []string) []string {
+	result := make([]string, 0)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if len(item) != 0 {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package apps
+
+import (
+	urls "commons/url"
+	"controller/dockercontroller"
+	"controller/monitoring/apps"
+	appsmocks "controller/monitoring/apps/mocks"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testAppId      = "testAppId"
+	testService    = "testService"
+	testSubscriber = 1
+)
+
+var (
+	invalidOperationList = map[string][]string{
+		"/api/v1/monitoring/apps/events/stream": []string{POST, PUT, DELETE},
+	}
+	streamUrl = urls.Base() + urls.Monitoring() + urls.Apps() + urls.Events() + urls.Stream()
+)
+
+var appsAPIExecutor Command
+
+func init() {
+	appsAPIExecutor = Executor{}
+}
+
+func TestAppsMonitoringAPIInvalidOperation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for api, invalidMethodList := range invalidOperationList {
+		for _, method := range invalidMethodList {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, api, nil)
+
+			appsAPIExecutor.Handle(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected error : %d, Actual Error : %d", http.StatusMethodNotAllowed, w.Code)
+			}
+		}
+	}
+}
+
+func TestAppsMonitoringAPIInvalidUrl(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/monitoring/apps/events/invalid", nil)
+
+	appsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestStreamAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appsMonitorMockObj := appsmocks.NewMockCommand(ctrl)
+
+	events := make(chan dockercontroller.Event, 1)
+	events <- dockercontroller.Event{AppID: testAppId, ServiceName: testService, Status: "die"}
+	close(events)
+
+	expectedFilter := apps.EventFilter{
+		AppIDs:   []string{testAppId},
+		Services: []string{testService, "other"},
+		Statuses: []string{},
+	}
+
+	gomock.InOrder(
+		appsMonitorMockObj.EXPECT().SubscribeEvents(expectedFilter).Return(testSubscriber, events),
+		appsMonitorMockObj.EXPECT().UnsubscribeEvents(testSubscriber),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, streamUrl+"?appid="+testAppId+"&service="+testService+",other", nil)
+
+	appsMonitor = appsMonitorMockObj
+
+	appsAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected return OK, Actual Return : %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Unexpected content type : %s", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "data: ") ||
+		!strings.Contains(w.Body.String(), "\"appid\":\""+testAppId+"\"") {
+		t.Errorf("Unexpected body : %s", w.Body.String())
+	}
+}
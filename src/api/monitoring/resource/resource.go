@@ -18,11 +18,10 @@ package resource
 
 import (
 	"api/common"
-	"commons/errors"
 	"commons/logger"
+	"commons/url"
 	"controller/monitoring/resource"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -30,6 +29,10 @@ const (
 	PUT    string = "PUT"
 	POST   string = "POST"
 	DELETE string = "DELETE"
+
+	APP_ID string = "appId"
+
+	OPENMETRICS_CONTENT_TYPE string = "application/openmetrics-text; version=1.0.0; charset=utf-8"
 )
 
 type Command interface {
@@ -39,6 +42,8 @@ type Command interface {
 type apiInnerCommand interface {
 	hostResource(w http.ResponseWriter, req *http.Request)
 	appResource(w http.ResponseWriter, req *http.Request, appId string)
+	metrics(w http.ResponseWriter, req *http.Request)
+	resourceHistory(w http.ResponseWriter, req *http.Request)
 }
 
 type Executor struct{}
@@ -46,10 +51,32 @@ type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
 var resourceExecutor resource.Command
+var router *common.Router
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	resourceExecutor = resource.Executor
+
+	monitoring := url.Base() + url.Monitoring()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: monitoring + url.Resource(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.hostResource(w, req)
+		}},
+		common.Route{Method: GET, Template: monitoring + url.Resource() + url.History(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.resourceHistory(w, req)
+		}},
+		common.Route{Method: GET, Template: monitoring + url.Apps() + "/{" + APP_ID + "}" + url.Resource(), Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.appResource(w, req, params.Get(APP_ID))
+		}},
+		common.Route{Method: GET, Template: url.Metrics(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.metrics(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of resource monitoring APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 // Handling requests which is getting device resource or app's resource information
@@ -57,15 +84,7 @@ func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl, split := req.URL.Path, strings.Split(req.URL.Path, "/"); {
-	case len(split) == 5: ///api/v1/monitoring/resource
-		apiInnerExecutor.hostResource(w, req)
-	case len(split) == 7: ///api/v1/monitoring/apps/{appid}/resource
-		apiInnerExecutor.appResource(w, req, split[len(split)-2])
-	default:
-		logger.Logging(logger.DEBUG, "Unmatched url")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-	}
+	router.Handle(w, req)
 }
 
 // Handling requests which is getting resources information
@@ -73,10 +92,6 @@ func (innerExecutorImpl) hostResource(w http.ResponseWriter, req *http.Request)
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, GET) {
-		return
-	}
-
 	response, e := resourceExecutor.GetHostResourceInfo()
 	if e != nil {
 		common.MakeErrorResponse(w, e)
@@ -90,14 +105,40 @@ func (innerExecutorImpl) appResource(w http.ResponseWriter, req *http.Request, a
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	if !common.CheckSupportedMethod(w, req.Method, GET) {
+	response, e := resourceExecutor.GetAppResourceInfo(appId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
 		return
 	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
 
-	response, e := resourceExecutor.GetAppResourceInfo(appId)
+// Handling requests which is getting aggregated history of resources.
+// range and resolution are given by 'from', 'to' and 'step' query.
+func (innerExecutorImpl) resourceHistory(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	query := req.URL.Query()
+	response, e := resourceExecutor.GetResourceHistory(query.Get("from"), query.Get("to"), query.Get("step"))
 	if e != nil {
 		common.MakeErrorResponse(w, e)
 		return
 	}
 	common.MakeResponse(w, common.ChangeToJson(response))
 }
+
+// Handling requests which is getting resources in OpenMetrics text format
+func (innerExecutorImpl) metrics(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := resourceExecutor.GetMetrics()
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	w.Header().Set("Content-Type", OPENMETRICS_CONTENT_TYPE)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(response))
+}
@@ -186,4 +186,104 @@ func TestAppResourceAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
 			t.Errorf("Unexpected error code : %d\n", w.Code)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestMetricsAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	metrics := "# TYPE pharos_node_memory_total_bytes gauge\npharos_node_memory_total_bytes 1024\n# EOF\n"
+	gomock.InOrder(
+		resourceExecutorMockObj.EXPECT().GetMetrics().Return(metrics, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Metrics(), nil)
+
+	resourceExecutor = resourceExecutorMockObj
+
+	resourceAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != OPENMETRICS_CONTENT_TYPE {
+		t.Errorf("Expected content type : %s, actual content type : %s", OPENMETRICS_CONTENT_TYPE, contentType)
+	}
+
+	if w.Body.String() != metrics {
+		t.Errorf("Expected body : %s, actual body : %s", metrics, w.Body.String())
+	}
+}
+
+func TestMetricsAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			resourceExecutorMockObj.EXPECT().GetMetrics().Return("", test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, urls.Metrics(), nil)
+
+		resourceExecutor = resourceExecutorMockObj
+
+		resourceAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Unexpected error code : %d\n", w.Code)
+		}
+	}
+}
+
+func TestResourceHistoryAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		resourceExecutorMockObj.EXPECT().GetResourceHistory("100", "200", "10").Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, urls.Base()+urls.Monitoring()+urls.Resource()+urls.History()+"?from=100&to=200&step=10", nil)
+
+	resourceExecutor = resourceExecutorMockObj
+
+	resourceAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestResourceHistoryAPIWhenControllerFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	for _, test := range testList {
+		gomock.InOrder(
+			resourceExecutorMockObj.EXPECT().GetResourceHistory("", "", "").Return(nil, test.err),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, urls.Base()+urls.Monitoring()+urls.Resource()+urls.History(), nil)
+
+		resourceExecutor = resourceExecutorMockObj
+
+		resourceAPIExecutor.Handle(w, req)
+
+		if w.Code != test.expectCode {
+			t.Errorf("Unexpected error code : %d\n", w.Code)
+		}
+	}
+}
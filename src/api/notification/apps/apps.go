@@ -23,7 +23,6 @@ import (
 	URL "commons/url"
 	"controller/notification/apps"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -46,38 +45,35 @@ type Executor struct{}
 type innerExecutorImpl struct{}
 
 var apiInnerExecutor apiInnerCommand
+var router *common.Router
 
 var appsExecutor apps.Command
 
 func init() {
 	apiInnerExecutor = innerExecutorImpl{}
 	appsExecutor = apps.Executor{}
+
+	watch := URL.Base() + URL.Notification() + URL.Apps() + URL.Watch()
+	router = common.NewRouter(
+		common.Route{Method: POST, Template: watch, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.subscribeEvent(w, req)
+		}},
+		common.Route{Method: DELETE, Template: watch, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.unsubscribeEvent(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of app notification APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	url := strings.Replace(req.URL.Path, URL.Base()+URL.Notification()+URL.Apps(), "", -1)
-	split := strings.Split(url, "/")
-
-	switch len(split) {
-	case 2:
-		if "/"+split[1] == URL.Watch() {
-			if req.Method == POST {
-				apiInnerExecutor.subscribeEvent(w, req)
-			} else if req.Method == DELETE {
-				apiInnerExecutor.unsubscribeEvent(w, req)
-			} else {
-				common.MakeErrorResponse(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.MakeErrorResponse(w, errors.NotFoundURL{url})
-		}
-	default:
-		logger.Logging(logger.DEBUG, "Unmatched url")
-		common.MakeErrorResponse(w, errors.NotFoundURL{url})
-	}
+	router.Handle(w, req)
 }
 
 func (innerExecutorImpl) subscribeEvent(w http.ResponseWriter, req *http.Request) {
@@ -20,11 +20,9 @@ package notification
 import (
 	"api/common"
 	"api/notification/apps"
-	"commons/errors"
+	"api/notification/outbox"
 	"commons/logger"
-	URL "commons/url"
 	"net/http"
-	"strings"
 )
 
 type Command interface {
@@ -34,26 +32,31 @@ type Command interface {
 type Executor struct{}
 
 var appsNotificationHandler apps.Command
+var outboxNotificationHandler outbox.Command
+
+var router *common.Router
 
 func init() {
 	appsNotificationHandler = apps.Executor{}
+	outboxNotificationHandler = outbox.Executor{}
+
+	router = common.NewRouter()
+	router.Add(common.Forward(apps.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		appsNotificationHandler.Handle(w, req)
+	})...)
+	router.Add(common.Forward(outbox.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		outboxNotificationHandler.Handle(w, req)
+	})...)
+}
+
+// Routes returns the route table of notification APIs.
+func Routes() []common.Route {
+	return router.Routes()
 }
 
 func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG, "receive msg", req.Method, req.URL.Path)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch url := req.URL.Path; {
-	default:
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.MakeErrorResponse(w, errors.NotFoundURL{url})
-
-	case !strings.Contains(url, URL.Base()):
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.MakeErrorResponse(w, errors.NotFoundURL{url})
-
-	case strings.Contains(url, URL.Apps()):
-		logger.Logging(logger.DEBUG, "Request Apps APIs")
-		appsNotificationHandler.Handle(w, req)
-	}
+	router.Handle(w, req)
 }
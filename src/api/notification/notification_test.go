@@ -19,6 +19,7 @@ package notification
 
 import (
 	appmocks "api/notification/apps/mocks"
+	outboxmocks "api/notification/outbox/mocks"
 	"github.com/golang/mock/gomock"
 	"net/http"
 	"net/http/httptest"
@@ -93,3 +94,22 @@ func TestCalledHandleWithUnsubscribeAppEventRequest_ExpectCalledAppHandle(t *tes
 
 	executor.Handle(w, req)
 }
+
+func TestCalledHandleWithOutboxStatusRequest_ExpectCalledOutboxHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outboxHandlerMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		outboxHandlerMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/notification/outbox", nil)
+
+	// pass mockObj to a real object.
+	outboxNotificationHandler = outboxHandlerMockObj
+
+	executor.Handle(w, req)
+}
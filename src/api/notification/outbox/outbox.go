@@ -0,0 +1,80 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package outbox
+
+import (
+	"api/common"
+	"commons/logger"
+	URL "commons/url"
+	"controller/outbox"
+	"net/http"
+)
+
+const (
+	GET string = "GET"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	getStatus(w http.ResponseWriter, req *http.Request)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var router *common.Router
+
+var outboxExecutor outbox.Command
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	outboxExecutor = outbox.Executor{}
+
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: URL.Base() + URL.Notification() + URL.Outbox(), Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.getStatus(w, req)
+		}},
+	)
+}
+
+// Routes returns the route table of outbox APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+func (innerExecutorImpl) getStatus(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := outboxExecutor.GetStatus()
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
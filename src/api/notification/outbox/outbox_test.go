@@ -0,0 +1,120 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package outbox
+
+import (
+	"commons/errors"
+	outboxmocks "controller/outbox/mocks"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var status = map[string]interface{}{
+	"pending": 0,
+}
+
+var Handler Command
+
+func init() {
+	Handler = Executor{}
+}
+
+func TestCalledHandleWithInvalidURL_UnExpectCalledAnyHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/notification/outbox/invalid", nil)
+
+	// pass mockObj to a real object.
+	outboxExecutor = outboxMockObj
+
+	Handler.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestCalledHandleWithInvalidMethod_UnExpectCalledAnyHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/v1/notification/outbox", nil)
+
+	// pass mockObj to a real object.
+	outboxExecutor = outboxMockObj
+
+	Handler.Handle(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestCalledHandleWithStatusRequest_ExpectCalledGetStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		outboxMockObj.EXPECT().GetStatus().Return(status, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/notification/outbox", nil)
+
+	// pass mockObj to a real object.
+	outboxExecutor = outboxMockObj
+
+	Handler.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestCalledHandleWhenGetStatusFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		outboxMockObj.EXPECT().GetStatus().Return(nil, errors.DBOperationError{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/notification/outbox", nil)
+
+	// pass mockObj to a real object.
+	outboxExecutor = outboxMockObj
+
+	Handler.Handle(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusInternalServerError, w.Code)
+	}
+}
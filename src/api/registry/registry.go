@@ -0,0 +1,169 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package registry
+
+import (
+	"api/common"
+	"commons/errors"
+	"commons/logger"
+	"commons/url"
+	"controller/registry"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	PUT    string = "PUT"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	REGISTRY_ID string = "registryId"
+)
+
+type Command interface {
+	Handle(w http.ResponseWriter, req *http.Request)
+}
+
+type apiInnerCommand interface {
+	addRegistry(w http.ResponseWriter, req *http.Request)
+	registries(w http.ResponseWriter, req *http.Request)
+	registry(w http.ResponseWriter, req *http.Request, registryId string)
+	updateRegistry(w http.ResponseWriter, req *http.Request, registryId string)
+	deleteRegistry(w http.ResponseWriter, req *http.Request, registryId string)
+}
+
+type Executor struct{}
+type innerExecutorImpl struct{}
+
+var apiInnerExecutor apiInnerCommand
+var registryExecutor registry.Command
+var router *common.Router
+
+func init() {
+	apiInnerExecutor = innerExecutorImpl{}
+	registryExecutor = registry.Executor{}
+
+	base := url.Base() + url.Management() + url.Registries()
+	router = common.NewRouter(
+		common.Route{Method: GET, Template: base, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.registries(w, req)
+		}},
+		common.Route{Method: POST, Template: base, Handler: func(w http.ResponseWriter, req *http.Request, _ common.Params) {
+			apiInnerExecutor.addRegistry(w, req)
+		}},
+		common.Route{Method: GET, Template: base + "/{" + REGISTRY_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.registry(w, req, params.Get(REGISTRY_ID))
+		}},
+		common.Route{Method: PUT, Template: base + "/{" + REGISTRY_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.updateRegistry(w, req, params.Get(REGISTRY_ID))
+		}},
+		common.Route{Method: DELETE, Template: base + "/{" + REGISTRY_ID + "}", Handler: func(w http.ResponseWriter, req *http.Request, params common.Params) {
+			apiInnerExecutor.deleteRegistry(w, req, params.Get(REGISTRY_ID))
+		}},
+	)
+}
+
+// Routes returns the route table of registry APIs.
+func Routes() []common.Route {
+	return router.Routes()
+}
+
+// Handling requests which is managing credentials of private registries.
+func (Executor) Handle(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	router.Handle(w, req)
+}
+
+// Handling requests which is adding credentials of a registry.
+func (innerExecutorImpl) addRegistry(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyStr, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "body is empty"})
+		return
+	}
+
+	response, e := registryExecutor.AddRegistry(bodyStr)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting all of registries.
+func (innerExecutorImpl) registries(w http.ResponseWriter, req *http.Request) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := registryExecutor.GetRegistries()
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is getting a registry.
+func (innerExecutorImpl) registry(w http.ResponseWriter, req *http.Request, registryId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	response, e := registryExecutor.GetRegistry(registryId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is updating credentials of a registry.
+func (innerExecutorImpl) updateRegistry(w http.ResponseWriter, req *http.Request, registryId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyStr, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeErrorResponse(w, errors.InvalidParam{Msg: "body is empty"})
+		return
+	}
+
+	response, e := registryExecutor.UpdateRegistry(registryId, bodyStr)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, common.ChangeToJson(response))
+}
+
+// Handling requests which is deleting a registry.
+func (innerExecutorImpl) deleteRegistry(w http.ResponseWriter, req *http.Request, registryId string) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	e := registryExecutor.DeleteRegistry(registryId)
+	if e != nil {
+		common.MakeErrorResponse(w, e)
+		return
+	}
+	common.MakeResponse(w, nil)
+}
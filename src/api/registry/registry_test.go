@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package registry
+
+import (
+	"bytes"
+	"commons/errors"
+	registrymocks "controller/registry/mocks"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testRegistryId = "test_registry_id"
+	testBodyString = `{"host":"test_url:5000","username":"test_user","password":"test_password"}`
+)
+
+var (
+	invalidOperationList = map[string][]string{
+		"/api/v1/management/registries":                  []string{PUT, DELETE},
+		"/api/v1/management/registries/test_registry_id": []string{POST},
+	}
+	testMap = map[string]interface{}{
+		"id": testRegistryId,
+	}
+)
+
+var registryAPIExecutor Command
+
+func init() {
+	registryAPIExecutor = Executor{}
+}
+
+func TestRegistryAPIInvalidOperation(t *testing.T) {
+	for api, invalidMethodList := range invalidOperationList {
+		for _, method := range invalidMethodList {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, api, nil)
+
+			registryAPIExecutor.Handle(w, req)
+
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Errorf("Expected error : %d, Actual Error : %d", http.StatusMethodNotAllowed, w.Code)
+			}
+		}
+	}
+}
+
+func TestAddRegistryAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().AddRegistry(testBodyString).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/management/registries", bytes.NewReader([]byte(testBodyString)))
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestAddRegistryAPIWithInvalidBody_ExpectBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().AddRegistry(testBodyString).Return(nil, errors.InvalidParam{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/management/registries", bytes.NewReader([]byte(testBodyString)))
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetRegistriesAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().GetRegistries().Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/registries", nil)
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestGetRegistryAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().GetRegistry(testRegistryId).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/registries/"+testRegistryId, nil)
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestUpdateRegistryAPI_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().UpdateRegistry(testRegistryId, testBodyString).Return(testMap, nil),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/management/registries/"+testRegistryId, bytes.NewReader([]byte(testBodyString)))
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected error code : %d", w.Code)
+	}
+}
+
+func TestDeleteRegistryAPIWhenRegistryNotExist_ExpectErrorResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		registryExecutorMockObj.EXPECT().DeleteRegistry(testRegistryId).Return(errors.NotFound{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/management/registries/"+testRegistryId, nil)
+
+	registryExecutor = registryExecutorMockObj
+
+	registryAPIExecutor.Handle(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected error : %d, Actual Error : %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
@@ -19,16 +19,23 @@
 package api
 
 import (
+	backupapi "api/backup"
 	"api/common"
 	configurationapi "api/configuration"
 	deploymentapi "api/deployment"
 	deviceapi "api/device"
 	healthapi "api/health"
+	jobapi "api/job"
+	alertsapi "api/monitoring/alerts"
+	appsmonitoringapi "api/monitoring/apps"
 	resourceapi "api/monitoring/resource"
 	notificationapi "api/notification"
+	registryapi "api/registry"
 	"commons/errors"
 	"commons/logger"
 	"commons/url"
+	"commons/util"
+	"controller/auth"
 	"net/http"
 	"strconv"
 	"strings"
@@ -38,16 +45,45 @@ import (
 func RunNodeWebServer(addr string, port int) {
 	logger.Logging(logger.DEBUG, "Start Pharos Node Web Server")
 	logger.Logging(logger.DEBUG, "Listening "+addr+":"+strconv.Itoa(port))
-	http.ListenAndServe(addr+":"+strconv.Itoa(port), &NodeAPIs)
+
+	tlsConfig, err := util.MakeServerTLSConfig()
+	if err != nil {
+		logger.Logging(logger.ERROR, "failed to load TLS configuration", err.Error())
+		return
+	}
+
+	server := &http.Server{
+		Addr:      addr + ":" + strconv.Itoa(port),
+		Handler:   &NodeAPIs,
+		TLSConfig: tlsConfig,
+	}
+
+	if tlsConfig == nil {
+		err = server.ListenAndServe()
+	} else {
+		logger.Logging(logger.DEBUG, "Serve over TLS")
+		// Certificates are already loaded in TLSConfig.
+		err = server.ListenAndServeTLS("", "")
+	}
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
 }
 
 var deploymentAPIExecutor deploymentapi.Command
 var healthAPIExecutor healthapi.Command
 var resourceAPIExecutor resourceapi.Command
+var appsMonitoringAPIExecutor appsmonitoringapi.Command
+var alertsAPIExecutor alertsapi.Command
 var configurationAPIExecutor configurationapi.Command
 var deviceAPIExecutor deviceapi.Command
 var notificationAPIExecutor notificationapi.Command
+var registryAPIExecutor registryapi.Command
+var backupAPIExecutor backupapi.Command
+var jobAPIExecutor jobapi.Command
+var authExecutor auth.Command
 var NodeAPIs Executor
+var router *common.Router
 
 type Executor struct{}
 
@@ -55,9 +91,52 @@ func init() {
 	deploymentAPIExecutor = deploymentapi.Executor{}
 	healthAPIExecutor = healthapi.Executor{}
 	resourceAPIExecutor = resourceapi.Executor{}
+	appsMonitoringAPIExecutor = appsmonitoringapi.Executor{}
+	alertsAPIExecutor = alertsapi.Executor{}
 	configurationAPIExecutor = configurationapi.Executor{}
 	deviceAPIExecutor = deviceapi.Executor{}
 	notificationAPIExecutor = notificationapi.Executor{}
+	registryAPIExecutor = registryapi.Executor{}
+	backupAPIExecutor = backupapi.Executor{}
+	jobAPIExecutor = jobapi.Executor{}
+	authExecutor = auth.Executor{}
+
+	// Each API package has its own route table,
+	// matched requests are passed to the package's handler.
+	router = common.NewRouter()
+	router.Add(common.Forward(healthapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		healthAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(deploymentapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		deploymentAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(appsmonitoringapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		appsMonitoringAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(resourceapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		resourceAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(alertsapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		alertsAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(configurationapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		configurationAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(deviceapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		deviceAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(notificationapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		notificationAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(registryapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		registryAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(backupapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		backupAPIExecutor.Handle(w, req)
+	})...)
+	router.Add(common.Forward(jobapi.Routes(), func(w http.ResponseWriter, req *http.Request) {
+		jobAPIExecutor.Handle(w, req)
+	})...)
 }
 
 // Implements of http serve interface.
@@ -66,34 +145,46 @@ func (Executor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger.Logging(logger.DEBUG, "receive msg", req.Method, req.URL.Path)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	switch reqUrl := req.URL.Path; {
-	default:
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-
-	case !(strings.Contains(reqUrl, (url.Base()+url.Management())) ||
-		strings.Contains(reqUrl, (url.Base()+url.Monitoring())) ||
-		strings.Contains(reqUrl, (url.Base()+url.Notification()))):
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.MakeErrorResponse(w, errors.NotFoundURL{reqUrl})
-
-	case strings.Contains(reqUrl, url.Unregister()):
-		healthAPIExecutor.Handle(w, req)
+	err := authExecutor.Authorize(req.Header.Get("Authorization"), requiredRole(req))
+	if err != nil {
+		if _, ok := err.(errors.Unauthorized); ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		common.MakeErrorResponse(w, err)
+		return
+	}
 
-	case strings.Contains(reqUrl, url.Management()) &&
-		strings.Contains(reqUrl, url.Apps()):
-		deploymentAPIExecutor.Handle(w, req)
+	router.Handle(w, req)
+}
 
-	case strings.Contains(reqUrl, url.Resource()):
-		resourceAPIExecutor.Handle(w, req)
+// requiredRole returns a role needed for the request.
+// Reading is allowed to every role except backups having secrets,
+// controlling the device, changing configuration which has apikeys,
+// execenabled and imagepolicy, executing commands in containers, changing
+// registry credentials, backing up or restoring the node or unregistering
+// the node needs admin role, and others need operator role.
+func requiredRole(req *http.Request) string {
+	management := url.Base() + url.Management()
+	switch req.URL.Path {
+	case management + url.Backup(), management + url.RestoreBackup():
+		return auth.ROLE_ADMIN
+	}
 
-	case strings.Contains(reqUrl, url.Configuration()):
-		configurationAPIExecutor.Handle(w, req)
+	if req.Method == common.POST && strings.HasSuffix(req.URL.Path, url.Configuration()) {
+		return auth.ROLE_ADMIN
+	}
 
-	case strings.Contains(reqUrl, url.Device()):
-		deviceAPIExecutor.Handle(w, req)
+	if req.Method == common.GET {
+		return auth.ROLE_MONITORING
+	}
 
-	case strings.Contains(reqUrl, url.Notification()):
-		notificationAPIExecutor.Handle(w, req)
+	switch {
+	case strings.HasPrefix(req.URL.Path, management+url.Device()),
+		strings.HasPrefix(req.URL.Path, management+url.Registries()),
+		req.URL.Path == management+url.Unregister(),
+		req.URL.Path == management+url.Nodes()+url.Unregister(),
+		strings.HasSuffix(req.URL.Path, url.Exec()):
+		return auth.ROLE_ADMIN
 	}
+	return auth.ROLE_OPERATOR
 }
@@ -24,12 +24,21 @@ import (
 	"strings"
 	"testing"
 
+	"commons/errors"
+	"controller/auth"
+	authmocks "controller/auth/mocks"
+
+	backupapi "api/backup/mocks"
 	configurationapi "api/configuration/mocks"
 	deploymentapi "api/deployment/mocks"
 	deviceapi "api/device/mocks"
 	healthapi "api/health/mocks"
+	jobapi "api/job/mocks"
+	alertsapi "api/monitoring/alerts/mocks"
+	appsmonitoringapi "api/monitoring/apps/mocks"
 	resourceapi "api/monitoring/resource/mocks"
 	notificationapi "api/notification/mocks"
+	registryapi "api/registry/mocks"
 )
 
 const (
@@ -45,6 +54,8 @@ func TestInvalidUrlList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	urlList := make(map[string][]string)
 	urlList["/test"] = []string{GET, PUT, POST, DELETE}
 	urlList["/api/v1/test"] = []string{GET, PUT, POST, DELETE}
@@ -71,10 +82,62 @@ func TestInvalidUrlList(t *testing.T) {
 	}
 }
 
+func TestUnmatchedUrlList_ExpectNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	deploymentAPIExecutorMockObj := deploymentapi.NewMockCommand(ctrl)
+
+	urlList := make(map[string]string)
+	urlList["/api/v1/management/apps/deploy-something/update/now"] = POST
+	urlList["/api/v1/management/apps/"+appId1+"/revisions/latest/redeploy"] = POST
+	urlList["/api/v1/management/apps/"+appId1+"/"] = GET
+	urlList["/api/v1/management/apps//update"] = POST
+
+	for key, method := range urlList {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, key, nil)
+
+		deploymentAPIExecutor = deploymentAPIExecutorMockObj
+		NodeAPIs.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected code : %d, actual code : %d, url : %s", http.StatusNotFound, w.Code, key)
+		}
+	}
+}
+
+func TestUnsupportedMethod_ExpectMethodNotAllowedWithAllowHeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	deploymentAPIExecutorMockObj := deploymentapi.NewMockCommand(ctrl)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/management/apps/"+appId1, nil)
+
+	deploymentAPIExecutor = deploymentAPIExecutorMockObj
+	NodeAPIs.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	if allow := w.Header().Get("Allow"); allow != "GET, POST, DELETE" {
+		t.Errorf("Expected Allow header : %s, actual : %s", "GET, POST, DELETE", allow)
+	}
+}
+
 func TestServeHTTPsendUnregisterAPI(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	healthAPIExecutorMockObj := healthapi.NewMockCommand(ctrl)
 
 	gomock.InOrder(
@@ -82,7 +145,7 @@ func TestServeHTTPsendUnregisterAPI(t *testing.T) {
 	)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/api/v1/management/unregister", nil)
+	req, _ := http.NewRequest(POST, "/api/v1/management/unregister", nil)
 
 	healthAPIExecutor = healthAPIExecutorMockObj
 	NodeAPIs.ServeHTTP(w, req)
@@ -92,15 +155,27 @@ func TestServeHTTPsendDeploymentAPI(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	deploymentAPIExecutorMockObj := deploymentapi.NewMockCommand(ctrl)
 
 	urlList := make(map[string][]string)
 	urlList["/api/v1/management/apps"] = []string{GET}
 	urlList["/api/v1/management/apps/deploy"] = []string{POST}
+	urlList["/api/v1/management/apps/validate"] = []string{POST}
 	urlList["/api/v1/management/apps/"+appId1] = []string{GET, POST, DELETE}
 	urlList["/api/v1/management/apps/"+appId1+"/update"] = []string{POST}
 	urlList["/api/v1/management/apps/"+appId1+"/stop"] = []string{POST}
 	urlList["/api/v1/management/apps/"+appId1+"/start"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/pause"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/unpause"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/logs"] = []string{GET}
+	urlList["/api/v1/management/apps/"+appId1+"/updatepolicy"] = []string{GET, POST}
+	urlList["/api/v1/management/apps/"+appId1+"/services/web/start"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/services/web/stop"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/services/web/restart"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/services/web/scale"] = []string{POST}
+	urlList["/api/v1/management/apps/"+appId1+"/services/web/exec"] = []string{POST}
 
 	for key, vals := range urlList {
 		for _, method := range vals {
@@ -117,15 +192,38 @@ func TestServeHTTPsendDeploymentAPI(t *testing.T) {
 	}
 }
 
+func TestServeHTTPsendAppsMonitoringAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	appsMonitoringAPIExecutorMockObj := appsmonitoringapi.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		appsMonitoringAPIExecutorMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/monitoring/apps/events/stream", nil)
+
+	appsMonitoringAPIExecutor = appsMonitoringAPIExecutorMockObj
+	NodeAPIs.ServeHTTP(w, req)
+}
+
 func TestServeHTTPsendResourceAPI(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	resourceAPIExecutorMockObj := resourceapi.NewMockCommand(ctrl)
 
 	urlList := make(map[string][]string)
 	urlList["/api/v1/monitoring/resource"] = []string{GET}
 	urlList["/api/v1/monitoring/apps/"+appId1+"/resource"] = []string{GET}
+	urlList["/api/v1/monitoring/resource/history"] = []string{GET}
+	urlList["/metrics"] = []string{GET}
 
 	for key, vals := range urlList {
 		for _, method := range vals {
@@ -142,10 +240,117 @@ func TestServeHTTPsendResourceAPI(t *testing.T) {
 	}
 }
 
+func TestServeHTTPsendAlertsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	alertsAPIExecutorMockObj := alertsapi.NewMockCommand(ctrl)
+
+	urlList := make(map[string][]string)
+	urlList["/api/v1/monitoring/alerts"] = []string{GET, POST}
+	urlList["/api/v1/monitoring/alerts/"+appId1] = []string{GET, DELETE}
+
+	for key, vals := range urlList {
+		for _, method := range vals {
+			gomock.InOrder(
+				alertsAPIExecutorMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, key, nil)
+
+			alertsAPIExecutor = alertsAPIExecutorMockObj
+			NodeAPIs.ServeHTTP(w, req)
+		}
+	}
+}
+
+func TestServeHTTPsendRegistryAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	registryAPIExecutorMockObj := registryapi.NewMockCommand(ctrl)
+
+	urlList := make(map[string][]string)
+	urlList["/api/v1/management/registries"] = []string{GET, POST}
+	urlList["/api/v1/management/registries/"+appId1] = []string{GET, PUT, DELETE}
+
+	for key, vals := range urlList {
+		for _, method := range vals {
+			gomock.InOrder(
+				registryAPIExecutorMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, key, nil)
+
+			registryAPIExecutor = registryAPIExecutorMockObj
+			NodeAPIs.ServeHTTP(w, req)
+		}
+	}
+}
+
+func TestServeHTTPsendBackupAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	backupAPIExecutorMockObj := backupapi.NewMockCommand(ctrl)
+
+	urlList := make(map[string]string)
+	urlList["/api/v1/management/backup"] = GET
+	urlList["/api/v1/management/restore-backup"] = POST
+
+	for key, method := range urlList {
+		gomock.InOrder(
+			backupAPIExecutorMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+		)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, key, nil)
+
+		backupAPIExecutor = backupAPIExecutorMockObj
+		NodeAPIs.ServeHTTP(w, req)
+	}
+}
+
+func TestServeHTTPsendJobAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	allowAllRequests(ctrl)
+
+	jobAPIExecutorMockObj := jobapi.NewMockCommand(ctrl)
+
+	urlList := make(map[string][]string)
+	urlList["/api/v1/management/jobs/"+appId1] = []string{GET, DELETE}
+
+	for key, methods := range urlList {
+		for _, method := range methods {
+			gomock.InOrder(
+				jobAPIExecutorMockObj.EXPECT().Handle(gomock.Any(), gomock.Any()),
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, key, nil)
+
+			jobAPIExecutor = jobAPIExecutorMockObj
+			NodeAPIs.ServeHTTP(w, req)
+		}
+	}
+}
+
 func TestServeHTTPsendDeviceAPI(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	deviceAPIExecutorMockObj := deviceapi.NewMockCommand(ctrl)
 
 	urlList := make(map[string][]string)
@@ -171,6 +376,8 @@ func TestServeHTTPsendConfigurationApi(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	configApiExecutorMockObj := configurationapi.NewMockCommand(ctrl)
 
 	urlList := make(map[string][]string)
@@ -195,6 +402,8 @@ func TestServeHTTPsendNotificationApi(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	allowAllRequests(ctrl)
+
 	notiApiExecutorMockObj := notificationapi.NewMockCommand(ctrl)
 
 	urlList := make(map[string][]string)
@@ -214,3 +423,95 @@ func TestServeHTTPsendNotificationApi(t *testing.T) {
 		}
 	}
 }
+
+func TestServeHTTPWithoutValidToken_ExpectUnauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authExecutorMockObj := authmocks.NewMockCommand(ctrl)
+	deploymentAPIExecutorMockObj := deploymentapi.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		authExecutorMockObj.EXPECT().Authorize("", auth.ROLE_MONITORING).Return(errors.Unauthorized{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/management/apps", nil)
+
+	authExecutor = authExecutorMockObj
+	deploymentAPIExecutor = deploymentAPIExecutorMockObj
+	NodeAPIs.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusUnauthorized, w.Code)
+	}
+
+	if header := w.Header().Get("WWW-Authenticate"); header != "Bearer" {
+		t.Errorf("Expected WWW-Authenticate header : %s, actual : %s", "Bearer", header)
+	}
+}
+
+func TestServeHTTPWithLowerRole_ExpectForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authExecutorMockObj := authmocks.NewMockCommand(ctrl)
+	deviceAPIExecutorMockObj := deviceapi.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		authExecutorMockObj.EXPECT().Authorize("Bearer token", auth.ROLE_ADMIN).Return(errors.Forbidden{}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/management/device/reboot", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	authExecutor = authExecutorMockObj
+	deviceAPIExecutor = deviceAPIExecutorMockObj
+	NodeAPIs.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected code : %d, actual code : %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestRequiredRole_ExpectRoleByRequest(t *testing.T) {
+	testList := []struct {
+		method string
+		path   string
+		role   string
+	}{
+		{GET, "/api/v1/management/apps", auth.ROLE_MONITORING},
+		{GET, "/api/v1/management/device/configuration", auth.ROLE_MONITORING},
+		{POST, "/api/v1/management/apps/deploy", auth.ROLE_OPERATOR},
+		{DELETE, "/api/v1/management/apps/" + appId1, auth.ROLE_OPERATOR},
+		{POST, "/api/v1/management/device/reboot", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/device/restore", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/device/configuration", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/device/configuration/", auth.ROLE_ADMIN},
+		{GET, "/api/v1/management/device/configuration", auth.ROLE_MONITORING},
+		{POST, "/api/v1/management/unregister", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/nodes/unregister", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/apps/11/services/web/exec", auth.ROLE_ADMIN},
+		{GET, "/api/v1/management/registries", auth.ROLE_MONITORING},
+		{POST, "/api/v1/management/registries", auth.ROLE_ADMIN},
+		{DELETE, "/api/v1/management/registries/" + appId1, auth.ROLE_ADMIN},
+		{GET, "/api/v1/management/backup", auth.ROLE_ADMIN},
+		{POST, "/api/v1/management/restore-backup", auth.ROLE_ADMIN},
+		{GET, "/api/v1/management/jobs/" + appId1, auth.ROLE_MONITORING},
+		{DELETE, "/api/v1/management/jobs/" + appId1, auth.ROLE_OPERATOR},
+	}
+
+	for _, test := range testList {
+		req, _ := http.NewRequest(test.method, test.path, nil)
+		if role := requiredRole(req); role != test.role {
+			t.Errorf("Expected role : %s, actual role : %s, request : %s %s", test.role, role, test.method, test.path)
+		}
+	}
+}
+
+func allowAllRequests(ctrl *gomock.Controller) {
+	authExecutorMockObj := authmocks.NewMockCommand(ctrl)
+	authExecutorMockObj.EXPECT().Authorize(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	authExecutor = authExecutorMockObj
+}
@@ -18,6 +18,8 @@
 // Package commons/errors defines error cases of Pharos Node.
 package errors
 
+import "net/http"
+
 // Struct InvalidParam will be used for return case of error
 // which value of unknown or invalid type, range in the parameters.
 type InvalidParam struct {
@@ -320,3 +322,105 @@ func (e DBOperationError) Error() string {
 func (e *DBOperationError) SetMsg(msg string) {
 	e.Msg = msg
 }
+
+// Struct RolledBack will be used for return case of error
+// when updated app is rolled back to previous images.
+type RolledBack struct {
+	Msg string
+}
+
+// Error sets an error message of RolledBack.
+func (e RolledBack) Error() string {
+	return "rolled back : " + e.Msg
+}
+
+// Set error message of RolledBack.
+func (e *RolledBack) SetMsg(msg string) {
+	e.Msg = msg
+}
+
+// Struct Unauthorized will be used for return case of error
+// when a request has no valid credentials.
+type Unauthorized struct {
+	Msg string
+}
+
+// Error sets an error message of Unauthorized.
+func (e Unauthorized) Error() string {
+	return "unauthorized : " + e.Msg
+}
+
+// Set error message of Unauthorized.
+func (e *Unauthorized) SetMsg(msg string) {
+	e.Msg = msg
+}
+
+// Struct Forbidden will be used for return case of error
+// when credentials of a request have no permission for the request.
+type Forbidden struct {
+	Msg string
+}
+
+// Error sets an error message of Forbidden.
+func (e Forbidden) Error() string {
+	return "forbidden : " + e.Msg
+}
+
+// Set error message of Forbidden.
+func (e *Forbidden) SetMsg(msg string) {
+	e.Msg = msg
+}
+
+// Struct InsufficientResource will be used for return case of error
+// when the device doesn't have enough resources for a request.
+type InsufficientResource struct {
+	Msg string
+}
+
+// Error sets an error message of InsufficientResource.
+func (e InsufficientResource) Error() string {
+	return "insufficient resource : " + e.Msg
+}
+
+// Set error message of InsufficientResource.
+func (e *InsufficientResource) SetMsg(msg string) {
+	e.Msg = msg
+}
+
+// StatusCode returns HTTP status code of the response for err,
+// which is also kept in jobs failed by err.
+func StatusCode(err error) int {
+	switch err.(type) {
+
+	case NotFoundURL:
+		return http.StatusNotFound
+
+	case InvalidMethod:
+		return http.StatusMethodNotAllowed
+
+	case Unauthorized:
+		return http.StatusUnauthorized
+
+	case Forbidden:
+		return http.StatusForbidden
+
+	case InvalidYaml, InvalidAppId,
+		InvalidParam, NotFoundImage,
+		AlreadyAllocatedPort, AlreadyUsedName,
+		InvalidContainerName:
+		return http.StatusBadRequest
+
+	case IOError:
+		return http.StatusInternalServerError
+
+	case ConnectionError, NotFound:
+		return http.StatusServiceUnavailable
+
+	case AlreadyReported:
+		return http.StatusAlreadyReported
+
+	case InsufficientResource:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -74,6 +74,14 @@ func TestTError(t *testing.T) {
 			testError: &DBConnectionError{}},
 		{testName: "DBOperationError", testPrefix: "db operation failed",
 			testError: &DBOperationError{}},
+		{testName: "RolledBack", testPrefix: "rolled back",
+			testError: &RolledBack{}},
+		{testName: "Unauthorized", testPrefix: "unauthorized",
+			testError: &Unauthorized{}},
+		{testName: "Forbidden", testPrefix: "forbidden",
+			testError: &Forbidden{}},
+		{testName: "InsufficientResource", testPrefix: "insufficient resource",
+			testError: &InsufficientResource{}},
 	}
 
 	testFunc := func(err commonsError, prefix string) {
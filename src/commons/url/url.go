@@ -89,3 +89,69 @@ func Watch() string { return "/watch" }
 
 //Returning Disk url as string.
 func Disk() string { return "/disk" }
+
+// Returning Revisions url as string.
+func Revisions() string { return "/revisions" }
+
+// Returning Diff url as string.
+func Diff() string { return "/diff" }
+
+// Returning Redeploy url as string.
+func Redeploy() string { return "/redeploy" }
+
+// Returning Stream url as string.
+func Stream() string { return "/stream" }
+
+// Returning Outbox url as string.
+func Outbox() string { return "/outbox" }
+
+// Returning Metrics url as string.
+func Metrics() string { return "/metrics" }
+
+// Returning History url as string.
+func History() string { return "/history" }
+
+// Returning Alerts url as string.
+func Alerts() string { return "/alerts" }
+
+// Returning Logs url as string.
+func Logs() string { return "/logs" }
+
+// Returning Services url as string.
+func Services() string { return "/services" }
+
+// Returning Exec url as string.
+func Exec() string { return "/exec" }
+
+// Returning Restart url as string.
+func Restart() string { return "/restart" }
+
+// Returning Scale url as string.
+func Scale() string { return "/scale" }
+
+// Returning Pause url as string.
+func Pause() string { return "/pause" }
+
+// Returning Unpause url as string.
+func Unpause() string { return "/unpause" }
+
+// Returning Validate url as string.
+func Validate() string { return "/validate" }
+
+// Returning Registries url as string.
+func Registries() string { return "/registries" }
+
+// Returning GC url as string.
+func GC() string { return "/gc" }
+
+// Returning UpdatePolicy url as string.
+func UpdatePolicy() string { return "/updatepolicy" }
+
+// Returning Backup url as string.
+func Backup() string { return "/backup" }
+
+// Returning RestoreBackup url as string.
+func RestoreBackup() string { return "/restore-backup" }
+
+// Returning Jobs url as string.
+func Jobs() string { return "/jobs" }
@@ -114,3 +114,108 @@ func ExampleWatch() {
 	fmt.Println(Watch())
 	// Output: /watch
 }
+func ExampleRevisions() {
+	fmt.Println(Revisions())
+	// Output: /revisions
+}
+func ExampleDiff() {
+	fmt.Println(Diff())
+	// Output: /diff
+}
+func ExampleRedeploy() {
+	fmt.Println(Redeploy())
+	// Output: /redeploy
+}
+func ExampleStream() {
+	fmt.Println(Stream())
+	// Output: /stream
+}
+func ExampleOutbox() {
+	fmt.Println(Outbox())
+	// Output: /outbox
+}
+
+func ExampleMetrics() {
+	fmt.Println(Metrics())
+	// Output: /metrics
+}
+
+func ExampleHistory() {
+	fmt.Println(History())
+	// Output: /history
+}
+
+func ExampleAlerts() {
+	fmt.Println(Alerts())
+	// Output: /alerts
+}
+
+func ExampleLogs() {
+	fmt.Println(Logs())
+	// Output: /logs
+}
+
+func ExampleServices() {
+	fmt.Println(Services())
+	// Output: /services
+}
+
+func ExampleExec() {
+	fmt.Println(Exec())
+	// Output: /exec
+}
+
+func ExampleRestart() {
+	fmt.Println(Restart())
+	// Output: /restart
+}
+
+func ExampleScale() {
+	fmt.Println(Scale())
+	// Output: /scale
+}
+
+func ExamplePause() {
+	fmt.Println(Pause())
+	// Output: /pause
+}
+
+func ExampleUnpause() {
+	fmt.Println(Unpause())
+	// Output: /unpause
+}
+
+func ExampleValidate() {
+	fmt.Println(Validate())
+	// Output: /validate
+}
+
+func ExampleRegistries() {
+	fmt.Println(Registries())
+	// Output: /registries
+}
+
+func ExampleGC() {
+	fmt.Println(GC())
+	// Output: /gc
+}
+
+func ExampleUpdatePolicy() {
+	fmt.Println(UpdatePolicy())
+	// Output: /updatepolicy
+}
+
+func ExampleBackup() {
+	fmt.Println(Backup())
+	// Output: /backup
+}
+
+func ExampleRestoreBackup() {
+	fmt.Println(RestoreBackup())
+	// Output: /restore-backup
+}
+
+func ExampleJobs() {
+	fmt.Println(Jobs())
+	// Output: /jobs
+}
@@ -23,7 +23,10 @@ import (
 	"commons/errors"
 	"commons/logger"
 	"commons/url"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"io/ioutil"
 	"net"
 	"os"
 	"strings"
@@ -32,6 +35,27 @@ import (
 const (
 	DEFAULT_ANCHOR_PORT                      = "48099"
 	UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY = "80"
+	SECURED_ANCHOR_PORT_WITH_REVERSE_PROXY   = "443"
+)
+
+const (
+	HTTP_SCHEME  = "http://"
+	HTTPS_SCHEME = "https://"
+
+	// Environments which have paths of PEM encoded certificates and keys.
+	TLS_CERT_FILE      = "TLS_CERT_FILE"
+	TLS_KEY_FILE       = "TLS_KEY_FILE"
+	TLS_CLIENT_CA_FILE = "TLS_CLIENT_CA_FILE"
+	ANCHOR_CA_FILE     = "ANCHOR_CA_FILE"
+
+	// Environment which has path of PEM encoded public key of Pharos Anchor,
+	// used to verify tokens issued by Pharos Anchor.
+	ANCHOR_JWT_KEY_FILE = "ANCHOR_JWT_KEY_FILE"
+
+	// Environments which have issuer and audience claims
+	// that tokens issued by Pharos Anchor must have.
+	ANCHOR_JWT_ISSUER   = "ANCHOR_JWT_ISSUER"
+	ANCHOR_JWT_AUDIENCE = "ANCHOR_JWT_AUDIENCE"
 )
 
 // convertJsonToMap converts JSON data into a map.
@@ -88,11 +112,16 @@ func MakeAnchorRequestUrl(api_parts ...string) (string, error) {
 		return "", errors.InvalidParam{"Anchor address's validation check failed"}
 	}
 
+	scheme, proxyPort := HTTP_SCHEME, UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY
+	if IsAnchorTLSEnabled() {
+		scheme, proxyPort = HTTPS_SCHEME, SECURED_ANCHOR_PORT_WITH_REVERSE_PROXY
+	}
+
 	anchorProxy := os.Getenv("ANCHOR_REVERSE_PROXY")
 	if len(anchorProxy) == 0 || anchorProxy == "false" {
-		full_url.WriteString("http://" + anchorIP + ":" + DEFAULT_ANCHOR_PORT + url.Base())
+		full_url.WriteString(scheme + anchorIP + ":" + DEFAULT_ANCHOR_PORT + url.Base())
 	} else if anchorProxy == "true" {
-		full_url.WriteString("http://" + anchorIP + ":" + UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY + url.PharosAnchor() + url.Base())
+		full_url.WriteString(scheme + anchorIP + ":" + proxyPort + url.PharosAnchor() + url.Base())
 	} else {
 		logger.Logging(logger.ERROR, "Invalid value for ANCHOR_REVERSE_PROXY")
 		return "", errors.InvalidParam{"Invalid value for ANCHOR_REVERSE_PROXY"}
@@ -118,3 +147,86 @@ func MakeSCRequestUrl(scIP string, api_parts ...string) string {
 	logger.Logging(logger.DEBUG, full_url.String())
 	return full_url.String()
 }
+
+// IsAnchorTLSEnabled returns true if a CA certificate of Pharos Anchor is given
+// by ANCHOR_CA_FILE environment, which means Pharos Anchor is served over HTTPS.
+func IsAnchorTLSEnabled() bool {
+	return len(os.Getenv(ANCHOR_CA_FILE)) != 0
+}
+
+// MakeServerTLSConfig makes TLS configuration of Pharos Node's web server
+// with TLS_CERT_FILE, TLS_KEY_FILE and TLS_CLIENT_CA_FILE environments.
+// if certificate or key is not given, returns nil which means serving plain HTTP.
+// if client CA is given, clients should present a certificate signed by the CA.
+func MakeServerTLSConfig() (*tls.Config, error) {
+	certFile, keyFile := os.Getenv(TLS_CERT_FILE), os.Getenv(TLS_KEY_FILE)
+	if len(certFile) == 0 || len(keyFile) == 0 {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.InvalidParam{Msg: "failed to load certificate : " + err.Error()}
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile := os.Getenv(TLS_CLIENT_CA_FILE); len(caFile) != 0 {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// MakeAnchorTLSConfig makes TLS configuration used to send requests to Pharos Anchor.
+// only the CA given by ANCHOR_CA_FILE environment is trusted, and certificate of
+// Pharos Node is presented as client certificate if TLS_CERT_FILE and TLS_KEY_FILE are given.
+// if CA of Pharos Anchor is not given, returns nil which means default configuration.
+func MakeAnchorTLSConfig() (*tls.Config, error) {
+	if !IsAnchorTLSEnabled() {
+		return nil, nil
+	}
+
+	pool, err := loadCertPool(os.Getenv(ANCHOR_CA_FILE))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	certFile, keyFile := os.Getenv(TLS_CERT_FILE), os.Getenv(TLS_KEY_FILE)
+	if len(certFile) != 0 && len(keyFile) != 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return nil, errors.InvalidParam{Msg: "failed to load certificate : " + err.Error()}
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.IOError{Msg: "failed to read CA certificate : " + err.Error()}
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.InvalidParam{Msg: "no valid CA certificate in " + caFile}
+	}
+	return pool, nil
+}
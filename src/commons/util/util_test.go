@@ -18,10 +18,21 @@ package util
 
 import (
 	"commons/errors"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"github.com/golang/mock/gomock"
+	"io/ioutil"
+	"math/big"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var (
@@ -210,3 +221,153 @@ func TestMakeAnchorRequestUrlWithNoAnchorRPEnv_ExpectSuccess(t *testing.T) {
 		t.Errorf("Expected return : %s, actual return : %s", expectedRet, ret)
 	}
 }
+
+func TestMakeAnchorRequestUrlWithAnchorCAEnv_ExpectHttpsUrl(t *testing.T) {
+	testUrlPart := "/testurl"
+	expectedUrls := map[string]string{
+		"false": "https://" + ip + ":" + DEFAULT_ANCHOR_PORT + "/api/v1/management" + testUrlPart,
+		"true":  "https://" + ip + ":" + SECURED_ANCHOR_PORT_WITH_REVERSE_PROXY + "/pharos-anchor/api/v1/management" + testUrlPart,
+	}
+
+	for proxy, expectedUrl := range expectedUrls {
+		os.Setenv(anchorAddressEnv, ip)
+		os.Setenv(anchorReverseProxyEnv, proxy)
+		os.Setenv(ANCHOR_CA_FILE, "/test/ca.pem")
+		ret, err := MakeAnchorRequestUrl("/management", testUrlPart)
+		os.Unsetenv(anchorAddressEnv)
+		os.Unsetenv(anchorReverseProxyEnv)
+		os.Unsetenv(ANCHOR_CA_FILE)
+
+		if err != nil {
+			t.Errorf("Expected error : nil, actual error : %s", err.Error())
+		}
+
+		if ret != expectedUrl {
+			t.Errorf("Expected result : %s, actual result : %s", expectedUrl, ret)
+		}
+	}
+}
+
+func TestMakeServerTLSConfigWithoutCertEnv_ExpectNilConfig(t *testing.T) {
+	config, err := MakeServerTLSConfig()
+
+	if err != nil {
+		t.Errorf("Expected error : nil, actual error : %s", err.Error())
+	}
+
+	if config != nil {
+		t.Errorf("Expected config : nil, actual config : %v", config)
+	}
+}
+
+func TestMakeServerTLSConfigWithClientCAEnv_ExpectClientCertRequired(t *testing.T) {
+	dir, certFile, keyFile := writeTestCertificate(t)
+	defer os.RemoveAll(dir)
+
+	os.Setenv(TLS_CERT_FILE, certFile)
+	os.Setenv(TLS_KEY_FILE, keyFile)
+	os.Setenv(TLS_CLIENT_CA_FILE, certFile)
+	config, err := MakeServerTLSConfig()
+	os.Unsetenv(TLS_CERT_FILE)
+	os.Unsetenv(TLS_KEY_FILE)
+	os.Unsetenv(TLS_CLIENT_CA_FILE)
+
+	if err != nil {
+		t.Fatalf("Expected error : nil, actual error : %s", err.Error())
+	}
+
+	if len(config.Certificates) != 1 || config.ClientCAs == nil {
+		t.Errorf("Unexpected config : %v", config)
+	}
+
+	if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("Expected client auth : %v, actual client auth : %v", tls.RequireAndVerifyClientCert, config.ClientAuth)
+	}
+}
+
+func TestMakeServerTLSConfigWithInvalidCertEnv_ExpectReturnError(t *testing.T) {
+	os.Setenv(TLS_CERT_FILE, "/not/exist/cert.pem")
+	os.Setenv(TLS_KEY_FILE, "/not/exist/key.pem")
+	_, err := MakeServerTLSConfig()
+	os.Unsetenv(TLS_CERT_FILE)
+	os.Unsetenv(TLS_KEY_FILE)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected error : %s, actual error : %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestMakeAnchorTLSConfigWithAnchorCAEnv_ExpectPinnedCA(t *testing.T) {
+	dir, certFile, keyFile := writeTestCertificate(t)
+	defer os.RemoveAll(dir)
+
+	os.Setenv(ANCHOR_CA_FILE, certFile)
+	os.Setenv(TLS_CERT_FILE, certFile)
+	os.Setenv(TLS_KEY_FILE, keyFile)
+	config, err := MakeAnchorTLSConfig()
+	os.Unsetenv(ANCHOR_CA_FILE)
+	os.Unsetenv(TLS_CERT_FILE)
+	os.Unsetenv(TLS_KEY_FILE)
+
+	if err != nil {
+		t.Fatalf("Expected error : nil, actual error : %s", err.Error())
+	}
+
+	if config.RootCAs == nil || len(config.Certificates) != 1 {
+		t.Errorf("Unexpected config : %v", config)
+	}
+}
+
+func TestMakeAnchorTLSConfigWithInvalidAnchorCAEnv_ExpectReturnError(t *testing.T) {
+	dir, _, keyFile := writeTestCertificate(t)
+	defer os.RemoveAll(dir)
+
+	os.Setenv(ANCHOR_CA_FILE, keyFile)
+	_, err := MakeAnchorTLSConfig()
+	os.Unsetenv(ANCHOR_CA_FILE)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected error : %s, actual error : %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate and its key
+// to a temporary directory.
+func writeTestCertificate(t *testing.T) (string, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pharos-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "pharos-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return dir, certFile, keyFile
+}
@@ -0,0 +1,166 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package auth provides authentication of requests to pharos-node APIs
+// and authorization based on roles.
+//
+// A request carries a bearer token, which is either a static key stored
+// in the configuration (apikeys property) or a JWT signed by pharos-anchor
+// whose public key is given by ANCHOR_JWT_KEY_FILE environment.
+// When neither of them is configured, all requests are allowed.
+// A JWT must have expiration time, and issuer and audience given by
+// ANCHOR_JWT_ISSUER and ANCHOR_JWT_AUDIENCE environments or their defaults.
+package auth
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"commons/util"
+	"crypto/subtle"
+	configDB "db/bolt/configuration"
+	"os"
+	"strings"
+)
+
+const (
+	ROLE_MONITORING = "monitoring"
+	ROLE_OPERATOR   = "operator"
+	ROLE_ADMIN      = "admin"
+	API_KEYS        = "apikeys"
+	KEY             = "key"
+	ROLE            = "role"
+	VALUE           = "value"
+	BEARER          = "Bearer "
+)
+
+// Roles are ordered, a role has every permission of lower roles.
+var roleLevels = map[string]int{
+	ROLE_MONITORING: 1,
+	ROLE_OPERATOR:   2,
+	ROLE_ADMIN:      3,
+}
+
+// Interface of auth operations.
+type Command interface {
+	// Authorize checks whether a value of Authorization header
+	// has a role equal to or higher than the required role.
+	Authorize(authorization string, required string) error
+}
+
+type Executor struct{}
+
+type apiKey struct {
+	key  string
+	role string
+}
+
+var configDbExecutor configDB.Command
+
+func init() {
+	configDbExecutor = configDB.Executor{}
+}
+
+// IsRole returns whether the name is one of roles.
+func IsRole(name string) bool {
+	return roleLevels[name] != 0
+}
+
+// Authorize returns nil when a request is allowed,
+// errors.Unauthorized when a token is missing or invalid,
+// errors.Forbidden when a role of the token is lower than the required role.
+func (Executor) Authorize(authorization string, required string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	keys, err := getAPIKeys()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	jwtKeyFile := os.Getenv(util.ANCHOR_JWT_KEY_FILE)
+	if len(keys) == 0 && len(jwtKeyFile) == 0 {
+		return nil
+	}
+
+	if !strings.HasPrefix(authorization, BEARER) {
+		return errors.Unauthorized{Msg: "bearer token is required"}
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, BEARER))
+	if len(token) == 0 {
+		return errors.Unauthorized{Msg: "bearer token is required"}
+	}
+
+	role, err := authenticate(token, keys, jwtKeyFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	if roleLevels[role] < roleLevels[required] {
+		return errors.Forbidden{Msg: "'" + required + "' role is required"}
+	}
+	return nil
+}
+
+// authenticate returns a role of the token.
+func authenticate(token string, keys []apiKey, jwtKeyFile string) (string, error) {
+	for _, key := range keys {
+		if len(key.key) == 0 {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(key.key), []byte(token)) == 1 {
+			return key.role, nil
+		}
+	}
+
+	if len(jwtKeyFile) != 0 && strings.Count(token, ".") == 2 {
+		return verifyJWT(token, jwtKeyFile)
+	}
+	return "", errors.Unauthorized{Msg: "invalid token"}
+}
+
+// getAPIKeys returns static keys stored in the configuration.
+// entries having an unknown role are kept, so that they are always forbidden
+// rather than disabling authentication.
+func getAPIKeys() ([]apiKey, error) {
+	prop, err := configDbExecutor.GetProperty(API_KEYS)
+	if err != nil {
+		switch err.(type) {
+		case errors.NotFound:
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	values, ok := prop[VALUE].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	keys := make([]apiKey, 0)
+	for _, value := range values {
+		entry, _ := value.(map[string]interface{})
+		key, _ := entry[KEY].(string)
+		role, _ := entry[ROLE].(string)
+		if !IsRole(role) {
+			logger.Logging(logger.ERROR, "unknown role of api key : "+role)
+		}
+		keys = append(keys, apiKey{key: key, role: role})
+	}
+	return keys, nil
+}
@@ -0,0 +1,339 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package auth
+
+import (
+	"commons/errors"
+	"commons/util"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	configmocks "db/bolt/configuration/mocks"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	gomock "github.com/golang/mock/gomock"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+const (
+	ADMIN_KEY      = "admin-key"
+	MONITORING_KEY = "monitoring-key"
+	UNKNOWN_KEY    = "unknown-key"
+	NOW            = 1500000000
+)
+
+var apiKeysProp = map[string]interface{}{
+	"name": API_KEYS,
+	"value": []interface{}{
+		map[string]interface{}{KEY: ADMIN_KEY, ROLE: ROLE_ADMIN},
+		map[string]interface{}{KEY: MONITORING_KEY, ROLE: ROLE_MONITORING},
+	},
+	"readOnly": false,
+}
+
+var notFoundError = errors.NotFound{}
+var dbError = errors.DBOperationError{}
+
+func init() {
+	now = func() time.Time { return time.Unix(NOW, 0) }
+}
+
+// validClaims returns claims of a valid token with the role,
+// which are overwritten by the given claims.
+func validClaims(role string, claims map[string]interface{}) map[string]interface{} {
+	valid := map[string]interface{}{
+		ROLE:  role,
+		"exp": NOW + 60,
+		"iss": DEFAULT_JWT_ISSUER,
+		"aud": DEFAULT_JWT_AUDIENCE,
+	}
+	for name, value := range claims {
+		valid[name] = value
+	}
+	return valid
+}
+
+func TestAuthorizeWithoutKeys_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(nil, notFoundError),
+	)
+	configDbExecutor = configDbExecutorMockObj
+
+	err := Executor{}.Authorize("", ROLE_ADMIN)
+
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+}
+
+func TestAuthorizeWithHigherRoleKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(apiKeysProp, nil),
+	)
+	configDbExecutor = configDbExecutorMockObj
+
+	err := Executor{}.Authorize(BEARER+ADMIN_KEY, ROLE_OPERATOR)
+
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+}
+
+func TestAuthorizeWithLowerRoleKey_ExpectForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(apiKeysProp, nil),
+	)
+	configDbExecutor = configDbExecutorMockObj
+
+	err := Executor{}.Authorize(BEARER+MONITORING_KEY, ROLE_OPERATOR)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err : %s, actual err : %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestAuthorizeWithInvalidCredentials_ExpectUnauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	configDbExecutor = configDbExecutorMockObj
+
+	for _, authorization := range []string{"", BEARER, "Basic " + ADMIN_KEY, BEARER + UNKNOWN_KEY} {
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(apiKeysProp, nil)
+
+		err := Executor{}.Authorize(authorization, ROLE_MONITORING)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err : %s, actual err : %v, authorization : %s", "Unauthorized", err, authorization)
+		case errors.Unauthorized:
+		}
+	}
+}
+
+func TestAuthorizeWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(nil, dbError),
+	)
+	configDbExecutor = configDbExecutorMockObj
+
+	err := Executor{}.Authorize(BEARER+ADMIN_KEY, ROLE_MONITORING)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err : %s, actual err : %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
+func TestAuthorizeWithValidJWT_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, keyFile := writeTestECKey(t)
+	defer os.Remove(keyFile)
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(nil, notFoundError),
+	)
+	configDbExecutor = configDbExecutorMockObj
+
+	token := signES256(t, key, validClaims(ROLE_OPERATOR, nil))
+
+	os.Setenv(util.ANCHOR_JWT_KEY_FILE, keyFile)
+	err := Executor{}.Authorize(BEARER+token, ROLE_OPERATOR)
+	os.Unsetenv(util.ANCHOR_JWT_KEY_FILE)
+
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+}
+
+func TestVerifyJWTWithInvalidClaimsOrSignature_ExpectUnauthorized(t *testing.T) {
+	key, keyFile := writeTestECKey(t)
+	defer os.Remove(keyFile)
+	otherKey, otherKeyFile := writeTestECKey(t)
+	defer os.Remove(otherKeyFile)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testList := map[string]string{
+		"expired":        signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"exp": NOW})),
+		"not yet valid":  signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"nbf": NOW + 60})),
+		"without exp":    signES256(t, key, map[string]interface{}{ROLE: ROLE_ADMIN, "iss": DEFAULT_JWT_ISSUER, "aud": DEFAULT_JWT_AUDIENCE}),
+		"other issuer":   signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"iss": "other"})),
+		"without issuer": signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"iss": ""})),
+		"other audience": signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"aud": []string{"other"}})),
+		"malformed aud":  signES256(t, key, validClaims(ROLE_ADMIN, map[string]interface{}{"aud": 1})),
+		"other key":      signES256(t, otherKey, validClaims(ROLE_ADMIN, nil)),
+		"alg mismatch":   signRS256(t, rsaKey, validClaims(ROLE_ADMIN, nil)),
+		"malformed":      "a.b.c",
+	}
+
+	for name, token := range testList {
+		_, err := verifyJWT(token, keyFile)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err : %s, actual err : %v, case : %s", "Unauthorized", err, name)
+		case errors.Unauthorized:
+		}
+	}
+}
+
+func TestVerifyJWTWithRS256_ExpectRoleReturn(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writeTestPublicKey(t, &rsaKey.PublicKey)
+	defer os.Remove(keyFile)
+
+	role, err := verifyJWT(signRS256(t, rsaKey, validClaims(ROLE_ADMIN, nil)), keyFile)
+
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+
+	if role != ROLE_ADMIN {
+		t.Errorf("Expected role : %s, actual role : %s", ROLE_ADMIN, role)
+	}
+}
+
+func TestVerifyJWTWithConfiguredIssuerAndAudience_ExpectRoleReturn(t *testing.T) {
+	key, keyFile := writeTestECKey(t)
+	defer os.Remove(keyFile)
+
+	os.Setenv(util.ANCHOR_JWT_ISSUER, "anchor")
+	os.Setenv(util.ANCHOR_JWT_AUDIENCE, "node")
+	defer os.Unsetenv(util.ANCHOR_JWT_ISSUER)
+	defer os.Unsetenv(util.ANCHOR_JWT_AUDIENCE)
+
+	claims := validClaims(ROLE_MONITORING, map[string]interface{}{"iss": "anchor", "aud": []string{"other", "node"}})
+	role, err := verifyJWT(signES256(t, key, claims), keyFile)
+
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+
+	if role != ROLE_MONITORING {
+		t.Errorf("Expected role : %s, actual role : %s", ROLE_MONITORING, role)
+	}
+
+	_, err = verifyJWT(signES256(t, key, validClaims(ROLE_MONITORING, nil)), keyFile)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err : %s, actual err : %v", "Unauthorized", err)
+	case errors.Unauthorized:
+	}
+}
+
+func writeTestECKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, writeTestPublicKey(t, &key.PublicKey)
+}
+
+func writeTestPublicKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.TempFile("", "pharos-jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	pem.Encode(file, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return file.Name()
+}
+
+func signingInput(t *testing.T, alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	input := signingInput(t, ALG_ES256, claims)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature := make([]byte, ES256_LEN)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(signature[ES256_LEN/2-len(rBytes):ES256_LEN/2], rBytes)
+	copy(signature[ES256_LEN-len(sBytes):], sBytes)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	input := signingInput(t, ALG_RS256, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
@@ -0,0 +1,209 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package auth
+
+import (
+	"commons/errors"
+	"commons/util"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	ALG_RS256 = "RS256"
+	ALG_ES256 = "ES256"
+	ES256_LEN = 64
+
+	// Claims which tokens must have when they are not configured
+	// by ANCHOR_JWT_ISSUER and ANCHOR_JWT_AUDIENCE environments.
+	DEFAULT_JWT_ISSUER   = "pharos-anchor"
+	DEFAULT_JWT_AUDIENCE = "pharos-node"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Role      string      `json:"role"`
+	Issuer    string      `json:"iss"`
+	Audience  jwtAudience `json:"aud"`
+	ExpiresAt *float64    `json:"exp"`
+	NotBefore *float64    `json:"nbf"`
+}
+
+// jwtAudience is an aud claim which is either a string or an array of strings.
+type jwtAudience []string
+
+func (aud *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*aud = jwtAudience{single}
+		return nil
+	}
+
+	var multiple []string
+	err := json.Unmarshal(data, &multiple)
+	if err != nil {
+		return err
+	}
+	*aud = jwtAudience(multiple)
+	return nil
+}
+
+func (aud jwtAudience) contains(audience string) bool {
+	for _, value := range aud {
+		if value == audience {
+			return true
+		}
+	}
+	return false
+}
+
+var now = time.Now
+
+// verifyJWT verifies a signature, validity period, issuer and audience of
+// the token with the public key of pharos-anchor, and returns a role claim
+// of the token. tokens without expiration time are rejected.
+// The key file is read on every verification, so that it can be replaced
+// without restarting pharos-node.
+func verifyJWT(token string, keyFile string) (string, error) {
+	parts := strings.Split(token, ".")
+
+	var header jwtHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.Unauthorized{Msg: "malformed token signature"}
+	}
+
+	key, err := loadPublicKey(keyFile)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch header.Alg {
+	default:
+		return "", errors.Unauthorized{Msg: "unsupported token algorithm : " + header.Alg}
+
+	case ALG_RS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return "", errors.Unauthorized{Msg: "invalid token signature"}
+		}
+
+	case ALG_ES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != ES256_LEN {
+			return "", errors.Unauthorized{Msg: "invalid token signature"}
+		}
+		r := new(big.Int).SetBytes(signature[:ES256_LEN/2])
+		s := new(big.Int).SetBytes(signature[ES256_LEN/2:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return "", errors.Unauthorized{Msg: "invalid token signature"}
+		}
+	}
+
+	var claims jwtClaims
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return "", err
+	}
+
+	current := float64(now().Unix())
+	if claims.ExpiresAt == nil {
+		return "", errors.Unauthorized{Msg: "token has no expiration time"}
+	}
+	if current >= *claims.ExpiresAt {
+		return "", errors.Unauthorized{Msg: "token is expired"}
+	}
+	if claims.NotBefore != nil && current < *claims.NotBefore {
+		return "", errors.Unauthorized{Msg: "token is not valid yet"}
+	}
+	if claims.Issuer != getEnv(util.ANCHOR_JWT_ISSUER, DEFAULT_JWT_ISSUER) {
+		return "", errors.Unauthorized{Msg: "invalid token issuer : " + claims.Issuer}
+	}
+	if !claims.Audience.contains(getEnv(util.ANCHOR_JWT_AUDIENCE, DEFAULT_JWT_AUDIENCE)) {
+		return "", errors.Unauthorized{Msg: "invalid token audience"}
+	}
+	return claims.Role, nil
+}
+
+// getEnv returns the environment, or the default value if it is not given.
+func getEnv(name string, defaultValue string) string {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.Unauthorized{Msg: "malformed token"}
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return errors.Unauthorized{Msg: "malformed token"}
+	}
+	return nil
+}
+
+// loadPublicKey reads a PEM encoded public key or certificate.
+func loadPublicKey(keyFile string) (interface{}, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.IOError{Msg: err.Error()}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.InvalidParam{Msg: "no PEM data in " + keyFile}
+	}
+
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.InvalidParam{Msg: err.Error()}
+		}
+		return cert.PublicKey, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.InvalidParam{Msg: err.Error()}
+	}
+	return key, nil
+}
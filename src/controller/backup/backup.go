@@ -0,0 +1,613 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package backup provides backup of node state as a single archive
+// and restoring the state from the archive on a fresh node.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"commons/errors"
+	"commons/logger"
+	"compress/gzip"
+	"controller/configuration"
+	"controller/deployment"
+	"controller/dockercontroller"
+	"controller/health"
+	"controller/imagepolicy"
+	configDB "db/bolt/configuration"
+	"db/bolt/event"
+	registryDB "db/bolt/registry"
+	"db/bolt/service"
+	"encoding/json"
+	"golang.org/x/net/context"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// Version of archive format, archives of newer version are not restored.
+	BACKUP_VERSION = 1
+
+	MANIFEST_FILE      = "manifest.json"
+	CONFIGURATION_FILE = "configuration.json"
+	APPS_FILE          = "apps.json"
+	EVENTS_FILE        = "events.json"
+	IMAGES_FILE        = "images.tar"
+	REGISTRIES_FILE    = "registries.json"
+
+	VERSION       = "version"
+	CREATED       = "created"
+	IMAGES        = "images"
+	ID            = "id"
+	NAME          = "name"
+	VALUE         = "value"
+	READONLY      = "readOnly"
+	PROPERTIES    = "properties"
+	DESCRIPTION   = "description"
+	STATE         = "state"
+	DIGESTS       = "digests"
+	UPDATE_POLICY = "updatepolicy"
+	APPS          = "apps"
+	APPID         = "appid"
+	IMAGENAME     = "imagename"
+	ERROR         = "error"
+	REGISTERED    = "registered"
+	SERVICES      = "services"
+	IMAGE         = "image"
+	REGISTRIES    = "registries"
+
+	fileMode = 0600
+)
+
+type Command interface {
+	// Backup makes an archive of apps with digests of their images,
+	// event subscriptions, writable configuration and registry credentials.
+	// images of apps are included if withImages is true.
+	// the caller should close the returned archive.
+	Backup(withImages bool) (io.ReadCloser, error)
+
+	// Restore recreates the state in the archive made by Backup,
+	// and returns results of restoring apps.
+	Restore(archive io.Reader) (map[string]interface{}, error)
+}
+
+type Executor struct{}
+
+// Manifest of archive, which is the first file of archive.
+type manifest struct {
+	Version int   `json:"version"`
+	Created int64 `json:"created"`
+	Images  bool  `json:"images"`
+}
+
+// Registry credentials in archive, which are encrypted with the key
+// and encrypted again with the key of the node when restored.
+type registries struct {
+	Records [][]byte `json:"records"`
+	Key     []byte   `json:"key,omitempty"`
+}
+
+// App in archive, which is redeployed when restored.
+type app struct {
+	ID           string                 `json:"id"`
+	Description  string                 `json:"description"`
+	State        string                 `json:"state"`
+	Digests      map[string]string      `json:"digests"`
+	UpdatePolicy map[string]interface{} `json:"updatepolicy,omitempty"`
+}
+
+var dbExecutor service.Command
+var eventDbExecutor event.Command
+var configDbExecutor configDB.Command
+var registryDbExecutor registryDB.Command
+var configurator configuration.Command
+var deploymentExecutor deployment.Command
+var dockerExecutor dockercontroller.Command
+var healthExecutor health.Command
+var imagePolicy imagepolicy.Command
+
+var now = time.Now
+
+func init() {
+	dbExecutor = service.Executor{}
+	eventDbExecutor = event.Executor{}
+	configDbExecutor = configDB.Executor{}
+	registryDbExecutor = registryDB.Executor{}
+	configurator = configuration.Executor{}
+	deploymentExecutor = deployment.Executor
+	dockerExecutor = dockercontroller.Executor
+	healthExecutor = health.Executor{}
+	imagePolicy = imagepolicy.Executor{}
+}
+
+// Archive in a temporary file, which is removed when it is closed.
+type tempArchive struct {
+	*os.File
+}
+
+func (archive tempArchive) Close() error {
+	defer os.Remove(archive.Name())
+	return archive.File.Close()
+}
+
+// Backup makes a gzipped tarball of manifest, writable configuration,
+// event subscriptions, apps and registry credentials in a temporary file.
+// credentials are archived encrypted together with their key, so that apps
+// from private registries can be redeployed on another node.
+// if withImages is true, images of apps are saved in it like 'docker save'.
+// if succeed to make, return the archive
+// otherwise, return error.
+func (Executor) Backup(withImages bool) (io.ReadCloser, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	props, err := configDbExecutor.GetProperties()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "db operation fail"}
+	}
+
+	events, err := eventDbExecutor.GetEventList()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "db operation fail"}
+	}
+
+	appList, err := dbExecutor.GetAppList()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "db operation fail"}
+	}
+
+	records, key, err := registryDbExecutor.ExportRegistries()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "db operation fail"}
+	}
+	creds := registries{Records: records}
+	if len(records) != 0 {
+		creds.Key = key
+	}
+
+	apps, images := makeApps(appList)
+
+	file, err := ioutil.TempFile("", "backup")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.IOError{Msg: "file io fail"}
+	}
+	archive := tempArchive{file}
+
+	err = writeArchive(archive, makeConfiguration(props), events, apps, creds, images, withImages)
+	if err != nil {
+		archive.Close()
+		return nil, err
+	}
+
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		archive.Close()
+		return nil, errors.IOError{Msg: "file io fail"}
+	}
+	return archive, nil
+}
+
+// Restore applies writable configuration, loads images if the archive has them,
+// imports registry credentials before apps are pulled from registries,
+// redeploys apps through the deployment controller with their states and
+// update policies, subscribes events and registers to pharos-anchor again.
+// a failure of an app doesn't stop restoring others, and events subscribed for
+// the app are not restored.
+// if succeed to restore, return results of apps
+// otherwise, return error.
+func (Executor) Restore(archive io.Reader) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	files, loaded, err := readArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string]interface{}
+	var events []map[string]interface{}
+	var apps []app
+	for _, file := range []struct {
+		name  string
+		value interface{}
+	}{
+		{CONFIGURATION_FILE, &config},
+		{EVENTS_FILE, &events},
+		{APPS_FILE, &apps},
+	} {
+		err = json.Unmarshal(files[file.name], file.value)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return nil, errors.InvalidParam{Msg: "invalid " + file.name + " in backup archive"}
+		}
+	}
+
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.InvalidParam{Msg: "invalid " + CONFIGURATION_FILE + " in backup archive"}
+	}
+	err = configurator.SetConfiguration(string(body))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	hosts, err := restoreRegistries(files[REGISTRIES_FILE])
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	failed := make(map[string]bool)
+	for _, app := range apps {
+		result := map[string]interface{}{ID: app.ID}
+		err = restoreApp(app, loaded)
+		if err != nil {
+			logger.Logging(logger.ERROR, app.ID, err.Error())
+			result[ERROR] = err.Error()
+			failed[app.ID] = true
+		}
+		results = append(results, result)
+	}
+
+	for _, evt := range events {
+		eventId, _ := evt[ID].(string)
+		appId, _ := evt[APPID].(string)
+		imageName, _ := evt[IMAGENAME].(string)
+		if failed[appId] {
+			continue
+		}
+
+		_, err = eventDbExecutor.InsertEvent(eventId, appId, imageName)
+		if err != nil {
+			if _, ok := err.(errors.AlreadyReported); !ok {
+				logger.Logging(logger.ERROR, err.Error())
+			}
+		}
+	}
+
+	// The anchor should know apps and configuration restored.
+	err = healthExecutor.Register()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+
+	res := make(map[string]interface{})
+	res[APPS] = results
+	res[REGISTRIES] = hosts
+	res[REGISTERED] = err == nil
+	return res, nil
+}
+
+// Imports registry credentials in the archive,
+// archives made before credentials are archived don't have them.
+// if succeed to import, return hosts of imported registries
+// otherwise, return error.
+func restoreRegistries(data []byte) ([]string, error) {
+	if data == nil {
+		return make([]string, 0), nil
+	}
+
+	var creds registries
+	err := json.Unmarshal(data, &creds)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.InvalidParam{Msg: "invalid " + REGISTRIES_FILE + " in backup archive"}
+	}
+	if len(creds.Records) == 0 {
+		return make([]string, 0), nil
+	}
+	return registryDbExecutor.ImportRegistries(creds.Records, creds.Key)
+}
+
+// Returns apps to be archived and images of them.
+func makeApps(appList []map[string]interface{}) ([]app, []string) {
+	apps := make([]app, 0)
+	images := make([]string, 0)
+	for _, info := range appList {
+		backup := app{
+			Digests: make(map[string]string),
+		}
+		backup.ID, _ = info[ID].(string)
+		backup.Description, _ = info[DESCRIPTION].(string)
+		backup.State, _ = info[STATE].(string)
+		backup.UpdatePolicy, _ = info[UPDATE_POLICY].(map[string]interface{})
+
+		// Images of the description are tagged, unlike repositories in the db.
+		for _, image := range getServiceImages(backup.Description) {
+			images = append(images, image)
+
+			// Images are restored with the same digests if possible.
+			digest, err := dockerExecutor.GetImageDigestByName(image)
+			if err == nil {
+				backup.Digests[image] = digest
+			}
+		}
+		apps = append(apps, backup)
+	}
+	return apps, images
+}
+
+// Get images of services in a description of app.
+func getServiceImages(description string) []string {
+	images := make([]string, 0)
+	desc := make(map[string]interface{})
+	if json.Unmarshal([]byte(description), &desc) != nil {
+		return images
+	}
+
+	services, _ := desc[SERVICES].(map[string]interface{})
+	for _, serviceInfo := range services {
+		info, _ := serviceInfo.(map[string]interface{})
+		if image, ok := info[IMAGE].(string); ok {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// Returns writable configuration in the form of SetConfiguration body.
+func makeConfiguration(props []map[string]interface{}) map[string]interface{} {
+	values := make([]map[string]interface{}, 0)
+	for _, prop := range props {
+		if readOnly, _ := prop[READONLY].(bool); readOnly {
+			continue
+		}
+		name, _ := prop[NAME].(string)
+		values = append(values, map[string]interface{}{name: prop[VALUE]})
+	}
+	return map[string]interface{}{PROPERTIES: values}
+}
+
+func writeArchive(w io.Writer, config map[string]interface{}, events []map[string]interface{}, apps []app, creds registries, images []string, withImages bool) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{MANIFEST_FILE, manifest{Version: BACKUP_VERSION, Created: now().Unix(), Images: withImages}},
+		{CONFIGURATION_FILE, config},
+		{EVENTS_FILE, events},
+		{APPS_FILE, apps},
+		{REGISTRIES_FILE, creds},
+	}
+	for _, file := range files {
+		data, err := json.Marshal(file.value)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return errors.InvalidJSON{Msg: "json marshal fail"}
+		}
+		err = writeFile(tw, file.name, int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+	}
+
+	if withImages && len(images) != 0 {
+		err := writeImages(tw, images)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tw.Close()
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.IOError{Msg: "file io fail"}
+	}
+	return nil
+}
+
+// Writes images saved by docker to archive,
+// they are saved in a temporary file at first since the size is needed.
+func writeImages(tw *tar.Writer, images []string) error {
+	saved, err := dockerExecutor.ImageSave(images)
+	if err != nil {
+		return err
+	}
+	defer saved.Close()
+
+	file, err := ioutil.TempFile("", "images")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.IOError{Msg: "file io fail"}
+	}
+	temp := tempArchive{file}
+	defer temp.Close()
+
+	size, err := io.Copy(temp, saved)
+	if err == nil {
+		_, err = temp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.IOError{Msg: "file io fail"}
+	}
+	return writeFile(tw, IMAGES_FILE, size, temp)
+}
+
+func writeFile(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    fileMode,
+		Size:    size,
+		ModTime: now(),
+	})
+	if err == nil {
+		_, err = io.Copy(tw, r)
+	}
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.IOError{Msg: "file io fail"}
+	}
+	return nil
+}
+
+// Reads files of archive except images, which are loaded to docker
+// while they are read.
+// returns contents of files and whether images are loaded.
+func readArchive(archive io.Reader) (map[string][]byte, bool, error) {
+	zr, err := gzip.NewReader(archive)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, false, errors.InvalidParam{Msg: "invalid backup archive"}
+	}
+	defer zr.Close()
+
+	files := make(map[string][]byte)
+	loaded := false
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return nil, false, errors.InvalidParam{Msg: "invalid backup archive"}
+		}
+
+		switch header.Name {
+		case IMAGES_FILE:
+			err = dockerExecutor.ImageLoad(tr)
+			if err != nil {
+				return nil, false, err
+			}
+			loaded = true
+		case MANIFEST_FILE, CONFIGURATION_FILE, EVENTS_FILE, APPS_FILE, REGISTRIES_FILE:
+			files[header.Name], err = ioutil.ReadAll(tr)
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+				return nil, false, errors.InvalidParam{Msg: "invalid backup archive"}
+			}
+			if header.Name == MANIFEST_FILE {
+				err = checkManifest(files[header.Name])
+				if err != nil {
+					return nil, false, err
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{MANIFEST_FILE, CONFIGURATION_FILE, EVENTS_FILE, APPS_FILE} {
+		if _, exists := files[name]; !exists {
+			return nil, false, errors.InvalidParam{Msg: name + " is missing in backup archive"}
+		}
+	}
+	return files, loaded, nil
+}
+
+func checkManifest(data []byte) error {
+	var m manifest
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.InvalidParam{Msg: "invalid " + MANIFEST_FILE + " in backup archive"}
+	}
+	if m.Version <= 0 || m.Version > BACKUP_VERSION {
+		return errors.InvalidParam{Msg: "not supported backup version"}
+	}
+	return nil
+}
+
+// Redeploys app with its state and update policy.
+// if images are not loaded from archive, images of the same digests
+// are pulled before deploying if possible.
+func restoreApp(backup app, loaded bool) error {
+	if !loaded {
+		pinImages(backup.Digests)
+	}
+
+	description := make(map[string]interface{})
+	err := json.Unmarshal([]byte(backup.Description), &description)
+	if err != nil {
+		return errors.InvalidParam{Msg: "invalid description of app " + backup.ID}
+	}
+	body, err := yaml.Marshal(description)
+	if err != nil {
+		return errors.InvalidYaml{Msg: "invalid yaml syntax"}
+	}
+
+	deployed, err := deploymentExecutor.DeployApp(context.Background(), string(body), make(map[string]interface{}), nil)
+	if err != nil {
+		return err
+	}
+	appId, _ := deployed[ID].(string)
+
+	switch backup.State {
+	case deployment.EXITED_STATE:
+		err = deploymentExecutor.StopApp(appId)
+	case deployment.PAUSED_STATE:
+		err = deploymentExecutor.PauseApp(appId)
+	}
+	if err != nil {
+		return err
+	}
+
+	if backup.UpdatePolicy != nil {
+		policy, err := json.Marshal(backup.UpdatePolicy)
+		if err != nil {
+			return errors.InvalidJSON{Msg: "json marshal fail"}
+		}
+		return deploymentExecutor.SetUpdatePolicy(appId, string(policy))
+	}
+	return nil
+}
+
+// Pulls images by digest and tags them with image names,
+// images not allowed by the image policy are not pulled
+// and images failed to be pulled are pulled by their names when deployed.
+func pinImages(digests map[string]string) {
+	for image, digest := range digests {
+		_, err := imagePolicy.Verify([]string{digest})
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		err = dockerExecutor.ImagePull(digest)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		imageID, err := dockerExecutor.GetImageIDByRepoDigest(digest)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		err = dockerExecutor.ImageTag(imageID, image)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}
+}
@@ -0,0 +1,404 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"commons/errors"
+	"compress/gzip"
+	configmocks "controller/configuration/mocks"
+	deploymentmocks "controller/deployment/mocks"
+	dockermocks "controller/dockercontroller/mocks"
+	healthmocks "controller/health/mocks"
+	policymocks "controller/imagepolicy/mocks"
+	configdbmocks "db/bolt/configuration/mocks"
+	eventdbmocks "db/bolt/event/mocks"
+	registrydbmocks "db/bolt/registry/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	APP_ID           = "test_app_id"
+	OTHER_APP_ID     = "test_other_app_id"
+	EVENT_ID         = "test_event_id"
+	REPOSITORY       = "test_url:5000/web"
+	IMAGE_NAME       = "test_url:5000/web:1.0"
+	REPO_DIGEST      = "test_url:5000/web@sha256:1111"
+	IMAGE_ID         = "test_image_id"
+	DESCRIPTION_JSON = "{\"services\":{\"web\":{\"image\":\"test_url:5000/web:1.0\"}},\"version\":\"2\"}"
+	IMAGES_TARBALL   = "test_images"
+	REGISTRY_HOST    = "test_url:5000"
+)
+
+var (
+	POLICY = map[string]interface{}{"mode": "immediate"}
+	PROPS  = []map[string]interface{}{
+		{"name": "devicename", "value": "test_device", "readOnly": false},
+		{"name": "deviceid", "value": "test_device_id", "readOnly": true},
+	}
+	TEST_EVENTS = []map[string]interface{}{
+		{"id": EVENT_ID, "appid": APP_ID, "imagename": ""},
+		{"id": "test_global_event_id", "appid": "", "imagename": ""},
+	}
+	APP_LIST = []map[string]interface{}{
+		{
+			"id":           APP_ID,
+			"description":  DESCRIPTION_JSON,
+			"state":        "exited",
+			"images":       []map[string]interface{}{{"name": REPOSITORY}},
+			"updatepolicy": POLICY,
+		},
+	}
+	TEST_APPS = []app{
+		{ID: APP_ID, Description: DESCRIPTION_JSON, State: "exited", Digests: map[string]string{IMAGE_NAME: REPO_DIGEST}, UpdatePolicy: POLICY},
+	}
+	CONFIG = map[string]interface{}{
+		"properties": []map[string]interface{}{{"devicename": "test_device"}},
+	}
+	RECORDS         = [][]byte{[]byte("test_encrypted_record")}
+	KEY             = []byte("test_key")
+	TEST_REGISTRIES = registries{Records: RECORDS, Key: KEY}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(1000, 0) }
+}
+
+// Returns contents of files in archive.
+func readTestArchive(t *testing.T, archive io.Reader) map[string]string {
+	zr, err := gzip.NewReader(archive)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	files := make(map[string]string)
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected err: %s", err.Error())
+		}
+		data, _ := ioutil.ReadAll(tr)
+		files[header.Name] = string(data)
+	}
+	return files
+}
+
+func makeTestArchive(t *testing.T, withImages bool) io.Reader {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj.EXPECT().ImageSave(gomock.Any()).Return(ioutil.NopCloser(strings.NewReader(IMAGES_TARBALL)), nil).AnyTimes()
+	dockerExecutor = dockerExecutorMockObj
+
+	var buf bytes.Buffer
+	err := writeArchive(&buf, CONFIG, TEST_EVENTS, TEST_APPS, TEST_REGISTRIES, []string{IMAGE_NAME}, withImages)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	return &buf
+}
+
+func toJson(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func TestCalledBackup_ExpectArchiveHasStateOfNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configdbmocks.NewMockCommand(ctrl)
+	eventDbExecutorMockObj := eventdbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	registryDbExecutorMockObj := registrydbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperties().Return(PROPS, nil),
+		eventDbExecutorMockObj.EXPECT().GetEventList().Return(TEST_EVENTS, nil),
+		dbExecutorMockObj.EXPECT().GetAppList().Return(APP_LIST, nil),
+		registryDbExecutorMockObj.EXPECT().ExportRegistries().Return(RECORDS, KEY, nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(IMAGE_NAME).Return(REPO_DIGEST, nil),
+		dockerExecutorMockObj.EXPECT().ImageSave([]string{IMAGE_NAME}).Return(ioutil.NopCloser(strings.NewReader(IMAGES_TARBALL)), nil),
+	)
+
+	// pass mockObj to a real object.
+	configDbExecutor = configDbExecutorMockObj
+	eventDbExecutor = eventDbExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+	registryDbExecutor = registryDbExecutorMockObj
+
+	archive, err := Executor{}.Backup(true)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	defer archive.Close()
+
+	files := readTestArchive(t, archive)
+
+	expected := map[string]string{
+		MANIFEST_FILE:      toJson(manifest{Version: BACKUP_VERSION, Created: 1000, Images: true}),
+		CONFIGURATION_FILE: toJson(CONFIG),
+		EVENTS_FILE:        toJson(TEST_EVENTS),
+		APPS_FILE:          toJson(TEST_APPS),
+		REGISTRIES_FILE:    toJson(TEST_REGISTRIES),
+		IMAGES_FILE:        IMAGES_TARBALL,
+	}
+	if !reflect.DeepEqual(expected, files) {
+		t.Errorf("Expected files: %v, actual files: %v", expected, files)
+	}
+}
+
+func TestCalledBackupWhenDBFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configdbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperties().Return(nil, errors.NotFound{}),
+	)
+
+	// pass mockObj to a real object.
+	configDbExecutor = configDbExecutorMockObj
+
+	_, err := Executor{}.Backup(false)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unknown", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledRestore_ExpectStateOfNodeRecreated(t *testing.T) {
+	archive := makeTestArchive(t, true)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	configuratorMockObj := configmocks.NewMockCommand(ctrl)
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	eventDbExecutorMockObj := eventdbmocks.NewMockCommand(ctrl)
+	healthExecutorMockObj := healthmocks.NewMockCommand(ctrl)
+	registryDbExecutorMockObj := registrydbmocks.NewMockCommand(ctrl)
+
+	description := make(map[string]interface{})
+	json.Unmarshal([]byte(DESCRIPTION_JSON), &description)
+	body, _ := yaml.Marshal(description)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().ImageLoad(gomock.Any()).DoAndReturn(func(input io.Reader) error {
+			if data, _ := ioutil.ReadAll(input); string(data) != IMAGES_TARBALL {
+				t.Errorf("Expected images: %s, actual images: %s", IMAGES_TARBALL, data)
+			}
+			return nil
+		}),
+		configuratorMockObj.EXPECT().SetConfiguration(toJson(CONFIG)).Return(nil),
+		registryDbExecutorMockObj.EXPECT().ImportRegistries(RECORDS, KEY).Return([]string{REGISTRY_HOST}, nil),
+		deploymentExecutorMockObj.EXPECT().DeployApp(gomock.Any(), string(body), gomock.Any(), gomock.Any()).Return(map[string]interface{}{"id": APP_ID}, nil),
+		deploymentExecutorMockObj.EXPECT().StopApp(APP_ID).Return(nil),
+		deploymentExecutorMockObj.EXPECT().SetUpdatePolicy(APP_ID, toJson(POLICY)).Return(nil),
+		eventDbExecutorMockObj.EXPECT().InsertEvent(EVENT_ID, APP_ID, "").Return(nil, nil),
+		eventDbExecutorMockObj.EXPECT().InsertEvent("test_global_event_id", "", "").Return(nil, nil),
+		healthExecutorMockObj.EXPECT().Register().Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	configurator = configuratorMockObj
+	deploymentExecutor = deploymentExecutorMockObj
+	eventDbExecutor = eventDbExecutorMockObj
+	healthExecutor = healthExecutorMockObj
+	registryDbExecutor = registryDbExecutorMockObj
+
+	res, err := Executor{}.Restore(archive)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		APPS:       []map[string]interface{}{{ID: APP_ID}},
+		REGISTRIES: []string{REGISTRY_HOST},
+		REGISTERED: true,
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
+
+func TestCalledRestoreWhenDeployFailed_ExpectEventsOfAppSkipped(t *testing.T) {
+	archive := makeTestArchive(t, false)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	configuratorMockObj := configmocks.NewMockCommand(ctrl)
+	deploymentExecutorMockObj := deploymentmocks.NewMockCommand(ctrl)
+	eventDbExecutorMockObj := eventdbmocks.NewMockCommand(ctrl)
+	healthExecutorMockObj := healthmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	registryDbExecutorMockObj := registrydbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configuratorMockObj.EXPECT().SetConfiguration(gomock.Any()).Return(nil),
+		registryDbExecutorMockObj.EXPECT().ImportRegistries(RECORDS, KEY).Return([]string{REGISTRY_HOST}, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPO_DIGEST}).Return(nil, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPO_DIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPO_DIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, IMAGE_NAME).Return(nil),
+		deploymentExecutorMockObj.EXPECT().DeployApp(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.InsufficientResource{Msg: "not enough memory"}),
+		eventDbExecutorMockObj.EXPECT().InsertEvent("test_global_event_id", "", "").Return(nil, nil),
+		healthExecutorMockObj.EXPECT().Register().Return(errors.ConnectionError{}),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	configurator = configuratorMockObj
+	deploymentExecutor = deploymentExecutorMockObj
+	eventDbExecutor = eventDbExecutorMockObj
+	healthExecutor = healthExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	registryDbExecutor = registryDbExecutorMockObj
+
+	res, err := Executor{}.Restore(archive)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		APPS:       []map[string]interface{}{{ID: APP_ID, ERROR: "insufficient resource : not enough memory"}},
+		REGISTRIES: []string{REGISTRY_HOST},
+		REGISTERED: false,
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
+
+func TestCalledRestoreWhenImportRegistriesFailed_ExpectErrorReturn(t *testing.T) {
+	archive := makeTestArchive(t, false)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configuratorMockObj := configmocks.NewMockCommand(ctrl)
+	registryDbExecutorMockObj := registrydbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configuratorMockObj.EXPECT().SetConfiguration(gomock.Any()).Return(nil),
+		registryDbExecutorMockObj.EXPECT().ImportRegistries(RECORDS, KEY).Return(nil, errors.DBOperationError{}),
+	)
+
+	// pass mockObj to a real object.
+	configurator = configuratorMockObj
+	registryDbExecutor = registryDbExecutorMockObj
+
+	_, err := Executor{}.Restore(archive)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
+func TestRestoreRegistriesOfArchiveWithoutThem_ExpectNothingImported(t *testing.T) {
+	tests := map[string][]byte{
+		"MissingFile":  nil,
+		"EmptyRecords": []byte(toJson(registries{Records: [][]byte{}})),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			hosts, err := restoreRegistries(data)
+
+			if err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if len(hosts) != 0 {
+				t.Errorf("Expected no hosts, actual hosts: %v", hosts)
+			}
+		})
+	}
+}
+
+func TestPinImagesWhenImageNotAllowed_ExpectImageNotPulled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+
+	policyExecutorMockObj.EXPECT().Verify([]string{REPO_DIGEST}).Return(nil, errors.Forbidden{})
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+
+	pinImages(map[string]string{IMAGE_NAME: REPO_DIGEST})
+}
+
+func TestCalledRestoreWithInvalidArchive_ExpectErrorReturn(t *testing.T) {
+	newerManifest := func() io.Reader {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		data := toJson(manifest{Version: BACKUP_VERSION + 1})
+		writeFile(tw, MANIFEST_FILE, int64(len(data)), strings.NewReader(data))
+		tw.Close()
+		zw.Close()
+		return &buf
+	}
+
+	tests := map[string]io.Reader{
+		"NotGzip":      strings.NewReader("archive"),
+		"NewerVersion": newerManifest(),
+		"MissingFiles": func() io.Reader { var buf bytes.Buffer; gzip.NewWriter(&buf).Close(); return &buf }(),
+	}
+
+	for name, archive := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Executor{}.Restore(archive)
+
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+		})
+	}
+}
@@ -23,8 +23,11 @@ import (
 	"commons/logger"
 	"commons/url"
 	"commons/util"
+	"controller/auth"
 	"controller/dockercontroller"
+	"controller/imagepolicy"
 	"db/bolt/configuration"
+	"db/bolt/wrapper"
 	"github.com/shirou/gopsutil/cpu"
 	"net"
 	"os"
@@ -60,14 +63,30 @@ const (
 	NAME                                     = "name"
 	VALUE                                    = "value"
 	READONLY                                 = "readOnly"
+	API_KEYS                                 = "apikeys"
+	EXEC_ENABLED                             = "execenabled"
+	IMAGE_POLICY                             = imagepolicy.IMAGE_POLICY
+	DB_PATH                                  = "dbpath"
+	DB_RECOVERY                              = "dbrecovery"
+	MASKED_KEY                               = "******"
 	DEFAULT_DEVICE_NAME                      = "EdgeDevice"
 	DEFAULT_PING_INTERVAL                    = "10"
+	DEFAULT_ROLLBACK_WATCH_PERIOD            = "30"
+	DEFAULT_OUTBOX_MAX_AGE                   = "86400"
+	DEFAULT_OUTBOX_MAX_SIZE                  = "1000"
+	DEFAULT_RESOURCE_SAMPLING_INTERVAL       = "10"
+	DEFAULT_RESOURCE_HISTORY_SIZE            = "8640"
+	DEFAULT_EXEC_ENABLED                     = "false"
+	DEFAULT_GC_INTERVAL                      = "3600"
+	DEFAULT_GC_RETENTION                     = "3"
+	DEFAULT_GC_DISK_WATERMARK                = "10"
 	UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY = "80"
 	DEFAULT_ANCHOR_PORT                      = "48099"
 )
 
 var dbExecutor configuration.Command
 var dockerExecutor dockercontroller.Command
+var getDBRecovery = wrapper.GetRecovery
 
 func init() {
 	dbExecutor = configuration.Executor{}
@@ -106,6 +125,14 @@ func initConfiguration() {
 		}
 	}
 
+	// Paths of certificates are given by environments only,
+	// they can't be changed through configuration API.
+	tlsCertFile := os.Getenv(util.TLS_CERT_FILE)
+	tlsKeyFile := os.Getenv(util.TLS_KEY_FILE)
+	tlsClientCAFile := os.Getenv(util.TLS_CLIENT_CA_FILE)
+	anchorCAFile := os.Getenv(util.ANCHOR_CA_FILE)
+	anchorJWTKeyFile := os.Getenv(util.ANCHOR_JWT_KEY_FILE)
+
 	anchorEndPoint, err := getAnchorEndPoint()
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -132,6 +159,74 @@ func initConfiguration() {
 		interval = prop["value"].(string)
 	}
 
+	watchPeriod := DEFAULT_ROLLBACK_WATCH_PERIOD
+	prop, err = dbExecutor.GetProperty("rollbackwatchperiod")
+	if err == nil {
+		watchPeriod = prop["value"].(string)
+	}
+
+	outboxMaxAge := DEFAULT_OUTBOX_MAX_AGE
+	prop, err = dbExecutor.GetProperty("outboxmaxage")
+	if err == nil {
+		outboxMaxAge = prop["value"].(string)
+	}
+
+	outboxMaxSize := DEFAULT_OUTBOX_MAX_SIZE
+	prop, err = dbExecutor.GetProperty("outboxmaxsize")
+	if err == nil {
+		outboxMaxSize = prop["value"].(string)
+	}
+
+	samplingInterval := DEFAULT_RESOURCE_SAMPLING_INTERVAL
+	prop, err = dbExecutor.GetProperty("resourcesamplinginterval")
+	if err == nil {
+		samplingInterval = prop["value"].(string)
+	}
+
+	historySize := DEFAULT_RESOURCE_HISTORY_SIZE
+	prop, err = dbExecutor.GetProperty("resourcehistorysize")
+	if err == nil {
+		historySize = prop["value"].(string)
+	}
+
+	gcInterval := DEFAULT_GC_INTERVAL
+	prop, err = dbExecutor.GetProperty("gcinterval")
+	if err == nil {
+		gcInterval = prop["value"].(string)
+	}
+
+	gcRetention := DEFAULT_GC_RETENTION
+	prop, err = dbExecutor.GetProperty("gcretention")
+	if err == nil {
+		gcRetention = prop["value"].(string)
+	}
+
+	gcDiskWatermark := DEFAULT_GC_DISK_WATERMARK
+	prop, err = dbExecutor.GetProperty("gcdiskwatermark")
+	if err == nil {
+		gcDiskWatermark = prop["value"].(string)
+	}
+
+	execEnabled := DEFAULT_EXEC_ENABLED
+	prop, err = dbExecutor.GetProperty(EXEC_ENABLED)
+	if err == nil {
+		execEnabled = prop["value"].(string)
+	}
+
+	apiKeys := make([]interface{}, 0)
+	prop, err = dbExecutor.GetProperty(API_KEYS)
+	if err == nil {
+		if keys, ok := prop["value"].([]interface{}); ok {
+			apiKeys = keys
+		}
+	}
+
+	imagePolicy := imagepolicy.DefaultPolicy()
+	prop, err = dbExecutor.GetProperty(IMAGE_POLICY)
+	if err == nil && imagepolicy.ValidatePolicy(prop["value"]) == nil {
+		imagePolicy = prop["value"].(map[string]interface{})
+	}
+
 	properties := make([]map[string]interface{}, 0)
 	properties = append(properties, makeProperty("anchoraddress", anchoraddress, true))
 	properties = append(properties, makeProperty("anchorendpoint", anchorEndPoint, true))
@@ -143,6 +238,23 @@ func initConfiguration() {
 	properties = append(properties, makeProperty("processor", processor, true))
 	properties = append(properties, makeProperty("deviceid", deviceid, true))
 	properties = append(properties, makeProperty("reverseproxy", proxy, true))
+	properties = append(properties, makeProperty("rollbackwatchperiod", watchPeriod, false))
+	properties = append(properties, makeProperty("outboxmaxage", outboxMaxAge, false))
+	properties = append(properties, makeProperty("outboxmaxsize", outboxMaxSize, false))
+	properties = append(properties, makeProperty("resourcesamplinginterval", samplingInterval, false))
+	properties = append(properties, makeProperty("resourcehistorysize", historySize, false))
+	properties = append(properties, makeProperty("gcinterval", gcInterval, false))
+	properties = append(properties, makeProperty("gcretention", gcRetention, false))
+	properties = append(properties, makeProperty("gcdiskwatermark", gcDiskWatermark, false))
+	properties = append(properties, makeProperty(EXEC_ENABLED, execEnabled, false))
+	properties = append(properties, makeProperty("tlscertfile", tlsCertFile, true))
+	properties = append(properties, makeProperty("tlskeyfile", tlsKeyFile, true))
+	properties = append(properties, makeProperty("tlsclientcafile", tlsClientCAFile, true))
+	properties = append(properties, makeProperty("anchorcafile", anchorCAFile, true))
+	properties = append(properties, makeProperty("anchorjwtkeyfile", anchorJWTKeyFile, true))
+	properties = append(properties, makeProperty(DB_PATH, wrapper.Path(), true))
+	properties = append(properties, makeProperty(API_KEYS, apiKeys, false))
+	properties = append(properties, makeProperty(IMAGE_POLICY, imagePolicy, false))
 
 	for _, prop := range properties {
 		err = dbExecutor.SetProperty(prop)
@@ -166,10 +278,21 @@ func (Executor) GetConfiguration() (map[string]interface{}, error) {
 	for _, prop := range props {
 		value := make(map[string]interface{})
 		value[prop["name"].(string)] = prop["value"]
+		if prop["name"] == API_KEYS {
+			// Keys are secrets, only their roles are shown.
+			value[API_KEYS] = maskAPIKeys(prop["value"])
+		}
 		value["readOnly"] = prop["readOnly"]
 		values = append(values, value)
 	}
 
+	// The last recovery of corrupt database is shown if it has ever happened,
+	// with ids of apps rebuilt from containers.
+	recovery, err := getDBRecovery()
+	if err == nil {
+		values = append(values, map[string]interface{}{DB_RECOVERY: recovery, "readOnly": true})
+	}
+
 	res := make(map[string]interface{})
 	res[PROPERTIES] = values
 
@@ -186,6 +309,9 @@ func (configurator Executor) SetConfiguration(body string) error {
 		return err
 	}
 
+	// All of properties are validated before any of them is updated,
+	// and then they are updated together in a single transaction.
+	updated := make([]map[string]interface{}, 0)
 	for _, prop := range bodyMap[PROPERTIES].([]interface{}) {
 		for key, value := range prop.(map[string]interface{}) {
 			property, err := dbExecutor.GetProperty(key)
@@ -198,18 +324,77 @@ func (configurator Executor) SetConfiguration(body string) error {
 				return errors.InvalidJSON{"read only property"}
 			}
 
-			property[VALUE] = value
-			err = dbExecutor.SetProperty(property)
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-				return convertDBError(err)
+			if key == API_KEYS {
+				err = validateAPIKeys(value)
+				if err != nil {
+					logger.Logging(logger.ERROR, err.Error())
+					return err
+				}
+			}
+
+			if key == IMAGE_POLICY {
+				err = imagepolicy.ValidatePolicy(value)
+				if err != nil {
+					logger.Logging(logger.ERROR, err.Error())
+					return err
+				}
+			}
+
+			if key == EXEC_ENABLED && value != "true" && value != "false" {
+				return errors.InvalidJSON{Msg: "execenabled should be \"true\" or \"false\""}
 			}
+
+			property[VALUE] = value
+			updated = append(updated, property)
 		}
 	}
 
+	err = dbExecutor.SetProperties(updated)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err)
+	}
+	return nil
+}
+
+// validateAPIKeys checks that the value is a list of
+// {"key": "...", "role": "..."} having a non-empty key and a known role.
+func validateAPIKeys(value interface{}) error {
+	keys, ok := value.([]interface{})
+	if !ok {
+		return errors.InvalidJSON{Msg: "apikeys should be a list"}
+	}
+
+	for _, key := range keys {
+		entry, ok := key.(map[string]interface{})
+		if !ok {
+			return errors.InvalidJSON{Msg: "apikeys should be a list of key and role"}
+		}
+		k, _ := entry["key"].(string)
+		role, _ := entry["role"].(string)
+		if len(k) == 0 || k == MASKED_KEY {
+			return errors.InvalidJSON{Msg: "empty or masked api key"}
+		}
+		if !auth.IsRole(role) {
+			return errors.InvalidJSON{Msg: "unknown role : " + role}
+		}
+	}
 	return nil
 }
 
+func maskAPIKeys(value interface{}) []interface{} {
+	masked := make([]interface{}, 0)
+	keys, _ := value.([]interface{})
+	for _, key := range keys {
+		entry, _ := key.(map[string]interface{})
+		masked = append(masked, map[string]interface{}{
+			"key":  MASKED_KEY,
+			"role": entry["role"],
+		})
+	}
+	return masked
+}
+
 func makeProperty(name string, value interface{}, readOnly bool) map[string]interface{} {
 	prop := make(map[string]interface{})
 	prop[NAME] = name
@@ -231,13 +416,18 @@ func getAnchorEndPoint() (string, error) {
 		return "", errors.InvalidParam{"Anchor address's validation check failed"}
 	}
 
+	scheme, proxyPort := util.HTTP_SCHEME, UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY
+	if util.IsAnchorTLSEnabled() {
+		scheme, proxyPort = util.HTTPS_SCHEME, util.SECURED_ANCHOR_PORT_WITH_REVERSE_PROXY
+	}
+
 	anchorProxy := os.Getenv("ANCHOR_REVERSE_PROXY")
 	anchorEndPoint := ""
 
 	if len(anchorProxy) == 0 || anchorProxy == "false" {
-		anchorEndPoint = "http://" + anchorIP + ":" + DEFAULT_ANCHOR_PORT + url.Base()
+		anchorEndPoint = scheme + anchorIP + ":" + DEFAULT_ANCHOR_PORT + url.Base()
 	} else if anchorProxy == "true" {
-		anchorEndPoint = "http://" + anchorIP + ":" + UNSECURED_ANCHOR_PORT_WITH_REVERSE_PROXY + url.PharosAnchor() + url.Base()
+		anchorEndPoint = scheme + anchorIP + ":" + proxyPort + url.PharosAnchor() + url.Base()
 	} else {
 		logger.Logging(logger.ERROR, "Invalid value for ANCHOR_REVERSE_PROXY")
 		return "", errors.InvalidParam{"Invalid value for ANCHOR_REVERSE_PROXY"}
@@ -82,6 +82,29 @@ func TestGetAnchorEndPointWithAnchorRPEnvTrue_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestGetAnchorEndPointWithAnchorCAEnv_ExpectHttpsEndPoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedRet := "https://127.0.0.1:443/pharos-anchor/api/v1"
+
+	os.Setenv("ANCHOR_ADDRESS", "127.0.0.1")
+	os.Setenv("ANCHOR_REVERSE_PROXY", "true")
+	os.Setenv("ANCHOR_CA_FILE", "/test/ca.pem")
+	ret, err := getAnchorEndPoint()
+	os.Unsetenv("ANCHOR_ADDRESS")
+	os.Unsetenv("ANCHOR_REVERSE_PROXY")
+	os.Unsetenv("ANCHOR_CA_FILE")
+
+	if err != nil {
+		t.Errorf("Expected error : nil, actual error : %s", err.Error())
+	}
+
+	if ret != expectedRet {
+		t.Errorf("Expected result : %v, actual result : %v", expectedRet, ret)
+	}
+}
+
 func TestGetProxyInfo_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -191,6 +214,39 @@ func TestGetConfiguration_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestGetConfigurationAfterDBRecovery_ExpectRecoveryReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetProperties().Return(properties["properties"], nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	recovery := map[string]interface{}{"time": "test_time", "corruptfile": "test_file", "apps": []interface{}{"app"}}
+	origGetDBRecovery := getDBRecovery
+	getDBRecovery = func() (map[string]interface{}, error) { return recovery, nil }
+	defer func() { getDBRecovery = origGetDBRecovery }()
+
+	res, err := Executor{}.GetConfiguration()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := []map[string]interface{}{
+		{"name": "value", "readOnly": false},
+		{DB_RECOVERY: recovery, "readOnly": true},
+	}
+	if !reflect.DeepEqual(expected, res[PROPERTIES]) {
+		t.Errorf("Expected result : %v, actual result : %v", expected, res[PROPERTIES])
+	}
+}
+
 func TestGetConfigurationWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -226,7 +282,7 @@ func TestSetConfiguration_ExpectSuccess(t *testing.T) {
 	prop := properties["properties"].([]map[string]interface{})[0]
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetProperty(prop["name"].(string)).Return(prop, nil),
-		dbExecutorMockObj.EXPECT().SetProperty(prop).Return(nil),
+		dbExecutorMockObj.EXPECT().SetProperties([]map[string]interface{}{prop}).Return(nil),
 	)
 
 	// pass mockObj to a real object.
@@ -277,7 +333,7 @@ func TestSetConfigurationWhenSetPropertyReturnsError_ExpectErrorReturn(t *testin
 	prop := properties["properties"].([]map[string]interface{})[0]
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetProperty(prop["name"].(string)).Return(prop, nil),
-		dbExecutorMockObj.EXPECT().SetProperty(prop).Return(notFoundError),
+		dbExecutorMockObj.EXPECT().SetProperties([]map[string]interface{}{prop}).Return(notFoundError),
 	)
 
 	// pass mockObj to a real object.
@@ -296,3 +352,178 @@ func TestSetConfigurationWhenSetPropertyReturnsError_ExpectErrorReturn(t *testin
 	case errors.NotFound:
 	}
 }
+
+func TestSetConfigurationWhenOneOfPropertiesInvalid_ExpectNothingUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	prop := map[string]interface{}{
+		"name":     EXEC_ENABLED,
+		"value":    DEFAULT_EXEC_ENABLED,
+		"readOnly": false,
+	}
+	readOnly := map[string]interface{}{
+		"name":     "os",
+		"value":    "linux",
+		"readOnly": true,
+	}
+	dbExecutorMockObj.EXPECT().GetProperty(EXEC_ENABLED).Return(prop, nil)
+	dbExecutorMockObj.EXPECT().GetProperty("os").Return(readOnly, nil)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	body := map[string]interface{}{
+		"properties": []map[string]interface{}{{EXEC_ENABLED: "true"}, {"os": "windows"}},
+	}
+	jsonString, _ := json.Marshal(body)
+	err := Executor{}.SetConfiguration(string(jsonString))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestGetConfigurationWithAPIKeys_ExpectMaskedKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	apiKeys := []map[string]interface{}{{
+		"name":     API_KEYS,
+		"value":    []interface{}{map[string]interface{}{"key": "secret", "role": "admin"}},
+		"readOnly": false,
+	}}
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetProperties().Return(apiKeys, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	res, err := Executor{}.GetConfiguration()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := []interface{}{map[string]interface{}{"key": MASKED_KEY, "role": "admin"}}
+	value := res[PROPERTIES].([]map[string]interface{})[0][API_KEYS]
+	if !reflect.DeepEqual(expected, value) {
+		t.Errorf("Expected result : %v, actual result : %v", expected, value)
+	}
+}
+
+func TestSetConfigurationWithInvalidAPIKeys_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	prop := map[string]interface{}{
+		"name":     API_KEYS,
+		"value":    []interface{}{},
+		"readOnly": false,
+	}
+
+	invalidValues := []interface{}{
+		"secret",
+		[]interface{}{map[string]interface{}{"key": "secret", "role": "root"}},
+		[]interface{}{map[string]interface{}{"key": MASKED_KEY, "role": "admin"}},
+	}
+
+	for _, value := range invalidValues {
+		dbExecutorMockObj.EXPECT().GetProperty(API_KEYS).Return(prop, nil)
+
+		// pass mockObj to a real object.
+		dbExecutor = dbExecutorMockObj
+
+		body := map[string]interface{}{
+			"properties": []map[string]interface{}{{API_KEYS: value}},
+		}
+		jsonString, _ := json.Marshal(body)
+		err := Executor{}.SetConfiguration(string(jsonString))
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+		case errors.InvalidJSON:
+		}
+	}
+}
+
+func TestSetConfigurationWithInvalidExecEnabled_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	prop := map[string]interface{}{
+		"name":     EXEC_ENABLED,
+		"value":    DEFAULT_EXEC_ENABLED,
+		"readOnly": false,
+	}
+
+	for _, value := range []interface{}{true, "yes"} {
+		dbExecutorMockObj.EXPECT().GetProperty(EXEC_ENABLED).Return(prop, nil)
+
+		// pass mockObj to a real object.
+		dbExecutor = dbExecutorMockObj
+
+		body := map[string]interface{}{
+			"properties": []map[string]interface{}{{EXEC_ENABLED: value}},
+		}
+		jsonString, _ := json.Marshal(body)
+		err := Executor{}.SetConfiguration(string(jsonString))
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+		case errors.InvalidJSON:
+		}
+	}
+}
+
+func TestSetConfigurationWithInvalidImagePolicy_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	prop := map[string]interface{}{
+		"name":     IMAGE_POLICY,
+		"value":    map[string]interface{}{},
+		"readOnly": false,
+	}
+
+	invalidValues := []interface{}{
+		"policy",
+		map[string]interface{}{"allowedregistries": "docker.io"},
+		map[string]interface{}{"pinneddigests": map[string]interface{}{"nginx": []interface{}{"latest"}}},
+		map[string]interface{}{"publickeys": []interface{}{"key"}},
+	}
+
+	for _, value := range invalidValues {
+		dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(prop, nil)
+
+		// pass mockObj to a real object.
+		dbExecutor = dbExecutorMockObj
+
+		body := map[string]interface{}{
+			"properties": []map[string]interface{}{{IMAGE_POLICY: value}},
+		}
+		jsonString, _ := json.Marshal(body)
+		err := Executor{}.SetConfiguration(string(jsonString))
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+		case errors.InvalidJSON:
+		}
+	}
+}
@@ -0,0 +1,199 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/monitoring/resource"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	MEM_LIMIT = "mem_limit"
+	CPUS      = "cpus"
+
+	// Query parameter which skips the admission check on deployment.
+	FORCE = "force"
+)
+
+// requirement is the sum of resources which services of an app ask for.
+type requirement struct {
+	cpus float64
+	mem  uint64
+	disk uint64
+
+	// Images of which size is unknown, not counted in disk.
+	unknownImages []string
+}
+
+// Check whether resources required by the description fit in the capacity of the device.
+// memory and cpus are summed from mem_limit and cpus of services,
+// disk is summed from estimated sizes of images which don't exist in the device,
+// and images of which size is unknown are reported to progress as warnings.
+// if the device has enough resources or the capacity is unknown, return nil
+// otherwise, return InsufficientResource error.
+func checkAdmission(description interface{}, progress Progress) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	root, _ := description.(map[string]interface{})
+	services, _ := root[SERVICES].(map[string]interface{})
+
+	required, err := getRequirement(services)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	capacity, err := resourceMonitor.GetCapacity()
+	if err != nil {
+		// Deployments are not blocked by failures of resource monitoring.
+		logger.Logging(logger.ERROR, "skip admission check : "+err.Error())
+		return nil
+	}
+
+	problems := make([]string, 0)
+	if cpus, ok := capacity[resource.CPU].(float64); ok && required.cpus > cpus {
+		problems = append(problems, fmt.Sprintf("cpus %.2f requested, %.2f available", required.cpus, cpus))
+	}
+	if mem, ok := capacity[resource.MEM].(uint64); ok && required.mem > mem {
+		problems = append(problems, fmt.Sprintf("memory %d bytes requested, %d bytes available", required.mem, mem))
+	}
+	if disk, ok := capacity[resource.DISK].(uint64); ok {
+		if required.disk > disk {
+			problems = append(problems, fmt.Sprintf("disk %d bytes requested, %d bytes available", required.disk, disk))
+		}
+		for _, image := range required.unknownImages {
+			reportWarning(progress, "size of image "+image+" is unknown, free disk space is not checked for it")
+		}
+	}
+
+	if len(problems) != 0 {
+		msg := strings.Join(problems, ", ")
+		logger.Logging(logger.ERROR, msg)
+		return errors.InsufficientResource{Msg: msg}
+	}
+	return nil
+}
+
+// Sum resources which services ask for.
+// images of which size is unknown are not counted but listed.
+func getRequirement(services map[string]interface{}) (requirement, error) {
+	required := requirement{}
+	images := make(map[string]bool)
+
+	for name, value := range services {
+		service, _ := value.(map[string]interface{})
+
+		if limit, exists := service[MEM_LIMIT]; exists {
+			mem, err := parseMemory(limit)
+			if err != nil {
+				return required, errors.InvalidYaml{Msg: "invalid " + MEM_LIMIT + " of " + name}
+			}
+			required.mem += mem
+		}
+
+		if value, exists := service[CPUS]; exists {
+			cpus, err := parseCPUs(value)
+			if err != nil {
+				return required, errors.InvalidYaml{Msg: "invalid " + CPUS + " of " + name}
+			}
+			required.cpus += cpus
+		}
+
+		image, ok := service[IMAGE].(string)
+		if !ok || images[image] {
+			continue
+		}
+		images[image] = true
+
+		size, err := dockerExecutor.EstimateImageSize(image)
+		if err != nil {
+			logger.Logging(logger.ERROR, "size of "+image+" is unknown : "+err.Error())
+			required.unknownImages = append(required.unknownImages, image)
+			continue
+		}
+		required.disk += uint64(size)
+	}
+	return required, nil
+}
+
+// Parse memory size in compose file format, e.g. 1024, "512m", "1g" or "1.5gb".
+func parseMemory(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("negative size : %d", v)
+		}
+		return uint64(v), nil
+	case string:
+		s := strings.ToLower(strings.TrimSpace(v))
+		s = strings.TrimSuffix(s, "b")
+		s = strings.TrimSuffix(s, "i")
+
+		multiplier := uint64(1)
+		if len(s) != 0 {
+			switch s[len(s)-1] {
+			case 'k':
+				multiplier = 1 << 10
+			case 'm':
+				multiplier = 1 << 20
+			case 'g':
+				multiplier = 1 << 30
+			case 't':
+				multiplier = 1 << 40
+			}
+			if multiplier != 1 {
+				s = strings.TrimSpace(s[:len(s)-1])
+			}
+		}
+
+		size, err := strconv.ParseFloat(s, 64)
+		if err != nil || size < 0 {
+			return 0, fmt.Errorf("invalid size : %s", v)
+		}
+		return uint64(size * float64(multiplier)), nil
+	}
+	return 0, fmt.Errorf("invalid size : %v", value)
+}
+
+// Parse number of cpus in compose file format, e.g. 1, 0.5 or "1.5".
+func parseCPUs(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return 0, fmt.Errorf("invalid cpus : %v", value)
+}
+
+// Check whether the admission check is skipped by force query parameter.
+func isForced(query map[string]interface{}) bool {
+	values, exists := query[FORCE].([]string)
+	if !exists || len(values) == 0 {
+		return false
+	}
+	forced, err := strconv.ParseBool(values[0])
+	return err == nil && forced
+}
@@ -0,0 +1,277 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	policymocks "controller/imagepolicy/mocks"
+	appmocks "controller/monitoring/apps/mocks"
+	"controller/monitoring/resource"
+	resourcemocks "controller/monitoring/resource/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+const (
+	LIMITED_DESCRIPTION_YAML = "services:\n  " + SERVICE + ":\n    image: " + REPOSITORY_WITH_PORT_IMAGE_WITH_TAG +
+		"\n    mem_limit: 512m\n    cpus: 0.5\nversion: \"2\"\n"
+	LIMITED_DESCRIPTION_JSON = "{\"services\":{\"" + SERVICE + "\":{\"cpus\":0.5,\"image\":\"" + REPOSITORY_WITH_PORT_IMAGE_WITH_TAG +
+		"\",\"mem_limit\":\"512m\"}},\"version\":\"2\"}"
+)
+
+var CAPACITY = map[string]interface{}{
+	resource.CPU:  2.0,
+	resource.MEM:  uint64(1 << 30),
+	resource.DISK: uint64(1 << 30),
+}
+
+func TestCalledDeployAppWhenResourceIsNotEnough_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	smallCapacity := map[string]interface{}{
+		resource.CPU:  2.0,
+		resource.MEM:  uint64(256 << 20),
+		resource.DISK: uint64(1 << 30),
+	}
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(LIMITED_DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(smallCapacity, nil),
+		dbExecutorMockObj.EXPECT().DeleteApp(APP_ID).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), LIMITED_DESCRIPTION_YAML, nil, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InsufficientResource", err)
+	case errors.InsufficientResource:
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
+func TestCalledDeployAppWithForceQuery_ExpectAdmissionSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	query := map[string]interface{}{
+		FORCE: []string{"true"},
+	}
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(LIMITED_DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().DownWithRemoveImages(gomock.Any(), gomock.Any()).Return(nil),
+		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), LIMITED_DESCRIPTION_YAML, query, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "UnknownError", err)
+	case errors.Unknown:
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
+func TestCheckAdmission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	description := map[string]interface{}{
+		SERVICES: map[string]interface{}{
+			"first":  map[string]interface{}{IMAGE: "first:1.0", MEM_LIMIT: "512m", CPUS: 1.5},
+			"second": map[string]interface{}{IMAGE: "second:1.0", MEM_LIMIT: 1024, CPUS: "0.5"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		capacity    map[string]interface{}
+		capacityErr error
+		imageSize   int64
+		imageErr    error
+		expected    error
+	}{
+		{"EnoughResource_ExpectSuccess", CAPACITY, nil, 1 << 20, nil, nil},
+		{"CapacityUnknown_ExpectSuccess", nil, UnknownError, 1 << 20, nil, nil},
+		{"ImageSizeUnknown_ExpectSuccess", CAPACITY, nil, 0, errors.NotFoundImage{}, nil},
+		{"NotEnoughCPU_ExpectError", map[string]interface{}{resource.CPU: 1.0}, nil, 0, nil, errors.InsufficientResource{}},
+		{"NotEnoughMemory_ExpectError", map[string]interface{}{resource.MEM: uint64(1 << 20)}, nil, 0, nil, errors.InsufficientResource{}},
+		{"NotEnoughDisk_ExpectError", map[string]interface{}{resource.DISK: uint64(1 << 20)}, nil, 1 << 20, nil, errors.InsufficientResource{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+			resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+			dockerExecutorMockObj.EXPECT().EstimateImageSize(gomock.Any()).Return(test.imageSize, test.imageErr).Times(2)
+			resourceExecutorMockObj.EXPECT().GetCapacity().Return(test.capacity, test.capacityErr)
+
+			dockerExecutor = dockerExecutorMockObj
+			resourceMonitor = resourceExecutorMockObj
+
+			err := checkAdmission(description, nil)
+			if test.expected == nil && err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if test.expected != nil {
+				switch err.(type) {
+				default:
+					t.Errorf("Expected err: %s, actual err: %v", "InsufficientResource", err)
+				case errors.InsufficientResource:
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAdmissionWhenImageSizeUnknown_ExpectWarningReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	description := map[string]interface{}{
+		SERVICES: map[string]interface{}{
+			SERVICE: map[string]interface{}{IMAGE: "first:1.0"},
+		},
+	}
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().EstimateImageSize("first:1.0").Return(int64(0), errors.NotFoundImage{}),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+
+	recorder := &phaseRecorder{}
+	err := checkAdmission(description, recorder)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := []string{"size of image first:1.0 is unknown, free disk space is not checked for it"}
+	if !reflect.DeepEqual(recorder.warnings, expected) {
+		t.Errorf("Expected warnings: %v, actual warnings: %v", expected, recorder.warnings)
+	}
+}
+
+func TestCheckAdmissionWithInvalidLimit_ExpectInvalidYaml(t *testing.T) {
+	description := map[string]interface{}{
+		SERVICES: map[string]interface{}{
+			SERVICE: map[string]interface{}{MEM_LIMIT: "many"},
+		},
+	}
+
+	err := checkAdmission(description, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidYaml", err)
+	case errors.InvalidYaml:
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected uint64
+		valid    bool
+	}{
+		{1024, 1024, true},
+		{"100", 100, true},
+		{"100b", 100, true},
+		{"2k", 2 << 10, true},
+		{"512m", 512 << 20, true},
+		{"512MB", 512 << 20, true},
+		{"1.5g", 3 << 29, true},
+		{"1GiB", 1 << 30, true},
+		{"-1m", 0, false},
+		{"many", 0, false},
+		{-1, 0, false},
+	}
+
+	for _, test := range tests {
+		size, err := parseMemory(test.value)
+		if test.valid != (err == nil) {
+			t.Errorf("Unexpected result of %v : %v", test.value, err)
+		}
+		if size != test.expected {
+			t.Errorf("Expected size of %v : %d, Actual size : %d", test.value, test.expected, size)
+		}
+	}
+}
+
+func TestIsForced(t *testing.T) {
+	tests := []struct {
+		query    map[string]interface{}
+		expected bool
+	}{
+		{nil, false},
+		{map[string]interface{}{FORCE: []string{"true"}}, true},
+		{map[string]interface{}{FORCE: []string{"false"}}, false},
+		{map[string]interface{}{FORCE: []string{"yes"}}, false},
+		{map[string]interface{}{EVENTID: []string{"true"}}, false},
+	}
+
+	for _, test := range tests {
+		if forced := isForced(test.query); forced != test.expected {
+			t.Errorf("Expected %v for %v, Actual %v", test.expected, test.query, forced)
+		}
+	}
+}
@@ -24,16 +24,23 @@ import (
 	"commons/logger"
 	"commons/util"
 	"controller/dockercontroller"
+	"controller/imagepolicy"
 	"controller/monitoring/apps"
+	"controller/monitoring/resource"
+	configDB "db/bolt/configuration"
+	"db/bolt/history"
 	"db/bolt/service"
 	"encoding/json"
+	"golang.org/x/net/context"
 	"gopkg.in/yaml.v2"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -44,6 +51,7 @@ const (
 	SERVICES       = "services"
 	IMAGE          = "image"
 	IMAGES         = "images"
+	REPLICAS       = "replicas"
 	NAME           = "name"
 	PORTS          = "ports"
 	STATE          = "state"
@@ -62,21 +70,79 @@ const (
 	RUNNING_STATE  = "running"
 	EXITED_STATE   = "exited"
 	UPDATING_STATE = "updating"
+	PAUSED_STATE   = "paused"
 	NONE           = "none"
 	CHANGES        = "changes"
 	EVENTID        = "eventId"
 )
 
+const (
+	VALUE         = "value"
+	RESTART_COUNT = "restartcount"
+	HEALTH        = "health"
+	UNHEALTHY     = "unhealthy"
+	DEAD_STATE    = "dead"
+
+	// App state marked after update is rolled back.
+	ROLLEDBACK_STATE = "rolledback"
+
+	// App state marked when update fails and previous images can't be restored.
+	ROLLBACK_FAILED_STATE = "rollbackfailed"
+
+	// App state of app rebuilt after the database was recovered,
+	// which is not restored on start-up until it is deployed again.
+	RECOVERED_STATE = "recovered"
+
+	// Configuration property holding how long(in seconds) updated services are watched.
+	ROLLBACK_WATCH_PERIOD         = "rollbackwatchperiod"
+	DEFAULT_ROLLBACK_WATCH_PERIOD = 30
+
+	// Restarts allowed during the watch period before the update is rolled back.
+	MAX_RESTART_COUNT = 3
+)
+
+// Phases of deploying or updating an app reported to Progress.
+const (
+	VALIDATING_PHASE = "validating"
+	PULLING_PHASE    = "pulling"
+	CREATING_PHASE   = "creating"
+	STARTING_PHASE   = "starting"
+	VERIFYING_PHASE  = "verifying"
+)
+
+// Progress receives the phase of deploying or updating an app,
+// progress of pulling images in it and warnings found on the way.
+type Progress interface {
+	SetPhase(phase string)
+	SetPullProgress(progress dockercontroller.PullProgress)
+	AddWarning(warning string)
+}
+
 type Command interface {
-	DeployApp(body string, query map[string]interface{}) (map[string]interface{}, error)
+	DeployApp(ctx context.Context, body string, query map[string]interface{}, progress Progress) (map[string]interface{}, error)
+	ValidateApp(body string) (map[string]interface{}, error)
 	Apps() (map[string]interface{}, error)
 	App(appId string) (map[string]interface{}, error)
 	UpdateAppInfo(appId string, body string) error
 	DeleteApp(appId string) error
 	StartApp(appId string) error
 	StopApp(appId string) error
+	PauseApp(appId string) error
+	UnpauseApp(appId string) error
+	StartService(appId string, service string) error
+	StopService(appId string, service string) error
+	RestartService(appId string, service string) error
+	ScaleService(appId string, service string, replicas int) error
 	HandleEvents(appId string, body string) error
-	UpdateApp(appId string, query map[string]interface{}) error
+	UpdatePolicy(appId string) (map[string]interface{}, error)
+	SetUpdatePolicy(appId string, body string) error
+	UpdateApp(ctx context.Context, appId string, query map[string]interface{}, progress Progress) error
+	Revisions(appId string) (map[string]interface{}, error)
+	Revision(appId string, revision string) (map[string]interface{}, error)
+	DiffRevisions(appId string, from string, to string) (map[string]interface{}, error)
+	RedeployRevision(ctx context.Context, appId string, revision string, progress Progress) error
+	AppLogs(ctx context.Context, appId string, options dockercontroller.LogOptions) (<-chan dockercontroller.LogEntry, error)
+	ExecService(ctx context.Context, appId string, service string, cmd []string, tty bool) (io.ReadWriteCloser, error)
 }
 
 type depExecutorImpl struct{}
@@ -84,28 +150,45 @@ type depExecutorImpl struct{}
 var Executor depExecutorImpl
 var dockerExecutor dockercontroller.Command
 var appsMonitor apps.Command
+var resourceMonitor resource.Command
+var imagePolicy imagepolicy.Command
 
 var fileMode = os.FileMode(0755)
 var dbExecutor service.Command
+var configDbExecutor configDB.Command
+var historyExecutor history.Command
+var watchTimeUnit = time.Second
 
 func init() {
 	dockerExecutor = dockercontroller.Executor
 	dbExecutor = service.Executor{}
+	configDbExecutor = configDB.Executor{}
+	historyExecutor = history.Executor{}
 	appsMonitor = apps.Executor{}
+	resourceMonitor = resource.Executor
+	imagePolicy = imagepolicy.Executor{}
 
 	restoreAllAppsState()
+	rebuildApps()
 }
 
 // Deploy app to target by yaml description.
 // yaml description will be inserted to db server
 // and docker images in the service list of yaml description will be downloaded
 // and create, start containers on the target.
+// the deployment is rejected if the target doesn't have enough resources for it,
+// unless force query parameter is true,
+// or if images of it are not allowed by the image policy.
+// the deployment is stopped when ctx is canceled, and its phases and
+// progress of pulling images are reported to progress if it is given.
 // if succeed to deploy, return app_id
 // otherwise, return error.
-func (executor depExecutorImpl) DeployApp(body string, query map[string]interface{}) (map[string]interface{}, error) {
+func (executor depExecutorImpl) DeployApp(ctx context.Context, body string, query map[string]interface{}, progress Progress) (map[string]interface{}, error) {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	reportPhase(progress, VALIDATING_PHASE)
+
 	var description interface{}
 	err := yaml.Unmarshal([]byte(body), &description)
 	if err != nil {
@@ -121,6 +204,9 @@ func (executor depExecutorImpl) DeployApp(body string, query map[string]interfac
 		return nil, errors.InvalidYaml{Msg: "invalid yaml syntax"}
 	}
 
+	// App rebuilt from containers after the database was recovered is
+	// deployed again by its original description, which has the same id.
+	recovered := false
 	data, err := dbExecutor.InsertComposeFile(string(jsonData), RUNNING_STATE)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -128,16 +214,34 @@ func (executor depExecutorImpl) DeployApp(body string, query map[string]interfac
 		default:
 			return nil, err
 		case errors.AlreadyReported:
-			deployedApp, err := executor.App(data[ID].(string))
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-				return nil, err
+			if data[STATE] != RECOVERED_STATE {
+				deployedApp, err := executor.App(data[ID].(string))
+				if err != nil {
+					logger.Logging(logger.ERROR, err.Error())
+					return nil, err
+				}
+				deployedApp[ID] = data[ID].(string)
+				return deployedApp, err
 			}
-			deployedApp[ID] = data[ID].(string)
-			return deployedApp, err
+			recovered = true
 		}
 	}
 
+	if !isForced(query) {
+		err = checkAdmission(description, progress)
+		if err != nil {
+			discardApp(data[ID].(string), recovered)
+			return nil, err
+		}
+	}
+
+	verified, err := imagePolicy.Verify(getServiceImages(jsonData))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		discardApp(data[ID].(string), recovered)
+		return nil, err
+	}
+
 	composeFile := genYamlFileName(data[ID].(string), "deploy")
 	err = ioutil.WriteFile(composeFile, []byte(body), fileMode)
 	if err != nil {
@@ -148,26 +252,32 @@ func (executor depExecutorImpl) DeployApp(body string, query map[string]interfac
 
 	err = appsMonitor.EnableEventMonitoring(data[ID].(string), composeFile)
 	if err != nil {
-		dbExecutor.DeleteApp(data[ID].(string))
+		discardApp(data[ID].(string), recovered)
 		return nil, err
 	}
 
-	if eventIds, exists := query[EVENTID]; exists {
-		err = dockerExecutor.UpWithEvent(data[ID].(string), composeFile, eventIds.([]string)[0], appsMonitor.GetEventChannel())
-	} else {
-		err = dockerExecutor.Up(data[ID].(string), composeFile, true)
-	}
-
+	err = deployContainers(ctx, data[ID].(string), composeFile, verified, query, progress)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
-		e := dockerExecutor.DownWithRemoveImages(data[ID].(string), composeFile)
-		if e != nil {
-			logger.Logging(logger.ERROR, e.Error())
+		if !recovered {
+			e := dockerExecutor.DownWithRemoveImages(data[ID].(string), composeFile)
+			if e != nil {
+				logger.Logging(logger.ERROR, e.Error())
+			}
 		}
-		dbExecutor.DeleteApp(data[ID].(string))
+		discardApp(data[ID].(string), recovered)
 		return nil, err
 	}
 
+	if recovered {
+		err = restoreRecoveredApp(data[ID].(string), string(jsonData))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reportPhase(progress, VERIFYING_PHASE)
+
 	deployedApp, err := executor.App(data[ID].(string))
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -175,9 +285,91 @@ func (executor depExecutorImpl) DeployApp(body string, query map[string]interfac
 	}
 	deployedApp[ID] = data[ID].(string)
 
+	recordRevision(data[ID].(string), string(jsonData), DEPLOY_OPERATION, nil)
+
 	return deployedApp, nil
 }
 
+// Deletes app of which deployment is failed.
+// app rebuilt after the database was recovered is kept as it is,
+// since its containers were running before the deployment.
+func discardApp(appId string, recovered bool) {
+	if !recovered {
+		dbExecutor.DeleteApp(appId)
+	}
+}
+
+// Replaces description of app rebuilt after the database was recovered
+// by the original one, and ends its recovered state.
+func restoreRecoveredApp(appId string, description string) error {
+	err := dbExecutor.UpdateAppInfo(appId, description)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	err = dbExecutor.UpdateAppState(appId, RUNNING_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Pulling missing images, creating and starting containers of app.
+// images verified by the image policy are pulled by their verified digests first.
+// containers of app deployed by an event are created and started together
+// to send events of them.
+func deployContainers(ctx context.Context, appId, composeFile string, verified map[string]string, query map[string]interface{}, progress Progress) error {
+	reportPhase(progress, PULLING_PHASE)
+	err := pullRepoDigests(verified)
+	if err != nil {
+		return err
+	}
+
+	err = dockerExecutor.PullMissing(ctx, appId, composeFile, pullProgress(progress))
+	if err != nil {
+		return err
+	}
+
+	if eventIds, exists := query[EVENTID]; exists {
+		reportPhase(progress, STARTING_PHASE)
+		return dockerExecutor.UpWithEvent(ctx, appId, composeFile, eventIds.([]string)[0], appsMonitor.GetEventChannel())
+	}
+
+	reportPhase(progress, CREATING_PHASE)
+	err = dockerExecutor.Create(ctx, appId, composeFile)
+	if err != nil {
+		return err
+	}
+
+	reportPhase(progress, STARTING_PHASE)
+	return dockerExecutor.Up(ctx, appId, composeFile, false)
+}
+
+// Reports phase to progress if it is given.
+func reportPhase(progress Progress, phase string) {
+	if progress != nil {
+		progress.SetPhase(phase)
+	}
+}
+
+func reportWarning(progress Progress, warning string) {
+	logger.Logging(logger.INFO, warning)
+	if progress != nil {
+		progress.AddWarning(warning)
+	}
+}
+
+// Returns the function reporting progress of pulling images to progress,
+// or nil if progress is not given.
+func pullProgress(progress Progress) func(dockercontroller.PullProgress) {
+	if progress == nil {
+		return nil
+	}
+	return progress.SetPullProgress
+}
+
 // Getting all of app informations in the target.
 // if succeed to get, return all of app informations as map
 // otherwise, return error.
@@ -267,6 +459,9 @@ func (depExecutorImpl) App(appId string) (map[string]interface{}, error) {
 	m[DESCRIPTION] = string(yaml)
 	m[SERVICES] = services
 	m[IMAGES] = app[IMAGES]
+	if app[REPLICAS] != nil {
+		m[REPLICAS] = app[REPLICAS]
+	}
 
 	return m, nil
 }
@@ -324,6 +519,13 @@ func (depExecutorImpl) StartApp(appId string) error {
 		return errors.AlreadyReported{Msg: state}
 	}
 
+	// containers removed while the app is stopped are created again on start.
+	err = verifyAndPullImages(getServiceImages([]byte(app[DESCRIPTION].(string))))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
 	composeFile, err := setYamlFile(appId, "start")
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -400,9 +602,106 @@ func (depExecutorImpl) StopApp(appId string) error {
 	return nil
 }
 
+// Pause app in the target by input appId.
+// processes in containers of the app are suspended
+// and resumed by UnpauseApp.
+// if succeed to pause, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) PauseApp(appId string) error {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	state := app["state"].(string)
+	if state == PAUSED_STATE {
+		return errors.AlreadyReported{Msg: state}
+	}
+	if state != RUNNING_STATE {
+		return errors.InvalidParam{Msg: "app is not running : " + state}
+	}
+
+	composeFile, err := setYamlFile(appId, "pause")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	defer os.RemoveAll(composeFile)
+
+	appsMonitor.LockUpdateAppState()
+	defer appsMonitor.UnlockUpdateAppState()
+
+	err = dockerExecutor.Pause(appId, composeFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		e := dockerExecutor.Unpause(appId, composeFile)
+		if e != nil {
+			logger.Logging(logger.ERROR, e.Error())
+		}
+		return err
+	}
+
+	err = dbExecutor.UpdateAppState(appId, PAUSED_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	return nil
+}
+
+// Unpause app in the target by input appId.
+// if succeed to unpause, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) UnpauseApp(appId string) error {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	state := app["state"].(string)
+	if state != PAUSED_STATE {
+		return errors.AlreadyReported{Msg: state}
+	}
+
+	composeFile, err := setYamlFile(appId, "unpause")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	defer os.RemoveAll(composeFile)
+
+	appsMonitor.LockUpdateAppState()
+	defer appsMonitor.UnlockUpdateAppState()
+
+	err = dockerExecutor.Unpause(appId, composeFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	err = dbExecutor.UpdateAppState(appId, RUNNING_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	return nil
+}
+
 // Handle app's event in the target by input appId.
 // Event information about the service of the app
 // is stored in repository information and tag information.
+// pushed images are applied in background if the update policy
+// of the app allows to apply them now.
 // if succeed to update, return error as nil
 // otherwise, return error.
 func (depExecutorImpl) HandleEvents(appId string, body string) error {
@@ -440,7 +739,18 @@ func (depExecutorImpl) HandleEvents(appId string, body string) error {
 		}
 	}
 
-	return err
+	// Pushed images are applied right away if the update policy allows.
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	policy := getUpdatePolicy(app)
+	if len(getPendingImages(app)) != 0 && isUpdateAllowed(policy, now()) {
+		scheduleUpdate(appId, policy)
+	}
+	return nil
 }
 
 // Update images and restart containers in the target
@@ -448,14 +758,22 @@ func (depExecutorImpl) HandleEvents(appId string, body string) error {
 // if you want to update images,
 // yaml should be updated as controller.UpdateAppInfo()
 // See also controller.UpdateAppInfo().
-// and if failed to update images,
+// and if failed to update images or updated services are not healthy
+// during the rollback watch period,
 // Pharos Node can make sure that previous images by digest.
+// the update is rejected before pulling images if new images are not allowed
+// by the image policy.
+// the update is stopped when ctx is canceled, and it is rolled back if
+// containers are already updated. its phases and progress of pulling images
+// are reported to progress if it is given.
 // if succeed to update, return error as nil
 // otherwise, return error.
-func (depExecutorImpl) UpdateApp(appId string, query map[string]interface{}) error {
+func (depExecutorImpl) UpdateApp(ctx context.Context, appId string, query map[string]interface{}, progress Progress) error {
 	logger.Logging(logger.DEBUG, "IN", appId)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	reportPhase(progress, VALIDATING_PHASE)
+
 	composeFile, err := setYamlFile(appId, "update")
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -469,8 +787,23 @@ func (depExecutorImpl) UpdateApp(appId string, query map[string]interface{}) err
 		return convertDBError(err, appId)
 	}
 
-	appsMonitor.LockUpdateAppState()
-	defer appsMonitor.UnlockUpdateAppState()
+	images, err := getUpdatedImages(app[DESCRIPTION].(string), query)
+	if err != nil {
+		logger.Logging(logger.DEBUG, err.Error())
+		return err
+	}
+
+	verified, err := imagePolicy.Verify(images)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	// state changes by events are blocked until containers are updated.
+	// the lock is released before watching updated services
+	// since the app in updating state is skipped by apps monitor.
+	unlock := lockUpdateAppState()
+	defer unlock()
 
 	err = dbExecutor.UpdateAppState(appId, UPDATING_STATE)
 	if err != nil {
@@ -478,78 +811,98 @@ func (depExecutorImpl) UpdateApp(appId string, query map[string]interface{}) err
 		return convertDBError(err, appId)
 	}
 
-	imageList, err := getImageNames([]byte(app[DESCRIPTION].(string)))
+	// From here, every failure goes through failUpdate not to leave
+	// the app in updating state. changed is set once containers of
+	// a service are updated, after which the app should be rolled back.
+	changed := false
+	repoDigests, err := getRepoDigests([]byte(app[DESCRIPTION].(string)))
 	if err != nil {
 		logger.Logging(logger.DEBUG, err.Error())
-		return err
+		return failUpdate(appId, app, repoDigests, changed, err)
 	}
 
-	repoDigests := make(map[string]string)
-	for _, image := range imageList {
-		repoDigest, err := dockerExecutor.GetImageDigestByName(image)
+	description := app[DESCRIPTION].(string)
+	operation := UPDATE_OPERATION
+	var updatedServices []string
+	if query == nil {
+		err = updateApp(ctx, appId, composeFile, app, repoDigests, verified, progress)
 		if err != nil {
 			logger.Logging(logger.DEBUG, err.Error())
-			return err
+			return failUpdate(appId, app, repoDigests, changed, err)
 		}
-		repoDigests[image] = repoDigest
-	}
-
-	if query == nil {
-		err = updateApp(appId, composeFile, app, repoDigests)
+		changed = true
+		updatedServices, err = getServiceNames([]byte(app[DESCRIPTION].(string)))
 		if err != nil {
 			logger.Logging(logger.DEBUG, err.Error())
-			return err
+			return failUpdate(appId, app, repoDigests, changed, err)
 		}
 	} else {
+		operation = EVENT_UPDATE_OPERATION
 		serviceName := ""
 		images := query[IMAGES].([]string)
 		updatedDescription := make(map[string]interface{})
+		serviceImages := getServiceImageMap([]byte(app[DESCRIPTION].(string)))
 
 		for _, imageName := range images {
 			tagExist, repo, tag, err := extractQueryInfo(imageName)
 			if err != nil {
 				logger.Logging(logger.DEBUG, err.Error())
-				return err
+				return failUpdate(appId, app, repoDigests, changed, err)
 			}
 			serviceName, err = getServiceName(repo, []byte(app[DESCRIPTION].(string)))
 			if err != nil {
 				logger.Logging(logger.DEBUG, err.Error())
-				return err
+				return failUpdate(appId, app, repoDigests, changed, err)
 			}
 			if tagExist {
 				updatedDescription, err = updateYamlFile(appId, composeFile, app[DESCRIPTION].(string), serviceName, repo+":"+tag)
 				if err != nil {
 					logger.Logging(logger.DEBUG, err.Error())
-					return err
+					return failUpdate(appId, app, repoDigests, changed, err)
 				}
+				serviceImages[serviceName] = repo + ":" + tag
 			}
-			err = updateService(appId, composeFile, app, repoDigests, serviceName)
+			err = updateService(ctx, appId, composeFile, app, repoDigests, verified, serviceImages, progress, serviceName)
 			if err != nil {
 				logger.Logging(logger.DEBUG, err.Error())
-				return err
+				return failUpdate(appId, app, repoDigests, changed, err)
 			}
+			changed = true
+			updatedServices = append(updatedServices, serviceName)
 			if tagExist {
 				jsonDescription, err := json.Marshal(convert(updatedDescription))
 				if err != nil {
 					logger.Logging(logger.ERROR, err.Error())
-					return errors.InvalidYaml{Msg: "invalid yaml syntax"}
+					return failUpdate(appId, app, repoDigests, changed, errors.InvalidYaml{Msg: "invalid yaml syntax"})
 				}
 
 				err = dbExecutor.UpdateAppInfo(appId, string(jsonDescription))
 				if err != nil {
 					logger.Logging(logger.ERROR, err.Error())
-					return convertDBError(err, appId)
+					return failUpdate(appId, app, repoDigests, changed, convertDBError(err, appId))
 				}
+				description = string(jsonDescription)
 			}
 		}
 	}
 
+	unlock()
+
+	reportPhase(progress, VERIFYING_PHASE)
+	err = watchUpdatedServices(ctx, appId, composeFile, updatedServices)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
 	err = dbExecutor.UpdateAppState(appId, RUNNING_STATE)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		return convertDBError(err, appId)
 	}
 
+	recordRevision(appId, description, operation, nil)
+
 	err = updateAppEvent(appId)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -604,10 +957,30 @@ func (depExecutorImpl) DeleteApp(appId string) error {
 		return convertDBError(err, appId)
 	}
 
+	err = historyExecutor.DeleteRevisions(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+
 	return nil
 }
 
 func restoreRepoDigests(appId, composeFile string, repoDigests map[string]string, state string) error {
+	err := pullRepoDigests(repoDigests)
+	if err != nil {
+		return err
+	}
+
+	err = restoreState(appId, composeFile, state, true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Pull images by repository digests and tag them with image names.
+func pullRepoDigests(repoDigests map[string]string) error {
 	for imageName, repoDigest := range repoDigests {
 		err := dockerExecutor.ImagePull(repoDigest)
 		if err != nil {
@@ -616,7 +989,6 @@ func restoreRepoDigests(appId, composeFile string, repoDigests map[string]string
 		}
 		imageID, err := dockerExecutor.GetImageIDByRepoDigest(repoDigest)
 		if err != nil {
-
 			logger.Logging(logger.ERROR, err.Error())
 			return err
 		}
@@ -626,17 +998,54 @@ func restoreRepoDigests(appId, composeFile string, repoDigests map[string]string
 			return err
 		}
 	}
+	return nil
+}
+
+// Verify images by the image policy before containers are created from them.
+// images whose digests are verified are pulled by the digests,
+// so that tags pushed again after the verification are not used.
+func verifyAndPullImages(images []string) error {
+	verified, err := imagePolicy.Verify(images)
+	if err != nil {
+		return err
+	}
+	return pullRepoDigests(verified)
+}
 
-	err := restoreState(appId, composeFile, state, true)
+// Pull images of services to update them.
+// images verified by the image policy are pulled by their verified digests
+// and images of the other services are pulled by docker-compose.
+func pullUpdatedImages(ctx context.Context, appId, composeFile string, verified, serviceImages map[string]string, progress Progress, services ...string) error {
+	if len(verified) == 0 {
+		return dockerExecutor.Pull(ctx, appId, composeFile, pullProgress(progress), services...)
+	}
+
+	err := pullRepoDigests(verified)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
 		return err
 	}
-	return nil
+
+	if len(services) == 0 {
+		for service := range serviceImages {
+			services = append(services, service)
+		}
+	}
+	unverified := make([]string, 0)
+	for _, service := range services {
+		if _, exists := verified[serviceImages[service]]; !exists {
+			unverified = append(unverified, service)
+		}
+	}
+	if len(unverified) == 0 {
+		return nil
+	}
+	return dockerExecutor.Pull(ctx, appId, composeFile, pullProgress(progress), unverified...)
 }
 
-func updateApp(appId, composeFile string, app map[string]interface{}, repoDigests map[string]string) error {
-	err := dockerExecutor.Pull(appId, composeFile)
+func updateApp(ctx context.Context, appId, composeFile string, app map[string]interface{}, repoDigests, verified map[string]string, progress Progress) error {
+	reportPhase(progress, PULLING_PHASE)
+	serviceImages := getServiceImageMap([]byte(app[DESCRIPTION].(string)))
+	err := pullUpdatedImages(ctx, appId, composeFile, verified, serviceImages, progress)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		e := restoreRepoDigests(appId, composeFile, repoDigests, app[STATE].(string))
@@ -645,7 +1054,9 @@ func updateApp(appId, composeFile string, app map[string]interface{}, repoDigest
 		}
 		return err
 	}
-	err = dockerExecutor.Up(appId, composeFile, true)
+
+	reportPhase(progress, STARTING_PHASE)
+	err = dockerExecutor.Up(ctx, appId, composeFile, true)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		e := restoreRepoDigests(appId, composeFile, repoDigests, app[STATE].(string))
@@ -657,8 +1068,9 @@ func updateApp(appId, composeFile string, app map[string]interface{}, repoDigest
 	return err
 }
 
-func updateService(appId, composeFile string, app map[string]interface{}, repoDigests map[string]string, services ...string) error {
-	err := dockerExecutor.Pull(appId, composeFile, services...)
+func updateService(ctx context.Context, appId, composeFile string, app map[string]interface{}, repoDigests, verified, serviceImages map[string]string, progress Progress, services ...string) error {
+	reportPhase(progress, PULLING_PHASE)
+	err := pullUpdatedImages(ctx, appId, composeFile, verified, serviceImages, progress, services...)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		e := restoreRepoDigests(appId, composeFile, repoDigests, app[STATE].(string))
@@ -667,7 +1079,9 @@ func updateService(appId, composeFile string, app map[string]interface{}, repoDi
 		}
 		return err
 	}
-	err = dockerExecutor.Up(appId, composeFile, true, services...)
+
+	reportPhase(progress, STARTING_PHASE)
+	err = dockerExecutor.Up(ctx, appId, composeFile, true, services...)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		e := restoreRepoDigests(appId, composeFile, repoDigests, app[STATE].(string))
@@ -679,6 +1093,166 @@ func updateService(appId, composeFile string, app map[string]interface{}, repoDi
 	return err
 }
 
+// Watch updated services during the rollback watch period.
+// The state of services is checked once per watch time unit,
+// and a service is regarded as failed if it exits with non-zero exit code,
+// restarts repeatedly or fails its healthcheck.
+// watching is stopped with error when ctx is canceled.
+// if all of services keep healthy, return error as nil
+// otherwise, return error.
+func watchUpdatedServices(ctx context.Context, appId, composeFile string, services []string) error {
+	period := getRollbackWatchPeriod()
+	if period <= 0 || len(services) == 0 {
+		return nil
+	}
+
+	restartCounts := make(map[string]int)
+	for i := 0; i <= period; i++ {
+		for _, serviceName := range services {
+			// Every replica of the service should keep healthy.
+			configs, err := getServiceContainerStates(appId, composeFile, serviceName)
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+				return errors.Unknown{Msg: "can't get state of service : " + serviceName}
+			}
+
+			for containerName, config := range configs {
+				restartCount, _ := config[RESTART_COUNT].(int)
+				if _, exists := restartCounts[containerName]; !exists {
+					restartCounts[containerName] = restartCount
+				}
+
+				switch {
+				case config[STATUS] == DEAD_STATE,
+					config[STATUS] == EXITED_STATE && config[EXIT_CODE] != "0":
+					return errors.Unknown{Msg: "service exited : " + serviceName}
+				case restartCount-restartCounts[containerName] >= MAX_RESTART_COUNT:
+					return errors.Unknown{Msg: "service restarts repeatedly : " + serviceName}
+				case config[HEALTH] == UNHEALTHY:
+					return errors.Unknown{Msg: "service failed healthcheck : " + serviceName}
+				}
+			}
+		}
+
+		if i < period {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(watchTimeUnit):
+			}
+		}
+	}
+	return nil
+}
+
+// Roll back app to the description and images before update.
+// Saved digests are retagged and old containers are recreated,
+// and then app state is marked as rolledback.
+// if succeed to roll back, return error as nil
+// otherwise, return error.
+func rollbackApp(appId string, app map[string]interface{}, repoDigests map[string]string) error {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	err := dbExecutor.UpdateAppInfo(appId, app[DESCRIPTION].(string))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	composeFile, err := setYamlFile(appId, "rollback")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	defer os.RemoveAll(composeFile)
+
+	err = restoreRepoDigests(appId, composeFile, repoDigests, RUNNING_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	err = dbExecutor.UpdateAppState(appId, ROLLEDBACK_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	recordRevision(appId, app[DESCRIPTION].(string), ROLLBACK_OPERATION, repoDigests)
+	return nil
+}
+
+// Rollback app and mark it as rollback failed if previous images can't be
+// restored, so that the app doesn't stay in updating state.
+// if succeed to rollback, return error as nil
+// otherwise, return error.
+func rollbackOrFail(appId string, app map[string]interface{}, repoDigests map[string]string) error {
+	err := rollbackApp(appId, app, repoDigests)
+	if err != nil {
+		e := dbExecutor.UpdateAppState(appId, ROLLBACK_FAILED_STATE)
+		if e != nil {
+			logger.Logging(logger.ERROR, e.Error())
+		}
+	}
+	return err
+}
+
+// Handle failure of update after app is marked as updating, so that the app
+// doesn't stay in updating state which is skipped by apps monitor.
+// if containers are changed, app is rolled back and RolledBack error is
+// returned unless the rollback fails, otherwise previous state is restored
+// and err is returned.
+func failUpdate(appId string, app map[string]interface{}, repoDigests map[string]string, changed bool, err error) error {
+	if changed {
+		e := rollbackOrFail(appId, app, repoDigests)
+		if e != nil {
+			logger.Logging(logger.ERROR, e.Error())
+			return e
+		}
+		return errors.RolledBack{Msg: err.Error()}
+	}
+
+	e := dbExecutor.UpdateAppState(appId, app[STATE].(string))
+	if e != nil {
+		logger.Logging(logger.ERROR, e.Error())
+	}
+	return err
+}
+
+// Lock state changes of apps by events, and return the function
+// unlocking it which can be called more than once.
+func lockUpdateAppState() func() {
+	appsMonitor.LockUpdateAppState()
+
+	var once sync.Once
+	return func() {
+		once.Do(appsMonitor.UnlockUpdateAppState)
+	}
+}
+
+// Get rollback watch period from configuration.
+// if the property is not found or invalid, return default period.
+func getRollbackWatchPeriod() int {
+	prop, err := configDbExecutor.GetProperty(ROLLBACK_WATCH_PERIOD)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return DEFAULT_ROLLBACK_WATCH_PERIOD
+	}
+
+	value, ok := prop[VALUE].(string)
+	if !ok {
+		return DEFAULT_ROLLBACK_WATCH_PERIOD
+	}
+
+	period, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return DEFAULT_ROLLBACK_WATCH_PERIOD
+	}
+	return period
+}
+
 // Restore app state by previous state.
 // See also controller.StartApp(), controller.StopApp()
 // if succeed to restore, return error as nil
@@ -703,7 +1277,7 @@ func restoreState(appId, composeFile, state string, forceRecreate bool) error {
 			logger.Logging(logger.ERROR, err.Error())
 		}
 	case RUNNING_STATE:
-		err = dockerExecutor.Up(appId, composeFile, forceRecreate)
+		err = dockerExecutor.Up(context.Background(), appId, composeFile, forceRecreate)
 		if err != nil {
 			if strings.Contains(err.Error(), "already exists in network") && forceRecreate == false {
 				logger.Logging(logger.INFO, "It occurs when a service is already restarted by itself and expected result")
@@ -716,6 +1290,26 @@ func restoreState(appId, composeFile, state string, forceRecreate bool) error {
 		if err != nil {
 			logger.Logging(logger.ERROR, err.Error())
 		}
+	case PAUSED_STATE:
+		// containers are not paused any more after docker daemon is restarted.
+		err = dockerExecutor.Up(context.Background(), appId, composeFile, forceRecreate)
+		if err != nil {
+			if strings.Contains(err.Error(), "already exists in network") && forceRecreate == false {
+				logger.Logging(logger.INFO, "It occurs when a service is already restarted by itself and expected result")
+			} else {
+				logger.Logging(logger.ERROR, err.Error())
+				return err
+			}
+		}
+		err = dockerExecutor.Pause(appId, composeFile)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return err
+		}
+		err = dbExecutor.UpdateAppState(appId, PAUSED_STATE)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
 	}
 
 	return err
@@ -730,8 +1324,15 @@ func setYamlFile(appId, api string) (string, error) {
 	if err != nil {
 		return "", convertDBError(err, appId)
 	}
+	return writeYamlFile(appId, api, app[DESCRIPTION].(string))
+}
+
+// Write YAML file of the description which is not stored yet.
+// if writing YAML is succeeded, return the path of the file
+// otherwise, return error.
+func writeYamlFile(appId, api, desc string) (string, error) {
 	description := make(map[string]interface{})
-	err = json.Unmarshal([]byte(app[DESCRIPTION].(string)), &description)
+	err := json.Unmarshal([]byte(desc), &description)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
 		return "", errors.IOError{"json unmarshal fail"}
@@ -803,6 +1404,29 @@ func getServiceState(appId, composeFile, serviceName string) (map[string]interfa
 	return serviceInfo, nil
 }
 
+// Get states of all containers of the service, keyed by container name.
+func getServiceContainerStates(appId, composeFile, serviceName string) (map[string]map[string]interface{}, error) {
+	infos, err := dockerExecutor.Ps(appId, composeFile, serviceName)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	if len(infos) == 0 {
+		logger.Logging(logger.ERROR, "no information about service")
+		return nil, errors.Unknown{Msg: "no information about service"}
+	}
+
+	configs := make(map[string]map[string]interface{})
+	for _, info := range infos {
+		config, err := dockerExecutor.GetContainerConfigByName(info["Name"])
+		if err != nil {
+			return nil, err
+		}
+		configs[info["Name"]] = config
+	}
+	return configs, nil
+}
+
 func convertDBError(err error, appId string) error {
 	switch err.(type) {
 	case errors.NotFound:
@@ -845,6 +1469,8 @@ func updatedDockerImageFromRegistry(appId string, imageInfo map[string]interface
 		return convertDBError(err, appId)
 	}
 
+	// a new image is pushed, so pending changes failed before are applied again.
+	clearFailedUpdate(appId)
 	return nil
 }
 
@@ -961,6 +1587,82 @@ func getImageNames(desc []byte) ([]string, error) {
 	return imageList, nil
 }
 
+// Get images of services which have them in a description.
+func getServiceImages(desc []byte) []string {
+	images := make([]string, 0)
+	for _, image := range getServiceImageMap(desc) {
+		images = append(images, image)
+	}
+	return images
+}
+
+// Get a map of service name to its image in a description.
+func getServiceImageMap(desc []byte) map[string]string {
+	images := make(map[string]string)
+	description := make(map[string]interface{})
+	if json.Unmarshal(desc, &description) != nil {
+		return images
+	}
+
+	services, _ := description[SERVICES].(map[string]interface{})
+	for serviceName, serviceInfo := range services {
+		info, _ := serviceInfo.(map[string]interface{})
+		if image, ok := info[IMAGE].(string); ok {
+			images[serviceName] = image
+		}
+	}
+	return images
+}
+
+// Get images which will be pulled by UpdateApp.
+// without query, they are all of images in the description,
+// otherwise, images of the query with tag or current images of services
+// using repositories of the query without tag.
+func getUpdatedImages(description string, query map[string]interface{}) ([]string, error) {
+	if query == nil {
+		return getServiceImages([]byte(description)), nil
+	}
+
+	serviceImages := getServiceImageMap([]byte(description))
+	images := make([]string, 0)
+	for _, imageName := range query[IMAGES].([]string) {
+		tagExist, repo, tag, err := extractQueryInfo(imageName)
+		if err != nil {
+			return nil, err
+		}
+		if tagExist {
+			images = append(images, repo+":"+tag)
+			continue
+		}
+		serviceName, err := getServiceName(repo, []byte(description))
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, serviceImages[serviceName])
+	}
+	return images, nil
+}
+
+// Get a service name list shown in app[DESCRIPTION]
+// If getting a service name list is succeeded, return a service name list.
+// otherwise, return error.
+func getServiceNames(desc []byte) ([]string, error) {
+	description := make(map[string]interface{})
+	err := json.Unmarshal(desc, &description)
+	if err != nil {
+		return nil, errors.IOError{Msg: "json unmarshal fail"}
+	}
+	if description[SERVICES] == nil {
+		return nil, errors.Unknown{Msg: "No service in YAML description"}
+	}
+
+	serviceList := make([]string, 0)
+	for serviceName := range description[SERVICES].(map[string]interface{}) {
+		serviceList = append(serviceList, serviceName)
+	}
+	return serviceList, nil
+}
+
 func updateAppEvent(appId string) error {
 	app, err := dbExecutor.GetApp(appId)
 	if err != nil {
@@ -1008,6 +1710,12 @@ func restoreAllAppsState() {
 	for _, app := range apps {
 		appId := app[ID].(string)
 
+		// Descriptions of rebuilt apps only have images of services,
+		// by which their containers can't be recreated.
+		if app[STATE] == RECOVERED_STATE {
+			continue
+		}
+
 		composeFile, err := setYamlFile(appId, "restoreAllAppsState")
 		if err != nil {
 			logger.Logging(logger.ERROR, err.Error())
@@ -1022,7 +1730,10 @@ func restoreAllAppsState() {
 		}
 
 		state := app["state"].(string)
-		restoreState(appId, composeFile, state, false)
+		err = restoreState(appId, composeFile, state, false)
+		if err == nil && state == RUNNING_STATE {
+			restoreReplicas(appId, composeFile, app)
+		}
 	}
 }
 
@@ -18,13 +18,20 @@ package deployment
 
 import (
 	"commons/errors"
+	"controller/dockercontroller"
 	dockermocks "controller/dockercontroller/mocks"
+	policymocks "controller/imagepolicy/mocks"
 	appmocks "controller/monitoring/apps/mocks"
+	resourcemocks "controller/monitoring/resource/mocks"
+	configmocks "db/bolt/configuration/mocks"
+	historymocks "db/bolt/history/mocks"
 	dbmocks "db/bolt/service/mocks"
 	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 const (
@@ -70,6 +77,29 @@ var (
 		"exitcode": EXIT_CODE_VALUE,
 	}
 
+	UNHEALTHY_INSPECT_RETURN_MSG = map[string]interface{}{
+		"cid":          CONTAINER_ID,
+		"ports":        SERVICE_PORT,
+		"status":       SERVICE_STATUS,
+		"exitcode":     EXIT_CODE_VALUE,
+		"restartcount": 0,
+		"health":       UNHEALTHY,
+	}
+
+	DISABLED_WATCH_PERIOD_PROP = map[string]interface{}{
+		"name":  ROLLBACK_WATCH_PERIOD,
+		"value": "0",
+	}
+
+	WATCH_PERIOD_PROP = map[string]interface{}{
+		"name":  ROLLBACK_WATCH_PERIOD,
+		"value": "1",
+	}
+
+	REPO_DIGESTS = map[string]string{
+		REPOSITORY_WITH_PORT_IMAGE_WITH_TAG: REPODIGEST,
+	}
+
 	PS_EXPECT_RETURN = []map[string]string{
 		{
 			"Name": CONTAINER,
@@ -91,6 +121,12 @@ var (
 		},
 	}
 
+	DB_GET_APP_WITH_PAUSED_STATE_OBJ = map[string]interface{}{
+		"id":          APP_ID,
+		"state":       PAUSED_STATE,
+		"description": ORIGIN_DESCRIPTION_JSON,
+	}
+
 	DB_GET_APP_UPDATING_OBJ = map[string]interface{}{
 		"id":          APP_ID,
 		"state":       UPDATING_STATE,
@@ -211,23 +247,36 @@ func TestCalledDeployApp_ExpectSuccess(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE_NAME).Return(PS_EXPECT_RETURN, nil),
 		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(gomock.Any()).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, DESCRIPTION_JSON, DEPLOY_OPERATION, REPO_DIGESTS).Return(nil, nil),
 	)
 
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	res, err := Executor.DeployApp(DESCRIPTION_YAML, nil)
+	res, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -276,24 +325,36 @@ func TestCalledDeployAppWithEventQuery_ExpectSuccess(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 		appExecutorMockObj.EXPECT().GetEventChannel().Return(nil),
-		dockerExecutorMockObj.EXPECT().UpWithEvent(gomock.Any(), gomock.Any(), testEventID, nil).Return(nil),
+		dockerExecutorMockObj.EXPECT().UpWithEvent(gomock.Any(), gomock.Any(), gomock.Any(), testEventID, nil).Return(nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE_NAME).Return(PS_EXPECT_RETURN, nil),
 		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(gomock.Any()).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, DESCRIPTION_JSON, DEPLOY_OPERATION, REPO_DIGESTS).Return(nil, nil),
 	)
 
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	res, err := Executor.DeployApp(DESCRIPTION_YAML, testQuery)
+	res, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, testQuery, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -351,7 +412,7 @@ func TestCalledDeployAppWhenAlreadyInstalled_ExpectSuccess(t *testing.T) {
 	dbExecutor = dbExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	res, err := Executor.DeployApp(DESCRIPTION_YAML, nil)
+	res, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -389,16 +450,112 @@ func TestCalledDeployAppWhenAlreadyInstalled_ExpectSuccess(t *testing.T) {
 	os.RemoveAll(COMPOSE_FILE)
 }
 
+func TestCalledDeployAppWhenRecoveredAppInstalled_ExpectRedeployed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	recoveredApp := map[string]interface{}{
+		"id":          APP_ID,
+		"state":       RECOVERED_STATE,
+		"description": "{\"services\":{\"test_service\":{\"image\":\"test_image\"}},\"version\":\"2\"}",
+	}
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(recoveredApp, errors.AlreadyReported{}),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE_NAME).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(gomock.Any()).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, DESCRIPTION_JSON, DEPLOY_OPERATION, REPO_DIGESTS).Return(nil, nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
+func TestCalledDeployAppWhenRecoveredAppFailedToDeploy_ExpectAppKept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	recoveredApp := map[string]interface{}{
+		"id":    APP_ID,
+		"state": RECOVERED_STATE,
+	}
+
+	// neither DownWithRemoveImages nor DeleteApp is expected to be called.
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(recoveredApp, errors.AlreadyReported{}),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(NotFoundError),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, map[string]interface{}{"force": []string{"true"}}, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFoundError", err)
+	case errors.NotFound:
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
 func TestCalledDeployAppWhenFailedToSetEventChannelFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(UnknownError),
 		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
 	)
@@ -406,9 +563,11 @@ func TestCalledDeployAppWhenFailedToSetEventChannelFailed_ExpectErrorReturn(t *t
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
 
-	_, err := Executor.DeployApp(DESCRIPTION_YAML, nil)
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknowError", "nil")
@@ -423,12 +582,19 @@ func TestCalledDeployAppWhenComposeUpFailed_ExpectErrorReturn(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().DownWithRemoveImages(gomock.Any(), gomock.Any()).Return(nil),
 		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
 	)
@@ -436,9 +602,11 @@ func TestCalledDeployAppWhenComposeUpFailed_ExpectErrorReturn(t *testing.T) {
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
 
-	_, err := Executor.DeployApp(DESCRIPTION_YAML, nil)
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknowError", "nil")
@@ -447,8 +615,142 @@ func TestCalledDeployAppWhenComposeUpFailed_ExpectErrorReturn(t *testing.T) {
 	os.RemoveAll(COMPOSE_FILE)
 }
 
+type phaseRecorder struct {
+	phases   []string
+	warnings []string
+}
+
+func (recorder *phaseRecorder) SetPhase(phase string) {
+	recorder.phases = append(recorder.phases, phase)
+}
+
+func (recorder *phaseRecorder) SetPullProgress(progress dockercontroller.PullProgress) {}
+
+func (recorder *phaseRecorder) AddWarning(warning string) {
+	recorder.warnings = append(recorder.warnings, warning)
+}
+
+func TestCalledDeployAppWithProgress_ExpectPhasesReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), APP_ID, gomock.Any(), gomock.Not(gomock.Nil())).Return(nil),
+		dockerExecutorMockObj.EXPECT().Create(gomock.Any(), APP_ID, gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), false).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE_NAME).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(gomock.Any()).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, DESCRIPTION_JSON, DEPLOY_OPERATION, REPO_DIGESTS).Return(nil, nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	recorder := &phaseRecorder{}
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, recorder)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := []string{VALIDATING_PHASE, PULLING_PHASE, CREATING_PHASE, STARTING_PHASE, VERIFYING_PHASE}
+	if !reflect.DeepEqual(expected, recorder.phases) {
+		t.Errorf("Expected phases: %v, actual phases: %v", expected, recorder.phases)
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
+func TestCalledDeployAppWhenPullMissingFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().EnableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().PullMissing(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(context.Canceled),
+		dockerExecutorMockObj.EXPECT().DownWithRemoveImages(gomock.Any(), gomock.Any()).Return(nil),
+		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
+
+	if err != context.Canceled {
+		t.Errorf("Expected err: %v, actual err: %v", context.Canceled, err)
+	}
+
+	os.RemoveAll(COMPOSE_FILE)
+}
+
+func TestCalledDeployAppWhenImagePolicyViolated_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	resourceExecutorMockObj := resourcemocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().InsertComposeFile(DESCRIPTION_JSON, RUNNING_STATE).Return(DB_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().EstimateImageSize(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(int64(0), nil),
+		resourceExecutorMockObj.EXPECT().GetCapacity().Return(CAPACITY, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(nil, errors.Forbidden{}),
+		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	resourceMonitor = resourceExecutorMockObj
+
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
 func TestCalledDeployAppWhenYAMLToJSONFailed_ExpectErrorReturn(t *testing.T) {
-	_, err := Executor.DeployApp(WRONG_DESCRIPTION_JSON, nil)
+	_, err := Executor.DeployApp(context.Background(), WRONG_DESCRIPTION_JSON, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "InvalidYAMLError", "nil")
@@ -470,7 +772,7 @@ func TestCalledDeployAppWhenInsertComposeFileFailed_ExpectErrorReturn(t *testing
 	// pass mockObj to a real object.
 	dbExecutor = dbExecutorMockObj
 
-	_, err := Executor.DeployApp(DESCRIPTION_YAML, nil)
+	_, err := Executor.DeployApp(context.Background(), DESCRIPTION_YAML, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "InsertComposeFileFailed", "nil")
@@ -670,6 +972,7 @@ func TestCalledHandleEventsWithUpdateEvent_ExpectSuccess(t *testing.T) {
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, EVENT_REPOSITORY, EVENT_TAG, UPDATE).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_PAUSED_STATE_OBJ, nil),
 	)
 
 	dbExecutor = dbExecutorMockObj
@@ -689,6 +992,7 @@ func TestCalledHandleEventsWithDeleteEvent_ExpectSuccess(t *testing.T) {
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, EVENT_REPOSITORY, EVENT_TAG, DELETE).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_PAUSED_STATE_OBJ, nil),
 	)
 
 	dbExecutor = dbExecutorMockObj
@@ -766,10 +1070,12 @@ func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dockerExecutorMockObj.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil),
@@ -780,6 +1086,7 @@ func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
 	err := Executor.StartApp(APP_ID)
@@ -839,10 +1146,12 @@ func TestCalledStartAppWhenComposeStartFailed_ExpectErrorReturn(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 
@@ -855,6 +1164,7 @@ func TestCalledStartAppWhenComposeStartFailed_ExpectErrorReturn(t *testing.T) {
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
 	err := Executor.StartApp(APP_ID)
@@ -870,10 +1180,12 @@ func TestStartAppWhenUpdateAppStateFailed_ExpectReturnError(t *testing.T) {
 
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dockerExecutorMockObj.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil),
@@ -884,6 +1196,7 @@ func TestStartAppWhenUpdateAppStateFailed_ExpectReturnError(t *testing.T) {
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
 	err := Executor.StartApp(APP_ID)
@@ -895,6 +1208,66 @@ func TestStartAppWhenUpdateAppStateFailed_ExpectReturnError(t *testing.T) {
 	}
 }
 
+func TestStartAppWhenImageNotAllowed_ExpectForbiddenReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(nil, errors.Forbidden{}),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+
+	err := Executor.StartApp(APP_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestStartAppWithVerifiedImage_ExpectImagePulledByVerifiedDigest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(REPO_DIGESTS, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.StartApp(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
 func TestCalledStopApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -984,7 +1357,7 @@ func TestCalledStopAppWhenComposeStopFailed_ExpectErrorReturn(t *testing.T) {
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dockerExecutorMockObj.EXPECT().Stop(gomock.Any(), gomock.Any()).Return(UnknownError),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
@@ -1008,18 +1381,21 @@ func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dockerExecutorMockObj.EXPECT().DownWithRemoveImages(gomock.Any(), gomock.Any()).Return(nil),
 		appExecutorMockObj.EXPECT().DisableEventMonitoring(gomock.Any(), gomock.Any()).Return(nil),
 		dbExecutorMockObj.EXPECT().DeleteApp(gomock.Any()).Return(nil),
+		historyExecutorMockObj.EXPECT().DeleteRevisions(APP_ID).Return(nil),
 	)
 
 	// pass mockObj to a real object.
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
 	err := Executor.DeleteApp(APP_ID)
 
@@ -1058,7 +1434,7 @@ func TestCalledDeleteAppWhenComposeDeleteFailed_ExpectErrorReturn(t *testing.T)
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_OBJ, nil),
 		dockerExecutorMockObj.EXPECT().DownWithRemoveImages(gomock.Any(), gomock.Any()).Return(UnknownError),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_OBJ, nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1132,134 +1508,253 @@ func TestUpdateAppWithoutQuery_ExpectSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, UPDATE_OPERATION, REPO_DIGESTS).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATED_OBJ, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestUpdateAppWithoutQueryWhenUpdateAppStateToupdatingFailed_ExpectReturnError(t *testing.T) {
+func TestUpdateAppWithVerifiedImages_ExpectImagesPulledByVerifiedDigests(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(REPO_DIGESTS, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
-		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(UnknownError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, UPDATE_OPERATION, REPO_DIGESTS).Return(nil, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATED_OBJ, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
 	)
 
+	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestUpdateAppWithoutQueryWhenGetAppFailed_ExpectReturnError(t *testing.T) {
+func TestUpdateAppWithoutQueryWhenUpdateAppStateToupdatingFailed_ExpectReturnError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, UnknownError),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(UnknownError),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
 	}
 }
 
-func TestUpdateAppWithoutQueryWhenGetImageDigestByNameFailed_ExpectReturnError(t *testing.T) {
+func TestUpdateAppWithoutQueryWhenGetAppFailed_ExpectReturnError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
-	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
-	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
-		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
-		appExecutorMockObj.EXPECT().LockUpdateAppState(),
-		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
-		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return("", UnknownError),
-		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, UnknownError),
 	)
 
 	dbExecutor = dbExecutorMockObj
-	dockerExecutor = dockerExecutorMockObj
-	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
 	}
 }
 
-func TestUpdateAppWithoutQueryWhenPullFailed_ExpectReturnError(t *testing.T) {
+func TestUpdateAppWhenImagePolicyViolated_ExpectReturnErrorBeforeUpdating(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	QUERY := map[string]interface{}{
+		"images": []string{FULL_IMAGE_NAME},
+	}
+
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
-	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
-	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{FULL_IMAGE_NAME}).Return(nil, errors.Forbidden{}),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, QUERY, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestGetUpdatedImages(t *testing.T) {
+	tests := map[string]struct {
+		query    map[string]interface{}
+		expected []string
+	}{
+		"WithoutQuery":    {nil, []string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}},
+		"QueryWithTag":    {map[string]interface{}{"images": []string{REPOSITORY_WITH_PORT_IMAGE + ":" + NEW_TAG}}, []string{REPOSITORY_WITH_PORT_IMAGE + ":" + NEW_TAG}},
+		"QueryWithoutTag": {map[string]interface{}{"images": []string{REPOSITORY_WITH_PORT_IMAGE}}, []string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := getUpdatedImages(DESCRIPTION_JSON, test.query)
+
+			if err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if !reflect.DeepEqual(test.expected, res) {
+				t.Errorf("Expected res: %v, actual res: %v", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestUpdateAppWithoutQueryWhenGetImageDigestByNameFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return("", UnknownError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
+	}
+}
+
+func TestUpdateAppWithoutQueryWhenPullFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
@@ -1271,25 +1766,29 @@ func TestUpdateAppWithoutQueryWhenPullAndImagePullFailed_ExpectReturnError(t *te
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(NotFoundError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	switch err.(type) {
 	default:
@@ -1303,26 +1802,30 @@ func TestUpdateAppWithoutQueryWhenPullAndGetImageIDByRepoDigestFailed_ExpectRetu
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return("", NotFoundError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	switch err.(type) {
 	default:
@@ -1336,27 +1839,31 @@ func TestUpdateAppWithoutQueryWhenPullAndImageTagFailed_ExpectReturnError(t *tes
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(NotFoundError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	switch err.(type) {
 	default:
@@ -1370,28 +1877,32 @@ func TestUpdateAppWithoutQueryWhenPullAndUpFailed_ExpectReturnError(t *testing.T
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, gomock.Any(), true).Return(NotFoundError),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(NotFoundError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	switch err.(type) {
 	default:
@@ -1405,30 +1916,34 @@ func TestUpdateAppWithoutQueryWhenUpFailed_ExpectReturnError(t *testing.T) {
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
@@ -1440,26 +1955,32 @@ func TestUpdateAppWithoutQueryWhenUpdateAppStateTorunningFailed_ExpectReturnErro
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATING_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATING_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
-		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(UnknownError),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
@@ -1471,34 +1992,271 @@ func TestUpdateAppWithoutQueryWhenUpdateAppEventFailed_ExpectReturnError(t *test
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, UPDATE_OPERATION, REPO_DIGESTS).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATED_OBJ, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, REPOSITORY_WITH_PORT_IMAGE, NEW_TAG, NONE_EVENT).Return(UnknownError),
-		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, nil)
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
 	}
 }
 
+func TestUpdateAppWithoutQueryWhenServicesKeepHealthy_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(WATCH_PERIOD_PROP, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(INSPECT_RETURN_MSG, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, UPDATE_OPERATION, REPO_DIGESTS).Return(nil, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATED_OBJ, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+	watchTimeUnit = time.Millisecond
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestUpdateAppWithoutQueryWhenServiceUnhealthy_ExpectRolledBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(WATCH_PERIOD_PROP, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(UNHEALTHY_INSPECT_RETURN_MSG, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, ORIGIN_DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, ROLLEDBACK_STATE).Return(nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, ROLLBACK_OPERATION, REPO_DIGESTS).Return(nil, nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+	watchTimeUnit = time.Millisecond
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: RolledBack, actual err: %v", err)
+	case errors.RolledBack:
+	}
+}
+
+func TestCalledWatchUpdatedServicesWhenOneOfReplicasUnhealthy_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	replicas := []map[string]string{{"Name": CONTAINER}, {"Name": CONTAINER_NAME}}
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(WATCH_PERIOD_PROP, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(replicas, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(INSPECT_RETURN_MSG, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER_NAME).Return(UNHEALTHY_INSPECT_RETURN_MSG, nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	watchTimeUnit = time.Millisecond
+
+	err := watchUpdatedServices(context.Background(), APP_ID, COMPOSE_FILE, []string{SERVICE})
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "Unknown", "nil")
+	}
+}
+
+func TestUpdateAppWithoutQueryWhenRollbackFailed_ExpectRollbackFailedState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(WATCH_PERIOD_PROP, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(UNHEALTHY_INSPECT_RETURN_MSG, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, ORIGIN_DESCRIPTION_JSON).Return(ConnectionError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, ROLLBACK_FAILED_STATE).Return(nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+	watchTimeUnit = time.Millisecond
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, nil, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: Unknown, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestUpdateAppWithoutQueryWhenCanceledWhileWatching_ExpectRolledBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(WATCH_PERIOD_PROP, nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXPECT_RETURN, nil),
+		dockerExecutorMockObj.EXPECT().GetContainerConfigByName(CONTAINER).Return(INSPECT_RETURN_MSG, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, ORIGIN_DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, ROLLEDBACK_STATE).Return(nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, ROLLBACK_OPERATION, REPO_DIGESTS).Return(nil, nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+	watchTimeUnit = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Executor.UpdateApp(ctx, APP_ID, nil, nil)
+	watchTimeUnit = time.Millisecond
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: RolledBack, actual err: %v", err)
+	case errors.RolledBack:
+	}
+}
+
 func TestUpdateAppWithQueryWithTag_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1509,36 +2267,82 @@ func TestUpdateAppWithQueryWithTag_ExpectSuccess(t *testing.T) {
 	}
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true, gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true, gomock.Any()).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, UPDATED_DESCRIPTION_JSON).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(FULL_IMAGE_NAME).Return(REPODIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, UPDATED_DESCRIPTION_JSON, EVENT_UPDATE_OPERATION, gomock.Any()).Return(nil, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_UPDATED_OBJ, nil),
 		dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, REPOSITORY_WITH_PORT_IMAGE, NEW_TAG, NONE_EVENT).Return(nil),
-		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, QUERY)
+	err := Executor.UpdateApp(context.Background(), APP_ID, QUERY, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestUpdateAppWithQueryWithTagWhenUpdateAppInfoFailed_ExpectReturnError(t *testing.T) {
+func TestUpdateAppWithQueryWhenServiceNotFound_ExpectStateRestored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	QUERY := map[string]interface{}{
+		"images": []string{"test_url:5000/unknown:1.0"},
+	}
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.UpdateApp(context.Background(), APP_ID, QUERY, nil)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidParam", "nil")
+	}
+}
+
+func TestUpdateAppWithQueryWithTagWhenUpdateAppInfoFailed_ExpectRolledBack(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1548,29 +2352,45 @@ func TestUpdateAppWithQueryWithTagWhenUpdateAppInfoFailed_ExpectReturnError(t *t
 	}
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify(gomock.Any()).Return(nil, nil),
 		appExecutorMockObj.EXPECT().LockUpdateAppState(),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
-		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), true, gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), gomock.Any(), gomock.Any(), true, gomock.Any()).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, UPDATED_DESCRIPTION_JSON).Return(UnknownError),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, ORIGIN_DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, ROLLEDBACK_STATE).Return(nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, ROLLBACK_OPERATION, REPO_DIGESTS).Return(nil, nil),
 		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
 	appsMonitor = appExecutorMockObj
+	historyExecutor = historyExecutorMockObj
 
-	err := Executor.UpdateApp(APP_ID, QUERY)
+	err := Executor.UpdateApp(context.Background(), APP_ID, QUERY, nil)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "UnknownError", "nil")
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: RolledBack, actual err: %v", err)
+	case errors.RolledBack:
 	}
 }
 
@@ -1647,7 +2467,7 @@ func TestRestoreRepoDigests_ExpectSuccess(t *testing.T) {
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1746,7 +2566,7 @@ func TestRestoreRepoDigestsWhenUpFailed_ExpectReturnError(t *testing.T) {
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(UnknownError),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
@@ -1769,8 +2589,8 @@ func TestInnerUpdateApp_ExpectSuccess(t *testing.T) {
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
@@ -1778,7 +2598,7 @@ func TestInnerUpdateApp_ExpectSuccess(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateApp(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests)
+	err := updateApp(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil)
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
@@ -1792,11 +2612,11 @@ func TestInnerUpdateAppWhenPullFailed_ExpectReturnErrror(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any()).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1806,7 +2626,7 @@ func TestInnerUpdateAppWhenPullFailed_ExpectReturnErrror(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateApp(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests)
+	err := updateApp(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil)
 	switch err.(type) {
 	default:
 		t.Errorf("Expected err: UnknownError, actual err: %s", err.Error())
@@ -1822,12 +2642,12 @@ func TestInnerUpdateAppWhenUpFailed_ExpectSuccess(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any()).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1837,7 +2657,7 @@ func TestInnerUpdateAppWhenUpFailed_ExpectSuccess(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateApp(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests)
+	err := updateApp(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil)
 	switch err.(type) {
 	default:
 		t.Errorf("Expected err: UnknownError, actual err: %s", err.Error())
@@ -1853,8 +2673,8 @@ func TestUpdateService_ExpectSuccess(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE, SERVICE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true, SERVICE).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any(), SERVICE).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true, SERVICE).Return(nil),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
@@ -1863,7 +2683,7 @@ func TestUpdateService_ExpectSuccess(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateService(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, SERVICE)
+	err := updateService(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil, nil, SERVICE)
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
@@ -1877,11 +2697,11 @@ func TestUpdateServiceWhenPullFailed_ExpectReturnError(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE, SERVICE).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any(), SERVICE).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1891,7 +2711,7 @@ func TestUpdateServiceWhenPullFailed_ExpectReturnError(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateService(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, SERVICE)
+	err := updateService(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil, nil, SERVICE)
 	switch err.(type) {
 	default:
 		t.Errorf("Expected err: UnknownError, actual err: %s", err.Error())
@@ -1907,12 +2727,12 @@ func TestUpdateServiceWhenUpFailed_ExpectReturnError(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Pull(APP_ID, COMPOSE_FILE, SERVICE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true, SERVICE).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Pull(gomock.Any(), APP_ID, COMPOSE_FILE, gomock.Any(), SERVICE).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true, SERVICE).Return(UnknownError),
 		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
 		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
 		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE).Return(nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1922,7 +2742,7 @@ func TestUpdateServiceWhenUpFailed_ExpectReturnError(t *testing.T) {
 	repoDigests := make(map[string]string, 0)
 	repoDigests[REPOSITORY_WITH_PORT_IMAGE] = REPODIGEST
 
-	err := updateService(APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, SERVICE)
+	err := updateService(context.Background(), APP_ID, COMPOSE_FILE, DB_GET_APP_OBJ, repoDigests, nil, nil, nil, SERVICE)
 	switch err.(type) {
 	default:
 		t.Errorf("Expected err: UnknownError, actual err: %s", err.Error())
@@ -1938,7 +2758,7 @@ func TestRestoreStateWithRUNNING_STATE_ExpectSuccess(t *testing.T) {
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
 	)
 
@@ -1958,7 +2778,7 @@ func TestRestoreStateWhenUpFailed_ExpectReturnError(t *testing.T) {
 	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(UnknownError),
 	)
 
 	dockerExecutor = dockerExecutorMockObj
@@ -1979,7 +2799,7 @@ func TestRestoreStateWhenUpdateAppStateToRunningFailed_ExpectReturnError(t *test
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(errors.Unknown{}),
 	)
 
@@ -2044,7 +2864,7 @@ func TestRestoreStateWhenUpdateAppStateToExitedFailed_ExpectReturnError(t *testi
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, COMPOSE_FILE, true).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, true).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(errors.Unknown{}),
 	)
 
@@ -2341,7 +3161,7 @@ func TestRestoreAllAppsState(t *testing.T) {
 		dbExecutorMockObj.EXPECT().GetAppList().Return(DB_OBJs, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, gomock.Any(), false).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), false).Return(nil),
 		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE),
 	)
 
@@ -2351,6 +3171,57 @@ func TestRestoreAllAppsState(t *testing.T) {
 	restoreAllAppsState()
 }
 
+func TestRestoreAllAppsStateWithRecoveredApp_ExpectNotRestored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	recoveredApps := []map[string]interface{}{
+		{
+			"id":          APP_ID,
+			"state":       RECOVERED_STATE,
+			"description": DESCRIPTION_JSON,
+		},
+	}
+
+	dbExecutorMockObj.EXPECT().GetAppList().Return(recoveredApps, nil)
+
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	restoreAllAppsState()
+}
+
+func TestRestoreAllAppsStateWithScaledService_ExpectReplicasRestored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	scaledApp := make(map[string]interface{})
+	for key, value := range DB_GET_APP_OBJ {
+		scaledApp[key] = value
+	}
+	scaledApp[REPLICAS] = map[string]int{SERVICE: 0}
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return(DB_OBJs, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(scaledApp, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(scaledApp, nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), false).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE),
+		dockerExecutorMockObj.EXPECT().Scale(APP_ID, gomock.Any(), SERVICE, 0).Return(nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	restoreAllAppsState()
+}
+
 func TestRestoreAllAppsStateGetAppListFailed_ExpectReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2395,7 +3266,7 @@ func TestRestoreAllAppsStateUpFailed_ExpectReturn(t *testing.T) {
 		dbExecutorMockObj.EXPECT().GetAppList().Return(DB_OBJs, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
 		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
-		dockerExecutorMockObj.EXPECT().Up(APP_ID, gomock.Any(), false).Return(errors.Unknown{}),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), false).Return(errors.Unknown{}),
 	)
 
 	dbExecutor = dbExecutorMockObj
@@ -2423,3 +3294,187 @@ func TestRestoreAllAppsStateStopFailed_ExpectReturn(t *testing.T) {
 
 	restoreAllAppsState()
 }
+
+func TestCalledPauseApp_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Pause(APP_ID, gomock.Any()).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, PAUSED_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.PauseApp(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledPauseAppWhenPauseFailed_ExpectUnpausedAndErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Pause(APP_ID, gomock.Any()).Return(UnknownError),
+		dockerExecutorMockObj.EXPECT().Unpause(APP_ID, gomock.Any()).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.PauseApp(APP_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: UnknownError, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledPauseAppWithInvalidState_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_PAUSED_STATE_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.PauseApp(APP_ID)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: AlreadyReported, actual err: %v", err)
+	case errors.AlreadyReported:
+	}
+
+	err = Executor.PauseApp(APP_ID)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledUnpauseApp_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_PAUSED_STATE_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_PAUSED_STATE_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Unpause(APP_ID, gomock.Any()).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.UnpauseApp(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUnpauseAppWhenNotPaused_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.UnpauseApp(APP_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: AlreadyReported, actual err: %v", err)
+	case errors.AlreadyReported:
+	}
+}
+
+func TestRestoreStateWithPausedState_ExpectPausedAgain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, false).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pause(APP_ID, COMPOSE_FILE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, PAUSED_STATE).Return(nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	err := restoreState(APP_ID, COMPOSE_FILE, PAUSED_STATE, false)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestRestoreStateWithPausedStateWhenPauseFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, COMPOSE_FILE, false).Return(nil),
+		dockerExecutorMockObj.EXPECT().Pause(APP_ID, COMPOSE_FILE).Return(UnknownError),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+
+	err := restoreState(APP_ID, COMPOSE_FILE, PAUSED_STATE, false)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: UnknownError, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
@@ -0,0 +1,71 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"golang.org/x/net/context"
+	"io"
+	"os"
+)
+
+const (
+	EXEC_ENABLED = "execenabled"
+)
+
+// Running a command in the container of a service of app in the target.
+// exec is allowed only if 'execenabled' property is "true".
+// if succeed to run, return a stream attached to stdin, stdout and stderr
+// of the command, which should be closed by caller.
+// otherwise, return error.
+func (depExecutorImpl) ExecService(ctx context.Context, appId string, service string, cmd []string, tty bool) (io.ReadWriteCloser, error) {
+	logger.Logging(logger.DEBUG, "IN", appId, service)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if !isExecEnabled() {
+		return nil, errors.Forbidden{Msg: "exec is disabled, set " + EXEC_ENABLED + " property to enable it"}
+	}
+
+	if len(cmd) == 0 {
+		return nil, errors.InvalidParam{Msg: "cmd is empty"}
+	}
+
+	composeFile, err := setYamlFile(appId, "exec")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	defer os.RemoveAll(composeFile)
+
+	stream, err := dockerExecutor.Exec(ctx, appId, composeFile, service, cmd, tty)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return stream, nil
+}
+
+func isExecEnabled() bool {
+	prop, err := configDbExecutor.GetProperty(EXEC_ENABLED)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return false
+	}
+	return prop[VALUE] == "true"
+}
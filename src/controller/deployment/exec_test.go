@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	configmocks "db/bolt/configuration/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+	"net"
+	"testing"
+)
+
+var (
+	EXEC_ENABLED_PROP  = map[string]interface{}{"name": EXEC_ENABLED, "value": "true", "readOnly": false}
+	EXEC_DISABLED_PROP = map[string]interface{}{"name": EXEC_ENABLED, "value": "false", "readOnly": false}
+	EXEC_CMD           = []string{"sh", "-c", "ls"}
+)
+
+func TestCalledExecService_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	stream, _ := net.Pipe()
+	defer stream.Close()
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(EXEC_ENABLED).Return(EXEC_ENABLED_PROP, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().Exec(gomock.Any(), APP_ID, gomock.Any(), "web", EXEC_CMD, true).Return(stream, nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+
+	res, err := Executor.ExecService(context.Background(), APP_ID, "web", EXEC_CMD, true)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res != stream {
+		t.Errorf("Expected the stream from docker executor")
+	}
+}
+
+func TestCalledExecServiceWhenDisabled_ExpectForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	props := []map[string]interface{}{EXEC_DISABLED_PROP, nil}
+	errs := []error{nil, NotFoundError}
+
+	for i := range props {
+		gomock.InOrder(
+			configDbExecutorMockObj.EXPECT().GetProperty(EXEC_ENABLED).Return(props[i], errs[i]),
+		)
+
+		configDbExecutor = configDbExecutorMockObj
+
+		_, err := Executor.ExecService(context.Background(), APP_ID, "web", EXEC_CMD, false)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: Forbidden, actual err: %v", err)
+		case errors.Forbidden:
+		}
+	}
+}
+
+func TestCalledExecServiceWithEmptyCmd_ExpectInvalidParam(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configDbExecutorMockObj.EXPECT().GetProperty(EXEC_ENABLED).Return(EXEC_ENABLED_PROP, nil),
+	)
+
+	configDbExecutor = configDbExecutorMockObj
+
+	_, err := Executor.ExecService(context.Background(), APP_ID, "web", nil, false)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
@@ -0,0 +1,63 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/dockercontroller"
+	"golang.org/x/net/context"
+	"os"
+	"strconv"
+)
+
+const (
+	TAIL_ALL = "all"
+)
+
+// Getting logs of containers of app in the target by input appId.
+// stdout and stderr of containers are sent to the returned channel
+// line by line with timestamps, until ctx is cancelled if options.Follow is set.
+// if succeed to open logs, return the channel
+// otherwise, return error.
+func (depExecutorImpl) AppLogs(ctx context.Context, appId string, options dockercontroller.LogOptions) (<-chan dockercontroller.LogEntry, error) {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(options.Tail) != 0 && options.Tail != TAIL_ALL {
+		if tail, err := strconv.Atoi(options.Tail); err != nil || tail < 0 {
+			return nil, errors.InvalidParam{Msg: "tail should be a non-negative number or all"}
+		}
+	}
+
+	composeFile, err := setYamlFile(appId, "logs")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	// log streams are opened before returning,
+	// so that compose file is not needed any more.
+	defer os.RemoveAll(composeFile)
+
+	entries, err := dockerExecutor.Logs(ctx, appId, composeFile, options)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return entries, nil
+}
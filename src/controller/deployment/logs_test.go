@@ -0,0 +1,116 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"controller/dockercontroller"
+	dockermocks "controller/dockercontroller/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+func TestCalledAppLogs_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	options := dockercontroller.LogOptions{Services: []string{"web"}, Tail: "10"}
+	var entries <-chan dockercontroller.LogEntry = make(chan dockercontroller.LogEntry)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().Logs(gomock.Any(), APP_ID, gomock.Any(), options).Return(entries, nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	res, err := Executor.AppLogs(context.Background(), APP_ID, options)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res != entries {
+		t.Errorf("Expected the channel from docker executor")
+	}
+}
+
+func TestCalledAppLogsWithInvalidTail_ExpectErrorReturn(t *testing.T) {
+	for _, tail := range []string{"-1", "ten"} {
+		_, err := Executor.AppLogs(context.Background(), APP_ID, dockercontroller.LogOptions{Tail: tail})
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: InvalidParam, actual err: %v, tail : %s", err, tail)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledAppLogsWhenGetAppFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, NotFoundError),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	_, err := Executor.AppLogs(context.Background(), APP_ID, dockercontroller.LogOptions{})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidAppId, actual err: %v", err)
+	case errors.InvalidAppId:
+	}
+}
+
+func TestCalledAppLogsWhenNoContainerOfService_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	options := dockercontroller.LogOptions{Services: []string{"unknown"}}
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().Logs(gomock.Any(), APP_ID, gomock.Any(), options).Return(nil, errors.InvalidParam{}),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	_, err := Executor.AppLogs(context.Background(), APP_ID, options)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
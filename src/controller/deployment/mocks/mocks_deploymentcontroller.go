@@ -21,7 +21,11 @@
 package mock_deployment
 
 import (
+	deployment "controller/deployment"
+	dockercontroller "controller/dockercontroller"
 	gomock "github.com/golang/mock/gomock"
+	context "golang.org/x/net/context"
+	io "io"
 	reflect "reflect"
 )
 
@@ -49,16 +53,29 @@ func (m *MockCommand) EXPECT() *MockCommandMockRecorder {
 }
 
 // DeployApp mocks base method
-func (m *MockCommand) DeployApp(body string, query map[string]interface{}) (map[string]interface{}, error) {
-	ret := m.ctrl.Call(m, "DeployApp", body, query)
+func (m *MockCommand) DeployApp(ctx context.Context, body string, query map[string]interface{}, progress deployment.Progress) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "DeployApp", ctx, body, query, progress)
 	ret0, _ := ret[0].(map[string]interface{})
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeployApp indicates an expected call of DeployApp
-func (mr *MockCommandMockRecorder) DeployApp(body, query interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployApp", reflect.TypeOf((*MockCommand)(nil).DeployApp), body, query)
+func (mr *MockCommandMockRecorder) DeployApp(ctx, body, query, progress interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployApp", reflect.TypeOf((*MockCommand)(nil).DeployApp), ctx, body, query, progress)
+}
+
+// ValidateApp mocks base method
+func (m *MockCommand) ValidateApp(body string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "ValidateApp", body)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateApp indicates an expected call of ValidateApp
+func (mr *MockCommandMockRecorder) ValidateApp(body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateApp", reflect.TypeOf((*MockCommand)(nil).ValidateApp), body)
 }
 
 // Apps mocks base method
@@ -135,6 +152,78 @@ func (mr *MockCommandMockRecorder) StopApp(appId interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopApp", reflect.TypeOf((*MockCommand)(nil).StopApp), appId)
 }
 
+// PauseApp mocks base method
+func (m *MockCommand) PauseApp(appId string) error {
+	ret := m.ctrl.Call(m, "PauseApp", appId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseApp indicates an expected call of PauseApp
+func (mr *MockCommandMockRecorder) PauseApp(appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseApp", reflect.TypeOf((*MockCommand)(nil).PauseApp), appId)
+}
+
+// UnpauseApp mocks base method
+func (m *MockCommand) UnpauseApp(appId string) error {
+	ret := m.ctrl.Call(m, "UnpauseApp", appId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpauseApp indicates an expected call of UnpauseApp
+func (mr *MockCommandMockRecorder) UnpauseApp(appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpauseApp", reflect.TypeOf((*MockCommand)(nil).UnpauseApp), appId)
+}
+
+// StartService mocks base method
+func (m *MockCommand) StartService(appId string, service string) error {
+	ret := m.ctrl.Call(m, "StartService", appId, service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartService indicates an expected call of StartService
+func (mr *MockCommandMockRecorder) StartService(appId, service interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartService", reflect.TypeOf((*MockCommand)(nil).StartService), appId, service)
+}
+
+// StopService mocks base method
+func (m *MockCommand) StopService(appId string, service string) error {
+	ret := m.ctrl.Call(m, "StopService", appId, service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopService indicates an expected call of StopService
+func (mr *MockCommandMockRecorder) StopService(appId, service interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopService", reflect.TypeOf((*MockCommand)(nil).StopService), appId, service)
+}
+
+// RestartService mocks base method
+func (m *MockCommand) RestartService(appId string, service string) error {
+	ret := m.ctrl.Call(m, "RestartService", appId, service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestartService indicates an expected call of RestartService
+func (mr *MockCommandMockRecorder) RestartService(appId, service interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartService", reflect.TypeOf((*MockCommand)(nil).RestartService), appId, service)
+}
+
+// ScaleService mocks base method
+func (m *MockCommand) ScaleService(appId string, service string, replicas int) error {
+	ret := m.ctrl.Call(m, "ScaleService", appId, service, replicas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScaleService indicates an expected call of ScaleService
+func (mr *MockCommandMockRecorder) ScaleService(appId, service, replicas interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleService", reflect.TypeOf((*MockCommand)(nil).ScaleService), appId, service, replicas)
+}
+
 // HandleEvents mocks base method
 func (m *MockCommand) HandleEvents(appId, body string) error {
 	ret := m.ctrl.Call(m, "HandleEvents", appId, body)
@@ -147,14 +236,116 @@ func (mr *MockCommandMockRecorder) HandleEvents(appId, body interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvents", reflect.TypeOf((*MockCommand)(nil).HandleEvents), appId, body)
 }
 
+// UpdatePolicy mocks base method
+func (m *MockCommand) UpdatePolicy(appId string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "UpdatePolicy", appId)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy
+func (mr *MockCommandMockRecorder) UpdatePolicy(appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockCommand)(nil).UpdatePolicy), appId)
+}
+
+// SetUpdatePolicy mocks base method
+func (m *MockCommand) SetUpdatePolicy(appId, body string) error {
+	ret := m.ctrl.Call(m, "SetUpdatePolicy", appId, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpdatePolicy indicates an expected call of SetUpdatePolicy
+func (mr *MockCommandMockRecorder) SetUpdatePolicy(appId, body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdatePolicy", reflect.TypeOf((*MockCommand)(nil).SetUpdatePolicy), appId, body)
+}
+
 // UpdateApp mocks base method
-func (m *MockCommand) UpdateApp(appId string, query map[string]interface{}) error {
-	ret := m.ctrl.Call(m, "UpdateApp", appId, query)
+func (m *MockCommand) UpdateApp(ctx context.Context, appId string, query map[string]interface{}, progress deployment.Progress) error {
+	ret := m.ctrl.Call(m, "UpdateApp", ctx, appId, query, progress)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateApp indicates an expected call of UpdateApp
-func (mr *MockCommandMockRecorder) UpdateApp(appId, query interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockCommand)(nil).UpdateApp), appId, query)
+func (mr *MockCommandMockRecorder) UpdateApp(ctx, appId, query, progress interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockCommand)(nil).UpdateApp), ctx, appId, query, progress)
+}
+
+// Revisions mocks base method
+func (m *MockCommand) Revisions(appId string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "Revisions", appId)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions
+func (mr *MockCommandMockRecorder) Revisions(appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockCommand)(nil).Revisions), appId)
+}
+
+// Revision mocks base method
+func (m *MockCommand) Revision(appId, revision string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "Revision", appId, revision)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revision indicates an expected call of Revision
+func (mr *MockCommandMockRecorder) Revision(appId, revision interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockCommand)(nil).Revision), appId, revision)
+}
+
+// DiffRevisions mocks base method
+func (m *MockCommand) DiffRevisions(appId, from, to string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "DiffRevisions", appId, from, to)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions
+func (mr *MockCommandMockRecorder) DiffRevisions(appId, from, to interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockCommand)(nil).DiffRevisions), appId, from, to)
+}
+
+// RedeployRevision mocks base method
+func (m *MockCommand) RedeployRevision(ctx context.Context, appId, revision string, progress deployment.Progress) error {
+	ret := m.ctrl.Call(m, "RedeployRevision", ctx, appId, revision, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeployRevision indicates an expected call of RedeployRevision
+func (mr *MockCommandMockRecorder) RedeployRevision(ctx, appId, revision, progress interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeployRevision", reflect.TypeOf((*MockCommand)(nil).RedeployRevision), ctx, appId, revision, progress)
+}
+
+// AppLogs mocks base method
+func (m *MockCommand) AppLogs(ctx context.Context, appId string, options dockercontroller.LogOptions) (<-chan dockercontroller.LogEntry, error) {
+	ret := m.ctrl.Call(m, "AppLogs", ctx, appId, options)
+	ret0, _ := ret[0].(<-chan dockercontroller.LogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppLogs indicates an expected call of AppLogs
+func (mr *MockCommandMockRecorder) AppLogs(ctx, appId, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppLogs", reflect.TypeOf((*MockCommand)(nil).AppLogs), ctx, appId, options)
+}
+
+// ExecService mocks base method
+func (m *MockCommand) ExecService(ctx context.Context, appId string, service string, cmd []string, tty bool) (io.ReadWriteCloser, error) {
+	ret := m.ctrl.Call(m, "ExecService", ctx, appId, service, cmd, tty)
+	ret0, _ := ret[0].(io.ReadWriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecService indicates an expected call of ExecService
+func (mr *MockCommandMockRecorder) ExecService(ctx, appId, service, cmd, tty interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecService", reflect.TypeOf((*MockCommand)(nil).ExecService), ctx, appId, service, cmd, tty)
 }
@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/dockercontroller"
+	"db/bolt/wrapper"
+	"encoding/json"
+	"sort"
+)
+
+const (
+	// Field of the record of database recovery holding ids of rebuilt apps.
+	RECOVERED_APPS = "apps"
+
+	RECOVERED_COMPOSE_VERSION = "2"
+)
+
+var getDBRecovery = wrapper.GetRecovery
+var setDBRecovery = wrapper.SetRecovery
+
+// Rebuilds apps from compose project labels of containers,
+// after a corrupt database was moved aside when it was opened.
+// descriptions of rebuilt apps only have images of services since the rest
+// of them can't be known from containers, so containers are left as they are
+// and apps are in recovered state until they are deployed again.
+// ids of rebuilt apps are added to the record of recovery, which makes apps
+// rebuilt only once per recovery.
+func rebuildApps() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	record, err := getDBRecovery()
+	if err != nil {
+		// Database has never been recovered.
+		return
+	}
+	if _, exists := record[RECOVERED_APPS]; exists {
+		return
+	}
+
+	containers, err := dockerExecutor.GetContainers()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	projects := make(map[string][]dockercontroller.ContainerInfo)
+	for _, container := range containers {
+		if len(container.Project) == 0 || len(container.Service) == 0 {
+			continue
+		}
+		projects[container.Project] = append(projects[container.Project], container)
+	}
+
+	appIds := make([]string, 0, len(projects))
+	for appId := range projects {
+		appIds = append(appIds, appId)
+	}
+	sort.Strings(appIds)
+
+	rebuilt := make([]string, 0)
+	for _, appId := range appIds {
+		description, err := makeRecoveredDescription(projects[appId])
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+
+		_, err = dbExecutor.InsertRecoveredApp(appId, description, RECOVERED_STATE)
+		if err != nil {
+			if _, ok := err.(errors.AlreadyReported); !ok {
+				logger.Logging(logger.ERROR, err.Error())
+				continue
+			}
+		}
+		logger.Logging(logger.INFO, "app is rebuilt from containers", appId)
+		rebuilt = append(rebuilt, appId)
+	}
+
+	record[RECOVERED_APPS] = rebuilt
+	err = setDBRecovery(record)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
+
+// Returns description in json of which services have images of containers.
+func makeRecoveredDescription(containers []dockercontroller.ContainerInfo) (string, error) {
+	services := make(map[string]interface{})
+	for _, container := range containers {
+		services[container.Service] = map[string]interface{}{IMAGE: container.Image}
+	}
+
+	description := map[string]interface{}{
+		"version": RECOVERED_COMPOSE_VERSION,
+		SERVICES:  services,
+	}
+	jsonData, err := json.Marshal(description)
+	if err != nil {
+		return "", errors.InvalidJSON{Msg: err.Error()}
+	}
+	return string(jsonData), nil
+}
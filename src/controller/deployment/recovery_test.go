@@ -0,0 +1,111 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"controller/dockercontroller"
+	dockermocks "controller/dockercontroller/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+const RECOVERED_DESCRIPTION = "{\"services\":{\"db\":{\"image\":\"db:1.0\"},\"web\":{\"image\":\"web:1.0\"}},\"version\":\"2\"}"
+
+var RECOVERY_CONTAINERS = []dockercontroller.ContainerInfo{
+	{ID: "web_1", State: "running", Image: "web:1.0", Project: "app_1", Service: "web"},
+	{ID: "db_1", State: "exited", Image: "db:1.0", Project: "app_1", Service: "db"},
+	{ID: "other_1", State: "exited", Image: "other:1.0", Project: "app_2", Service: "other"},
+	{ID: "standalone", State: "running", Image: "standalone:1.0"},
+}
+
+// Replaces the record of database recovery, and the record saved by
+// rebuildApps is stored in saved.
+func setTestRecovery(record map[string]interface{}, err error, saved *map[string]interface{}) func() {
+	origGet, origSet := getDBRecovery, setDBRecovery
+	getDBRecovery = func() (map[string]interface{}, error) {
+		return record, err
+	}
+	setDBRecovery = func(record map[string]interface{}) error {
+		*saved = record
+		return nil
+	}
+	return func() {
+		getDBRecovery, setDBRecovery = origGet, origSet
+	}
+}
+
+func TestCalledRebuildAppsAfterRecovery_ExpectAppsRebuiltFromContainers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(RECOVERY_CONTAINERS, nil),
+		dbExecutorMockObj.EXPECT().InsertRecoveredApp("app_1", RECOVERED_DESCRIPTION, RECOVERED_STATE).Return(nil, nil),
+		dbExecutorMockObj.EXPECT().InsertRecoveredApp("app_2", gomock.Any(), RECOVERED_STATE).Return(nil, errors.DBOperationError{}),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	var saved map[string]interface{}
+	defer setTestRecovery(map[string]interface{}{"time": "test_time"}, nil, &saved)()
+
+	rebuildApps()
+
+	expected := map[string]interface{}{"time": "test_time", RECOVERED_APPS: []string{"app_1"}}
+	if !reflect.DeepEqual(expected, saved) {
+		t.Errorf("Expected record: %v, actual record: %v", expected, saved)
+	}
+}
+
+func TestCalledRebuildAppsWithoutRecovery_ExpectNothingRebuilt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := map[string]struct {
+		record map[string]interface{}
+		err    error
+	}{
+		"NotRecovered":   {nil, errors.NotFound{}},
+		"AlreadyRebuilt": {map[string]interface{}{RECOVERED_APPS: []interface{}{}}, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// GetContainers is not expected to be called.
+			dockerExecutor = dockermocks.NewMockCommand(ctrl)
+			dbExecutor = dbmocks.NewMockCommand(ctrl)
+
+			var saved map[string]interface{}
+			defer setTestRecovery(test.record, test.err, &saved)()
+
+			rebuildApps()
+
+			if saved != nil {
+				t.Errorf("Expected record not saved, actual record: %v", saved)
+			}
+		})
+	}
+}
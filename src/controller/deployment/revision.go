@@ -0,0 +1,456 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"encoding/json"
+	"golang.org/x/net/context"
+	"gopkg.in/yaml.v2"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	REVISION  = "revision"
+	REVISIONS = "revisions"
+	DIGESTS   = "digests"
+	OPERATION = "operation"
+	TIMESTAMP = "timestamp"
+	FROM      = "from"
+	TO        = "to"
+	ADDED     = "added"
+	REMOVED   = "removed"
+	CHANGED   = "changed"
+
+	// Operations which produce a revision of app description.
+	DEPLOY_OPERATION       = "deploy"
+	UPDATE_OPERATION       = "update"
+	EVENT_UPDATE_OPERATION = "eventupdate"
+	ROLLBACK_OPERATION     = "rollback"
+	REDEPLOY_OPERATION     = "redeploy"
+)
+
+// Getting all of revisions of app in the target by input appId.
+// if succeed to get, return revision list without descriptions
+// otherwise, return error.
+func (depExecutorImpl) Revisions(appId string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	_, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, convertDBError(err, appId)
+	}
+
+	revisions, err := historyExecutor.GetRevisions(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "db operation fail"}
+	}
+
+	revisionList := make([]map[string]interface{}, 0)
+	for _, rev := range revisions {
+		m := make(map[string]interface{})
+		m[REVISION] = rev[REVISION]
+		m[OPERATION] = rev[OPERATION]
+		m[TIMESTAMP] = rev[TIMESTAMP]
+		m[DIGESTS] = rev[DIGESTS]
+		revisionList = append(revisionList, m)
+	}
+
+	res := make(map[string]interface{})
+	res[REVISIONS] = revisionList
+
+	return res, nil
+}
+
+// Getting a revision of app in the target by input appId and revision number.
+// if succeed to get, return revision information with yaml description
+// otherwise, return error.
+func (depExecutorImpl) Revision(appId string, revision string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", appId, revision)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	rev, err := getRevision(appId, revision)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	description, err := convertToYaml(rev[DESCRIPTION].(string))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	m[REVISION] = rev[REVISION]
+	m[OPERATION] = rev[OPERATION]
+	m[TIMESTAMP] = rev[TIMESTAMP]
+	m[DIGESTS] = rev[DIGESTS]
+	m[DESCRIPTION] = description
+
+	return m, nil
+}
+
+// Comparing two revisions of app in the target by input appId.
+// services which are added, removed or changed in 'to' revision
+// and images whose digest is changed are returned.
+// if succeed to compare, return difference of revisions
+// otherwise, return error.
+func (depExecutorImpl) DiffRevisions(appId string, from string, to string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", appId, from, to)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	fromRev, err := getRevision(appId, from)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	toRev, err := getRevision(appId, to)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	fromServices, err := getServices([]byte(fromRev[DESCRIPTION].(string)))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	toServices, err := getServices([]byte(toRev[DESCRIPTION].(string)))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	added := make([]string, 0)
+	removed := make([]string, 0)
+	changed := make([]map[string]interface{}, 0)
+	for _, serviceName := range sortedKeys(toServices) {
+		fromService, exists := fromServices[serviceName]
+		if !exists {
+			added = append(added, serviceName)
+			continue
+		}
+		if !reflect.DeepEqual(fromService, toServices[serviceName]) {
+			change := make(map[string]interface{})
+			change[NAME] = serviceName
+			change[FROM] = fromService
+			change[TO] = toServices[serviceName]
+			changed = append(changed, change)
+		}
+	}
+	for _, serviceName := range sortedKeys(fromServices) {
+		if _, exists := toServices[serviceName]; !exists {
+			removed = append(removed, serviceName)
+		}
+	}
+
+	fromDigests, _ := fromRev[DIGESTS].(map[string]string)
+	toDigests, _ := toRev[DIGESTS].(map[string]string)
+	digests := make([]map[string]interface{}, 0)
+	for _, image := range mergedKeys(fromDigests, toDigests) {
+		if fromDigests[image] != toDigests[image] {
+			digest := make(map[string]interface{})
+			digest[IMAGE] = image
+			digest[FROM] = fromDigests[image]
+			digest[TO] = toDigests[image]
+			digests = append(digests, digest)
+		}
+	}
+
+	res := make(map[string]interface{})
+	res[FROM] = fromRev[REVISION]
+	res[TO] = toRev[REVISION]
+	res[ADDED] = added
+	res[REMOVED] = removed
+	res[CHANGED] = changed
+	res[DIGESTS] = digests
+
+	return res, nil
+}
+
+// Redeploy app in the target with the description and image digests
+// stored in the revision.
+// if redeployed services are not healthy during the rollback watch period,
+// Pharos Node can make sure that previous description and images.
+// the description of the revision is stored only after the redeployment
+// succeeds, and previous state is restored if it fails before containers
+// are recreated.
+// the redeployment is stopped when ctx is canceled, and its phases are
+// reported to progress if it is given.
+// if succeed to redeploy, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) RedeployRevision(ctx context.Context, appId string, revision string, progress Progress) error {
+	logger.Logging(logger.DEBUG, "IN", appId, revision)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	reportPhase(progress, VALIDATING_PHASE)
+
+	rev, err := getRevision(appId, revision)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	unlock := lockUpdateAppState()
+	defer unlock()
+
+	err = dbExecutor.UpdateAppState(appId, UPDATING_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	// From here, every failure goes through failUpdate not to leave
+	// the app in updating state.
+	changed := false
+	repoDigests, err := getRepoDigests([]byte(app[DESCRIPTION].(string)))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
+	description := rev[DESCRIPTION].(string)
+	services, err := getServiceNames([]byte(description))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
+	composeFile, err := writeYamlFile(appId, "redeploy", description)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+	defer os.RemoveAll(composeFile)
+
+	reportPhase(progress, PULLING_PHASE)
+	err = pullRevisionImages(description, rev[DIGESTS])
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
+	// containers can be recreated partially even though it fails.
+	reportPhase(progress, STARTING_PHASE)
+	changed = true
+	err = dockerExecutor.Up(ctx, appId, composeFile, true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
+	unlock()
+
+	reportPhase(progress, VERIFYING_PHASE)
+	err = watchUpdatedServices(ctx, appId, composeFile, services)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, err)
+	}
+
+	err = dbExecutor.UpdateAppInfo(appId, description)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return failUpdate(appId, app, repoDigests, changed, convertDBError(err, appId))
+	}
+
+	err = dbExecutor.UpdateAppState(appId, RUNNING_STATE)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	recordRevision(appId, description, REDEPLOY_OPERATION, nil)
+
+	return nil
+}
+
+// Pull images of the revision by digest and tag them with image names,
+// images without digest are pulled by their name.
+// all of images are verified by the image policy before they are pulled,
+// and images without digest are pulled by their verified digests if exist.
+func pullRevisionImages(description string, digests interface{}) error {
+	revDigests := make(map[string]string)
+	if d, ok := digests.(map[string]string); ok {
+		for image, repoDigest := range d {
+			revDigests[image] = repoDigest
+		}
+	}
+
+	images, err := getImageNames([]byte(description))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	references := make([]string, 0)
+	for _, image := range images {
+		if repoDigest, exists := revDigests[image]; exists {
+			references = append(references, repoDigest)
+			continue
+		}
+		references = append(references, image)
+	}
+	verified, err := imagePolicy.Verify(references)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	for _, image := range images {
+		if _, exists := revDigests[image]; exists {
+			continue
+		}
+		if repoDigest, exists := verified[image]; exists {
+			revDigests[image] = repoDigest
+			continue
+		}
+		err = dockerExecutor.ImagePull(image)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return err
+		}
+	}
+
+	return pullRepoDigests(revDigests)
+}
+
+// Record description and image digests of app as a new revision.
+// if digests is nil, digests of images in the description are used.
+// failure of recording does not affect the result of operation.
+func recordRevision(appId, description, operation string, digests map[string]string) {
+	if digests == nil {
+		var err error
+		digests, err = getRepoDigests([]byte(description))
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			digests = make(map[string]string)
+		}
+	}
+
+	_, err := historyExecutor.InsertRevision(appId, description, operation, digests)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
+
+// Get repo digests of images in the description.
+// if getting repo digests is succeeded, return digests mapped by image name.
+// otherwise, return error.
+func getRepoDigests(desc []byte) (map[string]string, error) {
+	imageList, err := getImageNames(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	repoDigests := make(map[string]string)
+	for _, image := range imageList {
+		repoDigest, err := dockerExecutor.GetImageDigestByName(image)
+		if err != nil {
+			return nil, err
+		}
+		repoDigests[image] = repoDigest
+	}
+	return repoDigests, nil
+}
+
+func getRevision(appId string, revision string) (map[string]interface{}, error) {
+	number, err := strconv.Atoi(revision)
+	if err != nil {
+		return nil, errors.InvalidParam{Msg: "invalid revision : " + revision}
+	}
+
+	rev, err := historyExecutor.GetRevision(appId, number)
+	if err != nil {
+		switch err.(type) {
+		case errors.NotFound:
+			return nil, errors.InvalidParam{Msg: "failed to find revision : " + revision}
+		default:
+			return nil, errors.Unknown{Msg: "db operation fail"}
+		}
+	}
+	return rev, nil
+}
+
+// Get services shown in json description.
+func getServices(desc []byte) (map[string]interface{}, error) {
+	description := make(map[string]interface{})
+	err := json.Unmarshal(desc, &description)
+	if err != nil {
+		return nil, errors.IOError{Msg: "json unmarshal fail"}
+	}
+
+	services, ok := description[SERVICES].(map[string]interface{})
+	if !ok {
+		return nil, errors.Unknown{Msg: "No service in YAML description"}
+	}
+	return services, nil
+}
+
+func convertToYaml(desc string) (string, error) {
+	description := make(map[string]interface{})
+	err := json.Unmarshal([]byte(desc), &description)
+	if err != nil {
+		return "", errors.IOError{Msg: "json unmarshal fail"}
+	}
+
+	data, err := yaml.Marshal(description)
+	if err != nil {
+		return "", errors.InvalidYaml{Msg: "invalid yaml syntax"}
+	}
+	return string(data), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0)
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergedKeys(a, b map[string]string) []string {
+	set := make(map[string]interface{})
+	for key := range a {
+		set[key] = nil
+	}
+	for key := range b {
+		set[key] = nil
+	}
+	return sortedKeys(set)
+}
@@ -0,0 +1,323 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	policymocks "controller/imagepolicy/mocks"
+	appmocks "controller/monitoring/apps/mocks"
+	configmocks "db/bolt/configuration/mocks"
+	historymocks "db/bolt/history/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/context"
+	"reflect"
+	"testing"
+)
+
+var (
+	REVISION_1_OBJ = map[string]interface{}{
+		"appid":       APP_ID,
+		"revision":    1,
+		"description": ORIGIN_DESCRIPTION_JSON,
+		"digests":     map[string]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG: REPODIGEST},
+		"operation":   DEPLOY_OPERATION,
+		"timestamp":   int64(1),
+	}
+
+	REVISION_2_OBJ = map[string]interface{}{
+		"appid":       APP_ID,
+		"revision":    2,
+		"description": UPDATED_DESCRIPTION_JSON,
+		"digests":     map[string]string{FULL_IMAGE_NAME: REPOSITORY_WITH_PORT_IMAGE_DIGEST},
+		"operation":   EVENT_UPDATE_OPERATION,
+		"timestamp":   int64(2),
+	}
+)
+
+func TestCalledRevisions_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		historyExecutorMockObj.EXPECT().GetRevisions(APP_ID).Return([]map[string]interface{}{REVISION_1_OBJ, REVISION_2_OBJ}, nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	res, err := Executor.Revisions(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	revisions := res[REVISIONS].([]map[string]interface{})
+	if len(revisions) != 2 || revisions[0][REVISION] != 1 || revisions[1][OPERATION] != EVENT_UPDATE_OPERATION {
+		t.Errorf("Unexpected result : %v", res)
+	}
+	if _, exists := revisions[0][DESCRIPTION]; exists {
+		t.Errorf("Unexpected description in revision list : %v", revisions[0])
+	}
+}
+
+func TestCalledRevisionsWhenGetAppFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, NotFoundError),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	_, err := Executor.Revisions(APP_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidAppId, actual err: %v", err)
+	case errors.InvalidAppId:
+	}
+}
+
+func TestCalledRevision_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 1).Return(REVISION_1_OBJ, nil),
+	)
+
+	historyExecutor = historyExecutorMockObj
+
+	res, err := Executor.Revision(APP_ID, "1")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[DESCRIPTION] != DESCRIPTION_YAML {
+		t.Errorf("Expected description : %s, Actual description : %v", DESCRIPTION_YAML, res[DESCRIPTION])
+	}
+}
+
+func TestCalledRevisionWithInvalidRevision_ExpectErrorReturn(t *testing.T) {
+	_, err := Executor.Revision(APP_ID, "invalid")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledRevisionWhenNotFound_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 3).Return(nil, NotFoundError),
+	)
+
+	historyExecutor = historyExecutorMockObj
+
+	_, err := Executor.Revision(APP_ID, "3")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledDiffRevisions_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 1).Return(REVISION_1_OBJ, nil),
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 2).Return(REVISION_2_OBJ, nil),
+	)
+
+	historyExecutor = historyExecutorMockObj
+
+	res, err := Executor.DiffRevisions(APP_ID, "1", "2")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedChanged := []map[string]interface{}{
+		{
+			NAME: SERVICE,
+			FROM: map[string]interface{}{IMAGE: REPOSITORY_WITH_PORT_IMAGE_WITH_TAG},
+			TO:   map[string]interface{}{IMAGE: FULL_IMAGE_NAME},
+		},
+	}
+	if !reflect.DeepEqual(res[CHANGED], expectedChanged) {
+		t.Errorf("Expected changed : %v, Actual changed : %v", expectedChanged, res[CHANGED])
+	}
+	if len(res[ADDED].([]string)) != 0 || len(res[REMOVED].([]string)) != 0 {
+		t.Errorf("Unexpected result : %v", res)
+	}
+	if len(res[DIGESTS].([]map[string]interface{})) != 2 {
+		t.Errorf("Unexpected digests : %v", res[DIGESTS])
+	}
+}
+
+func TestCalledRedeployRevision_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 2).Return(REVISION_2_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_DIGEST}).Return(nil, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPOSITORY_WITH_PORT_IMAGE_DIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPOSITORY_WITH_PORT_IMAGE_DIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, FULL_IMAGE_NAME).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		configDbExecutorMockObj.EXPECT().GetProperty(ROLLBACK_WATCH_PERIOD).Return(DISABLED_WATCH_PERIOD_PROP, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, UPDATED_DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(FULL_IMAGE_NAME).Return(REPOSITORY_WITH_PORT_IMAGE_DIGEST, nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, UPDATED_DESCRIPTION_JSON, REDEPLOY_OPERATION, gomock.Any()).Return(nil, nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	err := Executor.RedeployRevision(context.Background(), APP_ID, "2", nil)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledRedeployRevisionWhenPullFailed_ExpectStateRestoredWithoutUpdatingDescription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 2).Return(REVISION_2_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_DIGEST}).Return(nil, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPOSITORY_WITH_PORT_IMAGE_DIGEST).Return(UnknownError),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	err := Executor.RedeployRevision(context.Background(), APP_ID, "2", nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: UnknownError, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledRedeployRevisionWhenUpFailed_ExpectRolledBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	historyExecutorMockObj := historymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		historyExecutorMockObj.EXPECT().GetRevision(APP_ID, 2).Return(REVISION_2_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, UPDATING_STATE).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageDigestByName(REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(REPODIGEST, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_DIGEST}).Return(nil, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPOSITORY_WITH_PORT_IMAGE_DIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPOSITORY_WITH_PORT_IMAGE_DIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, FULL_IMAGE_NAME).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(UnknownError),
+		dbExecutorMockObj.EXPECT().UpdateAppInfo(APP_ID, ORIGIN_DESCRIPTION_JSON).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dockerExecutorMockObj.EXPECT().Up(gomock.Any(), APP_ID, gomock.Any(), true).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, ROLLEDBACK_STATE).Return(nil),
+		historyExecutorMockObj.EXPECT().InsertRevision(APP_ID, ORIGIN_DESCRIPTION_JSON, ROLLBACK_OPERATION, REPO_DIGESTS).Return(nil, nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+	historyExecutor = historyExecutorMockObj
+
+	err := Executor.RedeployRevision(context.Background(), APP_ID, "2", nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: RolledBack, actual err: %v", err)
+	case errors.RolledBack:
+	}
+}
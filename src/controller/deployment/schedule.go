@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron-style schedule of "minute hour day-of-month month day-of-week".
+// each field is "*" or a comma separated list of values and ranges,
+// optionally followed by a step like "*/15" or "1-5/2".
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type scheduleField struct {
+	min, max int
+}
+
+var scheduleFields = []scheduleField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are Sunday
+}
+
+// Returns a schedule parsed from cron-style expression.
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(scheduleFields) {
+		return nil, errors.InvalidParam{Msg: "schedule should have 5 fields : " + expr}
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := parseScheduleField(field, scheduleFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = value
+	}
+
+	// Sunday can be written as both 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &schedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseScheduleField(field string, bounds scheduleField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx != -1 {
+			value, err := strconv.Atoi(part[idx+1:])
+			if err != nil || value <= 0 {
+				return 0, errors.InvalidParam{Msg: "invalid step of schedule : " + part}
+			}
+			rangePart, step = part[:idx], value
+		}
+
+		start, end := bounds.min, bounds.max
+		if rangePart != "*" {
+			values := strings.SplitN(rangePart, "-", 2)
+			var err error
+			start, err = strconv.Atoi(values[0])
+			if err != nil {
+				return 0, errors.InvalidParam{Msg: "invalid value of schedule : " + part}
+			}
+			end = start
+			if len(values) == 2 {
+				end, err = strconv.Atoi(values[1])
+				if err != nil {
+					return 0, errors.InvalidParam{Msg: "invalid value of schedule : " + part}
+				}
+			} else if step != 1 {
+				end = bounds.max
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, errors.InvalidParam{Msg: "value of schedule is out of range : " + part}
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// Returns whether the minute of t matches the schedule.
+// like cron, if both day of month and day of week are restricted,
+// the day matches either of them.
+func (s *schedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatched := s.dom&(1<<uint(t.Day())) != 0
+	dowMatched := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatched && dowMatched
+	}
+	return domMatched || dowMatched
+}
+
+// Returns whether t is in a window which starts at a minute
+// matching the schedule and lasts for duration.
+func (s *schedule) inWindow(t time.Time, duration time.Duration) bool {
+	start := t.Truncate(time.Minute)
+	for elapsed := time.Duration(0); elapsed < duration; elapsed += time.Minute {
+		if s.matches(start.Add(-elapsed)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"testing"
+	"time"
+)
+
+// 2018-01-01 is Monday.
+func makeTime(day, hour, minute int) time.Time {
+	return time.Date(2018, time.January, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestParseScheduleWithInvalidExpression_ExpectErrorReturn(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+	}
+
+	for _, expr := range tests {
+		_, err := parseSchedule(expr)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err of %q: %s, actual err: %v", expr, "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	tests := []struct {
+		expr    string
+		time    time.Time
+		matched bool
+	}{
+		{"* * * * *", makeTime(1, 0, 0), true},
+		{"30 2 * * *", makeTime(1, 2, 30), true},
+		{"30 2 * * *", makeTime(1, 2, 31), false},
+		{"*/15 * * * *", makeTime(1, 5, 45), true},
+		{"*/15 * * * *", makeTime(1, 5, 50), false},
+		{"0 1-5/2 * * *", makeTime(1, 3, 0), true},
+		{"0 1-5/2 * * *", makeTime(1, 4, 0), false},
+		{"0 0 * * 1,3", makeTime(3, 0, 0), true},
+		{"0 0 * * 1-5", makeTime(6, 0, 0), false},
+		{"0 0 * * 7", makeTime(7, 0, 0), true},
+		{"0 0 15 * 1", makeTime(8, 0, 0), true},
+		{"0 0 15 * 1", makeTime(9, 0, 0), false},
+		{"0 0 1 2 *", makeTime(1, 0, 0), false},
+	}
+
+	for _, test := range tests {
+		s, err := parseSchedule(test.expr)
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+			continue
+		}
+		if s.matches(test.time) != test.matched {
+			t.Errorf("Expected %q matches %s: %t", test.expr, test.time, test.matched)
+		}
+	}
+}
+
+func TestScheduleInWindow(t *testing.T) {
+	s, _ := parseSchedule("0 2 * * *")
+	duration := 2 * time.Hour
+
+	tests := []struct {
+		time     time.Time
+		inWindow bool
+	}{
+		{makeTime(1, 1, 59), false},
+		{makeTime(1, 2, 0), true},
+		{makeTime(1, 3, 59), true},
+		{makeTime(1, 4, 0), false},
+	}
+
+	for _, test := range tests {
+		if s.inWindow(test.time, duration) != test.inWindow {
+			t.Errorf("Expected %s in window: %t", test.time, test.inWindow)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/monitoring/apps"
+	"os"
+)
+
+const (
+	// App state marked when some of services are exited.
+	PARTIALLY_EXITED_STATE = "partially exited"
+)
+
+// Start containers of a service of app in the target by input appId.
+// if succeed to start, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) StartService(appId string, service string) error {
+	logger.Logging(logger.DEBUG, "IN", appId, service)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return controlService(appId, service, "startservice", true, func(composeFile string) error {
+		return dockerExecutor.Start(appId, composeFile, service)
+	})
+}
+
+// Stop containers of a service of app in the target by input appId.
+// if succeed to stop, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) StopService(appId string, service string) error {
+	logger.Logging(logger.DEBUG, "IN", appId, service)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return controlService(appId, service, "stopservice", false, func(composeFile string) error {
+		return dockerExecutor.Stop(appId, composeFile, service)
+	})
+}
+
+// Restart containers of a service of app in the target by input appId.
+// if succeed to restart, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) RestartService(appId string, service string) error {
+	logger.Logging(logger.DEBUG, "IN", appId, service)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return controlService(appId, service, "restartservice", false, func(composeFile string) error {
+		return dockerExecutor.Restart(appId, composeFile, service)
+	})
+}
+
+// Scale the number of containers of a service of app in the target.
+// scaling to 0 replicas makes the service exited.
+// the number is kept so that it is restored when the app is started again.
+// if succeed to scale, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) ScaleService(appId string, service string, replicas int) error {
+	logger.Logging(logger.DEBUG, "IN", appId, service)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if replicas < 0 {
+		return errors.InvalidParam{Msg: "replicas should be a non-negative number"}
+	}
+
+	return controlService(appId, service, "scaleservice", true, func(composeFile string) error {
+		err := dockerExecutor.Scale(appId, composeFile, service, replicas)
+		if err != nil {
+			return err
+		}
+		err = dbExecutor.UpdateAppReplicas(appId, service, replicas)
+		if err != nil {
+			return convertDBError(err, appId)
+		}
+		return nil
+	})
+}
+
+// controlService runs control on the compose file of the app and
+// updates the app state according to the states of its services.
+// if control creates containers, the image of the service is verified
+// by the image policy and pulled by its verified digest before control.
+// app state is updated even though control is failed, because containers
+// of the service may be changed in part.
+func controlService(appId string, service string, api string, creates bool, control func(composeFile string) error) error {
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+
+	services, err := getServiceNames([]byte(app[DESCRIPTION].(string)))
+	if err != nil {
+		return err
+	}
+
+	if !containsString(services, service) {
+		return errors.InvalidParam{Msg: "unknown service : " + service}
+	}
+
+	if creates {
+		image := getServiceImageMap([]byte(app[DESCRIPTION].(string)))[service]
+		err = verifyAndPullImages([]string{image})
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return err
+		}
+	}
+
+	composeFile, err := setYamlFile(appId, api)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+	defer os.RemoveAll(composeFile)
+
+	appsMonitor.LockUpdateAppState()
+	defer appsMonitor.UnlockUpdateAppState()
+
+	err = control(composeFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+
+	e := updateStateByServices(appId, composeFile, services)
+	if e != nil {
+		logger.Logging(logger.ERROR, e.Error())
+		if err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// updateStateByServices updates the app state decided by states of
+// all of containers of its services, in the same way as event monitoring.
+func updateStateByServices(appId, composeFile string, services []string) error {
+	serviceStates := make([]string, 0)
+	for _, service := range services {
+		infos, err := dockerExecutor.Ps(appId, composeFile, service)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return err
+		}
+		serviceStates = append(serviceStates, apps.ServiceState(infos))
+	}
+
+	err := dbExecutor.UpdateAppState(appId, apps.AppState(serviceStates))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+	return nil
+}
+
+// restoreReplicas scales services of the app to the number of containers kept
+// by ScaleService, because a service is started with a container when it has none.
+func restoreReplicas(appId, composeFile string, app map[string]interface{}) {
+	replicas, _ := app[REPLICAS].(map[string]int)
+	for service, number := range replicas {
+		err := dockerExecutor.Scale(appId, composeFile, service, number)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}
+}
+
+func containsString(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
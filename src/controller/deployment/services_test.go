@@ -0,0 +1,288 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	policymocks "controller/imagepolicy/mocks"
+	appmocks "controller/monitoring/apps/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+const (
+	DB_SERVICE                    = "db_service"
+	TWO_SERVICES_DESCRIPTION_JSON = "{\"services\":{\"" + SERVICE + "\":{\"image\":\"" + REPOSITORY_WITH_PORT_IMAGE + ":" + OLD_TAG + "\"},\"" + DB_SERVICE + "\":{\"image\":\"db:1.0\"}},\"version\":\"2\"}"
+)
+
+var (
+	DB_GET_TWO_SERVICES_APP_OBJ = map[string]interface{}{
+		"id":          APP_ID,
+		"state":       RUNNING_STATE,
+		"description": TWO_SERVICES_DESCRIPTION_JSON,
+	}
+
+	PS_UP_RETURN     = []map[string]string{{"Name": CONTAINER, "State": "Up"}}
+	PS_EXITED_RETURN = []map[string]string{{"Name": CONTAINER, "State": "Exited (137)"}}
+)
+
+func TestCalledStopService_ExpectPartiallyExited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_TWO_SERVICES_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_TWO_SERVICES_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Stop(APP_ID, gomock.Any(), SERVICE).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, PARTIALLY_EXITED_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+	dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXITED_RETURN, nil)
+	dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), DB_SERVICE).Return(PS_UP_RETURN, nil)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.StopService(APP_ID, SERVICE)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledStartService_ExpectImagePulledByVerifiedDigestAndRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(REPO_DIGESTS, nil),
+		dockerExecutorMockObj.EXPECT().ImagePull(REPODIGEST).Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImageIDByRepoDigest(REPODIGEST).Return(IMAGE_ID, nil),
+		dockerExecutorMockObj.EXPECT().ImageTag(IMAGE_ID, REPOSITORY_WITH_PORT_IMAGE_WITH_TAG).Return(nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_WITH_EXITED_STATE_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Start(APP_ID, gomock.Any(), SERVICE).Return(nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_UP_RETURN, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.StartService(APP_ID, SERVICE)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledRestartServiceWhenDockerFailed_ExpectStateUpdatedAndErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Restart(APP_ID, gomock.Any(), SERVICE).Return(errors.Unknown{}),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(PS_EXITED_RETURN, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, EXITED_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.RestartService(APP_ID, SERVICE)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: Unknown, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledScaleServiceToZero_ExpectExited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(nil, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Scale(APP_ID, gomock.Any(), SERVICE, 0).Return(nil),
+		dbExecutorMockObj.EXPECT().UpdateAppReplicas(APP_ID, SERVICE, 0).Return(nil),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return([]map[string]string{}, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, EXITED_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.ScaleService(APP_ID, SERVICE, 0)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledScaleServiceWhenImageNotAllowed_ExpectForbiddenReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	policyExecutorMockObj := policymocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		policyExecutorMockObj.EXPECT().Verify([]string{REPOSITORY_WITH_PORT_IMAGE_WITH_TAG}).Return(nil, errors.Forbidden{}),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	imagePolicy = policyExecutorMockObj
+
+	err := Executor.ScaleService(APP_ID, SERVICE, 2)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: Forbidden, actual err: %v", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestCalledStopServiceWhenOneOfReplicasRunning_ExpectRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().Stop(APP_ID, gomock.Any(), SERVICE).Return(errors.Unknown{}),
+		dockerExecutorMockObj.EXPECT().Ps(APP_ID, gomock.Any(), SERVICE).Return(append(PS_EXITED_RETURN, PS_UP_RETURN...), nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(APP_ID, RUNNING_STATE).Return(nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	err := Executor.StopService(APP_ID, SERVICE)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: Unknown, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledScaleServiceWithNegativeReplicas_ExpectErrorReturn(t *testing.T) {
+	err := Executor.ScaleService(APP_ID, SERVICE, -1)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledServiceControlWithUnknownService_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(DB_GET_APP_OBJ, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.StopService(APP_ID, "unknown")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledServiceControlWhenGetAppFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, NotFoundError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.StartService(APP_ID, SERVICE)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidAppId, actual err: %v", err)
+	case errors.InvalidAppId:
+	}
+}
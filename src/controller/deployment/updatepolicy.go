@@ -0,0 +1,369 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"commons/util"
+	"golang.org/x/net/context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	UPDATE_POLICY = "updatepolicy"
+	MODE          = "mode"
+	SCHEDULE      = "schedule"
+	DURATION      = "duration"
+	JITTER        = "jitter"
+
+	// Modes of update policy.
+	// with manual mode, changes are applied only by UpdateApp.
+	// with immediate mode, changes are applied as soon as events are received.
+	// with window mode, changes are applied in maintenance windows.
+	MANUAL_MODE    = "manual"
+	IMMEDIATE_MODE = "immediate"
+	WINDOW_MODE    = "window"
+
+	// Maximum length(in seconds) of a maintenance window.
+	MAX_WINDOW_DURATION = 7 * 24 * 60 * 60
+)
+
+var updateCheckInterval = time.Minute
+var now = time.Now
+var sleep = time.Sleep
+var runInBackground = func(f func()) { go f() }
+var randomJitter = func(max int) time.Duration {
+	return time.Duration(rand.Intn(max+1)) * time.Second
+}
+
+var schedulerOnce sync.Once
+var scheduledUpdates = struct {
+	sync.Mutex
+	apps map[string]bool
+}{apps: make(map[string]bool)}
+
+// Delay before the same changes are applied again after a failure,
+// which is doubled by each failure up to maxUpdateBackoff.
+var updateBackoff = time.Hour
+var maxUpdateBackoff = 24 * time.Hour
+
+// Last failed attempts to apply pending changes of apps.
+// an attempt is forgotten when a new image is pushed for the app.
+var failedUpdates = struct {
+	sync.Mutex
+	apps map[string]failedUpdate
+}{apps: make(map[string]failedUpdate)}
+
+type failedUpdate struct {
+	images   []string
+	failedAt time.Time
+	failures int
+}
+
+// Getting update policy of app in the target by input appId.
+// if the policy is not set, manual policy is returned.
+// if succeed to get, return update policy
+// otherwise, return error.
+func (depExecutorImpl) UpdatePolicy(appId string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, convertDBError(err, appId)
+	}
+
+	return getUpdatePolicy(app), nil
+}
+
+// Setting update policy of app in the target by input appId.
+// body has mode which is one of "manual", "immediate" and "window",
+// and window mode needs cron-style schedule and duration(in seconds)
+// of maintenance windows. changes are applied after random delay
+// up to jitter(in seconds) so that devices are not updated at once.
+// if succeed to set, return error as nil
+// otherwise, return error.
+func (depExecutorImpl) SetUpdatePolicy(appId string, body string) error {
+	logger.Logging(logger.DEBUG, "IN", appId)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	policy, err := util.ConvertJsonToMap(body)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	err = validateUpdatePolicy(policy)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	err = dbExecutor.UpdateAppPolicy(appId, policy)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, appId)
+	}
+	return nil
+}
+
+// StartUpdateScheduler starts to apply pending changes of apps
+// according to their update policies in background.
+// it is safe to call more than once.
+func StartUpdateScheduler() {
+	schedulerOnce.Do(func() {
+		go func() {
+			for {
+				sleep(updateCheckInterval)
+				checkPendingUpdates(now())
+			}
+		}()
+	})
+}
+
+// Schedules updates of apps which have pending changes
+// and of which update policies allow to apply them at t.
+func checkPendingUpdates(t time.Time) {
+	appList, err := dbExecutor.GetAppList()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	for _, app := range appList {
+		policy := getUpdatePolicy(app)
+		images := getPendingImages(app)
+		if len(images) == 0 || !isUpdateAllowed(policy, t) {
+			continue
+		}
+		if isBackingOff(app[ID].(string), images, t) {
+			logger.Logging(logger.INFO, "changes failed to be applied before, skip update of", app[ID].(string))
+			continue
+		}
+		scheduleUpdate(app[ID].(string), policy)
+	}
+}
+
+// Applies pending changes of app after jitter in background,
+// unless an update of the app is already scheduled.
+func scheduleUpdate(appId string, policy map[string]interface{}) {
+	scheduledUpdates.Lock()
+	if scheduledUpdates.apps[appId] {
+		scheduledUpdates.Unlock()
+		return
+	}
+	scheduledUpdates.apps[appId] = true
+	scheduledUpdates.Unlock()
+
+	runInBackground(func() {
+		defer func() {
+			scheduledUpdates.Lock()
+			delete(scheduledUpdates.apps, appId)
+			scheduledUpdates.Unlock()
+		}()
+
+		if jitter := getIntValue(policy[JITTER]); jitter > 0 {
+			sleep(randomJitter(jitter))
+		}
+		if !isUpdateAllowed(policy, now()) {
+			logger.Logging(logger.INFO, "maintenance window is closed, skip update of", appId)
+			return
+		}
+
+		err := applyPendingChanges(appId)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	})
+}
+
+// Updates services of app to the tags of pending changes.
+// app which is paused or being updated is not updated.
+// a failed attempt is recorded so that the same changes are backed off.
+func applyPendingChanges(appId string) error {
+	app, err := dbExecutor.GetApp(appId)
+	if err != nil {
+		return convertDBError(err, appId)
+	}
+
+	state, _ := app[STATE].(string)
+	if state == PAUSED_STATE || state == UPDATING_STATE {
+		logger.Logging(logger.INFO, "app is", state, ", skip update of", appId)
+		return nil
+	}
+
+	images := getPendingImages(app)
+	if len(images) == 0 {
+		return nil
+	}
+
+	query := make(map[string]interface{})
+	query[IMAGES] = images
+	err = Executor.UpdateApp(context.Background(), appId, query, nil)
+	if err != nil {
+		recordFailedUpdate(appId, images, now())
+		return err
+	}
+	clearFailedUpdate(appId)
+	return nil
+}
+
+// Records that images failed to be applied to app at t.
+// failures are counted while the same images fail again.
+func recordFailedUpdate(appId string, images []string, t time.Time) {
+	failedUpdates.Lock()
+	defer failedUpdates.Unlock()
+
+	failures := 1
+	if failed, exists := failedUpdates.apps[appId]; exists && isSameImages(failed.images, images) {
+		failures = failed.failures + 1
+	}
+	failedUpdates.apps[appId] = failedUpdate{images: images, failedAt: t, failures: failures}
+}
+
+// Forgets the failed attempt of app, so that pending changes are applied again.
+func clearFailedUpdate(appId string) {
+	failedUpdates.Lock()
+	delete(failedUpdates.apps, appId)
+	failedUpdates.Unlock()
+}
+
+// Returns whether the same images failed to be applied to app
+// and the backoff delay after the failure has not passed at t.
+func isBackingOff(appId string, images []string, t time.Time) bool {
+	failedUpdates.Lock()
+	defer failedUpdates.Unlock()
+
+	failed, exists := failedUpdates.apps[appId]
+	if !exists || !isSameImages(failed.images, images) {
+		return false
+	}
+
+	backoff := updateBackoff
+	for i := 1; i < failed.failures && backoff < maxUpdateBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxUpdateBackoff {
+		backoff = maxUpdateBackoff
+	}
+	return t.Before(failed.failedAt.Add(backoff))
+}
+
+func isSameImages(a, b []string) bool {
+	return strings.Join(a, " ") == strings.Join(b, " ")
+}
+
+// Returns images with tags pushed to the registry
+// which are not applied to app yet.
+func getPendingImages(app map[string]interface{}) []string {
+	images := make([]string, 0)
+	imageList, _ := app[IMAGES].([]map[string]interface{})
+	for _, image := range imageList {
+		changes, ok := image[CHANGES].(map[string]interface{})
+		if !ok || changes[STATUS] != UPDATE {
+			continue
+		}
+		name, _ := image[NAME].(string)
+		tag, _ := changes[TAG].(string)
+		if len(name) != 0 && len(tag) != 0 {
+			images = append(images, name+":"+tag)
+		}
+	}
+	return images
+}
+
+func getUpdatePolicy(app map[string]interface{}) map[string]interface{} {
+	policy, ok := app[UPDATE_POLICY].(map[string]interface{})
+	if !ok || validateUpdatePolicy(policy) != nil {
+		return map[string]interface{}{MODE: MANUAL_MODE}
+	}
+	return policy
+}
+
+// Returns whether changes can be applied at t by the policy.
+func isUpdateAllowed(policy map[string]interface{}, t time.Time) bool {
+	switch policy[MODE] {
+	case IMMEDIATE_MODE:
+		return true
+	case WINDOW_MODE:
+		s, err := parseSchedule(policy[SCHEDULE].(string))
+		if err != nil {
+			return false
+		}
+		duration := time.Duration(getIntValue(policy[DURATION])) * time.Second
+		return s.inWindow(t, duration)
+	}
+	return false
+}
+
+func validateUpdatePolicy(policy map[string]interface{}) error {
+	for key := range policy {
+		switch key {
+		case MODE, SCHEDULE, DURATION, JITTER:
+		default:
+			return errors.InvalidParam{Msg: "not supported field of update policy : " + key}
+		}
+	}
+
+	if jitter, exists := policy[JITTER]; exists && getIntValue(jitter) < 0 {
+		return errors.InvalidParam{Msg: "jitter should be a non-negative integer"}
+	}
+
+	switch policy[MODE] {
+	default:
+		return errors.InvalidParam{Msg: "mode should be one of manual, immediate and window"}
+	case MANUAL_MODE, IMMEDIATE_MODE:
+		_, hasSchedule := policy[SCHEDULE]
+		_, hasDuration := policy[DURATION]
+		if hasSchedule || hasDuration {
+			return errors.InvalidParam{Msg: "schedule and duration are only for window mode"}
+		}
+	case WINDOW_MODE:
+		expr, ok := policy[SCHEDULE].(string)
+		if !ok {
+			return errors.InvalidParam{Msg: "window mode needs schedule"}
+		}
+		_, err := parseSchedule(expr)
+		if err != nil {
+			return err
+		}
+
+		duration := getIntValue(policy[DURATION])
+		if duration <= 0 || duration > MAX_WINDOW_DURATION {
+			return errors.InvalidParam{Msg: "duration should be a positive integer up to a week"}
+		}
+		if getIntValue(policy[JITTER]) >= duration {
+			return errors.InvalidParam{Msg: "jitter should be shorter than duration"}
+		}
+	}
+	return nil
+}
+
+// Returns integer of json number, or -1 if value is not an integer.
+func getIntValue(value interface{}) int {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return -1
+	}
+	return int(number)
+}
@@ -0,0 +1,347 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	WINDOW_POLICY_JSON = `{"mode":"window","schedule":"0 2 * * *","duration":7200,"jitter":600}`
+)
+
+var (
+	WINDOW_POLICY = map[string]interface{}{
+		"mode":     "window",
+		"schedule": "0 2 * * *",
+		"duration": float64(7200),
+		"jitter":   float64(600),
+	}
+	IMMEDIATE_POLICY = map[string]interface{}{
+		"mode": "immediate",
+	}
+	PENDING_IMAGES = []map[string]interface{}{
+		{
+			"name": REPOSITORY_WITH_PORT_IMAGE,
+			"changes": map[string]interface{}{
+				"tag":    NEW_TAG,
+				"status": "update",
+			},
+		},
+	}
+)
+
+func makeApp(id string, state string, images []map[string]interface{}, policy map[string]interface{}) map[string]interface{} {
+	app := map[string]interface{}{
+		"id":          id,
+		"state":       state,
+		"description": ORIGIN_DESCRIPTION_JSON,
+		"images":      images,
+	}
+	if policy != nil {
+		app[UPDATE_POLICY] = policy
+	}
+	return app
+}
+
+func resetScheduledUpdates() {
+	scheduledUpdates.Lock()
+	scheduledUpdates.apps = make(map[string]bool)
+	scheduledUpdates.Unlock()
+}
+
+func resetFailedUpdates() {
+	failedUpdates.Lock()
+	failedUpdates.apps = make(map[string]failedUpdate)
+	failedUpdates.Unlock()
+}
+
+func TestCalledSetUpdatePolicy_ExpectPolicyStored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().UpdateAppPolicy(APP_ID, WINDOW_POLICY).Return(nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.SetUpdatePolicy(APP_ID, WINDOW_POLICY_JSON)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledSetUpdatePolicyWhenAppNotExist_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().UpdateAppPolicy(APP_ID, IMMEDIATE_POLICY).Return(errors.NotFound{}),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor.SetUpdatePolicy(APP_ID, `{"mode":"immediate"}`)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidAppId", err)
+	case errors.InvalidAppId:
+	}
+}
+
+func TestCalledSetUpdatePolicyWithInvalidPolicy_ExpectErrorReturn(t *testing.T) {
+	tests := map[string]string{
+		"UnknownMode":         `{"mode":"sometimes"}`,
+		"UnknownField":        `{"mode":"manual","unknown":1}`,
+		"ScheduleOfImmediate": `{"mode":"immediate","schedule":"0 2 * * *"}`,
+		"NoSchedule":          `{"mode":"window","duration":3600}`,
+		"InvalidSchedule":     `{"mode":"window","schedule":"0 25 * * *","duration":3600}`,
+		"NoDuration":          `{"mode":"window","schedule":"0 2 * * *"}`,
+		"TooLongDuration":     `{"mode":"window","schedule":"0 2 * * *","duration":604801}`,
+		"NegativeJitter":      `{"mode":"immediate","jitter":-1}`,
+		"NotIntegerJitter":    `{"mode":"immediate","jitter":1.5}`,
+		"JitterOverDuration":  `{"mode":"window","schedule":"0 2 * * *","duration":600,"jitter":600}`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Executor.SetUpdatePolicy(APP_ID, body)
+
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+		})
+	}
+}
+
+func TestCalledUpdatePolicyWithoutPolicy_ExpectManualPolicyReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(makeApp(APP_ID, RUNNING_STATE, nil, nil), nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(makeApp(APP_ID, RUNNING_STATE, nil, WINDOW_POLICY), nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	res, err := Executor.UpdatePolicy(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{"mode": "manual"}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+
+	res, err = Executor.UpdatePolicy(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(WINDOW_POLICY, res) {
+		t.Errorf("Expected res: %v, actual res: %v", WINDOW_POLICY, res)
+	}
+}
+
+func TestCheckPendingUpdates_ExpectOnlyAllowedUpdatesScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return([]map[string]interface{}{
+			makeApp("manual", RUNNING_STATE, PENDING_IMAGES, nil),
+			makeApp("immediate", RUNNING_STATE, PENDING_IMAGES, IMMEDIATE_POLICY),
+			makeApp("nochanges", RUNNING_STATE, nil, IMMEDIATE_POLICY),
+			makeApp("window", RUNNING_STATE, PENDING_IMAGES, WINDOW_POLICY),
+		}, nil),
+		dbExecutorMockObj.EXPECT().GetAppList().Return([]map[string]interface{}{
+			makeApp("window", RUNNING_STATE, PENDING_IMAGES, WINDOW_POLICY),
+		}, nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	origRun := runInBackground
+	defer func() { runInBackground = origRun }()
+	runInBackground = func(f func()) {}
+	defer resetScheduledUpdates()
+
+	checkPendingUpdates(makeTime(1, 3, 0))
+
+	expected := map[string]bool{"immediate": true, "window": true}
+	if !reflect.DeepEqual(expected, scheduledUpdates.apps) {
+		t.Errorf("Expected scheduled: %v, actual scheduled: %v", expected, scheduledUpdates.apps)
+	}
+
+	resetScheduledUpdates()
+	checkPendingUpdates(makeTime(1, 4, 0))
+
+	if len(scheduledUpdates.apps) != 0 {
+		t.Errorf("Expected nothing scheduled, actual scheduled: %v", scheduledUpdates.apps)
+	}
+}
+
+func TestScheduleUpdateWhenWindowClosedAfterJitter_ExpectUpdateSkipped(t *testing.T) {
+	origRun, origSleep, origNow, origJitter := runInBackground, sleep, now, randomJitter
+	defer func() {
+		runInBackground, sleep, now, randomJitter = origRun, origSleep, origNow, origJitter
+	}()
+
+	var slept time.Duration
+	runInBackground = func(f func()) { f() }
+	randomJitter = func(max int) time.Duration { return time.Duration(max) * time.Second }
+	sleep = func(d time.Duration) { slept = d }
+	now = func() time.Time { return makeTime(1, 4, 0) }
+
+	// GetApp is not expected since the window is closed.
+	scheduleUpdate(APP_ID, WINDOW_POLICY)
+
+	if slept != 600*time.Second {
+		t.Errorf("Expected jitter: %s, actual jitter: %s", 600*time.Second, slept)
+	}
+	if len(scheduledUpdates.apps) != 0 {
+		t.Errorf("Expected nothing scheduled, actual scheduled: %v", scheduledUpdates.apps)
+	}
+}
+
+func TestApplyPendingChangesWhenAppPaused_ExpectNotUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(makeApp(APP_ID, PAUSED_STATE, PENDING_IMAGES, IMMEDIATE_POLICY), nil),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	err := applyPendingChanges(APP_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestApplyPendingChangesWhenUpdateFailed_ExpectSameChangesBackedOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(makeApp(APP_ID, RUNNING_STATE, PENDING_IMAGES, IMMEDIATE_POLICY), nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, errors.Unknown{}),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(makeApp(APP_ID, RUNNING_STATE, PENDING_IMAGES, IMMEDIATE_POLICY), nil),
+		dbExecutorMockObj.EXPECT().GetApp(APP_ID).Return(nil, errors.Unknown{}),
+	)
+
+	dbExecutor = dbExecutorMockObj
+
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return makeTime(1, 3, 0) }
+	defer resetFailedUpdates()
+
+	images := []string{FULL_IMAGE_NAME}
+	if err := applyPendingChanges(APP_ID); err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "Unknown", "nil")
+	}
+	if !isBackingOff(APP_ID, images, makeTime(1, 3, 59)) {
+		t.Errorf("Expected backing off within %s after the failure", updateBackoff)
+	}
+	if isBackingOff(APP_ID, images, makeTime(1, 4, 0)) {
+		t.Errorf("Unexpected backing off after %s", updateBackoff)
+	}
+	if isBackingOff(APP_ID, []string{REPOSITORY_WITH_PORT_IMAGE + ":other"}, makeTime(1, 3, 1)) {
+		t.Errorf("Unexpected backing off of other changes")
+	}
+
+	now = func() time.Time { return makeTime(1, 4, 0) }
+	applyPendingChanges(APP_ID)
+
+	if !isBackingOff(APP_ID, images, makeTime(1, 5, 59)) {
+		t.Errorf("Expected backing off doubled by the second failure")
+	}
+	if isBackingOff(APP_ID, images, makeTime(1, 6, 0)) {
+		t.Errorf("Unexpected backing off after doubled backoff")
+	}
+}
+
+func TestCalledUpdatedDockerImageFromRegistryAfterFailure_ExpectChangesNotBackedOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().UpdateAppEvent(APP_ID, REPOSITORY_WITH_PORT_IMAGE, NEW_TAG, UPDATE).Return(nil)
+	dbExecutor = dbExecutorMockObj
+
+	defer resetFailedUpdates()
+
+	images := []string{FULL_IMAGE_NAME}
+	recordFailedUpdate(APP_ID, images, makeTime(1, 3, 0))
+
+	imageInfo := map[string]interface{}{
+		HOST:       "test_url:5000",
+		REPOSITORY: "test",
+		TAG:        NEW_TAG,
+	}
+	err := updatedDockerImageFromRegistry(APP_ID, imageInfo)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if isBackingOff(APP_ID, images, makeTime(1, 3, 1)) {
+		t.Errorf("Unexpected backing off after a new image is pushed")
+	}
+}
+
+func TestGetPendingImages(t *testing.T) {
+	images := []map[string]interface{}{
+		PENDING_IMAGES[0],
+		{"name": "deleted", "changes": map[string]interface{}{"tag": "1.0", "status": "delete"}},
+		{"name": "unchanged"},
+	}
+
+	res := getPendingImages(makeApp(APP_ID, RUNNING_STATE, images, nil))
+
+	expected := []string{FULL_IMAGE_NAME}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
@@ -0,0 +1,337 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/logger"
+	"controller/dockercontroller"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	VALID         = "valid"
+	ERRORS        = "errors"
+	WARNINGS      = "warnings"
+	ISSUE_SERVICE = "service"
+	ISSUE_FIELD   = "field"
+	ISSUE_MESSAGE = "message"
+	VERSION       = "version"
+	VOLUMES       = "volumes"
+	BUILD         = "build"
+	CONTAINERNAME = "container_name"
+
+	// Major version of compose file format supported by libcompose.
+	SUPPORTED_COMPOSE_VERSION = "2"
+)
+
+// validation collects errors and warnings found in a description.
+type validation struct {
+	errors   []map[string]interface{}
+	warnings []map[string]interface{}
+}
+
+func (v *validation) addError(service, field, message string) {
+	v.errors = append(v.errors, makeIssue(service, field, message))
+}
+
+func (v *validation) addWarning(service, field, message string) {
+	v.warnings = append(v.warnings, makeIssue(service, field, message))
+}
+
+func makeIssue(service, field, message string) map[string]interface{} {
+	return map[string]interface{}{ISSUE_SERVICE: service, ISSUE_FIELD: field, ISSUE_MESSAGE: message}
+}
+
+// hostPort is a host port which a service wants to publish.
+type hostPort struct {
+	ip       string
+	port     int
+	protocol string
+}
+
+// Validate yaml description of app without deploying it.
+// the description is checked against the supported compose file version,
+// host ports used by containers in the target, availability of images
+// in the registries and host paths of volumes.
+// return the result containing errors and warnings found,
+// the description is valid if there is no error.
+func (depExecutorImpl) ValidateApp(body string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	v := &validation{
+		errors:   make([]map[string]interface{}, 0),
+		warnings: make([]map[string]interface{}, 0),
+	}
+
+	var description interface{}
+	err := yaml.Unmarshal([]byte(body), &description)
+	if err != nil {
+		v.addError("", "", "invalid yaml syntax : "+err.Error())
+		return v.result(), nil
+	}
+
+	root, ok := convert(description).(map[string]interface{})
+	if !ok {
+		v.addError("", "", "description should be a mapping")
+		return v.result(), nil
+	}
+
+	validateVersion(v, root[VERSION])
+
+	services, ok := root[SERVICES].(map[string]interface{})
+	if !ok || len(services) == 0 {
+		v.addError("", SERVICES, "no service in the description")
+		return v.result(), nil
+	}
+
+	volumes, _ := root[VOLUMES].(map[string]interface{})
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	containers, err := dockerExecutor.GetContainers()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		v.addWarning("", "", "can't check conflicts with containers in the target : "+err.Error())
+	}
+
+	requested := make(map[string]string)
+	for _, name := range names {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			v.addError(name, "", "service should be a mapping")
+			continue
+		}
+		validateImage(v, name, service)
+		validatePorts(v, name, service[PORTS], containers, requested)
+		validateContainerName(v, name, service[CONTAINERNAME], containers)
+		validateVolumes(v, name, service[VOLUMES], volumes)
+	}
+
+	return v.result(), nil
+}
+
+func (v *validation) result() map[string]interface{} {
+	return map[string]interface{}{
+		VALID:    len(v.errors) == 0,
+		ERRORS:   v.errors,
+		WARNINGS: v.warnings,
+	}
+}
+
+func validateVersion(v *validation, version interface{}) {
+	if version == nil {
+		v.addError("", VERSION, "version is required, supported version is "+SUPPORTED_COMPOSE_VERSION+".x")
+		return
+	}
+
+	str := fmt.Sprint(version)
+	if strings.Split(str, ".")[0] != SUPPORTED_COMPOSE_VERSION {
+		v.addError("", VERSION, "unsupported version "+str+", supported version is "+SUPPORTED_COMPOSE_VERSION+".x")
+	}
+}
+
+func validateImage(v *validation, name string, service map[string]interface{}) {
+	if _, exists := service[BUILD]; exists {
+		v.addError(name, BUILD, "build is not supported, use a prebuilt image")
+	}
+
+	image, ok := service[IMAGE].(string)
+	if !ok || len(image) == 0 {
+		v.addError(name, IMAGE, "image is required")
+		return
+	}
+
+	err := dockerExecutor.CheckImage(image)
+	if err != nil {
+		v.addError(name, IMAGE, "image "+image+" is not available : "+err.Error())
+	}
+}
+
+func validatePorts(v *validation, name string, ports interface{}, containers []dockercontroller.ContainerInfo, requested map[string]string) {
+	if ports == nil {
+		return
+	}
+
+	list, ok := ports.([]interface{})
+	if !ok {
+		v.addError(name, PORTS, "ports should be a list")
+		return
+	}
+
+	for _, item := range list {
+		hostPorts, err := parseHostPorts(fmt.Sprint(item))
+		if err != nil {
+			v.addError(name, PORTS, err.Error())
+			continue
+		}
+
+		for _, hp := range hostPorts {
+			key := strconv.Itoa(hp.port) + "/" + hp.protocol
+			if other, exists := requested[key]; exists {
+				v.addError(name, PORTS, "host port "+key+" is also published by service "+other)
+				continue
+			}
+			requested[key] = name
+
+			for _, container := range containers {
+				if container.State != RUNNING_STATE {
+					continue
+				}
+				for _, used := range container.Ports {
+					if isConflictedPort(hp, used) {
+						v.addError(name, PORTS, "host port "+key+" is already allocated by container "+container.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// parseHostPorts returns host ports of a port mapping in short syntax,
+// [[ip:]host_port[-host_port]:]container_port[-container_port][/protocol].
+// a mapping without host port publishes a random port, so nothing is returned.
+func parseHostPorts(mapping string) ([]hostPort, error) {
+	protocol := "tcp"
+	if idx := strings.LastIndex(mapping, "/"); idx != -1 {
+		protocol = mapping[idx+1:]
+		mapping = mapping[:idx]
+	}
+
+	ip, host := "", ""
+	parts := strings.Split(mapping, ":")
+	switch len(parts) {
+	case 1:
+		return nil, nil
+	case 2:
+		host = parts[0]
+	case 3:
+		ip, host = parts[0], parts[1]
+	default:
+		return nil, fmt.Errorf("invalid port mapping %s", mapping)
+	}
+
+	if len(host) == 0 {
+		return nil, nil
+	}
+
+	start, end := host, host
+	if idx := strings.Index(host, "-"); idx != -1 {
+		start, end = host[:idx], host[idx+1:]
+	}
+
+	from, err := strconv.Atoi(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host port %s", host)
+	}
+	to, err := strconv.Atoi(end)
+	if err != nil || to < from {
+		return nil, fmt.Errorf("invalid host port %s", host)
+	}
+
+	hostPorts := make([]hostPort, 0, to-from+1)
+	for port := from; port <= to; port++ {
+		hostPorts = append(hostPorts, hostPort{ip: ip, port: port, protocol: protocol})
+	}
+	return hostPorts, nil
+}
+
+func isConflictedPort(hp hostPort, used dockercontroller.PortBinding) bool {
+	if hp.port != used.Port || hp.protocol != used.Protocol {
+		return false
+	}
+	if isAnyAddress(hp.ip) || isAnyAddress(used.IP) {
+		return true
+	}
+	return hp.ip == used.IP
+}
+
+func isAnyAddress(ip string) bool {
+	return len(ip) == 0 || ip == "0.0.0.0" || ip == "::"
+}
+
+func validateContainerName(v *validation, name string, containerName interface{}, containers []dockercontroller.ContainerInfo) {
+	if containerName == nil {
+		return
+	}
+
+	str := fmt.Sprint(containerName)
+	for _, container := range containers {
+		if container.Name == str {
+			v.addError(name, CONTAINERNAME, "container name "+str+" is already in use")
+			return
+		}
+	}
+}
+
+// validateVolumes checks volumes in short syntax, [source:]target[:mode].
+// a named volume should be declared in top-level volumes,
+// and a host path which does not exist is created by docker as a directory.
+func validateVolumes(v *validation, name string, volumes interface{}, declared map[string]interface{}) {
+	if volumes == nil {
+		return
+	}
+
+	list, ok := volumes.([]interface{})
+	if !ok {
+		v.addError(name, VOLUMES, "volumes should be a list")
+		return
+	}
+
+	for _, item := range list {
+		parts := strings.Split(fmt.Sprint(item), ":")
+		if len(parts) > 3 {
+			v.addError(name, VOLUMES, "invalid volume "+fmt.Sprint(item))
+			continue
+		}
+
+		target := parts[0]
+		if len(parts) > 1 {
+			target = parts[1]
+		}
+		if !filepath.IsAbs(target) {
+			v.addError(name, VOLUMES, "container path "+target+" should be absolute")
+		}
+
+		if len(parts) == 1 {
+			continue
+		}
+
+		// existence of an absolute host path is not checked, because
+		// the node may run in a container which doesn't see the host filesystem.
+		source := parts[0]
+		switch {
+		case filepath.IsAbs(source):
+		case strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~"):
+			v.addWarning(name, VOLUMES, "relative host path "+source+" depends on the working directory of the node")
+		default:
+			if _, exists := declared[source]; !exists {
+				v.addError(name, VOLUMES, "volume "+source+" is not declared in top-level volumes")
+			}
+		}
+	}
+}
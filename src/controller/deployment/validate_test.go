@@ -0,0 +1,204 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package deployment
+
+import (
+	"commons/errors"
+	"controller/dockercontroller"
+	dockermocks "controller/dockercontroller/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+const (
+	VALID_DESCRIPTION = `version: "2"
+services:
+  web:
+    image: web:1.0
+    ports:
+      - "8080:80"
+      - "9000"
+    volumes:
+      - data:/data
+volumes:
+  data: {}
+`
+	INVALID_DESCRIPTION = `version: "3"
+services:
+  web:
+    build: .
+    ports:
+      - "127.0.0.1:8080:80"
+      - "9000-9001:9000-9001/udp"
+    container_name: used_name
+    volumes:
+      - ./data:/data
+      - undeclared:/cache
+      - /host:relative
+  db:
+    image: db:1.0
+    ports:
+      - "9001:9001/udp"
+`
+)
+
+var NotFoundImageError = errors.NotFoundImage{Msg: "manifest unknown"}
+
+var RUNNING_CONTAINERS = []dockercontroller.ContainerInfo{
+	{
+		Name:  "used_name",
+		State: "running",
+		Ports: []dockercontroller.PortBinding{{IP: "0.0.0.0", Port: 8080, Protocol: "tcp"}},
+	},
+	{
+		Name:  "exited_container",
+		State: "exited",
+		Ports: []dockercontroller.PortBinding{},
+	},
+}
+
+func TestCalledValidateApp_ExpectValid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(RUNNING_CONTAINERS[1:], nil),
+		dockerExecutorMockObj.EXPECT().CheckImage("web:1.0").Return(nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+
+	res, err := Executor.ValidateApp(VALID_DESCRIPTION)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[VALID] != true || len(res[ERRORS].([]map[string]interface{})) != 0 {
+		t.Errorf("Expected valid result, actual result : %v", res)
+	}
+}
+
+func TestCalledValidateApp_ExpectErrorsAndWarnings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(RUNNING_CONTAINERS, nil),
+		dockerExecutorMockObj.EXPECT().CheckImage("db:1.0").Return(NotFoundImageError),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+
+	res, err := Executor.ValidateApp(INVALID_DESCRIPTION)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedErrors := []map[string]interface{}{
+		makeIssue("", VERSION, "unsupported version 3, supported version is 2.x"),
+		makeIssue("db", IMAGE, "image db:1.0 is not available : "+NotFoundImageError.Error()),
+		makeIssue("web", BUILD, "build is not supported, use a prebuilt image"),
+		makeIssue("web", IMAGE, "image is required"),
+		makeIssue("web", PORTS, "host port 8080/tcp is already allocated by container used_name"),
+		makeIssue("web", PORTS, "host port 9001/udp is also published by service db"),
+		makeIssue("web", CONTAINERNAME, "container name used_name is already in use"),
+		makeIssue("web", VOLUMES, "volume undeclared is not declared in top-level volumes"),
+		makeIssue("web", VOLUMES, "container path relative should be absolute"),
+	}
+	if !reflect.DeepEqual(res[ERRORS], expectedErrors) {
+		t.Errorf("Expected errors : %v, actual errors : %v", expectedErrors, res[ERRORS])
+	}
+
+	expectedWarnings := []map[string]interface{}{
+		makeIssue("web", VOLUMES, "relative host path ./data depends on the working directory of the node"),
+	}
+	if !reflect.DeepEqual(res[WARNINGS], expectedWarnings) {
+		t.Errorf("Expected warnings : %v, actual warnings : %v", expectedWarnings, res[WARNINGS])
+	}
+
+	if res[VALID] != false {
+		t.Errorf("Expected invalid result")
+	}
+}
+
+func TestCalledValidateAppWithInvalidYaml_ExpectInvalid(t *testing.T) {
+	for _, body := range []string{WRONG_DESCRIPTION_JSON, "just a string", "version: \"2\"\n"} {
+		res, err := Executor.ValidateApp(body)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+
+		if res[VALID] != false || len(res[ERRORS].([]map[string]interface{})) == 0 {
+			t.Errorf("Expected invalid result for %s, actual result : %v", body, res)
+		}
+	}
+}
+
+func TestCalledValidateAppWhenGetContainersFailed_ExpectWarning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(nil, errors.Unknown{Msg: "no daemon"}),
+		dockerExecutorMockObj.EXPECT().CheckImage("web:1.0").Return(nil),
+	)
+
+	dockerExecutor = dockerExecutorMockObj
+
+	res, _ := Executor.ValidateApp(VALID_DESCRIPTION)
+
+	if res[VALID] != true || len(res[WARNINGS].([]map[string]interface{})) != 1 {
+		t.Errorf("Expected valid result with a warning, actual result : %v", res)
+	}
+}
+
+func TestParseHostPorts(t *testing.T) {
+	tests := map[string][]hostPort{
+		"80":                    nil,
+		"8080:80":               {{"", 8080, "tcp"}},
+		"127.0.0.1:8080:80/udp": {{"127.0.0.1", 8080, "udp"}},
+		"127.0.0.1::80":         nil,
+		"9000-9001:9000-9001":   {{"", 9000, "tcp"}, {"", 9001, "tcp"}},
+	}
+
+	for mapping, expected := range tests {
+		ports, err := parseHostPorts(mapping)
+		if err != nil {
+			t.Errorf("Unexpected err for %s: %s", mapping, err.Error())
+		}
+		if !reflect.DeepEqual(ports, expected) {
+			t.Errorf("Expected ports of %s : %v, actual ports : %v", mapping, expected, ports)
+		}
+	}
+
+	for _, mapping := range []string{"a:80", "1:2:3:4", "9001-9000:80"} {
+		if _, err := parseHostPorts(mapping); err == nil {
+			t.Errorf("Expected err for %s", mapping)
+		}
+	}
+}
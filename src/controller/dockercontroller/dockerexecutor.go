@@ -18,12 +18,17 @@
 package dockercontroller
 
 import (
-	"bytes"
+	"bufio"
 	"commons/errors"
 	"commons/logger"
 	"commons/util"
+	"controller/imagepolicy"
+	privateRegistry "controller/registry"
 	"docker.io/go-docker"
 	"docker.io/go-docker/api/types"
+	"docker.io/go-docker/api/types/registry"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	dockercompose "github.com/docker/libcompose/docker"
@@ -32,9 +37,13 @@ import (
 	events "github.com/docker/libcompose/project/events"
 	"github.com/docker/libcompose/project/options"
 	"golang.org/x/net/context"
+	"gopkg.in/yaml.v2"
 	"io"
+	"io/ioutil"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type Event struct {
@@ -51,26 +60,113 @@ type ContainerEvent struct {
 	Timestamp string
 }
 
+// LogOptions is options of getting container logs.
+// Services restricts logs to the given services, all services by default.
+// Tail is the number of lines to show from the end of the logs of each container,
+// Since is a timestamp or a relative duration like 10m.
+type LogOptions struct {
+	Services []string
+	Tail     string
+	Since    string
+	Follow   bool
+}
+
+// ContainerInfo is a summary of a container in the docker engine.
+// Project is the compose project which is app id for containers of apps,
+// and Service is the compose service of the container in the project.
+type ContainerInfo struct {
+	ID      string
+	Name    string
+	State   string
+	Image   string
+	ImageID string
+	Project string
+	Service string
+	Ports   []PortBinding
+}
+
+// PullProgress is progress of pulling a layer of an image,
+// Current and Total are in bytes and they are zero when the layer
+// is not being downloaded or extracted.
+type PullProgress struct {
+	Image   string
+	Layer   string
+	Status  string
+	Current int64
+	Total   int64
+}
+
+// Message in the stream of pulling an image from docker engine.
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
+
+// ImageInfo is a summary of an image in the docker engine.
+// Created is unix time in seconds and Size is in bytes.
+type ImageInfo struct {
+	ID          string
+	RepoTags    []string
+	RepoDigests []string
+	Created     int64
+	Size        int64
+}
+
+// PortBinding is a container port published on a host port.
+type PortBinding struct {
+	IP       string
+	Port     int
+	Protocol string
+}
+
+// LogEntry is a line of container logs.
+type LogEntry struct {
+	Service   string
+	Container string
+	Stream    string
+	Timestamp string
+	Log       string
+}
+
 type Command interface {
-	Create(id, path string) error
-	Up(id, path string, forceRecreate bool, services ...string) error
+	Create(ctx context.Context, id, path string) error
+	Up(ctx context.Context, id, path string, forceRecreate bool, services ...string) error
 	Down(id, path string) error
 	DownWithRemoveImages(id, path string) error
-	Start(id, path string) error
-	Stop(id, path string) error
+	Start(id, path string, services ...string) error
+	Stop(id, path string, services ...string) error
+	Restart(id, path string, services ...string) error
+	Scale(id, path, service string, replicas int) error
 	Pause(id, path string) error
 	Unpause(id, path string) error
-	Pull(id, path string, services ...string) error
+	Pull(ctx context.Context, id, path string, progress func(PullProgress), services ...string) error
+	PullMissing(ctx context.Context, id, path string, progress func(PullProgress)) error
 	Ps(id, path string, args ...string) ([]map[string]string, error)
 	GetAppStats(id, path string) ([]map[string]interface{}, error)
+	GetAppMetrics(id, path string) ([]map[string]interface{}, error)
 	GetContainerConfigByName(containerName string) (map[string]interface{}, error)
 	GetImageDigestByName(imageName string) (string, error)
 	GetImageIDByRepoDigest(imageName string) (string, error)
+	GetContainers() ([]ContainerInfo, error)
+	RemoveContainer(containerID string) error
+	GetImages() ([]ImageInfo, error)
+	RemoveImage(imageID string) error
+	ImageSave(images []string) (io.ReadCloser, error)
+	ImageLoad(input io.Reader) error
+	CheckImage(image string) error
+	EstimateImageSize(image string) (int64, error)
 	ImagePull(image string) error
 	ImageTag(imageID string, repoTags string) error
 	Events(id, path string, evt chan Event, services ...string) error
-	UpWithEvent(id, path, eventID string, evt chan Event, services ...string) error
+	UpWithEvent(ctx context.Context, id, path, eventID string, evt chan Event, services ...string) error
 	Info() (map[string]interface{}, error)
+	Logs(ctx context.Context, id, path string, options LogOptions) (<-chan LogEntry, error)
+	Exec(ctx context.Context, id, path, service string, cmd []string, tty bool) (io.ReadWriteCloser, error)
 }
 
 const (
@@ -93,8 +189,37 @@ const (
 	PULLED        string = "pulled"
 	CREATED       string = "created"
 	STARTED       string = "started"
+	RESTARTCOUNT  string = "restartcount"
+	HEALTH        string = "health"
+	SERVICE       string = "service"
+	STDOUT        string = "stdout"
+	STDERR        string = "stderr"
+
+	COMPOSE_SERVICE_LABEL string = "com.docker.compose.service"
+	COMPOSE_PROJECT_LABEL string = "com.docker.compose.project"
+
+	// Prefix of the status of pulling an image which isn't about a layer.
+	PULLING_FROM string = "Pulling from"
+
+	// Seconds to wait for containers to stop before killing them.
+	STOP_TIMEOUT int = 10
 )
 
+// containerStats is a numeric form of docker stats of a container.
+type containerStats struct {
+	id            string
+	name          string
+	service       string
+	cpuPercent    float64
+	memUsage      float64
+	memLimit      float64
+	blockInput    float64
+	blockOutput   float64
+	networkInput  float64
+	networkOutput float64
+	pids          uint64
+}
+
 var Executor dockerExecutorImpl
 
 type dockerExecutorImpl struct{}
@@ -106,8 +231,18 @@ var getImageList func(*docker.Client, context.Context, types.ImageListOptions) (
 var getImagePull func(*docker.Client, context.Context, string, types.ImagePullOptions) (io.ReadCloser, error)
 var getImageTag func(*docker.Client, context.Context, string, string) error
 var getContainerList func(*docker.Client, context.Context, types.ContainerListOptions) ([]types.Container, error)
+var getContainerRemove func(*docker.Client, context.Context, string, types.ContainerRemoveOptions) error
+var getImageRemove func(*docker.Client, context.Context, string, types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+var getImageSave func(*docker.Client, context.Context, []string) (io.ReadCloser, error)
+var getImageLoad func(*docker.Client, context.Context, io.Reader, bool) (types.ImageLoadResponse, error)
 var getContainerInspect func(*docker.Client, context.Context, string) (types.ContainerJSON, error)
 var getContainerStats func(*docker.Client, context.Context, string, bool) (types.ContainerStats, error)
+var getContainerLogs func(*docker.Client, context.Context, string, types.ContainerLogsOptions) (io.ReadCloser, error)
+var getExecCreate func(*docker.Client, context.Context, string, types.ExecConfig) (types.IDResponse, error)
+var getExecAttach func(*docker.Client, context.Context, string, types.ExecStartCheck) (types.HijackedResponse, error)
+var getImageInspect func(*docker.Client, context.Context, string) (types.ImageInspect, []byte, error)
+var getManifestSize = imagepolicy.ManifestSize
+var getDistributionInspect func(*docker.Client, context.Context, string, string) (registry.DistributionInspect, error)
 var getPs func(instance project.APIProject, ctx context.Context, params ...string) (project.InfoSet, error)
 var getPull func(instance project.APIProject, ctx context.Context, services ...string) error
 var getUp func(instance project.APIProject, ctx context.Context, options options.Up, services ...string) error
@@ -115,6 +250,8 @@ var getComposeInstance func(string, string) (project.APIProject, error)
 
 var evts map[string]chan events.ContainerEvent
 
+var registryExecutor privateRegistry.Command
+
 var conflictRepository string = "unable to remove repository reference"
 
 func composePull(instance project.APIProject, ctx context.Context, services ...string) error {
@@ -133,15 +270,25 @@ func init() {
 	evts = make(map[string]chan events.ContainerEvent, 0)
 
 	getComposeInstance = getComposeInstanceImpl
+	registryExecutor = privateRegistry.Executor{}
 
 	client, _ = docker.NewEnvClient()
 	getInfo = (*docker.Client).Info
 	getImageList = (*docker.Client).ImageList
 	getContainerList = (*docker.Client).ContainerList
+	getContainerRemove = (*docker.Client).ContainerRemove
+	getImageRemove = (*docker.Client).ImageRemove
+	getImageSave = (*docker.Client).ImageSave
+	getImageLoad = (*docker.Client).ImageLoad
 	getContainerInspect = (*docker.Client).ContainerInspect
 	getImagePull = (*docker.Client).ImagePull
 	getImageTag = (*docker.Client).ImageTag
 	getContainerStats = (*docker.Client).ContainerStats
+	getContainerLogs = (*docker.Client).ContainerLogs
+	getExecCreate = (*docker.Client).ContainerExecCreate
+	getExecAttach = (*docker.Client).ContainerExecAttach
+	getImageInspect = (*docker.Client).ImageInspectWithRaw
+	getDistributionInspect = (*docker.Client).DistributionInspect
 	getPs = composePs
 	getPull = composePull
 	getUp = composeUp
@@ -177,6 +324,67 @@ func (dockerExecutorImpl) GetAppStats(id, path string) ([]map[string]interface{}
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	containersStats, err := getAppContainersStats(id, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, cStats := range containersStats {
+		memPercent := 0.0
+		if cStats.memLimit > 0.0 {
+			memPercent = cStats.memUsage / cStats.memLimit * 100.0
+		}
+
+		stats := make(map[string]interface{})
+		stats[CID] = cStats.id
+		stats[CNAME] = cStats.name
+		stats[CPU] = fmt.Sprintf("%.3f", cStats.cpuPercent) + "%%"
+		stats[MEM] = fmt.Sprintf("%.3f", memPercent) + "%%"
+		stats[MEMUSAGE] = convertToHumanReadableBinaryUnit(cStats.memUsage)
+		stats[MEMLIMIT] = convertToHumanReadableBinaryUnit(cStats.memLimit)
+		stats[BLOCKINPUT] = convertToHumanReadableUnit(cStats.blockInput)
+		stats[BLOCKOUTPUT] = convertToHumanReadableUnit(cStats.blockOutput)
+		stats[NETWORKINPUT] = convertToHumanReadableUnit(cStats.networkInput)
+		stats[NETWORKOUTPUT] = convertToHumanReadableUnit(cStats.networkOutput)
+		stats[PIDS] = cStats.pids
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+// GetAppMetrics returns raw numeric stats of containers of an app,
+// cpu as percent, memory, block and network I/O as bytes.
+func (dockerExecutorImpl) GetAppMetrics(id, path string) ([]map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	containersStats, err := getAppContainersStats(id, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, cStats := range containersStats {
+		stats := make(map[string]interface{})
+		stats[CID] = cStats.id
+		stats[CNAME] = cStats.name
+		stats[SERVICE] = cStats.service
+		stats[CPU] = cStats.cpuPercent
+		stats[MEMUSAGE] = cStats.memUsage
+		stats[MEMLIMIT] = cStats.memLimit
+		stats[BLOCKINPUT] = cStats.blockInput
+		stats[BLOCKOUTPUT] = cStats.blockOutput
+		stats[NETWORKINPUT] = cStats.networkInput
+		stats[NETWORKOUTPUT] = cStats.networkOutput
+		stats[PIDS] = float64(cStats.pids)
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+// getAppContainersStats reads stats of containers belonging to an app from docker engine.
+func getAppContainersStats(id, path string) ([]containerStats, error) {
 	compose, err := getComposeInstance(id, path)
 	if err != nil {
 		return nil, err
@@ -199,7 +407,7 @@ func (dockerExecutorImpl) GetAppStats(id, path string) ([]map[string]interface{}
 		return nil, errors.Unknown{Msg: "fail to get the container list from docker engine"}
 	}
 
-	result := make([]map[string]interface{}, 0)
+	result := make([]containerStats, 0)
 	for _, container := range containers {
 		if util.IsContainedStringInList(appContainersNames, container.Names[0]) {
 			cStats, err := getContainerStats(client, context.Background(), container.ID, false)
@@ -216,39 +424,260 @@ func (dockerExecutorImpl) GetAppStats(id, path string) ([]map[string]interface{}
 				logger.Logging(logger.ERROR)
 				return nil, errors.Unknown{Msg: "fail to decode types.StatsJSON"}
 			}
-			cpuPercent := calcCPUPercent(statsJSON)
-			memPercent := 0.0
-			memUsage := float64(statsJSON.MemoryStats.Usage)
-			memLimit := float64(statsJSON.MemoryStats.Limit)
-			if memLimit > 0.0 {
-				memPercent = memUsage / memLimit * 100.0
-			}
 
 			bi, bo := calcBlockIO(statsJSON.BlkioStats)
 			ni, no := calcNetworkIO(statsJSON.Networks)
 
-			stats := make(map[string]interface{})
-			stats[CID] = container.ID
-			stats[CNAME] = strings.Replace(container.Names[0], "/", "", -1)
-			stats[CPU] = fmt.Sprintf("%.3f", cpuPercent) + "%%"
-			stats[MEM] = fmt.Sprintf("%.3f", memPercent) + "%%"
-			stats[MEMUSAGE] = convertToHumanReadableBinaryUnit(float64(statsJSON.MemoryStats.Usage))
-			stats[MEMLIMIT] = convertToHumanReadableBinaryUnit(float64(statsJSON.MemoryStats.Limit))
-			stats[BLOCKINPUT] = convertToHumanReadableUnit(float64(bi))
-			stats[BLOCKOUTPUT] = convertToHumanReadableUnit(float64(bo))
-			stats[NETWORKINPUT] = convertToHumanReadableUnit(ni)
-			stats[NETWORKOUTPUT] = convertToHumanReadableUnit(no)
-			stats[PIDS] = statsJSON.PidsStats.Current
-			result = append(result, stats)
+			result = append(result, containerStats{
+				id:            container.ID,
+				name:          strings.Replace(container.Names[0], "/", "", -1),
+				service:       container.Labels[COMPOSE_SERVICE_LABEL],
+				cpuPercent:    calcCPUPercent(statsJSON),
+				memUsage:      float64(statsJSON.MemoryStats.Usage),
+				memLimit:      float64(statsJSON.MemoryStats.Limit),
+				blockInput:    float64(bi),
+				blockOutput:   float64(bo),
+				networkInput:  ni,
+				networkOutput: no,
+				pids:          statsJSON.PidsStats.Current,
+			})
 		}
 	}
 	return result, nil
 }
 
+// Logs reads stdout and stderr of containers of an app with timestamps.
+// all of log streams are opened before returning, and lines of them are sent
+// to the returned channel which is closed when all of streams end.
+// with Follow option, streams do not end until ctx is cancelled.
+func (dockerExecutorImpl) Logs(ctx context.Context, id, path string, options LogOptions) (<-chan LogEntry, error) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	compose, err := getComposeInstance(id, path)
+	if err != nil {
+		return nil, err
+	}
+
+	appContainers, err := getPs(compose, ctx)
+	if err != nil {
+		logger.Logging(logger.ERROR, "fail to execute dockercompose ps")
+		return nil, errors.Unknown{Msg: "fail to execute dockercompose ps"}
+	}
+
+	appContainersNames := make([]string, 0)
+	for _, appContainer := range appContainers {
+		appContainersNames = append(appContainersNames, "/"+appContainer["Name"])
+	}
+
+	containers, err := getContainerList(client, ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		logger.Logging(logger.ERROR)
+		return nil, errors.Unknown{Msg: "fail to get the container list from docker engine"}
+	}
+
+	targets := make([]types.Container, 0)
+	for _, container := range containers {
+		if !util.IsContainedStringInList(appContainersNames, container.Names[0]) {
+			continue
+		}
+		service := container.Labels[COMPOSE_SERVICE_LABEL]
+		if len(options.Services) != 0 && !util.IsContainedStringInList(options.Services, service) {
+			continue
+		}
+		targets = append(targets, container)
+	}
+	if len(options.Services) != 0 && len(targets) == 0 {
+		return nil, errors.InvalidParam{Msg: "no container of the service : " + strings.Join(options.Services, ",")}
+	}
+
+	logOptions := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     options.Follow,
+		Tail:       options.Tail,
+		Since:      options.Since,
+	}
+
+	// streams already opened should be closed when a later one fails.
+	bodies := make([]io.ReadCloser, 0)
+	closeBodies := func() {
+		for _, body := range bodies {
+			body.Close()
+		}
+	}
+
+	readers := make([]func(chan<- LogEntry), 0)
+	for _, container := range targets {
+		ins, err := getContainerInspect(client, ctx, container.ID)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			closeBodies()
+			return nil, errors.Unknown{Msg: "fail to inspect the container"}
+		}
+		tty := ins.Config != nil && ins.Config.Tty
+
+		body, err := getContainerLogs(client, ctx, container.ID, logOptions)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			closeBodies()
+			return nil, errors.Unknown{Msg: "fail to get the container logs from docker engine"}
+		}
+		bodies = append(bodies, body)
+
+		service := container.Labels[COMPOSE_SERVICE_LABEL]
+		name := strings.Replace(container.Names[0], "/", "", -1)
+		readers = append(readers, func(entries chan<- LogEntry) {
+			defer body.Close()
+			readLogs(ctx, body, tty, service, name, entries)
+		})
+	}
+
+	entries := make(chan LogEntry)
+	var wg sync.WaitGroup
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader func(chan<- LogEntry)) {
+			defer wg.Done()
+			reader(entries)
+		}(reader)
+	}
+	go func() {
+		wg.Wait()
+		close(entries)
+	}()
+
+	return entries, nil
+}
+
+// readLogs splits logs of a container into lines.
+// logs of a container without tty are multiplexed with 8 bytes header
+// which has the stream type at first byte and the size of payload at last 4 bytes.
+func readLogs(ctx context.Context, body io.Reader, tty bool, service, name string, entries chan<- LogEntry) {
+	send := func(stream, line string) bool {
+		entry := LogEntry{Service: service, Container: name, Stream: stream, Log: line}
+		if index := strings.Index(line, " "); index >= 0 {
+			entry.Timestamp, entry.Log = line[:index], line[index+1:]
+		}
+		select {
+		case entries <- entry:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// lines of tty are read without limit of length unlike bufio.Scanner.
+	if tty {
+		reader := bufio.NewReader(body)
+		for {
+			line, err := reader.ReadString('\n')
+			if len(line) != 0 {
+				line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+				if !send(STDOUT, line) {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	pending := map[string]string{}
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(body, header); err != nil {
+			break
+		}
+		stream := STDOUT
+		if header[0] == 2 {
+			stream = STDERR
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(body, payload); err != nil {
+			break
+		}
+
+		lines := strings.Split(pending[stream]+string(payload), "\n")
+		pending[stream] = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			if !send(stream, line) {
+				return
+			}
+		}
+	}
+
+	for _, stream := range []string{STDOUT, STDERR} {
+		if len(pending[stream]) != 0 && !send(stream, pending[stream]) {
+			return
+		}
+	}
+}
+
+// execStream is a connection attached to stdin, stdout and stderr of an exec process.
+type execStream struct {
+	resp types.HijackedResponse
+}
+
+func (s *execStream) Read(p []byte) (int, error) {
+	return s.resp.Reader.Read(p)
+}
+
+func (s *execStream) Write(p []byte) (int, error) {
+	return s.resp.Conn.Write(p)
+}
+
+func (s *execStream) Close() error {
+	s.resp.Close()
+	return nil
+}
+
+// CloseWrite closes stdin of the exec process so that the process gets EOF.
+func (s *execStream) CloseWrite() error {
+	return s.resp.CloseWrite()
+}
+
+// Exec runs a command in the container of a service of an app.
+// returned stream is attached to stdin, stdout and stderr of the command.
+// without tty, stdout and stderr are multiplexed in the same way as docker.
+// the stream should be closed by caller.
+func (dockerExecutorImpl) Exec(ctx context.Context, id, path, service string, cmd []string, tty bool) (io.ReadWriteCloser, error) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	cid, err := getContainerIDByServiceName(id, path, service)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.InvalidParam{Msg: "no container of the service : " + service}
+	}
+
+	config := types.ExecConfig{
+		Tty:          tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	}
+	exec, err := getExecCreate(client, ctx, cid, config)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "fail to create exec : " + err.Error()}
+	}
+
+	resp, err := getExecAttach(client, ctx, exec.ID, types.ExecStartCheck{Tty: tty})
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "fail to attach exec : " + err.Error()}
+	}
+	return &execStream{resp}, nil
+}
+
 // Creating containers of service list in the yaml description.
 // if succeed to create, return error as nil
 // otherwise, return error.
-func (dockerExecutorImpl) Create(id, path string) error {
+func (dockerExecutorImpl) Create(ctx context.Context, id, path string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -257,14 +686,14 @@ func (dockerExecutorImpl) Create(id, path string) error {
 		return err
 	}
 
-	return compose.Create(context.Background(), options.Create{ForceRecreate: true})
+	return compose.Create(ctx, options.Create{ForceRecreate: true})
 }
 
 // Pulling images and creating containers and start containers
 // of service list in the yaml description.
 // if succeed to up, return error as nil
 // otherwise, return error.
-func (dockerExecutorImpl) Up(id, path string, forceRecreate bool, services ...string) error {
+func (dockerExecutorImpl) Up(ctx context.Context, id, path string, forceRecreate bool, services ...string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -272,10 +701,15 @@ func (dockerExecutorImpl) Up(id, path string, forceRecreate bool, services ...st
 	if err != nil {
 		return err
 	}
-	return compose.Up(context.Background(), options.Up{Create: options.Create{ForceRecreate: forceRecreate}}, services...)
+
+	_, _, err = pullImages(ctx, path, false, true, nil, services...)
+	if err != nil {
+		return err
+	}
+	return compose.Up(ctx, options.Up{Create: options.Create{ForceRecreate: forceRecreate}}, services...)
 }
 
-func (dockerExecutorImpl) UpWithEvent(id, path, eventID string, evt chan Event, services ...string) error {
+func (dockerExecutorImpl) UpWithEvent(ctx context.Context, id, path, eventID string, evt chan Event, services ...string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -307,12 +741,12 @@ func (dockerExecutorImpl) UpWithEvent(id, path, eventID string, evt chan Event,
 		}
 	}(id, path, eventID)
 
-	err = composePull(compose, context.Background(), services...)
+	err = pullServices(ctx, compose, path, nil, services...)
 	if err != nil {
 		return err
 	}
 
-	err = composeUp(compose, context.Background(), options.Up{Create: options.Create{ForceRecreate: true}}, services...)
+	err = composeUp(compose, ctx, options.Up{Create: options.Create{ForceRecreate: true}}, services...)
 	if err != nil {
 		return err
 	}
@@ -359,9 +793,10 @@ func (dockerExecutorImpl) DownWithRemoveImages(id, path string) error {
 }
 
 // Starting containers of service list in the yaml description.
+// if services are given, only containers of the services are started.
 // if succeed to start, return error as nil
 // otherwise, return error. (if contianers is not created, return error)
-func (dockerExecutorImpl) Start(id, path string) error {
+func (dockerExecutorImpl) Start(id, path string, services ...string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -369,13 +804,14 @@ func (dockerExecutorImpl) Start(id, path string) error {
 	if err != nil {
 		return err
 	}
-	return compose.Start(context.Background())
+	return compose.Start(context.Background(), services...)
 }
 
 // Stopping containers of service list in the yaml description.
+// if services are given, only containers of the services are stopped.
 // if succeed to stop, return error as nil
 // otherwise, return error.
-func (dockerExecutorImpl) Stop(id, path string) error {
+func (dockerExecutorImpl) Stop(id, path string, services ...string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -383,7 +819,37 @@ func (dockerExecutorImpl) Stop(id, path string) error {
 	if err != nil {
 		return err
 	}
-	return compose.Stop(context.Background(), 10)
+	return compose.Stop(context.Background(), STOP_TIMEOUT, services...)
+}
+
+// Restarting containers of service list in the yaml description.
+// if services are given, only containers of the services are restarted.
+// if succeed to restart, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) Restart(id, path string, services ...string) error {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	compose, err := getComposeInstance(id, path)
+	if err != nil {
+		return err
+	}
+	return compose.Restart(context.Background(), STOP_TIMEOUT, services...)
+}
+
+// Scaling the number of containers of the service to replicas.
+// containers are created or removed to match the number.
+// if succeed to scale, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) Scale(id, path, service string, replicas int) error {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	compose, err := getComposeInstance(id, path)
+	if err != nil {
+		return err
+	}
+	return compose.Scale(context.Background(), STOP_TIMEOUT, map[string]int{service: replicas})
 }
 
 // Pause containers of service list in the yaml description.
@@ -415,9 +881,10 @@ func (dockerExecutorImpl) Unpause(id, path string) error {
 }
 
 // Pulling images of service list in the yaml description.
+// if progress is given, progress of each layer is reported to it.
 // if succeed to pull, return error as nil
 // otherwise, return error.
-func (dockerExecutorImpl) Pull(id, path string, services ...string) error {
+func (dockerExecutorImpl) Pull(ctx context.Context, id, path string, progress func(PullProgress), services ...string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
@@ -425,24 +892,193 @@ func (dockerExecutorImpl) Pull(id, path string, services ...string) error {
 	if err != nil {
 		return err
 	}
-	return compose.Pull(context.Background(), services...)
+	return pullServices(ctx, compose, path, progress, services...)
+}
+
+// Pulling images of services in the yaml description which don't exist yet,
+// by docker engine to report progress of each layer to progress if it is given.
+// if succeed to pull, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) PullMissing(ctx context.Context, id, path string, progress func(PullProgress)) error {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	_, _, err := pullImages(ctx, path, true, true, progress)
+	return err
+}
+
+// Pulling images of services with docker-compose.
+// docker-compose pulls images without credentials, so images of
+// private registries which have registered credentials are pulled
+// by docker engine with them in advance and left out.
+// docker-compose doesn't report progress either, so all of images are
+// pulled by docker engine if progress is given.
+func pullServices(ctx context.Context, compose project.APIProject, path string, progress func(PullProgress), services ...string) error {
+	remaining, pulled, err := pullImages(ctx, path, progress != nil, false, progress, services...)
+	if err != nil {
+		return err
+	}
+	if pulled && len(remaining) == 0 {
+		return nil
+	}
+	return getPull(compose, ctx, remaining...)
 }
 
-// Pulling an image
+// Pulling images of services by docker engine.
+// images which belong to private registries with credentials are pulled with them,
+// and the others are pulled only if all is true.
+// if onlyMissing is true, images which already exist are not pulled.
+// return names of services whose images are not pulled, all services if none is given,
+// and whether any image is pulled.
+func pullImages(ctx context.Context, path string, all, onlyMissing bool, progress func(PullProgress), services ...string) ([]string, bool, error) {
+	images, err := getServiceImages(path)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return services, false, nil
+	}
+
+	targets := services
+	if len(targets) == 0 {
+		for service := range images {
+			targets = append(targets, service)
+		}
+		sort.Strings(targets)
+	}
+
+	remaining := make([]string, 0)
+	pulled := false
+	for _, service := range targets {
+		image := images[service]
+		auth := getRegistryAuth(image)
+		if len(image) == 0 || (len(auth) == 0 && !all) {
+			remaining = append(remaining, service)
+			continue
+		}
+
+		if onlyMissing {
+			if _, _, err := getImageInspect(client, ctx, image); err == nil {
+				continue
+			}
+		}
+
+		err = pullImage(ctx, image, auth, progress)
+		if err != nil {
+			return nil, pulled, err
+		}
+		pulled = true
+	}
+
+	if !pulled {
+		return services, false, nil
+	}
+	return remaining, true, nil
+}
+
+// Returns images of services in the yaml description.
+func getServiceImages(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.IOError{Msg: "file io fail : " + err.Error()}
+	}
+
+	description := struct {
+		Services map[string]struct {
+			Image string `yaml:"image"`
+		} `yaml:"services"`
+	}{}
+	err = yaml.Unmarshal(data, &description)
+	if err != nil {
+		return nil, errors.InvalidYaml{Msg: "invalid yaml syntax"}
+	}
+
+	images := make(map[string]string)
+	for service, config := range description.Services {
+		images[service] = config.Image
+	}
+	return images, nil
+}
+
+// Returns encoded auth config of the registry an image belongs to,
+// or empty string if no credentials are registered for the registry.
+func getRegistryAuth(image string) string {
+	if len(image) == 0 {
+		return ""
+	}
+
+	credential, err := registryExecutor.FindCredential(image)
+	if err != nil {
+		if _, ok := err.(errors.NotFound); !ok {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+		return ""
+	}
+
+	authConfig := types.AuthConfig{
+		Username:      credential[privateRegistry.USERNAME].(string),
+		Password:      credential[privateRegistry.PASSWORD].(string),
+		ServerAddress: credential[privateRegistry.HOST].(string),
+	}
+	encoded, err := json.Marshal(authConfig)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return ""
+	}
+	return base64.URLEncoding.EncodeToString(encoded)
+}
+
+// Pulling an image,
+// with credentials if they are registered for the registry of the image.
 // if succeed to pull, return error as nil
 // otherwise, return error.
 func (dockerExecutorImpl) ImagePull(image string) error {
 	logger.Logging(logger.DEBUG)
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	rc, err := getImagePull(client, context.Background(), image, types.ImagePullOptions{})
+	return pullImage(context.Background(), image, getRegistryAuth(image), nil)
+}
+
+// Pulling an image with encoded auth config,
+// progress of each layer in the stream is reported to progress if it is given.
+func pullImage(ctx context.Context, image, auth string, progress func(PullProgress)) error {
+	rc, err := getImagePull(client, ctx, image, types.ImagePullOptions{RegistryAuth: auth})
 	if err != nil {
 		logger.Logging(logger.DEBUG, err.Error())
 		return err
 	}
-	var buf1 bytes.Buffer
-	io.Copy(&buf1, rc)
-	return err
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	for {
+		var message pullMessage
+		err = decoder.Decode(&message)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// The rest of stream which can't be decoded is just drained.
+			io.Copy(ioutil.Discard, rc)
+			break
+		}
+
+		if len(message.Error) != 0 {
+			logger.Logging(logger.ERROR, message.Error)
+			return errors.Unknown{Msg: "fail to pull " + image + " : " + message.Error}
+		}
+		// The first message of which id is the tag of image is not about a layer.
+		if progress != nil && len(message.ID) != 0 && !strings.HasPrefix(message.Status, PULLING_FROM) {
+			progress(PullProgress{
+				Image:   image,
+				Layer:   message.ID,
+				Status:  message.Status,
+				Current: message.ProgressDetail.Current,
+				Total:   message.ProgressDetail.Total,
+			})
+		}
+	}
+	return ctx.Err()
 }
 
 // Tagging an image with repoTags
@@ -486,7 +1122,8 @@ func (dockerExecutorImpl) Ps(id, path string, args ...string) ([]map[string]stri
 }
 
 // Getting container config in the docker engine by container name.
-// if succeed to get, return state and exit code of container,
+// if succeed to get, return state, exit code, restart count
+// and health status of container,
 // othewise, return error.
 func (d dockerExecutorImpl) GetContainerConfigByName(containerName string) (map[string]interface{}, error) {
 	logger.Logging(logger.DEBUG)
@@ -512,6 +1149,11 @@ func (d dockerExecutorImpl) GetContainerConfigByName(containerName string) (map[
 			ret[PORTS] = container.Ports
 			ret[STATUS] = container.State
 			ret[EXITCODE] = strconv.Itoa(ins.State.ExitCode)
+			ret[RESTARTCOUNT] = ins.RestartCount
+			ret[HEALTH] = ""
+			if ins.State.Health != nil {
+				ret[HEALTH] = ins.State.Health.Status
+			}
 
 			return ret, nil
 		}
@@ -519,6 +1161,216 @@ func (d dockerExecutorImpl) GetContainerConfigByName(containerName string) (map[
 	return nil, errors.NotFoundImage{Msg: "can not found container"}
 }
 
+// Getting all of containers in the docker engine with their published ports.
+// if succeed to get, return the list of containers,
+// otherwise, return error.
+func (dockerExecutorImpl) GetContainers() ([]ContainerInfo, error) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	containers, err := getContainerList(client, context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "fail to get the container list from docker engine"}
+	}
+
+	infos := make([]ContainerInfo, 0, len(containers))
+	for _, container := range containers {
+		info := ContainerInfo{
+			ID:      container.ID,
+			State:   container.State,
+			Image:   container.Image,
+			ImageID: container.ImageID,
+			Project: container.Labels[COMPOSE_PROJECT_LABEL],
+			Service: container.Labels[COMPOSE_SERVICE_LABEL],
+			Ports:   make([]PortBinding, 0),
+		}
+		if len(container.Names) != 0 {
+			info.Name = strings.TrimPrefix(container.Names[0], "/")
+		}
+		for _, port := range container.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+			info.Ports = append(info.Ports, PortBinding{IP: port.IP, Port: int(port.PublicPort), Protocol: port.Type})
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Checking an image is available without pulling it.
+// an image is available if it exists in the docker engine
+// or its manifest can be fetched from the registry,
+// with credentials if they are registered for the registry of the image.
+// if available, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) CheckImage(image string) error {
+	logger.Logging(logger.DEBUG, image)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if _, _, err := getImageInspect(client, context.Background(), image); err == nil {
+		return nil
+	}
+
+	_, err := getDistributionInspect(client, context.Background(), image, getRegistryAuth(image))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.NotFoundImage{Msg: "can not found image in the registry : " + err.Error()}
+	}
+	return nil
+}
+
+// Removing a container which is not running with its anonymous volumes.
+// if succeed to remove, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) RemoveContainer(containerID string) error {
+	logger.Logging(logger.DEBUG, containerID)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	err := getContainerRemove(client, context.Background(), containerID, types.ContainerRemoveOptions{RemoveVolumes: true})
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.Unknown{Msg: "fail to remove container " + containerID + " : " + err.Error()}
+	}
+	return nil
+}
+
+// Getting a summary list of images in the docker engine,
+// intermediate images are not included.
+// if succeed to get, return list of images
+// otherwise, return error.
+func (dockerExecutorImpl) GetImages() ([]ImageInfo, error) {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	images, err := getImageList(client, context.Background(), types.ImageListOptions{})
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "fail to get the image list from docker engine"}
+	}
+
+	infos := make([]ImageInfo, 0, len(images))
+	for _, image := range images {
+		infos = append(infos, ImageInfo{
+			ID:          image.ID,
+			RepoTags:    image.RepoTags,
+			RepoDigests: image.RepoDigests,
+			Created:     image.Created,
+			Size:        image.Size,
+		})
+	}
+	return infos, nil
+}
+
+// Removing an image with all of its tags and untagged parents.
+// the image is removed even though stopped containers use it,
+// so callers should check that no container uses it.
+// if succeed to remove, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) RemoveImage(imageID string) error {
+	logger.Logging(logger.DEBUG, imageID)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	_, err := getImageRemove(client, context.Background(), imageID, types.ImageRemoveOptions{Force: true, PruneChildren: true})
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.Unknown{Msg: "fail to remove image " + imageID + " : " + err.Error()}
+	}
+	return nil
+}
+
+// Saving images with their tags as a tarball like 'docker save'.
+// the caller should close the returned reader.
+// if succeed to save, return the tarball
+// otherwise, return error.
+func (dockerExecutorImpl) ImageSave(images []string) (io.ReadCloser, error) {
+	logger.Logging(logger.DEBUG, images...)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	output, err := getImageSave(client, context.Background(), images)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "fail to save images : " + err.Error()}
+	}
+	return output, nil
+}
+
+// Loading images from a tarball made by ImageSave like 'docker load'.
+// if succeed to load, return error as nil
+// otherwise, return error.
+func (dockerExecutorImpl) ImageLoad(input io.Reader) error {
+	logger.Logging(logger.DEBUG)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	resp, err := getImageLoad(client, context.Background(), input, true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.Unknown{Msg: "fail to load images : " + err.Error()}
+	}
+	defer resp.Body.Close()
+
+	// Images are loaded while the response is read.
+	_, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return errors.Unknown{Msg: "fail to load images : " + err.Error()}
+	}
+	return nil
+}
+
+// Estimating disk space which pulling an image will take.
+// if the image already exists in the docker engine, return 0,
+// otherwise, return the size of its manifest in the registry, or the largest size
+// of other tags of the same repository in the docker engine if the registry can't tell.
+// if the size can't be estimated, return NotFoundImage error.
+func (dockerExecutorImpl) EstimateImageSize(image string) (int64, error) {
+	logger.Logging(logger.DEBUG, image)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if _, _, err := getImageInspect(client, context.Background(), image); err == nil {
+		return 0, nil
+	}
+
+	size, err := getManifestSize(image)
+	if err == nil {
+		return size, nil
+	}
+	logger.Logging(logger.ERROR, "fail to get the size of image from registry : "+err.Error())
+
+	images, err := getImageList(client, context.Background(), types.ImageListOptions{})
+	if err != nil {
+		logger.Logging(logger.ERROR, "fail to get the image list from docker engine")
+		return 0, errors.Unknown{Msg: "fail to get the image list from docker engine"}
+	}
+
+	found := false
+	repository := getRepository(image)
+	for _, summary := range images {
+		for _, repoTag := range summary.RepoTags {
+			if getRepository(repoTag) == repository && summary.Size > size {
+				size = summary.Size
+				found = true
+			}
+		}
+	}
+	if !found {
+		return 0, errors.NotFoundImage{Msg: "can not estimate size of image : " + image}
+	}
+	return size, nil
+}
+
+// Returns the repository part of an image name, without tag and digest.
+func getRepository(image string) string {
+	if idx := strings.Index(image, "@"); idx != -1 {
+		image = image[:idx]
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		image = image[:idx]
+	}
+	return image
+}
+
 // Getting image digest in the docker engine by image name.
 // if succeed to get, return digest of image,
 // othewise, return error.
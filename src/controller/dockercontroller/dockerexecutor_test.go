@@ -17,20 +17,28 @@
 package dockercontroller
 
 import (
+	"bufio"
 	"bytes"
 	"commons/errors"
+	"controller/imagepolicy"
+	registrymocks "controller/registry/mocks"
 	"docker.io/go-docker"
 	"docker.io/go-docker/api/types"
 	"docker.io/go-docker/api/types/container"
+	"docker.io/go-docker/api/types/registry"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	origineErr "errors"
 	"github.com/docker/libcompose/project"
 	"github.com/docker/libcompose/project/events"
 	"github.com/docker/libcompose/project/options"
+	"github.com/golang/mock/gomock"
 	"golang.org/x/net/context"
 	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"reflect"
 	"strconv"
@@ -242,7 +250,7 @@ func TestUpWithEvent(t *testing.T) {
 		}
 
 		evt := make(chan Event)
-		err := Executor.UpWithEvent(testAppID, testFileName, testEventID, evt)
+		err := Executor.UpWithEvent(context.Background(), testAppID, testFileName, testEventID, evt)
 		if err != nil {
 			t.Errorf("Exepcted err : nil, Actual err : %s", err.Error())
 		}
@@ -251,6 +259,117 @@ func TestUpWithEvent(t *testing.T) {
 	})
 }
 
+func TestPullWithProgress(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	origin := registryExecutor
+	defer func() { registryExecutor = origin }()
+	defer func() { getImageInspect = (*docker.Client).ImageInspectWithRaw }()
+
+	composeFile := "progress-compose.yaml"
+	description := "services:\n  db:\n    image: db:1.0\n  web:\n    image: web:1.0\nversion: \"2\"\n"
+	ioutil.WriteFile(composeFile, []byte(description), os.FileMode(0644))
+	defer os.Remove(composeFile)
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+	registryExecutorMockObj.EXPECT().FindCredential(gomock.Any()).Return(nil, errors.NotFound{}).AnyTimes()
+	registryExecutor = registryExecutorMockObj
+
+	fakeGetComposeInstanceImpl = func() (project.APIProject, error) {
+		return nil, nil
+	}
+	stream := `{"status":"Pulling from library/test","id":"1.0"}
+{"status":"Downloading","id":"layer","progressDetail":{"current":10,"total":100}}
+{"status":"Pull complete","id":"layer","progressDetail":{}}
+{"status":"Status: Downloaded newer image"}
+`
+	var pulledImages []string
+	imagePull := func(_ *docker.Client, ctx context.Context, image string, options types.ImagePullOptions) (io.ReadCloser, error) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		pulledImages = append(pulledImages, image)
+		return ioutil.NopCloser(strings.NewReader(stream)), nil
+	}
+	getImagePull = imagePull
+	var composePulled [][]string
+	getPull = func(_ project.APIProject, _ context.Context, services ...string) error {
+		composePulled = append(composePulled, services)
+		return nil
+	}
+
+	var reported []PullProgress
+	progress := func(p PullProgress) {
+		reported = append(reported, p)
+	}
+
+	t.Run("AllServices_ExpectProgressOfLayersReported", func(t *testing.T) {
+		pulledImages, composePulled, reported = nil, nil, nil
+
+		err := Executor.Pull(context.Background(), "", composeFile, progress)
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if !reflect.DeepEqual(pulledImages, []string{"db:1.0", "web:1.0"}) || len(composePulled) != 0 {
+			t.Errorf("Unexpected pulls : %v, %v", pulledImages, composePulled)
+		}
+		expected := []PullProgress{
+			{Image: "db:1.0", Layer: "layer", Status: "Downloading", Current: 10, Total: 100},
+			{Image: "db:1.0", Layer: "layer", Status: "Pull complete"},
+			{Image: "web:1.0", Layer: "layer", Status: "Downloading", Current: 10, Total: 100},
+			{Image: "web:1.0", Layer: "layer", Status: "Pull complete"},
+		}
+		if !reflect.DeepEqual(expected, reported) {
+			t.Errorf("Expected progress : %v, Actual progress : %v", expected, reported)
+		}
+	})
+
+	t.Run("PullMissing_ExpectExistingImageSkipped", func(t *testing.T) {
+		pulledImages, composePulled, reported = nil, nil, nil
+		getImageInspect = func(_ *docker.Client, _ context.Context, image string) (types.ImageInspect, []byte, error) {
+			if image == "web:1.0" {
+				return types.ImageInspect{}, nil, nil
+			}
+			return types.ImageInspect{}, nil, origineErr.New("")
+		}
+
+		err := Executor.PullMissing(context.Background(), "", composeFile, progress)
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if !reflect.DeepEqual(pulledImages, []string{"db:1.0"}) || len(composePulled) != 0 {
+			t.Errorf("Unexpected pulls : %v, %v", pulledImages, composePulled)
+		}
+	})
+
+	t.Run("ErrorInStream_ExpectUnknown", func(t *testing.T) {
+		getImagePull = func(*docker.Client, context.Context, string, types.ImagePullOptions) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(`{"error":"pull access denied"}`)), nil
+		}
+
+		err := Executor.Pull(context.Background(), "", composeFile, progress, "db")
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err : %s, Actual err : %v", "Unknown", err)
+		case errors.Unknown:
+		}
+	})
+
+	t.Run("Canceled_ExpectContextError", func(t *testing.T) {
+		getImagePull = imagePull
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Executor.Pull(ctx, "", composeFile, progress)
+		if err != context.Canceled {
+			t.Errorf("Expected err : %v, Actual err : %v", context.Canceled, err)
+		}
+	})
+}
+
 func TestGetImageIDByRepoDigest(t *testing.T) {
 	tearDown := setUp(t)
 	defer tearDown(t)
@@ -559,6 +678,28 @@ func TestGetContainerConfigByName(t *testing.T) {
 			t.Error()
 		}
 	})
+
+	t.Run("GetHealthStatusSuccessful", func(t *testing.T) {
+		unhealthyState := types.ContainerState{ExitCode: 0, Health: &types.Health{Status: "unhealthy"}}
+		unhealthyInspect := types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{RestartCount: 2},
+			Mounts:            []types.MountPoint{},
+			Config:            new(container.Config),
+			NetworkSettings:   new(types.NetworkSettings),
+		}
+		unhealthyInspect.State = &unhealthyState
+
+		fakeRunContainerList = func() ([]types.Container, error) {
+			return retContainers, nil
+		}
+		fakeRunContaienrInspect = func() (types.ContainerJSON, error) {
+			return unhealthyInspect, nil
+		}
+		inspect, _ := Executor.GetContainerConfigByName("test_123")
+		if inspect[HEALTH].(string) != "unhealthy" || inspect[RESTARTCOUNT].(int) != 2 {
+			t.Error()
+		}
+	})
 }
 
 func TestCalcNetworkIO(t *testing.T) {
@@ -652,7 +793,7 @@ func TestComposeFunctionality(t *testing.T) {
 		return nil, origineErr.New("")
 	}
 
-	err := Executor.Create("", "")
+	err := Executor.Create(context.Background(), "", "")
 	checkError(t, err)
 	err = Executor.Down("", "")
 	checkError(t, err)
@@ -662,15 +803,19 @@ func TestComposeFunctionality(t *testing.T) {
 	checkError(t, err)
 	_, err = Executor.Ps("", "")
 	checkError(t, err)
-	err = Executor.Pull("", "")
+	err = Executor.Pull(context.Background(), "", "", nil)
 	checkError(t, err)
 	err = Executor.Start("", "")
 	checkError(t, err)
 	err = Executor.Stop("", "")
 	checkError(t, err)
+	err = Executor.Restart("", "", "web")
+	checkError(t, err)
+	err = Executor.Scale("", "", "web", 2)
+	checkError(t, err)
 	err = Executor.Unpause("", "")
 	checkError(t, err)
-	err = Executor.Up("", "", true)
+	err = Executor.Up(context.Background(), "", "", true)
 	checkError(t, err)
 }
 
@@ -688,3 +833,659 @@ func TestDockerFunctionality(t *testing.T) {
 	err = Executor.ImageTag("", "")
 	checkError(t, err)
 }
+
+func makeLogFrame(stream byte, payload string) []byte {
+	header := []byte{stream, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, []byte(payload)...)
+}
+
+func collectLogs(body io.Reader, tty bool) []LogEntry {
+	entries := make(chan LogEntry)
+	go func() {
+		readLogs(context.Background(), body, tty, "web", "app_web_1", entries)
+		close(entries)
+	}()
+
+	result := make([]LogEntry, 0)
+	for entry := range entries {
+		result = append(result, entry)
+	}
+	return result
+}
+
+func TestReadLogs(t *testing.T) {
+	t.Run("Multiplexed_ExpectDemultiplexedLines", func(t *testing.T) {
+		body := bytes.NewBuffer(nil)
+		body.Write(makeLogFrame(1, "2018-01-01T00:00:00.000000001Z hello\n"))
+		body.Write(makeLogFrame(2, "2018-01-01T00:00:00.000000002Z fail"))
+		body.Write(makeLogFrame(2, "ed\n2018-01-01T00:00:00.000000003Z again\n"))
+		body.Write(makeLogFrame(1, "2018-01-01T00:00:00.000000004Z no newline"))
+
+		expected := []LogEntry{
+			{"web", "app_web_1", STDOUT, "2018-01-01T00:00:00.000000001Z", "hello"},
+			{"web", "app_web_1", STDERR, "2018-01-01T00:00:00.000000002Z", "failed"},
+			{"web", "app_web_1", STDERR, "2018-01-01T00:00:00.000000003Z", "again"},
+			{"web", "app_web_1", STDOUT, "2018-01-01T00:00:00.000000004Z", "no newline"},
+		}
+		result := collectLogs(body, false)
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("Expected result : %v, Actual Result : %v", expected, result)
+		}
+	})
+
+	t.Run("Tty_ExpectStdoutLines", func(t *testing.T) {
+		body := strings.NewReader("2018-01-01T00:00:00.000000001Z hello\r\n2018-01-01T00:00:00.000000002Z world\n")
+
+		expected := []LogEntry{
+			{"web", "app_web_1", STDOUT, "2018-01-01T00:00:00.000000001Z", "hello"},
+			{"web", "app_web_1", STDOUT, "2018-01-01T00:00:00.000000002Z", "world"},
+		}
+		result := collectLogs(body, true)
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("Expected result : %v, Actual Result : %v", expected, result)
+		}
+	})
+
+	t.Run("TtyLongLine_ExpectWholeLine", func(t *testing.T) {
+		long := strings.Repeat("a", 100*1024)
+		body := strings.NewReader("2018-01-01T00:00:00.000000001Z " + long + "\n")
+
+		expected := []LogEntry{
+			{"web", "app_web_1", STDOUT, "2018-01-01T00:00:00.000000001Z", long},
+		}
+		result := collectLogs(body, true)
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("Unexpected result of long line, length : %d", len(result))
+		}
+	})
+
+	t.Run("Cancelled_ExpectReturn", func(t *testing.T) {
+		body := bytes.NewBuffer(makeLogFrame(1, "2018-01-01T00:00:00.000000001Z hello\n"))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// nobody receives entries, readLogs should return by cancellation.
+		readLogs(ctx, body, false, "web", "app_web_1", make(chan LogEntry))
+	})
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestLogsWhenInspectFailedAfterLogsOpened_ExpectOpenedLogsClosed(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+	defer func() {
+		getContainerLogs = (*docker.Client).ContainerLogs
+	}()
+
+	fakeGetComposeInstanceImpl = func() (project.APIProject, error) {
+		return nil, nil
+	}
+	fakeRunComposePs = func() (project.InfoSet, error) {
+		return project.InfoSet{{"Id": "cid1", "Name": "app_web_1"}, {"Id": "cid2", "Name": "app_db_1"}}, nil
+	}
+	fakeRunContainerList = func() ([]types.Container, error) {
+		return []types.Container{
+			{ID: "cid1", Names: []string{"/app_web_1"}, Labels: map[string]string{COMPOSE_SERVICE_LABEL: "web"}},
+			{ID: "cid2", Names: []string{"/app_db_1"}, Labels: map[string]string{COMPOSE_SERVICE_LABEL: "db"}},
+		}, nil
+	}
+	inspected := 0
+	fakeRunContaienrInspect = func() (types.ContainerJSON, error) {
+		inspected++
+		if inspected > 1 {
+			return types.ContainerJSON{}, errors.Unknown{}
+		}
+		return types.ContainerJSON{}, nil
+	}
+	body := &closeRecorder{Reader: strings.NewReader("")}
+	getContainerLogs = func(*docker.Client, context.Context, string, types.ContainerLogsOptions) (io.ReadCloser, error) {
+		return body, nil
+	}
+
+	_, err := Executor.Logs(context.Background(), "test", "path", LogOptions{})
+	if err == nil {
+		t.Errorf("Expected err, actual err : nil")
+	}
+	if !body.closed {
+		t.Errorf("Expected opened logs closed")
+	}
+}
+
+func TestExec(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+	defer func() {
+		getExecCreate = (*docker.Client).ContainerExecCreate
+		getExecAttach = (*docker.Client).ContainerExecAttach
+	}()
+
+	fakeGetComposeInstanceImpl = func() (project.APIProject, error) {
+		return nil, nil
+	}
+	fakeRunComposePs = func() (project.InfoSet, error) {
+		return project.InfoSet{{"Id": "testcid", "Name": "app_web_1"}}, nil
+	}
+
+	t.Run("Success_ExpectAttachedStream", func(t *testing.T) {
+		server, client := net.Pipe()
+		defer server.Close()
+
+		getExecCreate = func(_ *docker.Client, _ context.Context, cid string, config types.ExecConfig) (types.IDResponse, error) {
+			if cid != "testcid" || !reflect.DeepEqual(config.Cmd, []string{"sh"}) || !config.Tty || !config.AttachStdin {
+				t.Errorf("Unexpected exec : %s, %v", cid, config)
+			}
+			return types.IDResponse{ID: "testexec"}, nil
+		}
+		getExecAttach = func(_ *docker.Client, _ context.Context, execID string, _ types.ExecStartCheck) (types.HijackedResponse, error) {
+			return types.HijackedResponse{Conn: client, Reader: bufio.NewReader(client)}, nil
+		}
+
+		stream, err := Executor.Exec(context.Background(), "test", "path", "web", []string{"sh"}, true)
+		if err != nil {
+			t.Fatalf("Unexpected err : %s", err.Error())
+		}
+		defer stream.Close()
+
+		go stream.Write([]byte("ls\n"))
+		buf := make([]byte, 3)
+		io.ReadFull(server, buf)
+		if string(buf) != "ls\n" {
+			t.Errorf("Expected stdin : ls, Actual stdin : %s", buf)
+		}
+
+		go server.Write([]byte("ok\n"))
+		io.ReadFull(stream, buf)
+		if string(buf) != "ok\n" {
+			t.Errorf("Expected stdout : ok, Actual stdout : %s", buf)
+		}
+	})
+
+	t.Run("NoContainer_ExpectInvalidParam", func(t *testing.T) {
+		fakeRunComposePs = func() (project.InfoSet, error) {
+			return project.InfoSet{}, nil
+		}
+		defer func() {
+			fakeRunComposePs = func() (project.InfoSet, error) {
+				return project.InfoSet{{"Id": "testcid", "Name": "app_web_1"}}, nil
+			}
+		}()
+
+		_, err := Executor.Exec(context.Background(), "test", "path", "unknown", []string{"sh"}, false)
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: InvalidParam, actual err: %v", err)
+		case errors.InvalidParam:
+		}
+	})
+
+	t.Run("ExecCreateError_ExpectUnknown", func(t *testing.T) {
+		getExecCreate = func(*docker.Client, context.Context, string, types.ExecConfig) (types.IDResponse, error) {
+			return types.IDResponse{}, origineErr.New("container is not running")
+		}
+
+		_, err := Executor.Exec(context.Background(), "test", "path", "web", []string{"sh"}, false)
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: Unknown, actual err: %v", err)
+		case errors.Unknown:
+		}
+	})
+}
+
+func TestGetContainers(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+
+	t.Run("Success_ExpectPublishedPorts", func(t *testing.T) {
+		fakeRunContainerList = func() ([]types.Container, error) {
+			return []types.Container{
+				{
+					ID:      "container_id",
+					Names:   []string{"/app_web_1"},
+					State:   "running",
+					Image:   "web:1.0",
+					ImageID: "image_id",
+					Labels:  map[string]string{COMPOSE_PROJECT_LABEL: "app", COMPOSE_SERVICE_LABEL: "web"},
+					Ports: []types.Port{
+						{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+						{PrivatePort: 443, Type: "tcp"},
+					},
+				},
+			}, nil
+		}
+
+		containers, err := Executor.GetContainers()
+		if err != nil {
+			t.Fatalf("Unexpected err : %s", err.Error())
+		}
+
+		expected := []ContainerInfo{
+			{ID: "container_id", Name: "app_web_1", State: "running", Image: "web:1.0", ImageID: "image_id", Project: "app", Service: "web", Ports: []PortBinding{{IP: "0.0.0.0", Port: 8080, Protocol: "tcp"}}},
+		}
+		if !reflect.DeepEqual(containers, expected) {
+			t.Errorf("Expected containers : %v, Actual containers : %v", expected, containers)
+		}
+	})
+
+	t.Run("ContainerListError_ExpectUnknown", func(t *testing.T) {
+		fakeRunContainerList = func() ([]types.Container, error) {
+			return nil, origineErr.New("")
+		}
+
+		_, err := Executor.GetContainers()
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: Unknown, actual err: %v", err)
+		case errors.Unknown:
+		}
+	})
+}
+
+func TestCheckImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer func() {
+		getImageInspect = (*docker.Client).ImageInspectWithRaw
+		getDistributionInspect = (*docker.Client).DistributionInspect
+	}()
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+	registryExecutorMockObj.EXPECT().FindCredential(gomock.Any()).Return(nil, errors.NotFound{}).AnyTimes()
+	registryExecutor = registryExecutorMockObj
+
+	localImageErr := origineErr.New("no such image")
+	remoteImageErr := origineErr.New("manifest unknown")
+	tests := []struct {
+		name     string
+		localErr error
+		remote   error
+		expected error
+	}{
+		{"LocalImage_ExpectSuccess", nil, remoteImageErr, nil},
+		{"RemoteImage_ExpectSuccess", localImageErr, nil, nil},
+		{"NoImage_ExpectNotFoundImage", localImageErr, remoteImageErr, errors.NotFoundImage{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getImageInspect = func(*docker.Client, context.Context, string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{}, nil, test.localErr
+			}
+			getDistributionInspect = func(*docker.Client, context.Context, string, string) (registry.DistributionInspect, error) {
+				return registry.DistributionInspect{}, test.remote
+			}
+
+			err := Executor.CheckImage("test:1.0")
+			if reflect.TypeOf(err) != reflect.TypeOf(test.expected) {
+				t.Errorf("Expected err : %v, Actual err : %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckImageInPrivateRegistry_ExpectCredentialUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer func() {
+		getImageInspect = (*docker.Client).ImageInspectWithRaw
+		getDistributionInspect = (*docker.Client).DistributionInspect
+	}()
+
+	privateImage := "test_url:5000/private:1.0"
+	credential := map[string]interface{}{
+		"host":     "test_url:5000",
+		"username": "test_user",
+		"password": "test_password",
+	}
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+	registryExecutorMockObj.EXPECT().FindCredential(privateImage).Return(credential, nil)
+	registryExecutor = registryExecutorMockObj
+
+	getImageInspect = func(*docker.Client, context.Context, string) (types.ImageInspect, []byte, error) {
+		return types.ImageInspect{}, nil, origineErr.New("no such image")
+	}
+	var auth string
+	getDistributionInspect = func(_ *docker.Client, _ context.Context, _ string, encodedAuth string) (registry.DistributionInspect, error) {
+		auth = encodedAuth
+		return registry.DistributionInspect{}, nil
+	}
+
+	err := Executor.CheckImage(privateImage)
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(auth)
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+	authConfig := types.AuthConfig{}
+	json.Unmarshal(decoded, &authConfig)
+	expected := types.AuthConfig{Username: "test_user", Password: "test_password", ServerAddress: "test_url:5000"}
+	if authConfig != expected {
+		t.Errorf("Expected auth : %v, Actual auth : %v", expected, authConfig)
+	}
+}
+
+func TestEstimateImageSize(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+	defer func() {
+		getImageInspect = (*docker.Client).ImageInspectWithRaw
+		getManifestSize = imagepolicy.ManifestSize
+	}()
+
+	localImageErr := origineErr.New("no such image")
+	registryErr := errors.ConnectionError{Msg: "can't connect to registry"}
+	images := []types.ImageSummary{
+		{RepoTags: []string{"registry:5000/test:0.9"}, Size: 100},
+		{RepoTags: []string{"registry:5000/test:0.8"}, Size: 200},
+		{RepoTags: []string{"registry:5000/other:1.0"}, Size: 300},
+	}
+	tests := []struct {
+		name         string
+		image        string
+		localErr     error
+		registryErr  error
+		listErr      error
+		expectedSize int64
+		expected     error
+	}{
+		{"LocalImage_ExpectZero", "registry:5000/test:1.0", nil, nil, nil, 0, nil},
+		{"RegistryManifest_ExpectManifestSize", "registry:5000/test:1.0", localImageErr, nil, nil, 500, nil},
+		{"SameRepository_ExpectLargestSize", "registry:5000/test:1.0", localImageErr, registryErr, nil, 200, nil},
+		{"UnknownRepository_ExpectNotFoundImage", "registry:5000/unknown:1.0", localImageErr, registryErr, nil, 0, errors.NotFoundImage{}},
+		{"ImageListFailed_ExpectUnknown", "registry:5000/test:1.0", localImageErr, registryErr, origineErr.New(""), 0, errors.Unknown{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getImageInspect = func(*docker.Client, context.Context, string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{}, nil, test.localErr
+			}
+			getManifestSize = func(string) (int64, error) {
+				if test.registryErr != nil {
+					return 0, test.registryErr
+				}
+				return 500, nil
+			}
+			fakeRunImageList = func() ([]types.ImageSummary, error) {
+				return images, test.listErr
+			}
+
+			size, err := Executor.EstimateImageSize(test.image)
+			if reflect.TypeOf(err) != reflect.TypeOf(test.expected) {
+				t.Errorf("Expected err : %v, Actual err : %v", test.expected, err)
+			}
+			if size != test.expectedSize {
+				t.Errorf("Expected size : %d, Actual size : %d", test.expectedSize, size)
+			}
+		})
+	}
+}
+
+func TestPullWithPrivateRegistry(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	origin := registryExecutor
+	defer func() { registryExecutor = origin }()
+
+	privateImage := "test_url:5000/private:1.0"
+	publicImage := "ubuntu:latest"
+	composeFile := "private-registry-compose.yaml"
+	description := "services:\n  private:\n    image: " + privateImage + "\n  public:\n    image: " + publicImage + "\nversion: \"2\"\n"
+	ioutil.WriteFile(composeFile, []byte(description), os.FileMode(0644))
+	defer os.Remove(composeFile)
+
+	credential := map[string]interface{}{
+		"host":     "test_url:5000",
+		"username": "test_user",
+		"password": "test_password",
+	}
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+	registryExecutorMockObj.EXPECT().FindCredential(privateImage).Return(credential, nil).AnyTimes()
+	registryExecutorMockObj.EXPECT().FindCredential(publicImage).Return(nil, errors.NotFound{}).AnyTimes()
+	registryExecutor = registryExecutorMockObj
+
+	fakeGetComposeInstanceImpl = func() (project.APIProject, error) {
+		return nil, nil
+	}
+	var pulledImages []string
+	var auths []string
+	getImagePull = func(_ *docker.Client, _ context.Context, image string, options types.ImagePullOptions) (io.ReadCloser, error) {
+		pulledImages = append(pulledImages, image)
+		auths = append(auths, options.RegistryAuth)
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+	var composePulled [][]string
+	getPull = func(_ project.APIProject, _ context.Context, services ...string) error {
+		composePulled = append(composePulled, services)
+		return nil
+	}
+
+	t.Run("AllServices_ExpectPrivateImagePulledWithCredential", func(t *testing.T) {
+		pulledImages, auths, composePulled = nil, nil, nil
+
+		err := Executor.Pull(context.Background(), "", composeFile, nil)
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if !reflect.DeepEqual(pulledImages, []string{privateImage}) {
+			t.Errorf("Unexpected pulled images : %v", pulledImages)
+		}
+		if !reflect.DeepEqual(composePulled, [][]string{{"public"}}) {
+			t.Errorf("Unexpected services pulled by compose : %v", composePulled)
+		}
+
+		decoded, err := base64.URLEncoding.DecodeString(auths[0])
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		authConfig := types.AuthConfig{}
+		json.Unmarshal(decoded, &authConfig)
+		expected := types.AuthConfig{Username: "test_user", Password: "test_password", ServerAddress: "test_url:5000"}
+		if authConfig != expected {
+			t.Errorf("Expected auth config : %v, Actual auth config : %v", expected, authConfig)
+		}
+	})
+
+	t.Run("PrivateServiceOnly_ExpectComposePullSkipped", func(t *testing.T) {
+		pulledImages, auths, composePulled = nil, nil, nil
+
+		err := Executor.Pull(context.Background(), "", composeFile, nil, "private")
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if len(pulledImages) != 1 || len(composePulled) != 0 {
+			t.Errorf("Unexpected pulls : %v, %v", pulledImages, composePulled)
+		}
+	})
+
+	t.Run("PublicServiceOnly_ExpectPulledByCompose", func(t *testing.T) {
+		pulledImages, auths, composePulled = nil, nil, nil
+
+		err := Executor.Pull(context.Background(), "", composeFile, nil, "public")
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if len(pulledImages) != 0 || !reflect.DeepEqual(composePulled, [][]string{{"public"}}) {
+			t.Errorf("Unexpected pulls : %v, %v", pulledImages, composePulled)
+		}
+	})
+
+	t.Run("ImagePull_ExpectCredentialUsed", func(t *testing.T) {
+		pulledImages, auths, composePulled = nil, nil, nil
+
+		err := Executor.ImagePull(privateImage)
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		err = Executor.ImagePull(publicImage)
+		if err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if len(auths) != 2 || len(auths[0]) == 0 || len(auths[1]) != 0 {
+			t.Errorf("Unexpected auths : %v", auths)
+		}
+	})
+}
+
+func TestGetImages(t *testing.T) {
+	tearDown := setUp(t)
+	defer tearDown(t)
+
+	t.Run("Success_ExpectImageInfos", func(t *testing.T) {
+		fakeRunImageList = func() ([]types.ImageSummary, error) {
+			return []types.ImageSummary{
+				{ID: "image_id", RepoTags: []string{"test:1.0"}, RepoDigests: []string{"test@sha256:1"}, Created: 10, Size: 100},
+			}, nil
+		}
+
+		images, err := Executor.GetImages()
+		if err != nil {
+			t.Fatalf("Unexpected err : %s", err.Error())
+		}
+
+		expected := []ImageInfo{
+			{ID: "image_id", RepoTags: []string{"test:1.0"}, RepoDigests: []string{"test@sha256:1"}, Created: 10, Size: 100},
+		}
+		if !reflect.DeepEqual(images, expected) {
+			t.Errorf("Expected images : %v, Actual images : %v", expected, images)
+		}
+	})
+
+	t.Run("ImageListError_ExpectUnknown", func(t *testing.T) {
+		fakeRunImageList = func() ([]types.ImageSummary, error) {
+			return nil, origineErr.New("")
+		}
+
+		_, err := Executor.GetImages()
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: Unknown, actual err: %v", err)
+		case errors.Unknown:
+		}
+	})
+}
+
+func TestRemoveImageAndContainer(t *testing.T) {
+	defer func() {
+		getImageRemove = (*docker.Client).ImageRemove
+		getContainerRemove = (*docker.Client).ContainerRemove
+	}()
+
+	var removeErr error
+	var imageOptions types.ImageRemoveOptions
+	var containerOptions types.ContainerRemoveOptions
+	getImageRemove = func(_ *docker.Client, _ context.Context, _ string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+		imageOptions = options
+		return nil, removeErr
+	}
+	getContainerRemove = func(_ *docker.Client, _ context.Context, _ string, options types.ContainerRemoveOptions) error {
+		containerOptions = options
+		return removeErr
+	}
+
+	t.Run("Success_ExpectNil", func(t *testing.T) {
+		removeErr = nil
+		if err := Executor.RemoveImage("image_id"); err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if !imageOptions.Force || !imageOptions.PruneChildren {
+			t.Errorf("Expected forced removal with children, actual options : %v", imageOptions)
+		}
+		if err := Executor.RemoveContainer("container_id"); err != nil {
+			t.Errorf("Unexpected err : %s", err.Error())
+		}
+		if containerOptions.Force || !containerOptions.RemoveVolumes {
+			t.Errorf("Expected removal with volumes, actual options : %v", containerOptions)
+		}
+	})
+
+	t.Run("RemoveError_ExpectUnknown", func(t *testing.T) {
+		removeErr = origineErr.New("conflict")
+		for _, err := range []error{Executor.RemoveImage("image_id"), Executor.RemoveContainer("container_id")} {
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: Unknown, actual err: %v", err)
+			case errors.Unknown:
+			}
+		}
+	})
+}
+
+func TestImageSave(t *testing.T) {
+	defer func() {
+		getImageSave = (*docker.Client).ImageSave
+	}()
+
+	images := []string{"test_url:5000/test_app:v1", "nginx"}
+	getImageSave = func(_ *docker.Client, _ context.Context, imageIDs []string) (io.ReadCloser, error) {
+		if !reflect.DeepEqual(images, imageIDs) {
+			t.Errorf("Expected images : %v, Actual images : %v", images, imageIDs)
+		}
+		return ioutil.NopCloser(strings.NewReader("tarball")), nil
+	}
+
+	output, err := Executor.ImageSave(images)
+	if err != nil {
+		t.Fatalf("Unexpected err : %s", err.Error())
+	}
+	defer output.Close()
+
+	data, _ := ioutil.ReadAll(output)
+	if string(data) != "tarball" {
+		t.Errorf("Expected output : tarball, Actual output : %s", data)
+	}
+}
+
+func TestImageLoad(t *testing.T) {
+	defer func() {
+		getImageLoad = (*docker.Client).ImageLoad
+	}()
+
+	var loaded []byte
+	getImageLoad = func(_ *docker.Client, _ context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+		loaded, _ = ioutil.ReadAll(input)
+		return types.ImageLoadResponse{Body: ioutil.NopCloser(strings.NewReader("{}"))}, nil
+	}
+
+	err := Executor.ImageLoad(strings.NewReader("tarball"))
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+	if string(loaded) != "tarball" {
+		t.Errorf("Expected loaded : tarball, Actual loaded : %s", loaded)
+	}
+}
+
+func TestImageLoadWhenDockerFailed_ExpectErrorReturn(t *testing.T) {
+	defer func() {
+		getImageLoad = (*docker.Client).ImageLoad
+	}()
+
+	getImageLoad = func(_ *docker.Client, _ context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+		return types.ImageLoadResponse{}, errors.Unknown{}
+	}
+
+	err := Executor.ImageLoad(strings.NewReader("tarball"))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err : %s, Actual err : %v", "Unknown", err)
+	case errors.Unknown:
+	}
+}
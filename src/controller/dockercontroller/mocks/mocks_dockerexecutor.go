@@ -23,6 +23,8 @@ package mock_dockercontroller
 import (
 	"controller/dockercontroller"
 	gomock "github.com/golang/mock/gomock"
+	context "golang.org/x/net/context"
+	io "io"
 	reflect "reflect"
 )
 
@@ -50,20 +52,20 @@ func (m *MockCommand) EXPECT() *MockCommandMockRecorder {
 }
 
 // Create mocks base method
-func (m *MockCommand) Create(id, path string) error {
-	ret := m.ctrl.Call(m, "Create", id, path)
+func (m *MockCommand) Create(ctx context.Context, id, path string) error {
+	ret := m.ctrl.Call(m, "Create", ctx, id, path)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockCommandMockRecorder) Create(id, path interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommand)(nil).Create), id, path)
+func (mr *MockCommandMockRecorder) Create(ctx, id, path interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommand)(nil).Create), ctx, id, path)
 }
 
 // Up mocks base method
-func (m *MockCommand) Up(id, path string, forceRecreate bool, services ...string) error {
-	varargs := []interface{}{id, path, forceRecreate}
+func (m *MockCommand) Up(ctx context.Context, id, path string, forceRecreate bool, services ...string) error {
+	varargs := []interface{}{ctx, id, path, forceRecreate}
 	for _, a := range services {
 		varargs = append(varargs, a)
 	}
@@ -73,8 +75,8 @@ func (m *MockCommand) Up(id, path string, forceRecreate bool, services ...string
 }
 
 // Up indicates an expected call of Up
-func (mr *MockCommandMockRecorder) Up(id, path, forceRecreate interface{}, services ...interface{}) *gomock.Call {
-	varargs := append([]interface{}{id, path, forceRecreate}, services...)
+func (mr *MockCommandMockRecorder) Up(ctx, id, path, forceRecreate interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, id, path, forceRecreate}, services...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Up", reflect.TypeOf((*MockCommand)(nil).Up), varargs...)
 }
 
@@ -103,27 +105,66 @@ func (mr *MockCommandMockRecorder) DownWithRemoveImages(id, path interface{}) *g
 }
 
 // Start mocks base method
-func (m *MockCommand) Start(id, path string) error {
-	ret := m.ctrl.Call(m, "Start", id, path)
+func (m *MockCommand) Start(id, path string, services ...string) error {
+	varargs := []interface{}{id, path}
+	for _, a := range services {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Start", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start
-func (mr *MockCommandMockRecorder) Start(id, path interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockCommand)(nil).Start), id, path)
+func (mr *MockCommandMockRecorder) Start(id, path interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{id, path}, services...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockCommand)(nil).Start), varargs...)
 }
 
 // Stop mocks base method
-func (m *MockCommand) Stop(id, path string) error {
-	ret := m.ctrl.Call(m, "Stop", id, path)
+func (m *MockCommand) Stop(id, path string, services ...string) error {
+	varargs := []interface{}{id, path}
+	for _, a := range services {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Stop", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Stop indicates an expected call of Stop
-func (mr *MockCommandMockRecorder) Stop(id, path interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCommand)(nil).Stop), id, path)
+func (mr *MockCommandMockRecorder) Stop(id, path interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{id, path}, services...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCommand)(nil).Stop), varargs...)
+}
+
+// Restart mocks base method
+func (m *MockCommand) Restart(id, path string, services ...string) error {
+	varargs := []interface{}{id, path}
+	for _, a := range services {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Restart", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restart indicates an expected call of Restart
+func (mr *MockCommandMockRecorder) Restart(id, path interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{id, path}, services...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restart", reflect.TypeOf((*MockCommand)(nil).Restart), varargs...)
+}
+
+// Scale mocks base method
+func (m *MockCommand) Scale(id, path, service string, replicas int) error {
+	ret := m.ctrl.Call(m, "Scale", id, path, service, replicas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scale indicates an expected call of Scale
+func (mr *MockCommandMockRecorder) Scale(id, path, service, replicas interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scale", reflect.TypeOf((*MockCommand)(nil).Scale), id, path, service, replicas)
 }
 
 // Pause mocks base method
@@ -151,8 +192,8 @@ func (mr *MockCommandMockRecorder) Unpause(id, path interface{}) *gomock.Call {
 }
 
 // Pull mocks base method
-func (m *MockCommand) Pull(id, path string, services ...string) error {
-	varargs := []interface{}{id, path}
+func (m *MockCommand) Pull(ctx context.Context, id, path string, progress func(dockercontroller.PullProgress), services ...string) error {
+	varargs := []interface{}{ctx, id, path, progress}
 	for _, a := range services {
 		varargs = append(varargs, a)
 	}
@@ -162,11 +203,23 @@ func (m *MockCommand) Pull(id, path string, services ...string) error {
 }
 
 // Pull indicates an expected call of Pull
-func (mr *MockCommandMockRecorder) Pull(id, path interface{}, services ...interface{}) *gomock.Call {
-	varargs := append([]interface{}{id, path}, services...)
+func (mr *MockCommandMockRecorder) Pull(ctx, id, path, progress interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, id, path, progress}, services...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pull", reflect.TypeOf((*MockCommand)(nil).Pull), varargs...)
 }
 
+// PullMissing mocks base method
+func (m *MockCommand) PullMissing(ctx context.Context, id, path string, progress func(dockercontroller.PullProgress)) error {
+	ret := m.ctrl.Call(m, "PullMissing", ctx, id, path, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PullMissing indicates an expected call of PullMissing
+func (mr *MockCommandMockRecorder) PullMissing(ctx, id, path, progress interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullMissing", reflect.TypeOf((*MockCommand)(nil).PullMissing), ctx, id, path, progress)
+}
+
 // Ps mocks base method
 func (m *MockCommand) Ps(id, path string, args ...string) ([]map[string]string, error) {
 	varargs := []interface{}{id, path}
@@ -198,6 +251,19 @@ func (mr *MockCommandMockRecorder) GetAppStats(id, path interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppStats", reflect.TypeOf((*MockCommand)(nil).GetAppStats), id, path)
 }
 
+// GetAppMetrics mocks base method
+func (m *MockCommand) GetAppMetrics(id, path string) ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAppMetrics", id, path)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppMetrics indicates an expected call of GetAppMetrics
+func (mr *MockCommandMockRecorder) GetAppMetrics(id, path interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppMetrics", reflect.TypeOf((*MockCommand)(nil).GetAppMetrics), id, path)
+}
+
 // GetContainerConfigByName mocks base method
 func (m *MockCommand) GetContainerConfigByName(containerName string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetContainerConfigByName", containerName)
@@ -237,6 +303,19 @@ func (mr *MockCommandMockRecorder) GetImageIDByRepoDigest(imageName interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageIDByRepoDigest", reflect.TypeOf((*MockCommand)(nil).GetImageIDByRepoDigest), imageName)
 }
 
+// EstimateImageSize mocks base method
+func (m *MockCommand) EstimateImageSize(image string) (int64, error) {
+	ret := m.ctrl.Call(m, "EstimateImageSize", image)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateImageSize indicates an expected call of EstimateImageSize
+func (mr *MockCommandMockRecorder) EstimateImageSize(image interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateImageSize", reflect.TypeOf((*MockCommand)(nil).EstimateImageSize), image)
+}
+
 // ImagePull mocks base method
 func (m *MockCommand) ImagePull(image string) error {
 	ret := m.ctrl.Call(m, "ImagePull", image)
@@ -279,8 +358,8 @@ func (mr *MockCommandMockRecorder) Events(id, path, evt interface{}, services ..
 }
 
 // UpWithEvent mocks base method
-func (m *MockCommand) UpWithEvent(id, path, eventID string, evt chan dockercontroller.Event, services ...string) error {
-	varargs := []interface{}{id, path, eventID, evt}
+func (m *MockCommand) UpWithEvent(ctx context.Context, id, path, eventID string, evt chan dockercontroller.Event, services ...string) error {
+	varargs := []interface{}{ctx, id, path, eventID, evt}
 	for _, a := range services {
 		varargs = append(varargs, a)
 	}
@@ -290,8 +369,8 @@ func (m *MockCommand) UpWithEvent(id, path, eventID string, evt chan dockercontr
 }
 
 // UpWithEvent indicates an expected call of UpWithEvent
-func (mr *MockCommandMockRecorder) UpWithEvent(id, path, eventID, evt interface{}, services ...interface{}) *gomock.Call {
-	varargs := append([]interface{}{id, path, eventID, evt}, services...)
+func (mr *MockCommandMockRecorder) UpWithEvent(ctx, id, path, eventID, evt interface{}, services ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, id, path, eventID, evt}, services...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpWithEvent", reflect.TypeOf((*MockCommand)(nil).UpWithEvent), varargs...)
 }
 
@@ -307,3 +386,116 @@ func (m *MockCommand) Info() (map[string]interface{}, error) {
 func (mr *MockCommandMockRecorder) Info() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockCommand)(nil).Info))
 }
+
+// Logs mocks base method
+func (m *MockCommand) Logs(ctx context.Context, id, path string, options dockercontroller.LogOptions) (<-chan dockercontroller.LogEntry, error) {
+	ret := m.ctrl.Call(m, "Logs", ctx, id, path, options)
+	ret0, _ := ret[0].(<-chan dockercontroller.LogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logs indicates an expected call of Logs
+func (mr *MockCommandMockRecorder) Logs(ctx, id, path, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockCommand)(nil).Logs), ctx, id, path, options)
+}
+
+// Exec mocks base method
+func (m *MockCommand) Exec(ctx context.Context, id, path, service string, cmd []string, tty bool) (io.ReadWriteCloser, error) {
+	ret := m.ctrl.Call(m, "Exec", ctx, id, path, service, cmd, tty)
+	ret0, _ := ret[0].(io.ReadWriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec
+func (mr *MockCommandMockRecorder) Exec(ctx, id, path, service, cmd, tty interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockCommand)(nil).Exec), ctx, id, path, service, cmd, tty)
+}
+
+// GetContainers mocks base method
+func (m *MockCommand) GetContainers() ([]dockercontroller.ContainerInfo, error) {
+	ret := m.ctrl.Call(m, "GetContainers")
+	ret0, _ := ret[0].([]dockercontroller.ContainerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainers indicates an expected call of GetContainers
+func (mr *MockCommandMockRecorder) GetContainers() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainers", reflect.TypeOf((*MockCommand)(nil).GetContainers))
+}
+
+// RemoveContainer mocks base method
+func (m *MockCommand) RemoveContainer(containerID string) error {
+	ret := m.ctrl.Call(m, "RemoveContainer", containerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveContainer indicates an expected call of RemoveContainer
+func (mr *MockCommandMockRecorder) RemoveContainer(containerID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockCommand)(nil).RemoveContainer), containerID)
+}
+
+// GetImages mocks base method
+func (m *MockCommand) GetImages() ([]dockercontroller.ImageInfo, error) {
+	ret := m.ctrl.Call(m, "GetImages")
+	ret0, _ := ret[0].([]dockercontroller.ImageInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImages indicates an expected call of GetImages
+func (mr *MockCommandMockRecorder) GetImages() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImages", reflect.TypeOf((*MockCommand)(nil).GetImages))
+}
+
+// RemoveImage mocks base method
+func (m *MockCommand) RemoveImage(imageID string) error {
+	ret := m.ctrl.Call(m, "RemoveImage", imageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveImage indicates an expected call of RemoveImage
+func (mr *MockCommandMockRecorder) RemoveImage(imageID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveImage", reflect.TypeOf((*MockCommand)(nil).RemoveImage), imageID)
+}
+
+// ImageSave mocks base method
+func (m *MockCommand) ImageSave(images []string) (io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "ImageSave", images)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageSave indicates an expected call of ImageSave
+func (mr *MockCommandMockRecorder) ImageSave(images interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageSave", reflect.TypeOf((*MockCommand)(nil).ImageSave), images)
+}
+
+// ImageLoad mocks base method
+func (m *MockCommand) ImageLoad(input io.Reader) error {
+	ret := m.ctrl.Call(m, "ImageLoad", input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImageLoad indicates an expected call of ImageLoad
+func (mr *MockCommandMockRecorder) ImageLoad(input interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageLoad", reflect.TypeOf((*MockCommand)(nil).ImageLoad), input)
+}
+
+// CheckImage mocks base method
+func (m *MockCommand) CheckImage(image string) error {
+	ret := m.ctrl.Call(m, "CheckImage", image)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckImage indicates an expected call of CheckImage
+func (mr *MockCommandMockRecorder) CheckImage(image interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckImage", reflect.TypeOf((*MockCommand)(nil).CheckImage), image)
+}
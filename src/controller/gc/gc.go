@@ -0,0 +1,352 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package gc provides garbage collection of docker images and containers
+// to reclaim disk space of the device.
+package gc
+
+import (
+	"commons/logger"
+	"controller/dockercontroller"
+	"controller/monitoring/apps"
+	"controller/monitoring/resource"
+	configDB "db/bolt/configuration"
+	"db/bolt/service"
+	"encoding/json"
+	"github.com/shirou/gopsutil/disk"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Configuration property holding how often(in seconds) GC runs.
+	GC_INTERVAL         = "gcinterval"
+	DEFAULT_GC_INTERVAL = 3600
+
+	// Configuration property holding how many images are kept per repository for rollback.
+	GC_RETENTION         = "gcretention"
+	DEFAULT_GC_RETENTION = 3
+
+	// Configuration property holding free disk in percent which GC keeps
+	// by removing images kept for rollback.
+	GC_DISK_WATERMARK         = "gcdiskwatermark"
+	DEFAULT_GC_DISK_WATERMARK = 10
+
+	ID          = "id"
+	DESCRIPTION = "description"
+	SERVICES    = "services"
+	IMAGE       = "image"
+	VALUE       = "value"
+	RECLAIMED   = "reclaimed"
+	IMAGES      = "images"
+	CONTAINERS  = "containers"
+	DEFAULT_TAG = "latest"
+	NONE_TAG    = "<none>:<none>"
+	NONE_DIGEST = "<none>@<none>"
+)
+
+type Command interface {
+	// Collect removes garbage images and containers and returns reclaimed bytes.
+	Collect() (map[string]interface{}, error)
+}
+
+type Executor struct{}
+
+var dockerExecutor dockercontroller.Command
+var dbExecutor service.Command
+var configDbExecutor configDB.Command
+var appsMonitor apps.Command
+
+var readDiskUsage = disk.Usage
+var getDockerRootDir = resource.GetDockerRootDir
+var sleep = time.Sleep
+var collectorOnce sync.Once
+
+// States of containers which are not running.
+var stoppedStates = map[string]bool{"created": true, "exited": true, "dead": true}
+
+func init() {
+	dockerExecutor = dockercontroller.Executor
+	dbExecutor = service.Executor{}
+	configDbExecutor = configDB.Executor{}
+	appsMonitor = apps.Executor{}
+}
+
+// StartCollector starts to collect garbage every gcinterval seconds in background.
+// it is safe to call more than once.
+func StartCollector() {
+	collectorOnce.Do(func() {
+		go runCollector()
+	})
+}
+
+func runCollector() {
+	for {
+		interval := getIntProperty(GC_INTERVAL, DEFAULT_GC_INTERVAL)
+		sleep(time.Duration(interval) * time.Second)
+
+		_, err := Executor{}.Collect()
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}
+}
+
+// Collect removes stopped containers which don't belong to apps,
+// dangling images without tag and digest and unused images older than
+// the last gcretention images of each repository.
+// if free disk is still lower than gcdiskwatermark percent, unused images
+// kept for rollback are removed from the oldest one until free disk gets higher than it.
+// images of app descriptions and images used by containers are never removed.
+// if succeed to collect, return reclaimed bytes measured by free disk,
+// and ids of removed containers and images
+// otherwise, return error.
+func (Executor) Collect() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	rootDir := getDockerRootDir()
+	before := getFreeDisk(rootDir)
+
+	removedContainers, expired, retained, err := findGarbage()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	removedImages := make([]string, 0)
+	for _, image := range expired {
+		if dockerExecutor.RemoveImage(image.ID) == nil {
+			removedImages = append(removedImages, image.ID)
+		}
+	}
+
+	watermark := float64(getIntProperty(GC_DISK_WATERMARK, DEFAULT_GC_DISK_WATERMARK))
+	for _, image := range retained {
+		usage, err := readDiskUsage(rootDir)
+		if err != nil || 100-usage.UsedPercent >= watermark {
+			break
+		}
+		logger.Logging(logger.INFO, "free disk is lower than watermark, remove", image.ID)
+		if dockerExecutor.RemoveImage(image.ID) == nil {
+			removedImages = append(removedImages, image.ID)
+		}
+	}
+
+	var reclaimed uint64
+	if after := getFreeDisk(rootDir); before != 0 && after > before {
+		reclaimed = after - before
+	}
+
+	res := make(map[string]interface{})
+	res[RECLAIMED] = reclaimed
+	res[CONTAINERS] = removedContainers
+	res[IMAGES] = removedImages
+	return res, nil
+}
+
+// Finds unused images to be removed while apps are not updated, since images
+// pulled for the update are not used by containers yet.
+// stopped containers which don't belong to apps are removed while finding
+// images used by containers, and the lock is released before the images
+// are removed.
+// if succeed to find, return ids of removed containers,
+// expired images and retained images
+// otherwise, return error.
+func findGarbage() ([]string, []dockercontroller.ImageInfo, []dockercontroller.ImageInfo, error) {
+	appsMonitor.LockUpdateAppState()
+	defer appsMonitor.UnlockUpdateAppState()
+
+	appList, err := dbExecutor.GetAppList()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	containers, err := dockerExecutor.GetContainers()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	removedContainers, usedImages := removeContainers(containers, getAppIds(appList))
+
+	images, err := dockerExecutor.GetImages()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	retention := getIntProperty(GC_RETENTION, DEFAULT_GC_RETENTION)
+	expired, retained := classifyImages(images, usedImages, getAppImages(appList), retention)
+	return removedContainers, expired, retained, nil
+}
+
+// Removes stopped containers of which project is not one of apps,
+// and returns ids of removed containers and images used by the others.
+func removeContainers(containers []dockercontroller.ContainerInfo, appIds map[string]bool) ([]string, map[string]bool) {
+	removed := make([]string, 0)
+	usedImages := make(map[string]bool)
+	for _, container := range containers {
+		if stoppedStates[container.State] && !appIds[container.Project] {
+			err := dockerExecutor.RemoveContainer(container.ID)
+			if err == nil {
+				removed = append(removed, container.ID)
+				continue
+			}
+		}
+		usedImages[container.ImageID] = true
+	}
+	return removed, usedImages
+}
+
+// Splits unused images which are not in app descriptions into expired and retained ones.
+// the newest images of each repository as many as retention are retained,
+// and retained images are sorted from the oldest one.
+// images without tag are counted in repositories of their digests,
+// and only dangling images which have neither tag nor digest are expired
+// regardless of retention.
+func classifyImages(images []dockercontroller.ImageInfo, usedImages, appImages map[string]bool, retention int) ([]dockercontroller.ImageInfo, []dockercontroller.ImageInfo) {
+	sorted := make([]dockercontroller.ImageInfo, len(images))
+	copy(sorted, images)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created > sorted[j].Created
+	})
+
+	kept := make(map[string]bool)
+	counts := make(map[string]int)
+	for _, image := range sorted {
+		for repository := range getRepositories(image) {
+			if counts[repository] < retention {
+				kept[image.ID] = true
+			}
+			counts[repository]++
+		}
+	}
+
+	expired := make([]dockercontroller.ImageInfo, 0)
+	retained := make([]dockercontroller.ImageInfo, 0)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		image := sorted[i]
+		if usedImages[image.ID] || isAppImage(image, appImages) {
+			continue
+		}
+		if len(getRepositories(image)) != 0 && kept[image.ID] {
+			retained = append(retained, image)
+		} else {
+			expired = append(expired, image)
+		}
+	}
+	return expired, retained
+}
+
+// Returns repositories of tags and digests of an image.
+func getRepositories(image dockercontroller.ImageInfo) map[string]bool {
+	repositories := make(map[string]bool)
+	for _, tag := range image.RepoTags {
+		if tag == NONE_TAG {
+			continue
+		}
+		if idx := strings.LastIndex(tag, ":"); idx > strings.LastIndex(tag, "/") {
+			tag = tag[:idx]
+		}
+		repositories[tag] = true
+	}
+	for _, digest := range image.RepoDigests {
+		if digest == NONE_DIGEST {
+			continue
+		}
+		if idx := strings.Index(digest, "@"); idx != -1 {
+			digest = digest[:idx]
+		}
+		repositories[digest] = true
+	}
+	return repositories
+}
+
+func isAppImage(image dockercontroller.ImageInfo, appImages map[string]bool) bool {
+	for _, ref := range append(image.RepoTags, image.RepoDigests...) {
+		if appImages[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+func getAppIds(appList []map[string]interface{}) map[string]bool {
+	ids := make(map[string]bool)
+	for _, app := range appList {
+		if id, ok := app[ID].(string); ok {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// Returns images in descriptions of apps,
+// "latest" tag is added to images without tag and digest.
+func getAppImages(appList []map[string]interface{}) map[string]bool {
+	images := make(map[string]bool)
+	for _, app := range appList {
+		desc, _ := app[DESCRIPTION].(string)
+		description := make(map[string]interface{})
+		if json.Unmarshal([]byte(desc), &description) != nil {
+			continue
+		}
+
+		services, _ := description[SERVICES].(map[string]interface{})
+		for _, serviceInfo := range services {
+			info, _ := serviceInfo.(map[string]interface{})
+			image, ok := info[IMAGE].(string)
+			if !ok {
+				continue
+			}
+			if !strings.Contains(image, "@") && strings.LastIndex(image, ":") <= strings.LastIndex(image, "/") {
+				image += ":" + DEFAULT_TAG
+			}
+			images[image] = true
+		}
+	}
+	return images
+}
+
+func getFreeDisk(path string) uint64 {
+	usage, err := readDiskUsage(path)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return 0
+	}
+	return usage.Free
+}
+
+func getIntProperty(name string, defaultValue int) int {
+	prop, err := configDbExecutor.GetProperty(name)
+	if err != nil {
+		return defaultValue
+	}
+
+	value, ok := prop[VALUE].(string)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil || result <= 0 {
+		return defaultValue
+	}
+	return result
+}
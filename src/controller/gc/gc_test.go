@@ -0,0 +1,239 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package gc
+
+import (
+	"commons/errors"
+	"controller/dockercontroller"
+	dockermocks "controller/dockercontroller/mocks"
+	appmocks "controller/monitoring/apps/mocks"
+	configmocks "db/bolt/configuration/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shirou/gopsutil/disk"
+	"reflect"
+	"testing"
+)
+
+const (
+	APP_ID           = "test_app_id"
+	DESCRIPTION_JSON = "{\"services\":{\"web\":{\"image\":\"test_url:5000/web:3.0\"},\"db\":{\"image\":\"db\"}},\"version\":\"2\"}"
+)
+
+var (
+	APP_LIST = []map[string]interface{}{
+		{"id": APP_ID, "description": DESCRIPTION_JSON, "state": "running"},
+	}
+	TEST_CONTAINERS = []dockercontroller.ContainerInfo{
+		{ID: "app_container", State: "exited", ImageID: "web_3", Project: APP_ID},
+		{ID: "running_container", State: "running", ImageID: "other_1", Project: "other"},
+		{ID: "stopped_container", State: "exited", ImageID: "other_2", Project: "other"},
+	}
+	TEST_IMAGES = []dockercontroller.ImageInfo{
+		{ID: "web_1", RepoTags: []string{"test_url:5000/web:1.0"}, Created: 1},
+		{ID: "web_2", RepoTags: []string{"test_url:5000/web:2.0"}, Created: 2},
+		{ID: "web_3", RepoTags: []string{"test_url:5000/web:3.0"}, Created: 3},
+		{ID: "web_4", RepoDigests: []string{"test_url:5000/web@sha256:4"}, Created: 4},
+		{ID: "db_1", RepoTags: []string{"db:latest"}, Created: 1},
+		{ID: "other_1", RepoTags: []string{"other:1.0"}, Created: 1},
+		{ID: "other_2", RepoTags: []string{"other:2.0"}, Created: 2},
+		{ID: "dangling", RepoTags: []string{NONE_TAG}, RepoDigests: []string{NONE_DIGEST}, Created: 5},
+	}
+	WATERMARK_PROP = map[string]interface{}{"name": GC_DISK_WATERMARK, "value": "20", "readOnly": false}
+	RETENTION_PROP = map[string]interface{}{"name": GC_RETENTION, "value": "2", "readOnly": false}
+)
+
+func setDiskUsage(usages ...disk.UsageStat) func() {
+	getDockerRootDir = func() string { return "/" }
+	readDiskUsage = func(path string) (*disk.UsageStat, error) {
+		usage := usages[0]
+		if len(usages) > 1 {
+			usages = usages[1:]
+		}
+		return &usage, nil
+	}
+	return func() {
+		readDiskUsage = disk.Usage
+	}
+}
+
+func TestCalledCollect_ExpectGarbageRemovedAfterUnlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().GetAppList().Return(APP_LIST, nil),
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(TEST_CONTAINERS, nil),
+		dockerExecutorMockObj.EXPECT().RemoveContainer("stopped_container").Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImages().Return(TEST_IMAGES, nil),
+		configDbExecutorMockObj.EXPECT().GetProperty(GC_RETENTION).Return(RETENTION_PROP, nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().RemoveImage("web_1").Return(nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("web_2").Return(nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("dangling").Return(nil),
+		configDbExecutorMockObj.EXPECT().GetProperty(GC_DISK_WATERMARK).Return(WATERMARK_PROP, nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	defer setDiskUsage(
+		disk.UsageStat{Free: 100, UsedPercent: 50},
+		disk.UsageStat{Free: 150, UsedPercent: 50},
+	)()
+
+	res, err := Executor{}.Collect()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		RECLAIMED:  uint64(50),
+		CONTAINERS: []string{"stopped_container"},
+		IMAGES:     []string{"web_1", "web_2", "dangling"},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
+
+func TestCalledCollectWhenDiskIsLowerThanWatermark_ExpectRetainedImagesRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().GetAppList().Return(APP_LIST, nil),
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(TEST_CONTAINERS, nil),
+		dockerExecutorMockObj.EXPECT().RemoveContainer("stopped_container").Return(nil),
+		dockerExecutorMockObj.EXPECT().GetImages().Return(TEST_IMAGES, nil),
+		configDbExecutorMockObj.EXPECT().GetProperty(GC_RETENTION).Return(RETENTION_PROP, nil),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+		dockerExecutorMockObj.EXPECT().RemoveImage("web_1").Return(nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("web_2").Return(nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("dangling").Return(nil),
+		configDbExecutorMockObj.EXPECT().GetProperty(GC_DISK_WATERMARK).Return(WATERMARK_PROP, nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("other_2").Return(nil),
+		dockerExecutorMockObj.EXPECT().RemoveImage("web_4").Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	defer setDiskUsage(
+		disk.UsageStat{Free: 10, UsedPercent: 90},
+		disk.UsageStat{Free: 15, UsedPercent: 85},
+		disk.UsageStat{Free: 20, UsedPercent: 85},
+		disk.UsageStat{Free: 30, UsedPercent: 70},
+	)()
+
+	res, err := Executor{}.Collect()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := []string{"web_1", "web_2", "dangling", "other_2", "web_4"}
+	if !reflect.DeepEqual(expected, res[IMAGES]) {
+		t.Errorf("Expected images: %v, actual images: %v", expected, res[IMAGES])
+	}
+	if res[RECLAIMED] != uint64(20) {
+		t.Errorf("Expected reclaimed: 20, actual reclaimed: %v", res[RECLAIMED])
+	}
+}
+
+func TestCalledCollectWhenGetContainersFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	appExecutorMockObj := appmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		appExecutorMockObj.EXPECT().LockUpdateAppState(),
+		dbExecutorMockObj.EXPECT().GetAppList().Return(APP_LIST, nil),
+		dockerExecutorMockObj.EXPECT().GetContainers().Return(nil, errors.Unknown{}),
+		appExecutorMockObj.EXPECT().UnlockUpdateAppState(),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+	appsMonitor = appExecutorMockObj
+
+	defer setDiskUsage(disk.UsageStat{Free: 100})()
+
+	_, err := Executor{}.Collect()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unknown", err)
+	case errors.Unknown:
+	}
+}
+
+func TestClassifyImages(t *testing.T) {
+	usedImages := map[string]bool{"other_1": true}
+	appImages := map[string]bool{"test_url:5000/web:3.0": true}
+
+	expired, retained := classifyImages(TEST_IMAGES, usedImages, appImages, 1)
+
+	ids := func(images []dockercontroller.ImageInfo) []string {
+		result := make([]string, 0)
+		for _, image := range images {
+			result = append(result, image.ID)
+		}
+		return result
+	}
+	if expected := []string{"web_1", "web_2", "dangling"}; !reflect.DeepEqual(expected, ids(expired)) {
+		t.Errorf("Expected expired: %v, actual expired: %v", expected, ids(expired))
+	}
+	if expected := []string{"db_1", "other_2", "web_4"}; !reflect.DeepEqual(expected, ids(retained)) {
+		t.Errorf("Expected retained: %v, actual retained: %v", expected, ids(retained))
+	}
+}
+
+func TestGetAppImages(t *testing.T) {
+	expected := map[string]bool{
+		"test_url:5000/web:3.0": true,
+		"db:latest":             true,
+	}
+
+	res := getAppImages(APP_LIST)
+
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
@@ -44,37 +44,59 @@ func startHealthCheck() {
 		}
 	}
 
-	common.quit = make(chan bool)
+	common.mutex.Lock()
+	defer common.mutex.Unlock()
+
+	// Health check is started only once until it is stopped.
+	if common.quit != nil {
+		return
+	}
+	quit := make(chan bool)
+	common.quit = quit
+
 	intervalInt, _ := strconv.Atoi(interval)
-	common.ticker = time.NewTicker(time.Duration(intervalInt) * TIME_UNIT)
+	ticker := time.NewTicker(time.Duration(intervalInt) * TIME_UNIT)
 	go func() {
 		sendPingRequest(interval)
 		for {
 			select {
-			case <-common.ticker.C:
+			case <-ticker.C:
 				code, _ := sendPingRequest(interval)
 				if code == 404 {
 					logger.Logging(logger.ERROR, "received 'not found' error, re-registration is required")
-					common.ticker.Stop()
+					ticker.Stop()
 
 					err := register(false)
 					if err != nil {
 						logger.Logging(logger.ERROR, err.Error())
 					}
-					common.ticker = time.NewTicker(time.Duration(intervalInt) * TIME_UNIT)
+					ticker = time.NewTicker(time.Duration(intervalInt) * TIME_UNIT)
 				}
-			case <-common.quit:
-				common.ticker.Stop()
-				stopHealthCheck()
+			case <-quit:
+				ticker.Stop()
 				return
 			}
 		}
 	}()
 }
 
+// Stops health check if it is running.
 func stopHealthCheck() {
-	close(common.quit)
-	common.quit = nil
+	common.mutex.Lock()
+	defer common.mutex.Unlock()
+
+	if common.quit != nil {
+		close(common.quit)
+		common.quit = nil
+	}
+}
+
+// Returns whether health check is running.
+func isHealthCheckRunning() bool {
+	common.mutex.Lock()
+	defer common.mutex.Unlock()
+
+	return common.quit != nil
 }
 
 func sendPingRequest(interval string) (int, error) {
@@ -106,8 +128,17 @@ func sendPingRequest(interval string) (int, error) {
 	}
 
 	code, _, err := httpExecutor.SendHttpRequest("POST", reqUrl, []byte(jsonData))
-	if err != nil {
+	if err != nil || code >= 500 {
 		logger.Logging(logger.ERROR, "failed to send ping request")
+
+		// Keep the latest ping in outbox to be delivered when pharos-anchor is reachable.
+		e := outboxExecutor.EnqueueLatest(PING_KEY, "POST", reqUrl, []byte(jsonData))
+		if e != nil {
+			logger.Logging(logger.ERROR, e.Error())
+		}
+	}
+
+	if err != nil {
 		return code, err
 	}
 
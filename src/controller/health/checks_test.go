@@ -17,6 +17,7 @@
 package health
 
 import (
+	outboxmocks "controller/outbox/mocks"
 	dbmocks "db/bolt/configuration/mocks"
 	"errors"
 	"github.com/golang/mock/gomock"
@@ -31,13 +32,16 @@ func TestCalledSendPingRequestWhenFailedToSendHttpRequest_ExpectErrorReturn(t *t
 
 	msgMockObj := msgmocks.NewMockCommand(ctrl)
 	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		dbMockObj.EXPECT().GetProperty("deviceid").Return(PROPERTY, nil),
 		msgMockObj.EXPECT().SendHttpRequest("POST", gomock.Any(), gomock.Any()).Return(500, "", errors.New("Error")),
+		outboxMockObj.EXPECT().EnqueueLatest(PING_KEY, "POST", gomock.Any(), gomock.Any()).Return(nil),
 	)
 	configDbExecutor = dbMockObj
 	httpExecutor = msgMockObj
+	outboxExecutor = outboxMockObj
 
 	interval := "1"
 	os.Setenv("ANCHOR_ADDRESS", "127.0.0.1")
@@ -22,18 +22,20 @@ import (
 	"commons/errors"
 	"commons/logger"
 	"commons/util"
-	"time"
+	"sync"
 )
 
 var common context
 
 type context struct {
+	// mutex guards quit, which is accessed by API handlers
+	// and goroutines registering to pharos-anchor.
+	mutex          sync.Mutex
 	quit           chan bool
-	ticker         *time.Ticker
 	managerAddress string
 }
 
-func (ctx context) convertRespToMap(respStr string) (map[string]interface{}, error) {
+func (ctx *context) convertRespToMap(respStr string) (map[string]interface{}, error) {
 	resp, err := util.ConvertJsonToMap(respStr)
 	if err != nil {
 		logger.Logging(logger.ERROR, "Failed to convert response from string to map")
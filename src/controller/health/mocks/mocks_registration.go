@@ -32,6 +32,18 @@ func (m *MockCommand) EXPECT() *MockCommandMockRecorder {
 	return m.recorder
 }
 
+// Register mocks base method
+func (m *MockCommand) Register() error {
+	ret := m.ctrl.Call(m, "Register")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register
+func (mr *MockCommandMockRecorder) Register() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockCommand)(nil).Register))
+}
+
 // Unregister mocks base method
 func (m *MockCommand) Unregister() error {
 	ret := m.ctrl.Call(m, "Unregister")
@@ -24,6 +24,7 @@ import (
 	"commons/url"
 	"commons/util"
 	"controller/configuration"
+	"controller/outbox"
 	configDB "db/bolt/configuration"
 	"db/bolt/service"
 	"messenger"
@@ -38,11 +39,13 @@ const (
 	NODE                   = "node"
 	INTERVAL               = "interval"
 	HEALTH_CHECK           = "healthCheck"
+	PING_KEY               = "ping"
 	DEFAULT_RETRY_INTERVAL = 1
 	TIME_UNIT              = time.Minute
 )
 
 type Command interface {
+	Register() error
 	Unregister() error
 }
 
@@ -52,12 +55,14 @@ var httpExecutor messenger.Command
 var configurator configuration.Command
 var srvDbExecutor service.Command
 var configDbExecutor configDB.Command
+var outboxExecutor outbox.Command
 
 func init() {
 	httpExecutor = messenger.NewExecutor()
 	configurator = configuration.Executor{}
 	srvDbExecutor = service.Executor{}
 	configDbExecutor = configDB.Executor{}
+	outboxExecutor = outbox.Executor{}
 
 	// Request to register new pharos node.
 	err := register(true)
@@ -139,6 +144,18 @@ func register(enableHealthCheck bool) error {
 	return nil
 }
 
+// Register to pharos-anchor service again with current configuration and apps,
+// which is needed when they are changed by restoring a backup.
+// health check is started only if it is not running.
+// if succeed to register, return error as nil
+// otherwise, return error.
+func (Executor) Register() error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return register(!isHealthCheckRunning())
+}
+
 // Unregister to pharos-anchor service.
 // if succeed to unregister, return error as nil
 // otherwise, return error.
@@ -161,9 +178,7 @@ func (Executor) Unregister() error {
 	}
 
 	// Stop a ticker to send ping request.
-	if common.quit != nil {
-		common.quit <- true
-	}
+	stopHealthCheck()
 	return nil
 }
 
@@ -103,6 +103,43 @@ func TestCalledRegisterWhenFailedToSetConfiguration_ExpectErrorReturn(t *testing
 	}
 }
 
+func TestCalledRegisterWhenHealthCheckIsRunning_ExpectRegisteredAgain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	msgMockObj := msgmocks.NewMockCommand(ctrl)
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	url := "http://192.168.0.1:48099/api/v1/management/nodes/register"
+	expectedResp := `{"id":"deviceid"}`
+
+	gomock.InOrder(
+		configMockObj.EXPECT().GetConfiguration().Return(CONFIGURATION, nil),
+		msgMockObj.EXPECT().SendHttpRequest("POST", url, gomock.Any()).Return(200, expectedResp, nil),
+		dbMockObj.EXPECT().GetProperty("deviceid").Return(PROPERTY, nil),
+		dbMockObj.EXPECT().SetProperty(gomock.Any()).Return(nil),
+	)
+	configurator = configMockObj
+	httpExecutor = msgMockObj
+	configDbExecutor = dbMockObj
+
+	common.quit = make(chan bool)
+	defer func() {
+		common.quit = nil
+	}()
+
+	os.Setenv("ANCHOR_ADDRESS", ANCHOR_IP)
+	os.Setenv("ANCHOR_REVERSE_PROXY", "false")
+	err := healthExecutor.Register()
+	os.Unsetenv("ANCHOR_ADDRESS")
+	os.Unsetenv("ANCHOR_REVERSE_PROXY")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
 func TestCalledUnregister_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
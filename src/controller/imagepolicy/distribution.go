@@ -0,0 +1,446 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package imagepolicy
+
+import (
+	"commons/errors"
+	"commons/logger"
+	privateRegistry "controller/registry"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// Endpoint of the registry API of the default registry.
+	DOCKER_HUB_ENDPOINT = "registry-1.docker.io"
+
+	// Tag of the signature manifest is "sha256-<hex>.sig" as cosign stores it.
+	SIGNATURE_SUFFIX     = ".sig"
+	SIGNATURE_ANNOTATION = "dev.cosignproject.cosign/signature"
+
+	CONTENT_DIGEST_HEADER = "Docker-Content-Digest"
+	CHALLENGE_HEADER      = "WWW-Authenticate"
+)
+
+// OS of images run on the target.
+const TARGET_OS = "linux"
+
+// Media types of manifests accepted from registries.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// Schemes tried in order to connect to registries.
+var registrySchemes = []string{"https://", "http://"}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+var resolveDigest = resolveManifestDigest
+var fetchSignatures = fetchCosignSignatures
+
+// Client of the registry API v2 for a repository.
+type distributionClient struct {
+	ref           reference
+	credential    map[string]interface{}
+	endpoint      string
+	authorization string
+}
+
+// Manifest having detached signatures as its layers.
+type signatureManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// Manifest of an image, or list of manifests for platforms.
+type imageManifest struct {
+	Config struct {
+		Size int64 `json:"size"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+func newDistributionClient(ref reference) *distributionClient {
+	client := &distributionClient{ref: ref}
+
+	credential, err := registryExecutor.FindCredential(ref.name())
+	if err == nil {
+		client.credential = credential
+	} else if _, ok := err.(errors.NotFound); !ok {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+	return client
+}
+
+// Returns the digest which the tag of an image resolves to in the registry.
+func resolveManifestDigest(ref reference) (string, error) {
+	if len(ref.digest) != 0 {
+		return ref.digest, nil
+	}
+
+	body, header, err := newDistributionClient(ref).getManifest(ref.tag)
+	if err != nil {
+		return "", err
+	}
+
+	digest := header.Get(CONTENT_DIGEST_HEADER)
+	if len(digest) == 0 {
+		digest = sha256Digest(body)
+	}
+	return digest, nil
+}
+
+// ManifestSize returns the size of an image in its registry, which is
+// the sum of sizes of its config and compressed layers for the platform of the target.
+// credentials are used if they are registered for the registry.
+func ManifestSize(image string) (int64, error) {
+	ref := parseReference(image)
+	client := newDistributionClient(ref)
+
+	reference := ref.digest
+	if len(reference) == 0 {
+		reference = ref.tag
+	}
+	manifest, err := client.getImageManifest(reference)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(manifest.Manifests) != 0 {
+		digest := ""
+		for _, item := range manifest.Manifests {
+			if item.Platform.OS == TARGET_OS && item.Platform.Architecture == runtime.GOARCH {
+				digest = item.Digest
+				break
+			}
+		}
+		if len(digest) == 0 {
+			return 0, errors.NotFoundImage{Msg: "no manifest of " + image + " for " + TARGET_OS + "/" + runtime.GOARCH}
+		}
+		manifest, err = client.getImageManifest(digest)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size, nil
+}
+
+// Returns signatures stored for a digest of an image in the registry.
+// if there is no signature, return empty list.
+func fetchCosignSignatures(ref reference, digest string) ([]signature, error) {
+	client := newDistributionClient(ref)
+
+	body, _, err := client.getManifest(strings.Replace(digest, ":", "-", 1) + SIGNATURE_SUFFIX)
+	if err != nil {
+		switch err.(type) {
+		default:
+			return nil, err
+		case errors.NotFoundImage:
+			return nil, nil
+		}
+	}
+
+	manifest := signatureManifest{}
+	err = json.Unmarshal(body, &manifest)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "invalid signature manifest of " + ref.name()}
+	}
+
+	signatures := make([]signature, 0)
+	for _, layer := range manifest.Layers {
+		encoded, exists := layer.Annotations[SIGNATURE_ANNOTATION]
+		if !exists {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			logger.Logging(logger.ERROR, "invalid signature encoding of "+layer.Digest)
+			continue
+		}
+		payload, err := client.getBlob(layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature{payload: payload, value: value})
+	}
+	return signatures, nil
+}
+
+func (client *distributionClient) getManifest(reference string) ([]byte, http.Header, error) {
+	return client.get("/manifests/"+reference, manifestMediaTypes...)
+}
+
+func (client *distributionClient) getImageManifest(reference string) (*imageManifest, error) {
+	body, _, err := client.getManifest(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &imageManifest{}
+	err = json.Unmarshal(body, manifest)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "invalid manifest of " + client.ref.name()}
+	}
+	return manifest, nil
+}
+
+func (client *distributionClient) getBlob(digest string) ([]byte, error) {
+	body, _, err := client.get("/blobs/" + digest)
+	if err != nil {
+		return nil, err
+	}
+	if sha256Digest(body) != digest {
+		return nil, errors.Unknown{Msg: "digest mismatch of blob " + digest}
+	}
+	return body, nil
+}
+
+// Sends GET request for a path under the repository,
+// and returns body of the response if its status is OK.
+func (client *distributionClient) get(path string, accept ...string) ([]byte, http.Header, error) {
+	path = "/v2/" + client.ref.repository + path
+
+	resp, err := client.send(path, accept)
+	if err != nil {
+		if _, ok := err.(errors.ConnectionError); ok {
+			return nil, nil, err
+		}
+		return nil, nil, errors.ConnectionError{Msg: "can't connect to registry " + client.ref.host + " : " + err.Error()}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil, errors.NotFoundImage{Msg: "can't find " + client.ref.name() + path}
+	default:
+		return nil, nil, errors.ConnectionError{Msg: "registry " + client.ref.host + " responded " + resp.Status}
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.IOError{Msg: "can't read the response of registry " + client.ref.host}
+	}
+	return body, resp.Header, nil
+}
+
+// Sends a request, and sends it again with authorization
+// if the registry requires it.
+func (client *distributionClient) send(path string, accept []string) (*http.Response, error) {
+	resp, err := client.connect(path, accept)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge := resp.Header.Get(CHALLENGE_HEADER)
+	resp.Body.Close()
+
+	err = client.authorize(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return client.request(client.endpoint+path, accept)
+}
+
+// Sends a request trying schemes in order at the first time,
+// the scheme connected is used for the following requests.
+func (client *distributionClient) connect(path string, accept []string) (*http.Response, error) {
+	if len(client.endpoint) != 0 {
+		return client.request(client.endpoint+path, accept)
+	}
+
+	host := client.ref.host
+	if host == privateRegistry.DEFAULT_REGISTRY {
+		host = DOCKER_HUB_ENDPOINT
+	}
+
+	var err error
+	for _, scheme := range registrySchemes {
+		var resp *http.Response
+		resp, err = client.request(scheme+host+path, accept)
+		if err == nil {
+			client.endpoint = scheme + host
+			return resp, nil
+		}
+	}
+	return nil, err
+}
+
+func (client *distributionClient) request(address string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, mediaType := range accept {
+		req.Header.Add("Accept", mediaType)
+	}
+	if len(client.authorization) != 0 {
+		req.Header.Set("Authorization", client.authorization)
+	}
+	return httpClient.Do(req)
+}
+
+// Makes authorization for a challenge of the registry,
+// a token is issued for bearer challenge with credentials if they exist.
+func (client *distributionClient) authorize(challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if client.credential == nil {
+			return errors.ConnectionError{Msg: "no credentials for registry " + client.ref.host}
+		}
+		client.authorization = "Basic " + client.basicAuth()
+	case "bearer":
+		token, err := client.getToken(params)
+		if err != nil {
+			return err
+		}
+		client.authorization = "Bearer " + token
+	default:
+		return errors.ConnectionError{Msg: "unsupported authentication of registry " + client.ref.host}
+	}
+	return nil
+}
+
+func (client *distributionClient) getToken(params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || len(realm.Host) == 0 {
+		return "", errors.ConnectionError{Msg: "invalid token realm of registry " + client.ref.host}
+	}
+
+	scope := params["scope"]
+	if len(scope) == 0 {
+		scope = "repository:" + client.ref.repository + ":pull"
+	}
+	query := realm.Query()
+	query.Set("service", params["service"])
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if client.credential != nil {
+		req.Header.Set("Authorization", "Basic "+client.basicAuth())
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.ConnectionError{Msg: "can't get token of registry " + client.ref.host + " : " + resp.Status}
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", errors.ConnectionError{Msg: "invalid token of registry " + client.ref.host}
+	}
+	if len(token.Token) != 0 {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+func (client *distributionClient) basicAuth() string {
+	username, _ := client.credential[privateRegistry.USERNAME].(string)
+	password, _ := client.credential[privateRegistry.PASSWORD].(string)
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// Returns lower case scheme and parameters of a challenge like
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+
+	challenge = strings.TrimSpace(challenge)
+	idx := strings.Index(challenge, " ")
+	if idx == -1 {
+		return strings.ToLower(challenge), params
+	}
+	scheme := strings.ToLower(challenge[:idx])
+
+	rest := challenge[idx+1:]
+	for len(rest) != 0 {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.Index(rest, "=")
+		if eq == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		value := ""
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.Index(rest[1:], "\"")
+			if end == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end == -1 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+		params[key] = value
+	}
+	return scheme, params
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package imagepolicy
+
+import (
+	"commons/errors"
+	registrymocks "controller/registry/mocks"
+	"encoding/base64"
+	"github.com/golang/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const (
+	token    = "test_token"
+	payload  = `{"critical":{"image":{"docker-manifest-digest":"sha256:1111111111111111111111111111111111111111111111111111111111111111"}}}`
+	sigValue = "test_signature"
+)
+
+// Returns a registry which issues a token for bearer challenge
+// and serves a manifest of "test_app:v1" and its signature.
+func newTestRegistry(t *testing.T) *httptest.Server {
+	payloadDigest := sha256Digest([]byte(payload))
+	sigManifest := `{"layers":[{"digest":"` + payloadDigest + `","annotations":{"` + SIGNATURE_ANNOTATION + `":"` + base64.StdEncoding.EncodeToString([]byte(sigValue)) + `"}}]}`
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("scope") != "repository:test_app:pull" {
+			t.Errorf("Unexpected scope: %s", r.URL.Query().Get("scope"))
+		}
+		w.Write([]byte(`{"token":"` + token + `"}`))
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set(CHALLENGE_HEADER, `Bearer realm="`+server.URL+`/token",service="test_registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/test_app/manifests/v1":
+			if !strings.Contains(strings.Join(r.Header["Accept"], ","), manifestMediaTypes[0]) {
+				t.Errorf("Expected accept header: %s", manifestMediaTypes[0])
+			}
+			w.Header().Set(CONTENT_DIGEST_HEADER, digest)
+			w.Write([]byte("{}"))
+		case "/v2/test_app/manifests/v2":
+			w.Write([]byte(`{"manifests":[{"digest":"sha256:other","platform":{"architecture":"other","os":"` + TARGET_OS + `"}},` +
+				`{"digest":"sha256:target","platform":{"architecture":"` + runtime.GOARCH + `","os":"` + TARGET_OS + `"}}]}`))
+		case "/v2/test_app/manifests/sha256:target":
+			w.Write([]byte(`{"config":{"size":100},"layers":[{"size":1000},{"size":2000}]}`))
+		case "/v2/test_app/manifests/" + strings.Replace(digest, ":", "-", 1) + SIGNATURE_SUFFIX:
+			w.Write([]byte(sigManifest))
+		case "/v2/test_app/blobs/" + payloadDigest:
+			w.Write([]byte(payload))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return server
+}
+
+func setTestRegistry(t *testing.T, ctrl *gomock.Controller) (reference, func()) {
+	server := newTestRegistry(t)
+	origSchemes := registrySchemes
+	registrySchemes = []string{"http://"}
+
+	registryExecutorMockObj := registrymocks.NewMockCommand(ctrl)
+	registryExecutorMockObj.EXPECT().FindCredential(gomock.Any()).Return(nil, notFoundError).AnyTimes()
+	registryExecutor = registryExecutorMockObj
+
+	ref := parseReference(strings.TrimPrefix(server.URL, "http://") + "/test_app:v1")
+	return ref, func() {
+		registrySchemes = origSchemes
+		server.Close()
+	}
+}
+
+func TestResolveManifestDigest_ExpectDigestOfTagReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	res, err := resolveManifestDigest(ref)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if res != digest {
+		t.Errorf("Expected res: %s, actual res: %s", digest, res)
+	}
+}
+
+func TestResolveManifestDigestWithUnknownTag_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	ref.tag = "unknown"
+	_, err := resolveManifestDigest(ref)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFoundImage", err)
+	case errors.NotFoundImage:
+	}
+}
+
+func TestManifestSizeOfManifestList_ExpectSizeOfTargetPlatformReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	res, err := ManifestSize(ref.host + "/test_app:v2")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if res != 3100 {
+		t.Errorf("Expected res: %d, actual res: %d", 3100, res)
+	}
+}
+
+func TestManifestSizeWithUnknownTag_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	_, err := ManifestSize(ref.host + "/test_app:unknown")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFoundImage", err)
+	case errors.NotFoundImage:
+	}
+}
+
+func TestFetchCosignSignatures_ExpectSignaturesReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	res, err := fetchCosignSignatures(ref, digest)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := []signature{{payload: []byte(payload), value: []byte(sigValue)}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
+
+func TestFetchCosignSignaturesWithoutSignature_ExpectEmptyListReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ref, cleanup := setTestRegistry(t, ctrl)
+	defer cleanup()
+
+	res, err := fetchCosignSignatures(ref, otherDigest)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if len(res) != 0 {
+		t.Errorf("Expected empty res, actual res: %v", res)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a:pull,push"`)
+
+	if scheme != "bearer" {
+		t.Errorf("Expected scheme: bearer, actual scheme: %s", scheme)
+	}
+	expected := map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:a:pull,push",
+	}
+	if !reflect.DeepEqual(expected, params) {
+		t.Errorf("Expected params: %v, actual params: %v", expected, params)
+	}
+}
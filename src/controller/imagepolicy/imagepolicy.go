@@ -0,0 +1,293 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package imagepolicy provides enforcement of the image policy
+// which decides images allowed to be deployed on the target.
+package imagepolicy
+
+import (
+	"bytes"
+	"commons/errors"
+	"commons/logger"
+	"controller/registry"
+	"crypto"
+	configDB "db/bolt/configuration"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Configuration property holding the image policy.
+	IMAGE_POLICY = "imagepolicy"
+
+	ALLOWED_REGISTRIES = "allowedregistries"
+	PINNED_DIGESTS     = "pinneddigests"
+	REQUIRE_DIGEST     = "requiredigest"
+	PUBLIC_KEYS        = "publickeys"
+	VALUE              = "value"
+
+	// Tag of images which don't specify it.
+	DEFAULT_TAG = "latest"
+
+	// Namespace of official images in the default registry.
+	OFFICIAL_NAMESPACE = "library"
+)
+
+type Command interface {
+	// Verify checks that all of images are allowed by the image policy
+	// and returns repository digests of images whose digests are verified.
+	Verify(images []string) (map[string]string, error)
+}
+
+type Executor struct{}
+
+// Image policy stored in the configuration.
+// an empty list or map means that nothing is checked for it.
+type policy struct {
+	// Hosts of registries images can be pulled from.
+	AllowedRegistries []string `json:"allowedregistries"`
+
+	// Digests which tags of a repository are allowed to resolve to.
+	PinnedDigests map[string][]string `json:"pinneddigests"`
+
+	// Whether images of repositories without pinned digests are rejected.
+	RequireDigest bool `json:"requiredigest"`
+
+	// PEM encoded keys, one of them should verify a signature of images.
+	PublicKeys []string `json:"publickeys"`
+
+	allowed map[string]bool
+	pinned  map[string][]string
+	keys    []crypto.PublicKey
+}
+
+// Reference of an image split in the way registries address it.
+type reference struct {
+	host       string
+	repository string
+	tag        string
+	digest     string
+}
+
+var dbExecutor configDB.Command
+var registryExecutor registry.Command
+
+var digestPattern = regexp.MustCompile("^sha256:[0-9a-f]{64}$")
+
+func init() {
+	dbExecutor = configDB.Executor{}
+	registryExecutor = registry.Executor{}
+}
+
+// DefaultPolicy returns the image policy which allows any image.
+func DefaultPolicy() map[string]interface{} {
+	return map[string]interface{}{
+		ALLOWED_REGISTRIES: make([]interface{}, 0),
+		PINNED_DIGESTS:     make(map[string]interface{}),
+		REQUIRE_DIGEST:     false,
+		PUBLIC_KEYS:        make([]interface{}, 0),
+	}
+}
+
+// ValidatePolicy checks that value is a valid image policy.
+// if not, return error as InvalidJSON.
+func ValidatePolicy(value interface{}) error {
+	_, err := parsePolicy(value)
+	return err
+}
+
+// Verify checks images against the image policy before they are pulled.
+// registry of an image should be one of allowed registries,
+// the digest a tag resolves to should be one of pinned digests of the repository
+// and a signature of the digest should be verified by one of public keys.
+// images should be pulled by the returned repository digests like "nginx@sha256:..."
+// so that a tag pushed again after the verification is not pulled.
+// if all of images are allowed, return repository digests and error as nil
+// otherwise, return error as Forbidden.
+func (Executor) Verify(images []string) (map[string]string, error) {
+	logger.Logging(logger.DEBUG, "IN", strings.Join(images, " "))
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	p, err := getPolicy()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	repoDigests := make(map[string]string)
+	for _, image := range images {
+		digest, err := p.verify(image)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return nil, err
+		}
+		if len(digest) != 0 {
+			repoDigests[image] = getRepository(image) + "@" + digest
+		}
+	}
+	return repoDigests, nil
+}
+
+func getPolicy() (*policy, error) {
+	prop, err := dbExecutor.GetProperty(IMAGE_POLICY)
+	if err != nil {
+		switch err.(type) {
+		default:
+			return nil, err
+		case errors.NotFound:
+			return parsePolicy(DefaultPolicy())
+		}
+	}
+	return parsePolicy(prop[VALUE])
+}
+
+func parsePolicy(value interface{}) (*policy, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "invalid image policy"}
+	}
+
+	p := &policy{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(p); err != nil {
+		return nil, errors.InvalidJSON{Msg: "invalid image policy : " + err.Error()}
+	}
+
+	p.allowed = make(map[string]bool)
+	for _, host := range p.AllowedRegistries {
+		if len(strings.TrimSpace(host)) == 0 {
+			return nil, errors.InvalidJSON{Msg: "empty registry in " + ALLOWED_REGISTRIES}
+		}
+		p.allowed[registry.NormalizeHost(host)] = true
+	}
+
+	p.pinned = make(map[string][]string)
+	for repository, digests := range p.PinnedDigests {
+		for _, digest := range digests {
+			if !digestPattern.MatchString(digest) {
+				return nil, errors.InvalidJSON{Msg: "invalid digest of " + repository + " : " + digest}
+			}
+		}
+		name := parseReference(repository).name()
+		p.pinned[name] = append(p.pinned[name], digests...)
+	}
+
+	for _, data := range p.PublicKeys {
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		p.keys = append(p.keys, key)
+	}
+	return p, nil
+}
+
+// Returns the verified digest of an image,
+// or an empty digest if the policy does not check digests of the image.
+func (p *policy) verify(image string) (string, error) {
+	ref := parseReference(image)
+	if len(p.allowed) != 0 && !p.allowed[ref.host] {
+		return "", errors.Forbidden{Msg: "registry of " + image + " is not allowed : " + ref.host}
+	}
+
+	pinned := p.pinned[ref.name()]
+	if len(pinned) == 0 && p.RequireDigest {
+		return "", errors.Forbidden{Msg: "no digest is pinned for " + image}
+	}
+	if len(pinned) == 0 && len(p.keys) == 0 {
+		return "", nil
+	}
+
+	digest, err := resolveDigest(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if len(pinned) != 0 && !contains(pinned, digest) {
+		return "", errors.Forbidden{Msg: image + " resolves to the digest which is not pinned : " + digest}
+	}
+
+	if len(p.keys) != 0 {
+		signatures, err := fetchSignatures(ref, digest)
+		if err != nil {
+			return "", err
+		}
+		if !isSigned(digest, signatures, p.keys) {
+			return "", errors.Forbidden{Msg: "no valid signature of " + image + "@" + digest}
+		}
+	}
+	return digest, nil
+}
+
+// Returns reference of an image like "host:5000/app:v1" or "nginx@sha256:...",
+// repository of official images in the default registry is prefixed by "library/".
+func parseReference(image string) reference {
+	ref := reference{host: registry.GetRegistryHost(image)}
+
+	name := image
+	if idx := strings.Index(name, "@"); idx != -1 {
+		ref.digest = name[idx+1:]
+		name = name[:idx]
+	}
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		ref.tag = name[idx+1:]
+		name = name[:idx]
+	}
+	if idx := strings.Index(name, "/"); idx != -1 {
+		first := name[:idx]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			name = name[idx+1:]
+		}
+	}
+	if ref.host == registry.DEFAULT_REGISTRY && !strings.Contains(name, "/") {
+		name = OFFICIAL_NAMESPACE + "/" + name
+	}
+	ref.repository = name
+
+	if len(ref.tag) == 0 && len(ref.digest) == 0 {
+		ref.tag = DEFAULT_TAG
+	}
+	return ref
+}
+
+// Returns repository of an image as it is written, without tag and digest.
+func getRepository(image string) string {
+	name := image
+	if idx := strings.Index(name, "@"); idx != -1 {
+		name = name[:idx]
+	}
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name = name[:idx]
+	}
+	return name
+}
+
+// Returns name of the repository including registry host.
+func (ref reference) name() string {
+	return ref.host + "/" + ref.repository
+}
+
+func contains(list []string, item string) bool {
+	for _, value := range list {
+		if value == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package imagepolicy
+
+import (
+	"commons/errors"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	dbmocks "db/bolt/configuration/mocks"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+const (
+	image        = "test_url:5000/test_app:v1"
+	repository   = "test_url:5000/test_app"
+	digest       = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	otherDigest  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	signedFormat = `{"critical":{"identity":{"docker-reference":"test_url:5000/test_app"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"}}`
+)
+
+var notFoundError = errors.NotFound{}
+
+func makePolicyProperty(value map[string]interface{}) map[string]interface{} {
+	policy := DefaultPolicy()
+	for key, v := range value {
+		policy[key] = v
+	}
+	return map[string]interface{}{
+		"name":     IMAGE_POLICY,
+		"value":    policy,
+		"readOnly": false,
+	}
+}
+
+func makeKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload string) signature {
+	hash := sha256.Sum256([]byte(payload))
+	value, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	return signature{payload: []byte(payload), value: value}
+}
+
+func setRegistryStubs(digests map[string]string, signatures []signature) func() {
+	origResolve, origFetch := resolveDigest, fetchSignatures
+	resolveDigest = func(ref reference) (string, error) {
+		if len(ref.digest) != 0 {
+			return ref.digest, nil
+		}
+		if d, exists := digests[ref.name()+":"+ref.tag]; exists {
+			return d, nil
+		}
+		return "", errors.NotFoundImage{}
+	}
+	fetchSignatures = func(ref reference, digest string) ([]signature, error) {
+		return signatures, nil
+	}
+	return func() {
+		resolveDigest, fetchSignatures = origResolve, origFetch
+	}
+}
+
+func TestCalledVerifyWithoutPolicy_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(nil, notFoundError)
+	dbExecutor = dbExecutorMockObj
+
+	defer setRegistryStubs(nil, nil)()
+
+	repoDigests, err := Executor{}.Verify([]string{image, "nginx"})
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if len(repoDigests) != 0 {
+		t.Errorf("Unexpected repository digests: %v", repoDigests)
+	}
+}
+
+func TestCalledVerifyWithNotAllowedRegistry_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	property := makePolicyProperty(map[string]interface{}{
+		ALLOWED_REGISTRIES: []interface{}{"https://TEST_URL:5000"},
+	})
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(property, nil).Times(2)
+	dbExecutor = dbExecutorMockObj
+
+	defer setRegistryStubs(nil, nil)()
+
+	_, err := Executor{}.Verify([]string{image})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	_, err = Executor{}.Verify([]string{image, "nginx:latest"})
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestCalledVerifyWithPinnedDigests_ExpectTagsResolvedToPinnedDigestAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	property := makePolicyProperty(map[string]interface{}{
+		PINNED_DIGESTS: map[string]interface{}{
+			repository: []interface{}{digest},
+		},
+	})
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(property, nil).Times(3)
+	dbExecutor = dbExecutorMockObj
+
+	defer setRegistryStubs(map[string]string{
+		repository + ":v1": digest,
+		repository + ":v2": otherDigest,
+	}, nil)()
+
+	repoDigests, err := Executor{}.Verify([]string{image})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if repoDigests[image] != repository+"@"+digest {
+		t.Errorf("Expected repository digest: %s, actual: %s", repository+"@"+digest, repoDigests[image])
+	}
+
+	_, err = Executor{}.Verify([]string{repository + ":v2"})
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+
+	_, err = Executor{}.Verify([]string{repository + "@" + otherDigest})
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestCalledVerifyWhenDigestRequired_ExpectNotPinnedRepositoryRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	property := makePolicyProperty(map[string]interface{}{
+		PINNED_DIGESTS: map[string]interface{}{
+			repository: []interface{}{digest},
+		},
+		REQUIRE_DIGEST: true,
+	})
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(property, nil)
+	dbExecutor = dbExecutorMockObj
+
+	defer setRegistryStubs(map[string]string{repository + ":v1": digest}, nil)()
+
+	_, err := Executor{}.Verify([]string{image, "nginx"})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+	case errors.Forbidden:
+	}
+}
+
+func TestCalledVerifyWhenRegistryFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	property := makePolicyProperty(map[string]interface{}{
+		PINNED_DIGESTS: map[string]interface{}{
+			repository: []interface{}{digest},
+		},
+	})
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(property, nil)
+	dbExecutor = dbExecutorMockObj
+
+	defer setRegistryStubs(nil, nil)()
+
+	_, err := Executor{}.Verify([]string{image})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFoundImage", err)
+	case errors.NotFoundImage:
+	}
+}
+
+func TestCalledVerifyWithPublicKeys_ExpectOnlySignedImagesAllowed(t *testing.T) {
+	key, publicKey := makeKey(t)
+	otherKey, _ := makeKey(t)
+
+	tests := map[string]struct {
+		signatures []signature
+		allowed    bool
+	}{
+		"Signed":         {[]signature{sign(t, key, fmt.Sprintf(signedFormat, digest))}, true},
+		"NotSigned":      {nil, false},
+		"SignedByOther":  {[]signature{sign(t, otherKey, fmt.Sprintf(signedFormat, digest))}, false},
+		"OtherDigest":    {[]signature{sign(t, key, fmt.Sprintf(signedFormat, otherDigest))}, false},
+		"InvalidPayload": {[]signature{sign(t, key, "{")}, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			property := makePolicyProperty(map[string]interface{}{
+				PUBLIC_KEYS: []interface{}{publicKey},
+			})
+
+			dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+			dbExecutorMockObj.EXPECT().GetProperty(IMAGE_POLICY).Return(property, nil)
+			dbExecutor = dbExecutorMockObj
+
+			defer setRegistryStubs(map[string]string{repository + ":v1": digest}, test.signatures)()
+
+			_, err := Executor{}.Verify([]string{image})
+
+			if test.allowed && err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if !test.allowed {
+				switch err.(type) {
+				default:
+					t.Errorf("Expected err: %s, actual err: %v", "Forbidden", err)
+				case errors.Forbidden:
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	_, publicKey := makeKey(t)
+
+	tests := map[string]struct {
+		value interface{}
+		valid bool
+	}{
+		"Default":         {DefaultPolicy(), true},
+		"Full":            {map[string]interface{}{ALLOWED_REGISTRIES: []interface{}{"docker.io"}, PINNED_DIGESTS: map[string]interface{}{"nginx": []interface{}{digest}}, REQUIRE_DIGEST: true, PUBLIC_KEYS: []interface{}{publicKey}}, true},
+		"NotMap":          {"policy", false},
+		"UnknownField":    {map[string]interface{}{"unknown": true}, false},
+		"EmptyRegistry":   {map[string]interface{}{ALLOWED_REGISTRIES: []interface{}{" "}}, false},
+		"InvalidDigest":   {map[string]interface{}{PINNED_DIGESTS: map[string]interface{}{"nginx": []interface{}{"latest"}}}, false},
+		"InvalidKey":      {map[string]interface{}{PUBLIC_KEYS: []interface{}{"key"}}, false},
+		"NotBoolRequired": {map[string]interface{}{REQUIRE_DIGEST: "true"}, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePolicy(test.value)
+			if test.valid && err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if !test.valid {
+				switch err.(type) {
+				default:
+					t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+				case errors.InvalidJSON:
+				}
+			}
+		})
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]reference{
+		"nginx":                            {"docker.io", "library/nginx", "latest", ""},
+		"docker.io/user/app:v1":            {"docker.io", "user/app", "v1", ""},
+		"test_url:5000/test_app":           {"test_url:5000", "test_app", "latest", ""},
+		"localhost/group/app:v2":           {"localhost", "group/app", "v2", ""},
+		"test_url:5000/test_app@" + digest: {"test_url:5000", "test_app", "", digest},
+		"nginx:1.13@" + digest:             {"docker.io", "library/nginx", "1.13", digest},
+	}
+
+	for image, expected := range tests {
+		if ref := parseReference(image); ref != expected {
+			t.Errorf("Expected reference of %s: %v, actual: %v", image, expected, ref)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package imagepolicy
+
+import (
+	"commons/errors"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+)
+
+// Detached signature of an image.
+// value is a signature of payload which names the signed digest.
+type signature struct {
+	payload []byte
+	value   []byte
+}
+
+// Payload of a signature in the simple signing format.
+type signedPayload struct {
+	Critical struct {
+		Image struct {
+			Digest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Returns a public key from PEM encoded "PUBLIC KEY" block,
+// ECDSA, RSA and Ed25519 keys are supported.
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.InvalidJSON{Msg: "invalid PEM encoded public key"}
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "invalid public key : " + err.Error()}
+	}
+
+	switch key.(type) {
+	default:
+		return nil, errors.InvalidJSON{Msg: "unsupported type of public key"}
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+}
+
+// Returns whether one of signatures is for the digest
+// and verified by one of keys.
+func isSigned(digest string, signatures []signature, keys []crypto.PublicKey) bool {
+	for _, sig := range signatures {
+		payload := signedPayload{}
+		err := json.Unmarshal(sig.payload, &payload)
+		if err != nil || payload.Critical.Image.Digest != digest {
+			continue
+		}
+
+		for _, key := range keys {
+			if verifySignature(key, sig) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func verifySignature(key crypto.PublicKey, sig signature) bool {
+	hash := sha256.Sum256(sig.payload)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], sig.value)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig.value) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, sig.payload, sig.value)
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+// Package job runs long operations such as deploying and updating apps
+// in background, and keeps their phase, progress and result for a while
+// so that they can be polled and canceled.
+package job
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/dockercontroller"
+	"crypto/rand"
+	"encoding/hex"
+	"golang.org/x/net/context"
+	"sync"
+	"time"
+)
+
+const (
+	ID              = "id"
+	TYPE            = "type"
+	STATE           = "state"
+	PHASE           = "phase"
+	PROGRESS        = "progress"
+	LAYERS          = "layers"
+	IMAGE           = "image"
+	LAYER           = "layer"
+	STATUS          = "status"
+	CURRENT         = "current"
+	TOTAL           = "total"
+	RESULT          = "result"
+	ERROR           = "error"
+	CODE            = "code"
+	WARNINGS        = "warnings"
+	CREATED         = "created"
+	UPDATED         = "updated"
+	DEPLOY          = "deploy"
+	UPDATE          = "update"
+	REDEPLOY        = "redeploy"
+	RUNNING_STATE   = "running"
+	SUCCEEDED_STATE = "succeeded"
+	FAILED_STATE    = "failed"
+	CANCELED_STATE  = "canceled"
+	ID_LENGTH       = 16
+
+	// Finished jobs are kept for this duration after they are finished.
+	RETENTION = time.Hour
+)
+
+// Operation run by a job.
+// it should stop when ctx is canceled, and may report its phase and
+// progress of pulling images to job.
+type Task func(ctx context.Context, job *Job) (map[string]interface{}, error)
+
+// Interface of job operations.
+type Command interface {
+	// Start runs task in background and returns the job running it.
+	Start(kind string, task Task) (map[string]interface{}, error)
+
+	// Get returns the job of the given id.
+	Get(id string) (map[string]interface{}, error)
+
+	// Cancel requests to stop the job of the given id and returns it.
+	Cancel(id string) (map[string]interface{}, error)
+}
+
+type Executor struct{}
+
+// Job keeps state of a task running in background.
+type Job struct {
+	mutex    sync.Mutex
+	id       string
+	kind     string
+	state    string
+	phase    string
+	layers   []dockercontroller.PullProgress
+	result   map[string]interface{}
+	err      string
+	code     int
+	warnings []string
+	created  int64
+	updated  int64
+	cancel   context.CancelFunc
+}
+
+var now = time.Now
+var generateID = func() (string, error) {
+	bytes := make([]byte, ID_LENGTH)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", errors.Unknown{Msg: err.Error()}
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+var jobs = struct {
+	sync.Mutex
+	m map[string]*Job
+}{m: make(map[string]*Job)}
+
+// Starting task in background as a job of kind.
+// if succeed to start, return the job in running state.
+// otherwise, return error.
+func (Executor) Start(kind string, task Task) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", kind)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	id, err := generateID()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		id:      id,
+		kind:    kind,
+		state:   RUNNING_STATE,
+		created: now().Unix(),
+		updated: now().Unix(),
+		cancel:  cancel,
+	}
+
+	jobs.Lock()
+	pruneJobs()
+	jobs.m[id] = job
+	jobs.Unlock()
+
+	go job.run(ctx, task)
+
+	return job.toMap(), nil
+}
+
+// Getting the job of id.
+// if succeed to get, return its state, phase, progress and result.
+// otherwise, return error.
+func (Executor) Get(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", id)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	job, err := getJob(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return job.toMap(), nil
+}
+
+// Canceling the job of id.
+// the job is stopped by canceling the context given to its task,
+// and becomes canceled state when the task returns.
+// canceling a finished job has no effect.
+// if succeed to request, return the job.
+// otherwise, return error.
+func (Executor) Cancel(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN", id)
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	job, err := getJob(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	job.cancel()
+	return job.toMap(), nil
+}
+
+// SetPhase changes the phase of the job.
+func (job *Job) SetPhase(phase string) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.phase = phase
+	job.updated = now().Unix()
+}
+
+// SetPullProgress updates progress of pulling a layer of an image.
+func (job *Job) SetPullProgress(progress dockercontroller.PullProgress) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.updated = now().Unix()
+	for i, layer := range job.layers {
+		if layer.Image == progress.Image && layer.Layer == progress.Layer {
+			job.layers[i] = progress
+			return
+		}
+	}
+	job.layers = append(job.layers, progress)
+}
+
+// AddWarning adds a problem which doesn't fail the job.
+func (job *Job) AddWarning(warning string) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.updated = now().Unix()
+	job.warnings = append(job.warnings, warning)
+}
+
+func (job *Job) run(ctx context.Context, task Task) {
+	defer job.cancel()
+
+	result, err := task(ctx, job)
+
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.updated = now().Unix()
+	switch {
+	case err != nil && ctx.Err() != nil:
+		logger.Logging(logger.INFO, "job is canceled :", job.id)
+		job.state = CANCELED_STATE
+		job.err = err.Error()
+		job.code = errors.StatusCode(err)
+	case err != nil:
+		logger.Logging(logger.ERROR, "job is failed :", job.id, err.Error())
+		job.state = FAILED_STATE
+		job.err = err.Error()
+		job.code = errors.StatusCode(err)
+	default:
+		job.state = SUCCEEDED_STATE
+		job.result = result
+	}
+}
+
+func (job *Job) toMap() map[string]interface{} {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	res := map[string]interface{}{
+		ID:      job.id,
+		TYPE:    job.kind,
+		STATE:   job.state,
+		CREATED: job.created,
+		UPDATED: job.updated,
+	}
+	if job.phase != "" {
+		res[PHASE] = job.phase
+	}
+	if len(job.layers) != 0 {
+		res[PROGRESS] = makeProgress(job.layers)
+	}
+	if job.result != nil {
+		res[RESULT] = job.result
+	}
+	// Status code is the one which the request would have returned
+	// if it had been done synchronously.
+	if job.err != "" {
+		res[ERROR] = job.err
+		res[CODE] = job.code
+	}
+	if len(job.warnings) != 0 {
+		res[WARNINGS] = job.warnings
+	}
+	return res
+}
+
+// Returns progress of pulling images with bytes of each layer and their sum.
+func makeProgress(layers []dockercontroller.PullProgress) map[string]interface{} {
+	var current, total int64
+	list := make([]map[string]interface{}, 0, len(layers))
+	for _, layer := range layers {
+		current += layer.Current
+		total += layer.Total
+		list = append(list, map[string]interface{}{
+			IMAGE:   layer.Image,
+			LAYER:   layer.Layer,
+			STATUS:  layer.Status,
+			CURRENT: layer.Current,
+			TOTAL:   layer.Total,
+		})
+	}
+	return map[string]interface{}{
+		CURRENT: current,
+		TOTAL:   total,
+		LAYERS:  list,
+	}
+}
+
+func getJob(id string) (*Job, error) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	pruneJobs()
+	job, exists := jobs.m[id]
+	if !exists {
+		return nil, errors.NotFoundURL{Msg: "job not found : " + id}
+	}
+	return job, nil
+}
+
+// Removes jobs finished longer than RETENTION ago.
+// jobs.Mutex should be locked by the caller.
+func pruneJobs() {
+	expired := now().Add(-RETENTION).Unix()
+	for id, job := range jobs.m {
+		job.mutex.Lock()
+		finished := job.state != RUNNING_STATE && job.updated < expired
+		job.mutex.Unlock()
+		if finished {
+			delete(jobs.m, id)
+		}
+	}
+}
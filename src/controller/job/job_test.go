@@ -0,0 +1,223 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package job
+
+import (
+	"commons/errors"
+	"controller/dockercontroller"
+	"golang.org/x/net/context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	APP_ID     = "000000000000000000000000"
+	IMAGE_NAME = "test_url:5000/test:1.0"
+)
+
+var executor Executor
+
+// Waits until the job of id is finished and returns it.
+func waitJob(t *testing.T, id string) map[string]interface{} {
+	for i := 0; i < 100; i++ {
+		job, err := executor.Get(id)
+		if err != nil {
+			t.Fatalf("Unexpected err: %s", err.Error())
+		}
+		if job[STATE] != RUNNING_STATE {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job is not finished : %s", id)
+	return nil
+}
+
+func TestStart_ExpectSucceeded(t *testing.T) {
+	result := map[string]interface{}{"id": APP_ID}
+	job, err := executor.Start(DEPLOY, func(ctx context.Context, job *Job) (map[string]interface{}, error) {
+		job.SetPhase("pulling")
+		job.AddWarning("size of image is unknown")
+		return result, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	if job[TYPE] != DEPLOY {
+		t.Errorf("Expected type: %s, actual type: %v", DEPLOY, job[TYPE])
+	}
+
+	job = waitJob(t, job[ID].(string))
+	if job[STATE] != SUCCEEDED_STATE {
+		t.Errorf("Expected state: %s, actual state: %v", SUCCEEDED_STATE, job[STATE])
+	}
+	if job[PHASE] != "pulling" {
+		t.Errorf("Expected phase: pulling, actual phase: %v", job[PHASE])
+	}
+	if !reflect.DeepEqual(job[RESULT], result) {
+		t.Errorf("Expected result: %v, actual result: %v", result, job[RESULT])
+	}
+	if !reflect.DeepEqual(job[WARNINGS], []string{"size of image is unknown"}) {
+		t.Errorf("Expected warnings: [size of image is unknown], actual warnings: %v", job[WARNINGS])
+	}
+}
+
+func TestStartWhenTaskFailed_ExpectFailed(t *testing.T) {
+	job, err := executor.Start(UPDATE, func(ctx context.Context, job *Job) (map[string]interface{}, error) {
+		return nil, errors.Unknown{Msg: "fail to pull"}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	job = waitJob(t, job[ID].(string))
+	if job[STATE] != FAILED_STATE {
+		t.Errorf("Expected state: %s, actual state: %v", FAILED_STATE, job[STATE])
+	}
+	if job[ERROR] != (errors.Unknown{Msg: "fail to pull"}).Error() {
+		t.Errorf("Expected error: fail to pull, actual error: %v", job[ERROR])
+	}
+}
+
+func TestStartWhenTaskFailed_ExpectStatusCodeOfError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected int
+	}{
+		"InvalidYaml":          {errors.InvalidYaml{}, http.StatusBadRequest},
+		"Forbidden":            {errors.Forbidden{}, http.StatusForbidden},
+		"InsufficientResource": {errors.InsufficientResource{}, http.StatusConflict},
+		"Unknown":              {errors.Unknown{}, http.StatusInternalServerError},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			job, err := executor.Start(DEPLOY, func(ctx context.Context, job *Job) (map[string]interface{}, error) {
+				return nil, test.err
+			})
+			if err != nil {
+				t.Fatalf("Unexpected err: %s", err.Error())
+			}
+
+			job = waitJob(t, job[ID].(string))
+			if job[CODE] != test.expected {
+				t.Errorf("Expected code: %d, actual code: %v", test.expected, job[CODE])
+			}
+		})
+	}
+}
+
+func TestStartWhenGenerateIDFailed_ExpectErrorReturn(t *testing.T) {
+	generate := generateID
+	defer func() { generateID = generate }()
+	generateID = func() (string, error) {
+		return "", errors.Unknown{}
+	}
+
+	_, err := executor.Start(DEPLOY, nil)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: Unknown, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCancel_ExpectCanceled(t *testing.T) {
+	started := make(chan bool)
+	job, err := executor.Start(DEPLOY, func(ctx context.Context, job *Job) (map[string]interface{}, error) {
+		started <- true
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	<-started
+
+	_, err = executor.Cancel(job[ID].(string))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	job = waitJob(t, job[ID].(string))
+	if job[STATE] != CANCELED_STATE {
+		t.Errorf("Expected state: %s, actual state: %v", CANCELED_STATE, job[STATE])
+	}
+}
+
+func TestGetWithUnknownID_ExpectErrorReturn(t *testing.T) {
+	_, err := executor.Get("unknown")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFoundURL, actual err: %v", err)
+	case errors.NotFoundURL:
+	}
+}
+
+func TestCancelWithUnknownID_ExpectErrorReturn(t *testing.T) {
+	_, err := executor.Cancel("unknown")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFoundURL, actual err: %v", err)
+	case errors.NotFoundURL:
+	}
+}
+
+func TestSetPullProgress_ExpectLayersSummed(t *testing.T) {
+	job := &Job{id: APP_ID, state: RUNNING_STATE}
+
+	job.SetPullProgress(dockercontroller.PullProgress{Image: IMAGE_NAME, Layer: "a", Status: "Downloading", Current: 10, Total: 100})
+	job.SetPullProgress(dockercontroller.PullProgress{Image: IMAGE_NAME, Layer: "b", Status: "Downloading", Current: 20, Total: 50})
+	job.SetPullProgress(dockercontroller.PullProgress{Image: IMAGE_NAME, Layer: "a", Status: "Downloading", Current: 60, Total: 100})
+
+	progress := job.toMap()[PROGRESS].(map[string]interface{})
+	if progress[CURRENT] != int64(80) || progress[TOTAL] != int64(150) {
+		t.Errorf("Expected progress: 80/150, actual progress: %v/%v", progress[CURRENT], progress[TOTAL])
+	}
+	if len(progress[LAYERS].([]map[string]interface{})) != 2 {
+		t.Errorf("Expected 2 layers, actual layers: %v", progress[LAYERS])
+	}
+}
+
+func TestGetAfterRetention_ExpectJobRemoved(t *testing.T) {
+	current := time.Now()
+	defer func() { now = time.Now }()
+	now = func() time.Time { return current }
+
+	job, err := executor.Start(DEPLOY, func(ctx context.Context, job *Job) (map[string]interface{}, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	id := job[ID].(string)
+	waitJob(t, id)
+
+	now = func() time.Time { return current.Add(RETENTION + time.Second) }
+	_, err = executor.Get(id)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFoundURL, actual err: %v", err)
+	case errors.NotFoundURL:
+	}
+}
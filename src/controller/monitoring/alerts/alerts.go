@@ -0,0 +1,334 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package alerts provides threshold-based alert rules on resource usage.
+// rules are evaluated whenever a new resource sample is collected and
+// firing and resolved transitions are notified to pharos-anchor.
+package alerts
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/monitoring/resource"
+	notification "controller/notification/alerts"
+	alertDB "db/bolt/alert"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	ID                  = "id"
+	TARGET              = "target"
+	APP_ID              = "appid"
+	SERVICE             = "service"
+	METRIC              = "metric"
+	OPERATOR            = "operator"
+	THRESHOLD           = "threshold"
+	FOR                 = "for"
+	STATE               = "state"
+	SINCE               = "since"
+	VALUE               = "value"
+	TIMESTAMP           = "timestamp"
+	ALERTS              = "alerts"
+	TARGET_HOST         = "host"
+	TARGET_SERVICE      = "service"
+	STATE_OK            = "ok"
+	STATE_PENDING       = "pending"
+	STATE_FIRING        = "firing"
+	STATE_RESOLVED      = "resolved"
+	EVALUATION_INTERVAL = 5 * time.Second
+)
+
+type Command interface {
+	// AddRule adds a new alert rule described by body.
+	AddRule(body string) (map[string]interface{}, error)
+
+	// GetRules returns all of alert rules with their states.
+	GetRules() (map[string]interface{}, error)
+
+	// GetRule returns an alert rule with its state.
+	GetRule(id string) (map[string]interface{}, error)
+
+	// DeleteRule deletes an alert rule.
+	DeleteRule(id string) error
+}
+
+type Executor struct{}
+
+var dbExecutor alertDB.Command
+var resourceExecutor resource.Command
+var notificator notification.Command
+
+var metrics = map[string]bool{resource.CPU: true, resource.MEM: true}
+var operators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+}
+
+var evaluatorOnce sync.Once
+
+func init() {
+	dbExecutor = alertDB.Executor{}
+	resourceExecutor = resource.Executor
+	notificator = notification.Executor{}
+}
+
+// StartEvaluator starts to evaluate alert rules in background.
+// it is safe to call more than once.
+func StartEvaluator() {
+	evaluatorOnce.Do(func() {
+		go runEvaluator()
+	})
+}
+
+func runEvaluator() {
+	var lastTimestamp int64
+	for {
+		time.Sleep(EVALUATION_INTERVAL)
+
+		sample, err := resourceExecutor.GetLatestSample()
+		if err != nil {
+			continue
+		}
+		timestamp := sample[resource.TIMESTAMP].(int64)
+		if timestamp == lastTimestamp {
+			continue
+		}
+		lastTimestamp = timestamp
+		evaluate(sample)
+	}
+}
+
+// AddRule adds a new alert rule.
+// body should have target("host" or "service"), metric("cpu" or "mem"),
+// operator(">", ">=", "<" or "<=") and threshold. appid and service are
+// required when target is "service". for is how long the condition should hold
+// before firing, which is a duration string like "2m" or a number of seconds.
+// cpu and mem of host are in percent, cpu of a service is in percent and
+// mem of a service is in bytes.
+func (Executor) AddRule(body string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyMap := make(map[string]interface{})
+	err := json.Unmarshal([]byte(body), &bodyMap)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "Unmarshalling Failed"}
+	}
+
+	target, _ := bodyMap[TARGET].(string)
+	appId, _ := bodyMap[APP_ID].(string)
+	service, _ := bodyMap[SERVICE].(string)
+	metric, _ := bodyMap[METRIC].(string)
+	operator, _ := bodyMap[OPERATOR].(string)
+	threshold, ok := bodyMap[THRESHOLD].(float64)
+	if !ok {
+		return nil, errors.InvalidParam{Msg: "threshold should be a number"}
+	}
+
+	switch target {
+	default:
+		return nil, errors.InvalidParam{Msg: "target should be host or service"}
+	case TARGET_HOST:
+		appId, service = "", ""
+	case TARGET_SERVICE:
+		if len(appId) == 0 || len(service) == 0 {
+			return nil, errors.InvalidParam{Msg: "appid and service are required for service target"}
+		}
+	}
+	if !metrics[metric] {
+		return nil, errors.InvalidParam{Msg: "metric should be cpu or mem"}
+	}
+	if _, exists := operators[operator]; !exists {
+		return nil, errors.InvalidParam{Msg: "operator should be one of >, >=, < and <="}
+	}
+
+	duration, err := parseDuration(bodyMap[FOR])
+	if err != nil {
+		return nil, err
+	}
+
+	return dbExecutor.InsertRule(target, appId, service, metric, operator, threshold, duration)
+}
+
+func (Executor) GetRules() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	rules, err := dbExecutor.GetRules()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[ALERTS] = rules
+	return res, nil
+}
+
+func (Executor) GetRule(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	rule, err := dbExecutor.GetRule(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, convertDBError(err, id)
+	}
+	return rule, nil
+}
+
+func (Executor) DeleteRule(id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	err := dbExecutor.DeleteRule(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return convertDBError(err, id)
+	}
+	return nil
+}
+
+// convertDBError converts an error of db so that a missing rule
+// is reported as an unknown url instead of a db connection problem.
+func convertDBError(err error, id string) error {
+	switch err.(type) {
+	case errors.NotFound:
+		return errors.NotFoundURL{Msg: "failed to find alert rule : " + id}
+	default:
+		return err
+	}
+}
+
+// evaluate checks all of alert rules against a resource sample.
+// a rule whose condition holds becomes pending and then firing after
+// its duration, and goes back to ok as soon as the condition does not hold.
+// becoming firing and going back to ok from firing are notified.
+// a firing rule whose value is missing, e.g. its service disappeared,
+// keeps firing because the problem is not known to be resolved.
+func evaluate(sample map[string]interface{}) {
+	rules, err := dbExecutor.GetRules()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	timestamp := sample[resource.TIMESTAMP].(int64)
+	for _, rule := range rules {
+		id := rule[ID].(string)
+		state := rule[STATE].(string)
+		since := rule[SINCE].(int64)
+		duration := rule[FOR].(int64)
+
+		value, exists := getValue(rule, sample)
+		active := exists && operators[rule[OPERATOR].(string)](value, rule[THRESHOLD].(float64))
+
+		switch {
+		case active && state == STATE_OK && duration > 0:
+			updateState(id, STATE_PENDING, timestamp)
+		case active && state == STATE_OK:
+			if updateState(id, STATE_FIRING, timestamp) {
+				notify(rule, STATE_FIRING, value, timestamp)
+			}
+		case active && state == STATE_PENDING && timestamp-since >= duration:
+			if updateState(id, STATE_FIRING, since) {
+				notify(rule, STATE_FIRING, value, timestamp)
+			}
+		case !active && state == STATE_PENDING:
+			updateState(id, STATE_OK, 0)
+		case exists && !active && state == STATE_FIRING:
+			if updateState(id, STATE_OK, 0) {
+				notify(rule, STATE_RESOLVED, value, timestamp)
+			}
+		}
+	}
+}
+
+// getValue returns the value of a sample that a rule is about.
+// if the sample does not have the value, e.g. a service is not running,
+// return false as the second value.
+func getValue(rule map[string]interface{}, sample map[string]interface{}) (float64, bool) {
+	metric := rule[METRIC].(string)
+
+	if rule[TARGET].(string) == TARGET_HOST {
+		if metric == resource.MEM {
+			value, ok := sample[resource.MEM].(float64)
+			return value, ok
+		}
+		cpus, _ := sample[resource.CPU].([]float64)
+		if len(cpus) == 0 {
+			return 0, false
+		}
+		sum := 0.0
+		for _, value := range cpus {
+			sum += value
+		}
+		return sum / float64(len(cpus)), true
+	}
+
+	apps, _ := sample[resource.APPS].(map[string]interface{})
+	services, _ := apps[rule[APP_ID].(string)].(map[string]interface{})
+	stats, _ := services[rule[SERVICE].(string)].(map[string]interface{})
+	value, ok := stats[metric].(float64)
+	return value, ok
+}
+
+func updateState(id string, state string, since int64) bool {
+	err := dbExecutor.UpdateRuleState(id, state, since)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return false
+	}
+	return true
+}
+
+func notify(rule map[string]interface{}, state string, value float64, timestamp int64) {
+	alert := make(map[string]interface{})
+	for _, key := range []string{ID, TARGET, APP_ID, SERVICE, METRIC, OPERATOR, THRESHOLD, FOR} {
+		alert[key] = rule[key]
+	}
+	alert[STATE] = state
+	alert[VALUE] = value
+	alert[TIMESTAMP] = timestamp
+
+	notificator.SendAlert(alert)
+}
+
+// parseDuration returns duration in seconds from a duration string like "2m"
+// or a number of seconds. no duration means zero.
+func parseDuration(value interface{}) (int64, error) {
+	switch duration := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		if duration < 0 {
+			return 0, errors.InvalidParam{Msg: "for should not be negative"}
+		}
+		return int64(duration), nil
+	case string:
+		parsed, err := time.ParseDuration(duration)
+		if err != nil || parsed < 0 {
+			return 0, errors.InvalidParam{Msg: "for should be a duration like 2m"}
+		}
+		return int64(parsed / time.Second), nil
+	}
+	return 0, errors.InvalidParam{Msg: "for should be a duration like 2m"}
+}
@@ -0,0 +1,376 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alerts
+
+import (
+	"commons/errors"
+	notificationmocks "controller/notification/alerts/mocks"
+	dbmocks "db/bolt/alert/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+const (
+	ruleId    = "test_rule_id"
+	appId     = "test_app_id"
+	service   = "test_service"
+	timestamp = int64(1500000000)
+)
+
+var (
+	sample = map[string]interface{}{
+		"timestamp": timestamp,
+		"cpu":       []float64{80, 100},
+		"mem":       95.0,
+		"apps": map[string]interface{}{
+			appId: map[string]interface{}{
+				service: map[string]interface{}{"cpu": 10.0, "mem": 2048.0},
+			},
+		},
+	}
+	notFoundError = errors.NotFound{}
+)
+
+func makeRule(target, metric, operator string, threshold float64, duration int64, state string, since int64) map[string]interface{} {
+	rule := map[string]interface{}{
+		"id":        ruleId,
+		"target":    target,
+		"appid":     "",
+		"service":   "",
+		"metric":    metric,
+		"operator":  operator,
+		"threshold": threshold,
+		"for":       duration,
+		"state":     state,
+		"since":     since,
+		"timestamp": int64(1),
+	}
+	if target == TARGET_SERVICE {
+		rule["appid"] = appId
+		rule["service"] = service
+	}
+	return rule
+}
+
+func TestAddRule_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "mem", ">", 90, 120, STATE_OK, 0)
+	gomock.InOrder(
+		dbMockObj.EXPECT().InsertRule(TARGET_HOST, "", "", "mem", ">", 90.0, int64(120)).Return(rule, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+
+	res, err := Executor{}.AddRule(`{"target":"host","metric":"mem","operator":">","threshold":90,"for":"2m"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(rule, res) {
+		t.Errorf("Expected res: %v, actual res: %v", rule, res)
+	}
+}
+
+func TestAddServiceRuleWithSecondsDuration_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_SERVICE, "cpu", ">=", 80, 30, STATE_OK, 0)
+	gomock.InOrder(
+		dbMockObj.EXPECT().InsertRule(TARGET_SERVICE, appId, service, "cpu", ">=", 80.0, int64(30)).Return(rule, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+
+	_, err := Executor{}.AddRule(`{"target":"service","appid":"test_app_id","service":"test_service","metric":"cpu","operator":">=","threshold":80,"for":30}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestAddRuleWithInvalidJSON_ExpectInvalidJSON(t *testing.T) {
+	_, err := Executor{}.AddRule(`invalid`)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: InvalidJSON, actual err: %v", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestAddRuleWithInvalidParams_ExpectInvalidParam(t *testing.T) {
+	testList := []string{
+		`{"target":"host","metric":"mem","operator":">"}`,
+		`{"target":"node","metric":"mem","operator":">","threshold":90}`,
+		`{"target":"service","metric":"mem","operator":">","threshold":90}`,
+		`{"target":"host","metric":"disk","operator":">","threshold":90}`,
+		`{"target":"host","metric":"mem","operator":"!=","threshold":90}`,
+		`{"target":"host","metric":"mem","operator":">","threshold":90,"for":"soon"}`,
+		`{"target":"host","metric":"mem","operator":">","threshold":90,"for":-1}`,
+	}
+
+	for _, body := range testList {
+		_, err := Executor{}.AddRule(body)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: InvalidParam, actual err: %v, body : %s", err, body)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestGetRules_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	rules := []map[string]interface{}{makeRule(TARGET_HOST, "mem", ">", 90, 0, STATE_OK, 0)}
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return(rules, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+
+	res, err := Executor{}.GetRules()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{ALERTS: rules}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestGetRuleWhenRuleNotExist_ExpectNotFoundURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRule(ruleId).Return(nil, notFoundError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+
+	_, err := Executor{}.GetRule(ruleId)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFoundURL, actual err: %v", err)
+	case errors.NotFoundURL:
+	}
+}
+
+func TestDeleteRuleWhenRuleNotExist_ExpectNotFoundURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().DeleteRule(ruleId).Return(notFoundError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+
+	err := Executor{}.DeleteRule(ruleId)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFoundURL, actual err: %v", err)
+	case errors.NotFoundURL:
+	}
+}
+
+func TestEvaluateWhenConditionHolds_ExpectPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "cpu", ">", 85, 120, STATE_OK, 0)
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+		dbMockObj.EXPECT().UpdateRuleState(ruleId, STATE_PENDING, timestamp).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenConditionHoldsWithoutDuration_ExpectFiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_SERVICE, "mem", ">=", 1024, 0, STATE_OK, 0)
+	expectedAlert := map[string]interface{}{
+		"id":        ruleId,
+		"target":    TARGET_SERVICE,
+		"appid":     appId,
+		"service":   service,
+		"metric":    "mem",
+		"operator":  ">=",
+		"threshold": 1024.0,
+		"for":       int64(0),
+		"state":     STATE_FIRING,
+		"value":     2048.0,
+		"timestamp": timestamp,
+	}
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+		dbMockObj.EXPECT().UpdateRuleState(ruleId, STATE_FIRING, timestamp).Return(nil),
+		notificationMockObj.EXPECT().SendAlert(expectedAlert),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenPendingLongEnough_ExpectFiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "mem", ">", 90, 120, STATE_PENDING, timestamp-120)
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+		dbMockObj.EXPECT().UpdateRuleState(ruleId, STATE_FIRING, timestamp-120).Return(nil),
+		notificationMockObj.EXPECT().SendAlert(gomock.Any()),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenPendingNotLongEnough_ExpectNothingChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "mem", ">", 90, 120, STATE_PENDING, timestamp-60)
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenFiringConditionCleared_ExpectResolved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "cpu", ">", 95, 0, STATE_FIRING, timestamp-10)
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+		dbMockObj.EXPECT().UpdateRuleState(ruleId, STATE_OK, int64(0)).Return(nil),
+		notificationMockObj.EXPECT().SendAlert(gomock.Any()).Do(func(alert map[string]interface{}) {
+			if alert[STATE] != STATE_RESOLVED || alert[VALUE] != 90.0 {
+				t.Errorf("Unexpected alert: %v", alert)
+			}
+		}),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenServiceNotExist_ExpectStillFiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_SERVICE, "cpu", ">", 5, 0, STATE_FIRING, timestamp-10)
+	rule["service"] = "unknown_service"
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
+
+func TestEvaluateWhenUpdateStateFailed_ExpectNotNotified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	notificationMockObj := notificationmocks.NewMockCommand(ctrl)
+
+	rule := makeRule(TARGET_HOST, "mem", ">", 90, 0, STATE_OK, 0)
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetRules().Return([]map[string]interface{}{rule}, nil),
+		dbMockObj.EXPECT().UpdateRuleState(ruleId, STATE_FIRING, timestamp).Return(notFoundError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbMockObj
+	notificator = notificationMockObj
+
+	evaluate(sample)
+}
@@ -35,8 +35,14 @@ const (
 	EXITED_STATE           = "exited"
 	RUNNING_STATE          = "running"
 	PARTIALLY_EXITED_STATE = "partially exited"
+	PAUSED_STATE           = "paused"
+	UPDATING_STATE         = "updating"
 	START                  = "start"
 	DIE                    = "die"
+	PAUSE                  = "pause"
+	UNPAUSE                = "unpause"
+	APP                    = "app"
+	EVENT_BUFFER_SIZE      = 64
 )
 
 type Command interface {
@@ -45,6 +51,16 @@ type Command interface {
 	LockUpdateAppState()
 	UnlockUpdateAppState()
 	GetEventChannel() chan dockercontroller.Event
+	SubscribeEvents(filter EventFilter) (int, chan dockercontroller.Event)
+	UnsubscribeEvents(id int)
+}
+
+// Filter of events delivered to a local subscriber.
+// Empty list means that all of events are accepted for the field.
+type EventFilter struct {
+	AppIDs   []string
+	Services []string
+	Statuses []string
 }
 
 type Executor struct{}
@@ -55,6 +71,15 @@ var notiExecutor apps.Command
 var events chan dockercontroller.Event
 var appStateMutex = &sync.Mutex{}
 
+var subscribers = make(map[int]subscriber)
+var subscriberMutex = &sync.Mutex{}
+var lastSubscriberId = 0
+
+type subscriber struct {
+	filter EventFilter
+	events chan dockercontroller.Event
+}
+
 func init() {
 	dockerExecutor = dockercontroller.Executor
 	dbExecutor = service.Executor{}
@@ -103,14 +128,49 @@ func (Executor) DisableEventMonitoring(appId, path string) error {
 	return nil
 }
 
+// Subscribe container and app state changes which match the filter.
+// Events are delivered through the returned channel
+// until UnsubscribeEvents is called with the returned id.
+func (Executor) SubscribeEvents(filter EventFilter) (int, chan dockercontroller.Event) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	subscriberMutex.Lock()
+	defer subscriberMutex.Unlock()
+
+	lastSubscriberId++
+	subscribers[lastSubscriberId] = subscriber{
+		filter: filter,
+		events: make(chan dockercontroller.Event, EVENT_BUFFER_SIZE),
+	}
+	return lastSubscriberId, subscribers[lastSubscriberId].events
+}
+
+// Stop delivering events to the subscriber and close its channel.
+func (Executor) UnsubscribeEvents(id int) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	subscriberMutex.Lock()
+	defer subscriberMutex.Unlock()
+
+	if sub, exists := subscribers[id]; exists {
+		delete(subscribers, id)
+		close(sub.events)
+	}
+}
+
 func startEventMonitoring() {
 	go func() {
 		for {
 			select {
 			case event := <-events:
 				notiExecutor.SendNotification(event)
+				publishEvent(event)
 				if event.Status == START ||
-					event.Status == DIE {
+					event.Status == DIE ||
+					event.Status == PAUSE ||
+					event.Status == UNPAUSE {
 					appStateMutex.Lock()
 					updateAppState(event)
 					appStateMutex.Unlock()
@@ -137,6 +197,12 @@ func updateAppState(event dockercontroller.Event) {
 		return
 	}
 
+	// state of the app being updated is decided by the update.
+	if app["state"].(string) == UPDATING_STATE {
+		logger.Logging(logger.DEBUG, "App state is updating")
+		return
+	}
+
 	description := make(map[string]interface{})
 	err = json.Unmarshal([]byte(app["description"].(string)), &description)
 	if err != nil {
@@ -161,34 +227,49 @@ func updateAppState(event dockercontroller.Event) {
 		return
 	}
 
-	exitedServiceCnt := 0
-	serviceCnt := len(description["services"].(map[string]interface{}))
-
+	serviceStates := make([]string, 0)
 	for _, serviceName := range reflect.ValueOf(description["services"].(map[string]interface{})).MapKeys() {
 		infos, err := dockerExecutor.Ps(event.AppID, "docker-compose.yml", serviceName.String())
-		if len(infos) == 0 {
-			logger.Logging(logger.ERROR, "no information about service")
-			return
-		}
 		if err != nil {
 			logger.Logging(logger.ERROR, err.Error())
 			return
 		}
+		serviceStates = append(serviceStates, ServiceState(infos))
+	}
 
-		if strings.Contains(infos[0]["State"], "Exited") {
-			exitCode, _ := strconv.ParseUint(extractStringInParenthesis(infos[0]["State"]), 10, 32)
-			if exitCode != 0 {
-				exitedServiceCnt++
-			}
-		}
+	state := AppState(serviceStates)
+	if state != app["state"].(string) {
+		dbExecutor.UpdateAppState(event.AppID, state)
+		publishEvent(dockercontroller.Event{Type: APP, AppID: event.AppID, Status: state})
 	}
+}
 
-	if exitedServiceCnt == 0 {
-	} else if exitedServiceCnt < serviceCnt {
-		dbExecutor.UpdateAppState(event.AppID, PARTIALLY_EXITED_STATE)
-	} else if exitedServiceCnt == serviceCnt {
-		dbExecutor.UpdateAppState(event.AppID, EXITED_STATE)
+// ServiceState decides the state of a service by states of all of its containers.
+// the service is running if any of containers is running, paused if some of
+// containers are paused but none is running, and exited if some of containers
+// exited with non-zero exit code or the service is scaled to 0.
+// containers exited with code 0 such as one-shot init services are regarded
+// as completed, which don't make the service exited.
+func ServiceState(infos []map[string]string) string {
+	if len(infos) == 0 {
+		return EXITED_STATE
+	}
+
+	state := RUNNING_STATE
+	for _, info := range infos {
+		switch {
+		case strings.Contains(info["State"], "Paused"):
+			state = PAUSED_STATE
+		case strings.HasPrefix(info["State"], "Up"):
+			return RUNNING_STATE
+		case strings.Contains(info["State"], "Exited"):
+			exitCode, _ := strconv.ParseUint(extractStringInParenthesis(info["State"]), 10, 32)
+			if exitCode != 0 && state != PAUSED_STATE {
+				state = EXITED_STATE
+			}
+		}
 	}
+	return state
 }
 
 func extractStringInParenthesis(s string) string {
@@ -201,3 +282,65 @@ func extractStringInParenthesis(s string) string {
 	}
 	return ""
 }
+
+// AppState decides the state of an app by states of its services.
+// the app is paused if all of services are paused, exited if all of services
+// are exited, partially exited if some of services are exited, and running otherwise.
+func AppState(serviceStates []string) string {
+	exitedServiceCnt := 0
+	pausedServiceCnt := 0
+	for _, state := range serviceStates {
+		switch state {
+		case EXITED_STATE:
+			exitedServiceCnt++
+		case PAUSED_STATE:
+			pausedServiceCnt++
+		}
+	}
+
+	switch {
+	case pausedServiceCnt == len(serviceStates):
+		return PAUSED_STATE
+	case exitedServiceCnt == len(serviceStates):
+		return EXITED_STATE
+	case exitedServiceCnt > 0:
+		return PARTIALLY_EXITED_STATE
+	}
+	return RUNNING_STATE
+}
+
+// Deliver the event to subscribers whose filter matches the event.
+// if a subscriber does not consume events in time, the event is dropped for it.
+func publishEvent(event dockercontroller.Event) {
+	subscriberMutex.Lock()
+	defer subscriberMutex.Unlock()
+
+	for id, sub := range subscribers {
+		if !sub.filter.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			logger.Logging(logger.ERROR, "event is dropped for slow subscriber", strconv.Itoa(id))
+		}
+	}
+}
+
+func (filter EventFilter) match(event dockercontroller.Event) bool {
+	return matchField(filter.AppIDs, event.AppID) &&
+		matchField(filter.Services, event.ServiceName) &&
+		matchField(filter.Statuses, event.Status)
+}
+
+func matchField(values []string, field string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == field {
+			return true
+		}
+	}
+	return false
+}
@@ -49,6 +49,11 @@ var (
 			"State": "Exited (137)",
 		},
 	}
+	psWithPausedObj = []map[string]string{
+		{
+			"State": "Paused",
+		},
+	}
 	psWithGracefullyExitedObj = []map[string]string{
 		{
 			"State": "Exited (0)",
@@ -190,34 +195,49 @@ func TestGetEventChannel(t *testing.T) {
 	}
 }
 
-func TestExtractStringInParenthesis(t *testing.T) {
+func TestUpdateAppStateWhenGetAppFailed_ExpectReturnAfterGetApp(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	expectedRet := "123"
-	testStr := "(" + expectedRet + ") test string"
-	ret := extractStringInParenthesis(testStr)
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(nil, unknownError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
 
-	if ret != expectedRet {
-		t.Errorf("Expected result : %s, actual result : %s", expectedRet, ret)
+	testEvent := dockercontroller.Event{
+		AppID: appId,
 	}
+	updateAppState(testEvent)
 }
 
-func TestUpdateAppStateWhenGetAppFailed_ExpectReturnAfterGetApp(t *testing.T) {
+func TestUpdateAppStateWhenAppIsUpdating_ExpectStateNotUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	updatingApp := map[string]interface{}{
+		"id":          appId,
+		"state":       UPDATING_STATE,
+		"description": dbGetAppObj["description"],
+	}
 
 	gomock.InOrder(
-		dbExecutorMockObj.EXPECT().GetApp(appId).Return(nil, unknownError),
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(updatingApp, nil),
 	)
 
 	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
 	dbExecutor = dbExecutorMockObj
 
 	testEvent := dockercontroller.Event{
-		AppID: appId,
+		AppID:  appId,
+		Status: DIE,
 	}
 	updateAppState(testEvent)
 }
@@ -269,3 +289,209 @@ func TestUpdateAppstate_ExpectUpdateAppStateToExited(t *testing.T) {
 	}
 	updateAppState(testEvent)
 }
+
+func TestSubscribeEvents_ExpectFilteredEventsDelivered(t *testing.T) {
+	id, ch := Executor{}.SubscribeEvents(EventFilter{AppIDs: []string{appId}, Statuses: []string{DIE}})
+	defer Executor{}.UnsubscribeEvents(id)
+
+	publishEvent(dockercontroller.Event{AppID: "other_app_id", Status: DIE})
+	publishEvent(dockercontroller.Event{AppID: appId, ServiceName: serviceName, Status: START})
+	publishEvent(dockercontroller.Event{AppID: appId, ServiceName: serviceName, Status: DIE})
+
+	if len(ch) != 1 {
+		t.Fatalf("Expected number of events : 1, actual number of events : %d", len(ch))
+	}
+
+	event := <-ch
+	if event.AppID != appId || event.Status != DIE {
+		t.Errorf("Unexpected event : %v", event)
+	}
+}
+
+func TestUpdateAppstate_ExpectAppEventPublished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithForcefullyExitedObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithForcefullyExitedObj, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(appId, EXITED_STATE),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	id, ch := Executor{}.SubscribeEvents(EventFilter{})
+	defer Executor{}.UnsubscribeEvents(id)
+
+	updateAppState(dockercontroller.Event{AppID: appId})
+
+	if len(ch) != 1 {
+		t.Fatalf("Expected number of events : 1, actual number of events : %d", len(ch))
+	}
+
+	event := <-ch
+	if event.Type != APP || event.Status != EXITED_STATE {
+		t.Errorf("Unexpected event : %v", event)
+	}
+}
+
+func TestUnsubscribeEvents_ExpectChannelClosed(t *testing.T) {
+	id, ch := Executor{}.SubscribeEvents(EventFilter{})
+	Executor{}.UnsubscribeEvents(id)
+
+	publishEvent(dockercontroller.Event{AppID: appId, Status: DIE})
+
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected closed channel")
+	}
+}
+
+func TestUpdateAppstateWithPauseEvent_ExpectUpdateAppStateToPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithPausedObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithPausedObj, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(appId, PAUSED_STATE),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	updateAppState(dockercontroller.Event{AppID: appId, Status: PAUSE})
+}
+
+func TestUpdateAppstateWithPauseEventOfSomeServices_ExpectStateNotUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithPausedObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithUpObj, nil),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	updateAppState(dockercontroller.Event{AppID: appId, Status: PAUSE})
+}
+
+func TestUpdateAppstateWithUnpauseEvent_ExpectUpdateAppStateToRunning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	pausedApp := make(map[string]interface{})
+	for key, value := range dbGetAppObj {
+		pausedApp[key] = value
+	}
+	pausedApp["state"] = PAUSED_STATE
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(pausedApp, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithUpObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithPausedObj, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(appId, RUNNING_STATE),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	updateAppState(dockercontroller.Event{AppID: appId, Status: UNPAUSE})
+}
+
+func TestUpdateAppstateWhenServiceScaledToZero_ExpectUpdateAppStateToPartiallyExited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return([]map[string]string{}, nil),
+		dockerExecutorMockObj.EXPECT().Ps(appId, "docker-compose.yml", gomock.Any()).Return(psWithUpObj, nil),
+		dbExecutorMockObj.EXPECT().UpdateAppState(appId, PARTIALLY_EXITED_STATE),
+	)
+
+	// pass mockObj to a real object.
+	dockerExecutor = dockerExecutorMockObj
+	dbExecutor = dbExecutorMockObj
+
+	updateAppState(dockercontroller.Event{AppID: appId, Status: DIE})
+}
+
+func TestServiceState(t *testing.T) {
+	tests := map[string]struct {
+		infos    []map[string]string
+		expected string
+	}{
+		"ScaledToZero_ExpectExited": {[]map[string]string{}, EXITED_STATE},
+		"AllExited_ExpectExited": {
+			append(psWithForcefullyExitedObj, psWithGracefullyExitedObj...), EXITED_STATE,
+		},
+		"OneOfReplicasUp_ExpectRunning": {
+			append(psWithForcefullyExitedObj, psWithUpObj...), RUNNING_STATE,
+		},
+		"PausedWithoutRunning_ExpectPaused": {
+			append(psWithPausedObj, psWithGracefullyExitedObj...), PAUSED_STATE,
+		},
+		"AllExitedWithZero_ExpectRunning": {
+			append(psWithGracefullyExitedObj, psWithGracefullyExitedObj...), RUNNING_STATE,
+		},
+		"ExitedWithNonZeroAfterPaused_ExpectPaused": {
+			append(psWithPausedObj, psWithForcefullyExitedObj...), PAUSED_STATE,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := ServiceState(test.infos)
+			if state != test.expected {
+				t.Errorf("Expected state : %s, actual state : %s", test.expected, state)
+			}
+		})
+	}
+}
+
+func TestAppState(t *testing.T) {
+	tests := map[string]struct {
+		serviceStates []string
+		expected      string
+	}{
+		"AllRunning_ExpectRunning":   {[]string{RUNNING_STATE, RUNNING_STATE}, RUNNING_STATE},
+		"SomePaused_ExpectRunning":   {[]string{RUNNING_STATE, PAUSED_STATE}, RUNNING_STATE},
+		"AllPaused_ExpectPaused":     {[]string{PAUSED_STATE, PAUSED_STATE}, PAUSED_STATE},
+		"SomeExited_ExpectPartially": {[]string{RUNNING_STATE, EXITED_STATE}, PARTIALLY_EXITED_STATE},
+		"AllExited_ExpectExited":     {[]string{EXITED_STATE, EXITED_STATE}, EXITED_STATE},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := AppState(test.serviceStates)
+			if state != test.expected {
+				t.Errorf("Expected state : %s, actual state : %s", test.expected, state)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ package mock_apps
 
 import (
 	dockercontroller "controller/dockercontroller"
+	apps "controller/monitoring/apps"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -104,3 +105,26 @@ func (m *MockCommand) GetEventChannel() chan dockercontroller.Event {
 func (mr *MockCommandMockRecorder) GetEventChannel() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventChannel", reflect.TypeOf((*MockCommand)(nil).GetEventChannel))
 }
+
+// SubscribeEvents mocks base method
+func (m *MockCommand) SubscribeEvents(filter apps.EventFilter) (int, chan dockercontroller.Event) {
+	ret := m.ctrl.Call(m, "SubscribeEvents", filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(chan dockercontroller.Event)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents
+func (mr *MockCommandMockRecorder) SubscribeEvents(filter interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockCommand)(nil).SubscribeEvents), filter)
+}
+
+// UnsubscribeEvents mocks base method
+func (m *MockCommand) UnsubscribeEvents(id int) {
+	m.ctrl.Call(m, "UnsubscribeEvents", id)
+}
+
+// UnsubscribeEvents indicates an expected call of UnsubscribeEvents
+func (mr *MockCommandMockRecorder) UnsubscribeEvents(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeEvents", reflect.TypeOf((*MockCommand)(nil).UnsubscribeEvents), id)
+}
@@ -0,0 +1,88 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"os"
+	"time"
+)
+
+const (
+	DOCKER_ROOT_DIR  = "DockerRootDir"
+	DEFAULT_DISK_DIR = "/"
+)
+
+var statPath = os.Stat
+
+// GetCapacity returns resources of the host which are available for new apps.
+// cpu is the number of idle cores, mem is the available memory in bytes and
+// disk is the free space in bytes of the filesystem docker stores images on.
+func (resExecutorImpl) GetCapacity() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	percents, sampled := latestCPUPercents()
+	if !sampled {
+		var err error
+		percents, err = readCPUPercent(time.Second, true)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return nil, errors.Unknown{Msg: "gopsutil cpu.Percent() error"}
+		}
+	}
+	idleCPUs := 0.0
+	for _, percent := range percents {
+		idleCPUs += (100.0 - percent) / 100.0
+	}
+
+	memory, err := readVirtualMemory()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "gopsutil mem.VirtualMemory() error"}
+	}
+
+	usage, err := readDiskUsage(GetDockerRootDir())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.Unknown{Msg: "gopsutil disk.Usage() error"}
+	}
+
+	capacity := make(map[string]interface{})
+	capacity[CPU] = idleCPUs
+	capacity[MEM] = memory.Available
+	capacity[DISK] = usage.Free
+	return capacity, nil
+}
+
+// GetDockerRootDir returns the directory docker stores images on,
+// or the root directory if docker engine doesn't tell it.
+func GetDockerRootDir() string {
+	info, err := dockerExecutor.Info()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return DEFAULT_DISK_DIR
+	}
+	if dir, ok := info[DOCKER_ROOT_DIR].(string); ok && len(dir) != 0 {
+		if _, err := statPath(dir); err == nil {
+			return dir
+		}
+	}
+	return DEFAULT_DISK_DIR
+}
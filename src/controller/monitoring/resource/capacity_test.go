@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGetCapacity_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	history = ringBuffer{}
+	readCPUPercent = func(time.Duration, bool) ([]float64, error) {
+		return []float64{25, 75}, nil
+	}
+	readVirtualMemory = func() (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{Total: 1024, Available: 512}, nil
+	}
+	diskPath := ""
+	readDiskUsage = func(path string) (*disk.UsageStat, error) {
+		diskPath = path
+		return &disk.UsageStat{Path: path, Total: 100, Free: 60}, nil
+	}
+	statPath = func(string) (os.FileInfo, error) {
+		return nil, nil
+	}
+	defer func() { statPath = os.Stat }()
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj.EXPECT().Info().Return(map[string]interface{}{DOCKER_ROOT_DIR: "/var/lib/docker"}, nil)
+	dockerExecutor = dockerExecutorMockObj
+
+	capacity, err := Executor.GetCapacity()
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+	if capacity[CPU] != 1.0 {
+		t.Errorf("Expected cpu : 1.0, Actual cpu : %v", capacity[CPU])
+	}
+	if capacity[MEM] != uint64(512) {
+		t.Errorf("Expected mem : 512, Actual mem : %v", capacity[MEM])
+	}
+	if capacity[DISK] != uint64(60) {
+		t.Errorf("Expected disk : 60, Actual disk : %v", capacity[DISK])
+	}
+	if diskPath != "/var/lib/docker" {
+		t.Errorf("Expected disk path : /var/lib/docker, Actual disk path : %s", diskPath)
+	}
+}
+
+func TestGetCapacityWhenDockerInfoFailed_ExpectRootDirUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setUpHostReaders()
+	history = ringBuffer{}
+	diskPath := ""
+	readDiskUsage = func(path string) (*disk.UsageStat, error) {
+		diskPath = path
+		return &disk.UsageStat{Path: path, Total: 100, Free: 60}, nil
+	}
+
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj.EXPECT().Info().Return(nil, errors.Unknown{})
+	dockerExecutor = dockerExecutorMockObj
+
+	_, err := Executor.GetCapacity()
+	if err != nil {
+		t.Errorf("Unexpected err : %s", err.Error())
+	}
+	if diskPath != DEFAULT_DISK_DIR {
+		t.Errorf("Expected disk path : %s, Actual disk path : %s", DEFAULT_DISK_DIR, diskPath)
+	}
+}
+
+func TestGetCapacityWhenReadMemoryFailed_ExpectReturnError(t *testing.T) {
+	setUpHostReaders()
+	history = ringBuffer{}
+	readVirtualMemory = func() (*mem.VirtualMemoryStat, error) {
+		return nil, errors.Unknown{}
+	}
+
+	_, err := Executor.GetCapacity()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "Unknown", err.Error())
+	case errors.Unknown:
+	}
+}
@@ -0,0 +1,289 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"bytes"
+	"commons/logger"
+	"controller/dockercontroller"
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	METRIC_PREFIX = "pharos_node_"
+	COUNTER       = "counter"
+	GAUGE         = "gauge"
+	ID            = "id"
+
+	LABEL_CPU       = "cpu"
+	LABEL_MODE      = "mode"
+	LABEL_PATH      = "path"
+	LABEL_INTERFACE = "interface"
+	LABEL_APP_ID    = "app_id"
+	LABEL_SERVICE   = "service"
+	LABEL_CONTAINER = "container"
+)
+
+// Functions reading host resources, replaced in tests.
+var readCPUTimes = cpu.Times
+var readCPUPercent = cpu.Percent
+var readVirtualMemory = mem.VirtualMemory
+var readPartitions = disk.Partitions
+var readDiskUsage = disk.Usage
+var readIOCounters = net.IOCounters
+
+type label struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	labels []label
+	value  float64
+}
+
+// metricFamily is a set of samples having the same name and type.
+// Names of counter families don't have _total suffix,
+// it is appended to the name of each sample.
+type metricFamily struct {
+	name    string
+	typ     string
+	help    string
+	samples []sample
+}
+
+func (family *metricFamily) add(value float64, labels ...label) {
+	family.samples = append(family.samples, sample{labels: labels, value: value})
+}
+
+// GetMetrics returns host and container resources in OpenMetrics text format.
+// Resources which can't be read are left out of the result.
+func (resExecutorImpl) GetMetrics() (string, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	families := make([]*metricFamily, 0)
+	families = append(families, collectCPUMetrics()...)
+	families = append(families, collectMemMetrics()...)
+	families = append(families, collectDiskMetrics()...)
+	families = append(families, collectNetworkMetrics()...)
+
+	containerFamilies, err := collectContainerMetrics()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return "", err
+	}
+	families = append(families, containerFamilies...)
+
+	return writeOpenMetrics(families), nil
+}
+
+func collectCPUMetrics() []*metricFamily {
+	seconds := &metricFamily{name: "cpu_seconds", typ: COUNTER, help: "Seconds the CPUs spent in each mode."}
+	usage := &metricFamily{name: "cpu_usage_percent", typ: GAUGE, help: "CPU usage since the last scrape in percent."}
+
+	times, err := readCPUTimes(true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil
+	}
+	for _, t := range times {
+		cpuLabel := label{LABEL_CPU, strings.TrimPrefix(t.CPU, "cpu")}
+		modes := []struct {
+			mode  string
+			value float64
+		}{
+			{"user", t.User}, {"system", t.System}, {"idle", t.Idle}, {"nice", t.Nice},
+			{"iowait", t.Iowait}, {"irq", t.Irq}, {"softirq", t.Softirq}, {"steal", t.Steal},
+		}
+		for _, m := range modes {
+			seconds.add(m.value, cpuLabel, label{LABEL_MODE, m.mode})
+		}
+	}
+
+	// Usage is computed against the previous call,
+	// so there is no usage on the first scrape.
+	percents, err := readCPUPercent(0, true)
+	if err == nil {
+		for i, percent := range percents {
+			usage.add(percent, label{LABEL_CPU, strconv.Itoa(i)})
+		}
+	}
+	return []*metricFamily{seconds, usage}
+}
+
+func collectMemMetrics() []*metricFamily {
+	memory, err := readVirtualMemory()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil
+	}
+
+	total := &metricFamily{name: "memory_total_bytes", typ: GAUGE, help: "Total memory in bytes."}
+	free := &metricFamily{name: "memory_free_bytes", typ: GAUGE, help: "Free memory in bytes."}
+	used := &metricFamily{name: "memory_used_bytes", typ: GAUGE, help: "Used memory in bytes."}
+	total.add(float64(memory.Total))
+	free.add(float64(memory.Free))
+	used.add(float64(memory.Used))
+	return []*metricFamily{total, free, used}
+}
+
+func collectDiskMetrics() []*metricFamily {
+	partitions, err := readPartitions(false)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil
+	}
+
+	total := &metricFamily{name: "disk_total_bytes", typ: GAUGE, help: "Total size of the filesystem in bytes."}
+	free := &metricFamily{name: "disk_free_bytes", typ: GAUGE, help: "Free space of the filesystem in bytes."}
+	used := &metricFamily{name: "disk_used_bytes", typ: GAUGE, help: "Used space of the filesystem in bytes."}
+	for _, partition := range partitions {
+		usage, err := readDiskUsage(partition.Mountpoint)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		pathLabel := label{LABEL_PATH, usage.Path}
+		total.add(float64(usage.Total), pathLabel)
+		free.add(float64(usage.Free), pathLabel)
+		used.add(float64(usage.Used), pathLabel)
+	}
+	return []*metricFamily{total, free, used}
+}
+
+func collectNetworkMetrics() []*metricFamily {
+	counters, err := readIOCounters(true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil
+	}
+
+	bytesRecv := &metricFamily{name: "network_receive_bytes", typ: COUNTER, help: "Bytes received by the interface."}
+	bytesSent := &metricFamily{name: "network_transmit_bytes", typ: COUNTER, help: "Bytes sent by the interface."}
+	packetsRecv := &metricFamily{name: "network_receive_packets", typ: COUNTER, help: "Packets received by the interface."}
+	packetsSent := &metricFamily{name: "network_transmit_packets", typ: COUNTER, help: "Packets sent by the interface."}
+	for _, counter := range counters {
+		interfaceLabel := label{LABEL_INTERFACE, counter.Name}
+		bytesRecv.add(float64(counter.BytesRecv), interfaceLabel)
+		bytesSent.add(float64(counter.BytesSent), interfaceLabel)
+		packetsRecv.add(float64(counter.PacketsRecv), interfaceLabel)
+		packetsSent.add(float64(counter.PacketsSent), interfaceLabel)
+	}
+	return []*metricFamily{bytesRecv, bytesSent, packetsRecv, packetsSent}
+}
+
+// collectContainerMetrics returns stats of containers of all apps.
+// an app whose stats can't be read is left out.
+func collectContainerMetrics() ([]*metricFamily, error) {
+	apps, err := dbExecutor.GetAppList()
+	if err != nil {
+		return nil, convertDBError(err, "")
+	}
+
+	families := []struct {
+		key    string
+		family *metricFamily
+	}{
+		{dockercontroller.CPU, &metricFamily{name: "container_cpu_usage_percent", typ: GAUGE, help: "CPU usage of the container in percent."}},
+		{dockercontroller.MEMUSAGE, &metricFamily{name: "container_memory_usage_bytes", typ: GAUGE, help: "Memory usage of the container in bytes."}},
+		{dockercontroller.MEMLIMIT, &metricFamily{name: "container_memory_limit_bytes", typ: GAUGE, help: "Memory limit of the container in bytes."}},
+		{dockercontroller.BLOCKINPUT, &metricFamily{name: "container_block_read_bytes", typ: COUNTER, help: "Bytes read from block devices by the container."}},
+		{dockercontroller.BLOCKOUTPUT, &metricFamily{name: "container_block_write_bytes", typ: COUNTER, help: "Bytes written to block devices by the container."}},
+		{dockercontroller.NETWORKINPUT, &metricFamily{name: "container_network_receive_bytes", typ: COUNTER, help: "Bytes received by the container."}},
+		{dockercontroller.NETWORKOUTPUT, &metricFamily{name: "container_network_transmit_bytes", typ: COUNTER, help: "Bytes sent by the container."}},
+		{dockercontroller.PIDS, &metricFamily{name: "container_pids", typ: GAUGE, help: "Number of processes in the container."}},
+	}
+
+	for _, app := range apps {
+		appId := app[ID].(string)
+		stats, err := getAppMetrics(appId)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+
+		for _, container := range stats {
+			labels := []label{
+				{LABEL_APP_ID, appId},
+				{LABEL_SERVICE, container[dockercontroller.SERVICE].(string)},
+				{LABEL_CONTAINER, container[dockercontroller.CNAME].(string)},
+			}
+			for _, f := range families {
+				f.family.add(container[f.key].(float64), labels...)
+			}
+		}
+	}
+
+	result := make([]*metricFamily, 0)
+	for _, f := range families {
+		result = append(result, f.family)
+	}
+	return result, nil
+}
+
+func getAppMetrics(appId string) ([]map[string]interface{}, error) {
+	composeFileMutex.Lock()
+	defer composeFileMutex.Unlock()
+
+	err := setYamlFile(appId)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(COMPOSE_FILE)
+
+	return dockerExecutor.GetAppMetrics(appId, COMPOSE_FILE)
+}
+
+// writeOpenMetrics encodes metric families in OpenMetrics text format.
+func writeOpenMetrics(families []*metricFamily) string {
+	var buf bytes.Buffer
+	for _, family := range families {
+		name := METRIC_PREFIX + family.name
+		buf.WriteString("# TYPE " + name + " " + family.typ + "\n")
+		buf.WriteString("# HELP " + name + " " + family.help + "\n")
+
+		sampleName := name
+		if family.typ == COUNTER {
+			sampleName += "_total"
+		}
+		for _, s := range family.samples {
+			buf.WriteString(sampleName)
+			if len(s.labels) != 0 {
+				pairs := make([]string, 0)
+				for _, l := range s.labels {
+					pairs = append(pairs, l.name+"=\""+escapeLabelValue(l.value)+"\"")
+				}
+				buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			buf.WriteString(" " + strconv.FormatFloat(s.value, 'f', -1, 64) + "\n")
+		}
+	}
+	buf.WriteString("# EOF\n")
+	return buf.String()
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
@@ -0,0 +1,182 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/net"
+	"strings"
+	"testing"
+	"time"
+)
+
+var containerMetrics = []map[string]interface{}{{
+	"cid":           testContainerId,
+	"cname":         testContainerName,
+	"service":       testService,
+	"cpu":           1.5,
+	"memusage":      2048.0,
+	"memlimit":      4096.0,
+	"blockinput":    10.0,
+	"blockoutput":   20.0,
+	"networkinput":  30.0,
+	"networkoutput": 40.0,
+	"pids":          3.0,
+}}
+
+func setUpHostReaders() {
+	readCPUTimes = func(bool) ([]cpu.TimesStat, error) {
+		return []cpu.TimesStat{{CPU: "cpu0", User: 1.5, Idle: 10}}, nil
+	}
+	readCPUPercent = func(time.Duration, bool) ([]float64, error) {
+		return []float64{12.5}, nil
+	}
+	readVirtualMemory = func() (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{Total: 1024, Free: 512, Used: 512}, nil
+	}
+	readPartitions = func(bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{{Mountpoint: "/"}}, nil
+	}
+	readDiskUsage = func(path string) (*disk.UsageStat, error) {
+		return &disk.UsageStat{Path: path, Total: 100, Free: 60, Used: 40}, nil
+	}
+	readIOCounters = func(bool) ([]net.IOCountersStat, error) {
+		return []net.IOCountersStat{{Name: "eth0", BytesRecv: 7, BytesSent: 8}}, nil
+	}
+}
+
+func TestGetMetrics_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setUpHostReaders()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return([]map[string]interface{}{dbGetAppObj}, nil),
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().GetAppMetrics(appId, COMPOSE_FILE).Return(containerMetrics, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	result, err := Executor.GetMetrics()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	containerLabels := "{app_id=\"" + appId + "\",service=\"" + testService + "\",container=\"" + testContainerName + "\"}"
+	expectedLines := []string{
+		"# TYPE pharos_node_cpu_seconds counter",
+		"pharos_node_cpu_seconds_total{cpu=\"0\",mode=\"user\"} 1.5",
+		"pharos_node_cpu_usage_percent{cpu=\"0\"} 12.5",
+		"pharos_node_memory_total_bytes 1024",
+		"pharos_node_disk_used_bytes{path=\"/\"} 40",
+		"pharos_node_network_receive_bytes_total{interface=\"eth0\"} 7",
+		"pharos_node_container_cpu_usage_percent" + containerLabels + " 1.5",
+		"pharos_node_container_memory_usage_bytes" + containerLabels + " 2048",
+		"pharos_node_container_network_transmit_bytes_total" + containerLabels + " 40",
+		"pharos_node_container_pids" + containerLabels + " 3",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(result, line+"\n") {
+			t.Errorf("Expected line : %s, actual result : %s", line, result)
+		}
+	}
+
+	if !strings.HasSuffix(result, "# EOF\n") {
+		t.Errorf("Unexpected result, no EOF marker : %s", result)
+	}
+}
+
+func TestGetMetricsWhenGetAppMetricsFailed_ExpectAppLeftOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setUpHostReaders()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return([]map[string]interface{}{dbGetAppObj}, nil),
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().GetAppMetrics(appId, COMPOSE_FILE).Return(nil, UnknownError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+
+	result, err := Executor.GetMetrics()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if strings.Contains(result, appId) {
+		t.Errorf("Unexpected result, failed app is included : %s", result)
+	}
+}
+
+func TestGetMetricsWhenGetAppListFailed_ExpectReturnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setUpHostReaders()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return(nil, UnknownError),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+
+	_, err := Executor.GetMetrics()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: UnknownError, actual err: %v", err)
+	case errors.Unknown:
+	}
+}
+
+func TestWriteOpenMetricsWithSpecialCharacters_ExpectEscapedLabel(t *testing.T) {
+	family := &metricFamily{name: "test", typ: GAUGE, help: "test"}
+	family.add(1, label{"name", "a\"b\\c\nd"})
+
+	result := writeOpenMetrics([]*metricFamily{family})
+
+	expected := "# TYPE pharos_node_test gauge\n# HELP pharos_node_test test\npharos_node_test{name=\"a\\\"b\\\\c\\nd\"} 1\n# EOF\n"
+	if result != expected {
+		t.Errorf("Expected result : %s, actual result : %s", expected, result)
+	}
+}
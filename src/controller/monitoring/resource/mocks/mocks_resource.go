@@ -73,3 +73,55 @@ func (m *MockCommand) GetAppResourceInfo(appId string) (map[string]interface{},
 func (mr *MockCommandMockRecorder) GetAppResourceInfo(appId interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppResourceInfo", reflect.TypeOf((*MockCommand)(nil).GetAppResourceInfo), appId)
 }
+
+// GetMetrics mocks base method
+func (m *MockCommand) GetMetrics() (string, error) {
+	ret := m.ctrl.Call(m, "GetMetrics")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetrics indicates an expected call of GetMetrics
+func (mr *MockCommandMockRecorder) GetMetrics() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockCommand)(nil).GetMetrics))
+}
+
+// GetResourceHistory mocks base method
+func (m *MockCommand) GetResourceHistory(from, to, step string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetResourceHistory", from, to, step)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceHistory indicates an expected call of GetResourceHistory
+func (mr *MockCommandMockRecorder) GetResourceHistory(from, to, step interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceHistory", reflect.TypeOf((*MockCommand)(nil).GetResourceHistory), from, to, step)
+}
+
+// GetLatestSample mocks base method
+func (m *MockCommand) GetLatestSample() (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetLatestSample")
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestSample indicates an expected call of GetLatestSample
+func (mr *MockCommandMockRecorder) GetLatestSample() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestSample", reflect.TypeOf((*MockCommand)(nil).GetLatestSample))
+}
+
+// GetCapacity mocks base method
+func (m *MockCommand) GetCapacity() (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetCapacity")
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapacity indicates an expected call of GetCapacity
+func (mr *MockCommandMockRecorder) GetCapacity() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockCommand)(nil).GetCapacity))
+}
@@ -22,6 +22,7 @@ import (
 	"commons/url"
 	"commons/util"
 	"controller/dockercontroller"
+	configDB "db/bolt/configuration"
 	"db/bolt/service"
 	"encoding/json"
 	"github.com/shirou/gopsutil/cpu"
@@ -33,6 +34,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -60,6 +62,10 @@ const (
 type Command interface {
 	GetHostResourceInfo() (map[string]interface{}, error)
 	GetAppResourceInfo(appId string) (map[string]interface{}, error)
+	GetMetrics() (string, error)
+	GetResourceHistory(from, to, step string) (map[string]interface{}, error)
+	GetLatestSample() (map[string]interface{}, error)
+	GetCapacity() (map[string]interface{}, error)
 }
 
 type networkTraffic struct {
@@ -90,19 +96,27 @@ type resExecutorImpl struct{}
 var dockerExecutor dockercontroller.Command
 var dbExecutor service.Command
 var httpExecutor messenger.Command
+var configDbExecutor configDB.Command
 var Executor resExecutorImpl
 var fileMode = os.FileMode(0755)
 
+// COMPOSE_FILE is shared by API requests and the sampler.
+var composeFileMutex sync.Mutex
+
 func init() {
 	dockerExecutor = dockercontroller.Executor
 	dbExecutor = service.Executor{}
 	httpExecutor = messenger.NewExecutor()
+	configDbExecutor = configDB.Executor{}
 }
 
 func (resExecutorImpl) GetAppResourceInfo(appId string) (map[string]interface{}, error) {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	composeFileMutex.Lock()
+	defer composeFileMutex.Unlock()
+
 	err := setYamlFile(appId)
 	if err != nil {
 		logger.Logging(logger.ERROR, err.Error())
@@ -159,10 +173,16 @@ func getCPUUsage() ([]string, error) {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	percent, err := cpu.Percent(time.Second, true)
-	if err != nil {
-		logger.Logging(logger.DEBUG, "gopsutil cpu.Percent() error")
-		return nil, errors.Unknown{"gopsutil cpu.Percent() error"}
+	// The latest sample of the sampler is used if exists,
+	// to avoid blocking for a second.
+	percent, sampled := latestCPUPercents()
+	if !sampled {
+		var err error
+		percent, err = cpu.Percent(time.Second, true)
+		if err != nil {
+			logger.Logging(logger.DEBUG, "gopsutil cpu.Percent() error")
+			return nil, errors.Unknown{Msg: "gopsutil cpu.Percent() error"}
+		}
 	}
 
 	result := make([]string, 0)
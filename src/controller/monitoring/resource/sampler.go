@@ -0,0 +1,449 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"controller/dockercontroller"
+	"github.com/shirou/gopsutil/cpu"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	SAMPLING_INTERVAL         = "resourcesamplinginterval"
+	HISTORY_SIZE              = "resourcehistorysize"
+	DEFAULT_SAMPLING_INTERVAL = 10
+	DEFAULT_HISTORY_SIZE      = 8640
+	DEFAULT_HISTORY_RANGE     = 3600
+	DEFAULT_HISTORY_STEP      = 60
+	MAX_HISTORY_POINTS        = 10000
+	VALUE                     = "value"
+	FROM                      = "from"
+	TO                        = "to"
+	STEP                      = "step"
+	HISTORY                   = "history"
+	TIMESTAMP                 = "timestamp"
+	SAMPLES                   = "samples"
+	APPS                      = "apps"
+	MIN                       = "min"
+	MAX                       = "max"
+	AVG                       = "avg"
+)
+
+// resourceSample is resource usage at a moment.
+// cpu is usage of each cpu and mem is used memory in percent,
+// apps has stats of services of each app.
+type resourceSample struct {
+	timestamp int64
+	cpu       []float64
+	mem       float64
+	apps      map[string]map[string]serviceSample
+}
+
+// serviceSample is cpu usage in percent and
+// memory usage in bytes of containers of a service.
+type serviceSample struct {
+	cpu float64
+	mem float64
+}
+
+// ringBuffer keeps the latest samples, the oldest one is overwritten when it is full.
+type ringBuffer struct {
+	sync.Mutex
+	samples []resourceSample
+	start   int
+	count   int
+}
+
+var history ringBuffer
+var samplerOnce sync.Once
+var lastCPUTimes []cpu.TimesStat
+var now = time.Now
+var sleep = time.Sleep
+
+// StartSampler starts collecting resource samples in background.
+// samples are collected every resourcesamplinginterval seconds and
+// the latest resourcehistorysize samples are kept in memory.
+func StartSampler() {
+	samplerOnce.Do(func() {
+		go runSampler()
+	})
+}
+
+func runSampler() {
+	for {
+		collectSample()
+		interval := getIntProperty(SAMPLING_INTERVAL, DEFAULT_SAMPLING_INTERVAL)
+		sleep(time.Duration(interval) * time.Second)
+	}
+}
+
+// collectSample reads host and app resources once and adds them to the history.
+func collectSample() {
+	sample := resourceSample{
+		timestamp: now().Unix(),
+		apps:      make(map[string]map[string]serviceSample),
+	}
+
+	times, err := readCPUTimes(true)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	} else {
+		sample.cpu = calcCPUPercents(lastCPUTimes, times)
+		lastCPUTimes = times
+	}
+
+	memory, err := readVirtualMemory()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	} else {
+		sample.mem = memory.UsedPercent
+	}
+
+	apps, err := dbExecutor.GetAppList()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+	for _, app := range apps {
+		appId := app[ID].(string)
+		stats, err := getAppMetrics(appId)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+
+		services := make(map[string]serviceSample)
+		for _, container := range stats {
+			// Containers of a scaled service are summed up.
+			name := container[dockercontroller.SERVICE].(string)
+			service := services[name]
+			service.cpu += container[dockercontroller.CPU].(float64)
+			service.mem += container[dockercontroller.MEMUSAGE].(float64)
+			services[name] = service
+		}
+		sample.apps[appId] = services
+	}
+
+	history.add(sample, getIntProperty(HISTORY_SIZE, DEFAULT_HISTORY_SIZE))
+}
+
+// calcCPUPercents returns busy time of each cpu in percent between two readings.
+// there is no result without the previous reading.
+func calcCPUPercents(prev, cur []cpu.TimesStat) []float64 {
+	if len(prev) != len(cur) {
+		return nil
+	}
+
+	percents := make([]float64, 0)
+	for i := range cur {
+		total := cur[i].Total() - prev[i].Total()
+		idle := (cur[i].Idle + cur[i].Iowait) - (prev[i].Idle + prev[i].Iowait)
+		percent := 0.0
+		if total > 0 {
+			percent = (total - idle) / total * 100
+		}
+		percents = append(percents, percent)
+	}
+	return percents
+}
+
+// latestCPUPercents returns cpu usage of the latest sample
+// if it is not older than twice of the sampling interval.
+func latestCPUPercents() ([]float64, bool) {
+	sample, ok := history.latest()
+	if !ok || len(sample.cpu) == 0 {
+		return nil, false
+	}
+
+	interval := int64(getIntProperty(SAMPLING_INTERVAL, DEFAULT_SAMPLING_INTERVAL))
+	if now().Unix()-sample.timestamp > 2*interval {
+		return nil, false
+	}
+	return sample.cpu, true
+}
+
+// GetResourceHistory returns min, max and average of samples for each step
+// between from and to, which are unix time in seconds.
+// to is now and from is an hour before to by default, step is 60 seconds by default.
+func (resExecutorImpl) GetResourceHistory(from, to, step string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	end, err := parseSeconds(TO, to, now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseSeconds(FROM, from, end-DEFAULT_HISTORY_RANGE)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := parseSeconds(STEP, step, DEFAULT_HISTORY_STEP)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case interval <= 0:
+		return nil, errors.InvalidParam{Msg: "step should be positive"}
+	case start > end:
+		return nil, errors.InvalidParam{Msg: "from should not be later than to"}
+	case (end-start)/interval >= MAX_HISTORY_POINTS:
+		return nil, errors.InvalidParam{Msg: "too many points, increase step"}
+	}
+
+	buckets := make(map[int64]*bucket)
+	for _, sample := range history.snapshot() {
+		if sample.timestamp < start || sample.timestamp > end {
+			continue
+		}
+		index := (sample.timestamp - start) / interval
+		if _, exists := buckets[index]; !exists {
+			buckets[index] = newBucket()
+		}
+		buckets[index].add(sample)
+	}
+
+	indexes := make([]int64, 0)
+	for index := range buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	points := make([]map[string]interface{}, 0)
+	for _, index := range indexes {
+		point := buckets[index].toMap()
+		point[TIMESTAMP] = start + index*interval
+		points = append(points, point)
+	}
+
+	res := make(map[string]interface{})
+	res[FROM] = start
+	res[TO] = end
+	res[STEP] = interval
+	res[HISTORY] = points
+	return res, nil
+}
+
+// GetLatestSample returns the latest sample collected by the sampler.
+// cpu is usage of each cpu and mem is used memory in percent,
+// cpu of a service is in percent and mem of a service is in bytes.
+func (resExecutorImpl) GetLatestSample() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	sample, ok := history.latest()
+	if !ok {
+		return nil, errors.NotFound{Msg: "no resource sample is collected yet"}
+	}
+
+	apps := make(map[string]interface{})
+	for appId, services := range sample.apps {
+		result := make(map[string]interface{})
+		for name, stats := range services {
+			result[name] = map[string]interface{}{
+				CPU: stats.cpu,
+				MEM: stats.mem,
+			}
+		}
+		apps[appId] = result
+	}
+
+	return map[string]interface{}{
+		TIMESTAMP: sample.timestamp,
+		CPU:       sample.cpu,
+		MEM:       sample.mem,
+		APPS:      apps,
+	}, nil
+}
+
+func parseSeconds(name, value string, defaultValue int64) (int64, error) {
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.InvalidParam{Msg: name + " should be unix time in seconds"}
+	}
+	return seconds, nil
+}
+
+func getIntProperty(name string, defaultValue int) int {
+	prop, err := configDbExecutor.GetProperty(name)
+	if err != nil {
+		return defaultValue
+	}
+
+	value, ok := prop[VALUE].(string)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil || result <= 0 {
+		return defaultValue
+	}
+	return result
+}
+
+func (r *ringBuffer) add(sample resourceSample, size int) {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.samples) != size {
+		r.resize(size)
+	}
+	r.samples[(r.start+r.count)%size] = sample
+	if r.count < size {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % size
+	}
+}
+
+// resize changes capacity of the buffer, keeping the latest samples.
+func (r *ringBuffer) resize(size int) {
+	ordered := r.ordered()
+	if len(ordered) > size {
+		ordered = ordered[len(ordered)-size:]
+	}
+	r.samples = make([]resourceSample, size)
+	copy(r.samples, ordered)
+	r.start, r.count = 0, len(ordered)
+}
+
+// ordered returns samples from the oldest to the latest.
+func (r *ringBuffer) ordered() []resourceSample {
+	result := make([]resourceSample, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		result = append(result, r.samples[(r.start+i)%len(r.samples)])
+	}
+	return result
+}
+
+func (r *ringBuffer) snapshot() []resourceSample {
+	r.Lock()
+	defer r.Unlock()
+	return r.ordered()
+}
+
+func (r *ringBuffer) latest() (resourceSample, bool) {
+	r.Lock()
+	defer r.Unlock()
+	if r.count == 0 {
+		return resourceSample{}, false
+	}
+	return r.samples[(r.start+r.count-1)%len(r.samples)], true
+}
+
+type aggregate struct {
+	min   float64
+	max   float64
+	sum   float64
+	count int
+}
+
+func (a *aggregate) add(value float64) {
+	if a.count == 0 || value < a.min {
+		a.min = value
+	}
+	if a.count == 0 || value > a.max {
+		a.max = value
+	}
+	a.sum += value
+	a.count++
+}
+
+func (a *aggregate) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		MIN: a.min,
+		MAX: a.max,
+		AVG: a.sum / float64(a.count),
+	}
+}
+
+// bucket aggregates samples in a step.
+type bucket struct {
+	samples int
+	cpu     []*aggregate
+	mem     *aggregate
+	apps    map[string]map[string][2]*aggregate
+}
+
+func newBucket() *bucket {
+	return &bucket{
+		mem:  &aggregate{},
+		apps: make(map[string]map[string][2]*aggregate),
+	}
+}
+
+func (b *bucket) add(sample resourceSample) {
+	b.samples++
+	for i, percent := range sample.cpu {
+		if i >= len(b.cpu) {
+			b.cpu = append(b.cpu, &aggregate{})
+		}
+		b.cpu[i].add(percent)
+	}
+	b.mem.add(sample.mem)
+
+	for appId, services := range sample.apps {
+		if _, exists := b.apps[appId]; !exists {
+			b.apps[appId] = make(map[string][2]*aggregate)
+		}
+		for name, service := range services {
+			aggregates, exists := b.apps[appId][name]
+			if !exists {
+				aggregates = [2]*aggregate{{}, {}}
+				b.apps[appId][name] = aggregates
+			}
+			aggregates[0].add(service.cpu)
+			aggregates[1].add(service.mem)
+		}
+	}
+}
+
+func (b *bucket) toMap() map[string]interface{} {
+	cpus := make([]map[string]interface{}, 0)
+	for _, a := range b.cpu {
+		cpus = append(cpus, a.toMap())
+	}
+
+	apps := make(map[string]interface{})
+	for appId, services := range b.apps {
+		result := make(map[string]interface{})
+		for name, aggregates := range services {
+			result[name] = map[string]interface{}{
+				CPU: aggregates[0].toMap(),
+				MEM: aggregates[1].toMap(),
+			}
+		}
+		apps[appId] = result
+	}
+
+	return map[string]interface{}{
+		SAMPLES: b.samples,
+		CPU:     cpus,
+		MEM:     b.mem.toMap(),
+		APPS:    apps,
+	}
+}
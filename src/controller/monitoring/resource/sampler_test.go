@@ -0,0 +1,239 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package resource
+
+import (
+	"commons/errors"
+	dockermocks "controller/dockercontroller/mocks"
+	configmocks "db/bolt/configuration/mocks"
+	dbmocks "db/bolt/service/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shirou/gopsutil/cpu"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	sampleTime = int64(1500000000)
+)
+
+func makeSample(timestamp int64, cpu float64, mem float64) resourceSample {
+	return resourceSample{
+		timestamp: timestamp,
+		cpu:       []float64{cpu},
+		mem:       mem,
+		apps: map[string]map[string]serviceSample{
+			appId: {testService: {cpu: cpu, mem: mem}},
+		},
+	}
+}
+
+func getTimestamps(samples []resourceSample) []int64 {
+	timestamps := make([]int64, 0)
+	for _, sample := range samples {
+		timestamps = append(timestamps, sample.timestamp)
+	}
+	return timestamps
+}
+
+func TestRingBufferAddWhenFull_ExpectOldestOverwritten(t *testing.T) {
+	buffer := ringBuffer{}
+	for i := int64(1); i <= 5; i++ {
+		buffer.add(resourceSample{timestamp: i}, 3)
+	}
+
+	expected := []int64{3, 4, 5}
+	if result := getTimestamps(buffer.snapshot()); !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected result : %v, actual result : %v", expected, result)
+	}
+
+	latest, ok := buffer.latest()
+	if !ok || latest.timestamp != 5 {
+		t.Errorf("Expected latest : %d, actual latest : %v", 5, latest)
+	}
+
+	buffer.add(resourceSample{timestamp: 6}, 2)
+
+	expected = []int64{5, 6}
+	if result := getTimestamps(buffer.snapshot()); !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected result : %v, actual result : %v", expected, result)
+	}
+}
+
+func TestCalcCPUPercents_ExpectBusyPercent(t *testing.T) {
+	prev := []cpu.TimesStat{{CPU: "cpu0", User: 10, Idle: 10}}
+	cur := []cpu.TimesStat{{CPU: "cpu0", User: 13, Idle: 11}}
+
+	expected := []float64{75}
+	if result := calcCPUPercents(prev, cur); !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected result : %v, actual result : %v", expected, result)
+	}
+
+	if result := calcCPUPercents(nil, cur); result != nil {
+		t.Errorf("Expected result : nil, actual result : %v", result)
+	}
+}
+
+func TestCollectSample_ExpectSampleAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setUpHostReaders()
+	history = ringBuffer{}
+	defer func() { history = ringBuffer{} }()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dockerExecutorMockObj := dockermocks.NewMockCommand(ctrl)
+	configDbExecutorMockObj := configmocks.NewMockCommand(ctrl)
+
+	scaledMetrics := []map[string]interface{}{containerMetrics[0], containerMetrics[0]}
+	gomock.InOrder(
+		dbExecutorMockObj.EXPECT().GetAppList().Return([]map[string]interface{}{dbGetAppObj}, nil),
+		dbExecutorMockObj.EXPECT().GetApp(appId).Return(dbGetAppObj, nil),
+		dockerExecutorMockObj.EXPECT().GetAppMetrics(appId, COMPOSE_FILE).Return(scaledMetrics, nil),
+		configDbExecutorMockObj.EXPECT().GetProperty(HISTORY_SIZE).Return(map[string]interface{}{VALUE: "10"}, nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	dockerExecutor = dockerExecutorMockObj
+	configDbExecutor = configDbExecutorMockObj
+
+	collectSample()
+
+	sample, ok := history.latest()
+	if !ok {
+		t.Fatalf("Expected a sample, actual nothing")
+	}
+
+	expected := serviceSample{cpu: 3.0, mem: 4096.0}
+	if result := sample.apps[appId][testService]; result != expected {
+		t.Errorf("Expected result : %v, actual result : %v", expected, result)
+	}
+}
+
+func TestGetResourceHistory_ExpectAggregatedByStep(t *testing.T) {
+	history = ringBuffer{}
+	defer func() { history = ringBuffer{} }()
+
+	history.add(makeSample(sampleTime, 10, 20), 10)
+	history.add(makeSample(sampleTime+10, 30, 40), 10)
+	history.add(makeSample(sampleTime+70, 50, 60), 10)
+	history.add(makeSample(sampleTime+200, 70, 80), 10)
+
+	result, err := Executor.GetResourceHistory("1500000000", "1500000120", "60")
+
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	points := result[HISTORY].([]map[string]interface{})
+	if len(points) != 2 {
+		t.Fatalf("Expected points : %d, actual points : %d", 2, len(points))
+	}
+
+	if points[0][TIMESTAMP] != sampleTime || points[1][TIMESTAMP] != sampleTime+60 {
+		t.Errorf("Unexpected timestamps : %v, %v", points[0][TIMESTAMP], points[1][TIMESTAMP])
+	}
+
+	expectedCPU := []map[string]interface{}{{MIN: 10.0, MAX: 30.0, AVG: 20.0}}
+	if !reflect.DeepEqual(expectedCPU, points[0][CPU]) {
+		t.Errorf("Expected result : %v, actual result : %v", expectedCPU, points[0][CPU])
+	}
+
+	expectedMem := map[string]interface{}{MIN: 20.0, MAX: 40.0, AVG: 30.0}
+	service := points[0][APPS].(map[string]interface{})[appId].(map[string]interface{})[testService]
+	if !reflect.DeepEqual(expectedMem, service.(map[string]interface{})[MEM]) {
+		t.Errorf("Expected result : %v, actual result : %v", expectedMem, service)
+	}
+}
+
+func TestGetResourceHistoryWithDefaultRange_ExpectLastHour(t *testing.T) {
+	now = func() time.Time { return time.Unix(sampleTime, 0) }
+	defer func() { now = time.Now }()
+
+	result, err := Executor.GetResourceHistory("", "", "")
+
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	if result[FROM] != sampleTime-DEFAULT_HISTORY_RANGE || result[TO] != sampleTime || result[STEP] != int64(DEFAULT_HISTORY_STEP) {
+		t.Errorf("Unexpected result : %v", result)
+	}
+}
+
+func TestGetResourceHistoryWithInvalidParams_ExpectReturnError(t *testing.T) {
+	testList := [][]string{
+		{"from", "", ""},
+		{"", "", "0"},
+		{"1500000100", "1500000000", ""},
+		{"0", "1500000000", "1"},
+	}
+
+	for _, params := range testList {
+		_, err := Executor.GetResourceHistory(params[0], params[1], params[2])
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: InvalidParam, actual err: %v, params : %v", err, params)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestGetLatestSample_ExpectLatestReturned(t *testing.T) {
+	history = ringBuffer{}
+	defer func() { history = ringBuffer{} }()
+
+	history.add(makeSample(sampleTime, 10, 20), 10)
+	history.add(makeSample(sampleTime+10, 30, 40), 10)
+
+	res, err := Executor.GetLatestSample()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{
+		TIMESTAMP: sampleTime + 10,
+		CPU:       []float64{30},
+		MEM:       40.0,
+		APPS: map[string]interface{}{
+			appId: map[string]interface{}{
+				testService: map[string]interface{}{CPU: 30.0, MEM: 40.0},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestGetLatestSampleWhenNoSample_ExpectNotFound(t *testing.T) {
+	history = ringBuffer{}
+
+	_, err := Executor.GetLatestSample()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: NotFound, actual err: %v", err)
+	case errors.NotFound:
+	}
+}
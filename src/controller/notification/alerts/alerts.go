@@ -0,0 +1,86 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alerts
+
+import (
+	"commons/logger"
+	"commons/url"
+	"commons/util"
+	"controller/configuration"
+	"controller/outbox"
+	"encoding/json"
+)
+
+const (
+	ALERT_KEY = "alert"
+)
+
+type Command interface {
+	// SendAlert notifies a state transition of an alert rule to pharos-anchor.
+	SendAlert(alert map[string]interface{})
+}
+
+type Executor struct{}
+
+var outboxExecutor outbox.Command
+var configurator configuration.Command
+
+func init() {
+	outboxExecutor = outbox.Executor{}
+	configurator = configuration.Executor{}
+}
+
+func (Executor) SendAlert(alert map[string]interface{}) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	nodeId := ""
+	config, err := configurator.GetConfiguration()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	for _, prop := range config["properties"].([]map[string]interface{}) {
+		if value, exists := prop["deviceid"]; exists {
+			nodeId = value.(string)
+		}
+	}
+
+	notiInfo := make(map[string]interface{})
+	notiInfo["nodeid"] = nodeId
+	notiInfo["alert"] = alert
+
+	// Notify alert to pharos-anchor.
+	// alerts are queued in a single key so that firing and resolved
+	// transitions are delivered in order.
+	url, err := util.MakeAnchorRequestUrl(url.Notification(), url.Alerts())
+	if err != nil {
+		logger.Logging(logger.ERROR, "failed to make anchor request url")
+		return
+	}
+	jsonData, err := json.Marshal(notiInfo)
+	if err != nil {
+		logger.Logging(logger.ERROR, "json marshalling failed")
+		return
+	}
+	err = outboxExecutor.Enqueue(ALERT_KEY, "POST", url, jsonData)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
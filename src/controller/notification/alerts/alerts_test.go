@@ -0,0 +1,93 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alerts
+
+import (
+	"commons/errors"
+	configmocks "controller/configuration/mocks"
+	outboxmocks "controller/outbox/mocks"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var (
+	testAlert = map[string]interface{}{
+		"id":    "test_alert_id",
+		"state": "firing",
+	}
+	config = map[string]interface{}{
+		"properties": []map[string]interface{}{
+			{"deviceid": "test_device_id"},
+		},
+	}
+)
+
+func TestSendAlert_ExpectAlertEnqueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	var body []byte
+	gomock.InOrder(
+		configMockObj.EXPECT().GetConfiguration().Return(config, nil),
+		outboxMockObj.EXPECT().Enqueue(ALERT_KEY, "POST", "http://127.0.0.1:48099/api/v1/notification/alerts", gomock.Any()).
+			Do(func(key string, method string, url string, data []byte) { body = data }).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	configurator = configMockObj
+	outboxExecutor = outboxMockObj
+
+	os.Setenv("ANCHOR_ADDRESS", "127.0.0.1")
+	Executor{}.SendAlert(testAlert)
+	os.Unsetenv("ANCHOR_ADDRESS")
+
+	result := make(map[string]interface{})
+	json.Unmarshal(body, &result)
+
+	expected := map[string]interface{}{
+		"nodeid": "test_device_id",
+		"alert":  testAlert,
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected body: %v, actual body: %v", expected, result)
+	}
+}
+
+func TestSendAlertWhenGetConfigurationFailed_ExpectNotEnqueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		configMockObj.EXPECT().GetConfiguration().Return(nil, errors.Unknown{}),
+	)
+
+	// pass mockObj to a real object.
+	configurator = configMockObj
+	outboxExecutor = outboxMockObj
+
+	Executor{}.SendAlert(testAlert)
+}
@@ -23,10 +23,10 @@ import (
 	"commons/util"
 	"controller/configuration"
 	"controller/dockercontroller"
+	"controller/outbox"
 	"db/bolt/event"
 	"db/bolt/service"
 	"encoding/json"
-	"messenger"
 	"strings"
 )
 
@@ -47,7 +47,7 @@ type Command interface {
 
 type Executor struct{}
 
-var httpExecutor messenger.Command
+var outboxExecutor outbox.Command
 var dockerExecutor dockercontroller.Command
 var dbExecutor event.Command
 var serviceExecutor service.Command
@@ -56,7 +56,7 @@ var configurator configuration.Command
 var Events chan dockercontroller.Event
 
 func init() {
-	httpExecutor = messenger.NewExecutor()
+	outboxExecutor = outbox.Executor{}
 	dockerExecutor = dockercontroller.Executor
 	dbExecutor = event.Executor{}
 	serviceExecutor = service.Executor{}
@@ -155,13 +155,18 @@ func (Executor) SendNotification(e dockercontroller.Event) {
 	notiInfo["event"] = eventInfo
 
 	// Notify container event to pharos-anchor.
+	// events are queued per app so that they are delivered in order
+	// even if pharos-anchor is unreachable for a while.
 	url, err := util.MakeAnchorRequestUrl(url.Notification(), url.Events())
 	if err != nil {
 		logger.Logging(logger.ERROR, "failed to make anchor request url")
 		return
 	}
 	jsonData, _ := convertMapToJson(notiInfo)
-	httpExecutor.SendHttpRequest("POST", url, []byte(jsonData))
+	err = outboxExecutor.Enqueue(e.AppID, "POST", url, []byte(jsonData))
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
 }
 
 func (Executor) UnsubscribeEvent(body string) error {
@@ -18,10 +18,13 @@ package apps
 
 import (
 	"commons/errors"
+	configmocks "controller/configuration/mocks"
 	"controller/dockercontroller"
+	outboxmocks "controller/outbox/mocks"
 	dbmocks "db/bolt/event/mocks"
 	servicedbmocks "db/bolt/service/mocks"
 	"github.com/golang/mock/gomock"
+	"os"
 	"testing"
 )
 
@@ -124,17 +127,63 @@ func TestSendNotification_ExpectSuccess(t *testing.T) {
 
 	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
 	serviceDbExecutor := servicedbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
 
 	gomock.InOrder(
 		serviceDbExecutor.EXPECT().GetApp(appId).Return(app, nil),
 		dbExecutorMockObj.EXPECT().GetEvents(appId, imageName).Return([]map[string]interface{}{evt}, nil),
+		configMockObj.EXPECT().GetConfiguration().Return(map[string]interface{}{"properties": []map[string]interface{}{}}, nil),
+		outboxMockObj.EXPECT().Enqueue(appId, "POST", gomock.Any(), gomock.Any()).Return(nil),
 	)
 
 	// pass mockObj to a real object.
 	dbExecutor = dbExecutorMockObj
 	serviceExecutor = serviceDbExecutor
+	configurator = configMockObj
+	outboxExecutor = outboxMockObj
 
+	os.Setenv("ANCHOR_ADDRESS", "127.0.0.1")
 	Executor{}.SendNotification(testEvent)
+	os.Unsetenv("ANCHOR_ADDRESS")
+}
+
+func TestSendNotificationWithSubscribers_ExpectNotificationEnqueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	evt := map[string]interface{}{
+		"id":        "test_event_id",
+		"appid":     "test_app_id",
+		"imagename": "test_image_name",
+	}
+	config := map[string]interface{}{
+		"properties": []map[string]interface{}{
+			{"deviceid": "test_device_id"},
+		},
+	}
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	serviceDbExecutor := servicedbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	outboxMockObj := outboxmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		serviceDbExecutor.EXPECT().GetApp(appId).Return(app, nil),
+		dbExecutorMockObj.EXPECT().GetEvents(appId, imageName).Return([]map[string]interface{}{evt}, nil),
+		configMockObj.EXPECT().GetConfiguration().Return(config, nil),
+		outboxMockObj.EXPECT().Enqueue(appId, "POST", "http://127.0.0.1:48099/api/v1/notification/events", gomock.Any()).Return(nil),
+	)
+
+	// pass mockObj to a real object.
+	dbExecutor = dbExecutorMockObj
+	serviceExecutor = serviceDbExecutor
+	configurator = configMockObj
+	outboxExecutor = outboxMockObj
+
+	os.Setenv("ANCHOR_ADDRESS", "127.0.0.1")
+	Executor{}.SendNotification(testEvent)
+	os.Unsetenv("ANCHOR_ADDRESS")
 }
 
 func TestUnsubscribeEvent_ExpectSuccess(t *testing.T) {
@@ -0,0 +1,337 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+// Package outbox provides a durable queue of outbound requests to pharos-anchor.
+// Queued requests are delivered in order per key and retried with
+// exponential backoff until they are delivered or expired.
+package outbox
+
+import (
+	"commons/errors"
+	"commons/logger"
+	configDB "db/bolt/configuration"
+	"db/bolt/outbox"
+	"messenger"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	ID                = "id"
+	KEY               = "key"
+	METHOD            = "method"
+	URL               = "url"
+	BODY              = "body"
+	ATTEMPTS          = "attempts"
+	NEXT_ATTEMPT      = "nextattempt"
+	LAST_ERROR        = "lasterror"
+	TIMESTAMP         = "timestamp"
+	VALUE             = "value"
+	PENDING           = "pending"
+	DELIVERED         = "delivered"
+	DROPPED           = "dropped"
+	MESSAGES          = "messages"
+	MAX_AGE           = "outboxmaxage"
+	MAX_SIZE          = "outboxmaxsize"
+	DEFAULT_MAX_AGE   = 86400
+	DEFAULT_MAX_SIZE  = 1000
+	INITIAL_BACKOFF   = 1
+	MAX_BACKOFF       = 300
+	DELIVERY_INTERVAL = time.Second
+)
+
+// Interface of outbox operations.
+type Command interface {
+	// Enqueue stores a request to pharos-anchor and delivers it in background.
+	// requests having the same key are delivered in the order they were enqueued.
+	Enqueue(key string, method string, url string, body []byte) error
+
+	// EnqueueLatest stores a request like Enqueue, but pending requests having
+	// the same key are replaced so that only the latest one is delivered.
+	EnqueueLatest(key string, method string, url string, body []byte) error
+
+	// GetStatus returns the state of the queue.
+	GetStatus() (map[string]interface{}, error)
+}
+
+type Executor struct{}
+
+var dbExecutor outbox.Command
+var configDbExecutor configDB.Command
+var httpExecutor messenger.Command
+
+var now = time.Now
+var wakeUp chan bool
+var deliveryOnce sync.Once
+
+// Held while the queue is read, trimmed and inserted into,
+// not to exceed outboxmaxsize or lose replaced messages by concurrent requests.
+var enqueueMutex sync.Mutex
+
+var counters = struct {
+	sync.Mutex
+	delivered int
+	dropped   int
+}{}
+
+func init() {
+	dbExecutor = outbox.Executor{}
+	configDbExecutor = configDB.Executor{}
+	httpExecutor = messenger.NewExecutor()
+
+	wakeUp = make(chan bool, 1)
+}
+
+// StartDelivery starts to deliver queued messages in background,
+// which resumes delivery of messages queued before restarting.
+// it is safe to call more than once.
+func StartDelivery() {
+	deliveryOnce.Do(func() {
+		go runDelivery()
+	})
+}
+
+// Enqueue a request to pharos-anchor.
+// if the queue is full, the oldest message is dropped.
+// if succeed to enqueue, return error as nil.
+// otherwise, return error.
+func (Executor) Enqueue(key string, method string, url string, body []byte) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return enqueue(key, method, url, body, false)
+}
+
+// Enqueue a request to pharos-anchor replacing pending requests of the key.
+// it is used for requests whose latest one is only meaningful such as ping.
+// if succeed to enqueue, return error as nil.
+// otherwise, return error.
+func (Executor) EnqueueLatest(key string, method string, url string, body []byte) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return enqueue(key, method, url, body, true)
+}
+
+func enqueue(key string, method string, url string, body []byte, replace bool) error {
+	enqueueMutex.Lock()
+	defer enqueueMutex.Unlock()
+
+	messages, err := dbExecutor.GetMessages()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	if replace {
+		remains := make([]map[string]interface{}, 0, len(messages))
+		for _, message := range messages {
+			if message[KEY] != key {
+				remains = append(remains, message)
+				continue
+			}
+			err = dbExecutor.DeleteMessage(message[ID].(string))
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+				return err
+			}
+		}
+		messages = remains
+	}
+
+	maxSize := getLimit(MAX_SIZE, DEFAULT_MAX_SIZE)
+	for len(messages) != 0 && len(messages) >= maxSize {
+		logger.Logging(logger.ERROR, "outbox is full, drop message : "+messages[0][ID].(string))
+		err = dbExecutor.DeleteMessage(messages[0][ID].(string))
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return err
+		}
+		increaseDropped()
+		messages = messages[1:]
+	}
+
+	_, err = dbExecutor.InsertMessage(key, method, url, body)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return err
+	}
+
+	select {
+	case wakeUp <- true:
+	default:
+	}
+	return nil
+}
+
+// Getting state of the queue.
+// if succeed to get, return number of pending, delivered and dropped messages
+// and list of pending messages without body.
+// otherwise, return error.
+func (Executor) GetStatus() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	messages, err := dbExecutor.GetMessages()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	pending := make([]map[string]interface{}, 0)
+	for _, msg := range messages {
+		info := make(map[string]interface{})
+		for k, v := range msg {
+			if k != BODY {
+				info[k] = v
+			}
+		}
+		pending = append(pending, info)
+	}
+
+	counters.Lock()
+	defer counters.Unlock()
+
+	res := make(map[string]interface{})
+	res[PENDING] = len(messages)
+	res[DELIVERED] = counters.delivered
+	res[DROPPED] = counters.dropped
+	res[MAX_AGE] = getLimit(MAX_AGE, DEFAULT_MAX_AGE)
+	res[MAX_SIZE] = getLimit(MAX_SIZE, DEFAULT_MAX_SIZE)
+	res[MESSAGES] = pending
+	return res, nil
+}
+
+func runDelivery() {
+	ticker := time.NewTicker(DELIVERY_INTERVAL)
+	for {
+		select {
+		case <-ticker.C:
+		case <-wakeUp:
+		}
+		deliver()
+	}
+}
+
+// deliver sends due messages once.
+// while the first message of a key is waiting for retry,
+// later messages of the key are held back to keep their order.
+func deliver() {
+	messages, err := dbExecutor.GetMessages()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	current := now().Unix()
+	maxAge := int64(getLimit(MAX_AGE, DEFAULT_MAX_AGE))
+	blocked := make(map[string]bool)
+
+	for _, msg := range messages {
+		id := msg[ID].(string)
+		key := msg[KEY].(string)
+		if blocked[key] {
+			continue
+		}
+
+		if current-msg[TIMESTAMP].(int64) > maxAge {
+			logger.Logging(logger.ERROR, "message expired, drop message : "+id)
+			dropMessage(id)
+			continue
+		}
+
+		if msg[NEXT_ATTEMPT].(int64) > current {
+			blocked[key] = true
+			continue
+		}
+
+		code, _, err := httpExecutor.SendHttpRequest(msg[METHOD].(string), msg[URL].(string), []byte(msg[BODY].(string)))
+		switch {
+		case err != nil || code >= 500:
+			if err == nil {
+				err = errors.Unknown{Msg: "received error code : " + strconv.Itoa(code)}
+			}
+			logger.Logging(logger.ERROR, "failed to deliver message : "+id+", "+err.Error())
+
+			attempts := msg[ATTEMPTS].(int) + 1
+			err = dbExecutor.UpdateMessage(id, attempts, current+backoff(attempts), err.Error())
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+			}
+			blocked[key] = true
+		case code >= 400:
+			logger.Logging(logger.ERROR, "message rejected, drop message : "+id+", code : "+strconv.Itoa(code))
+			dropMessage(id)
+		default:
+			err = dbExecutor.DeleteMessage(id)
+			if err != nil {
+				logger.Logging(logger.ERROR, err.Error())
+				blocked[key] = true
+				continue
+			}
+			counters.Lock()
+			counters.delivered++
+			counters.Unlock()
+		}
+	}
+}
+
+func dropMessage(id string) {
+	err := dbExecutor.DeleteMessage(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	increaseDropped()
+}
+
+func increaseDropped() {
+	counters.Lock()
+	counters.dropped++
+	counters.Unlock()
+}
+
+// backoff returns seconds to wait before the next attempt.
+// it doubles on each attempt up to MAX_BACKOFF.
+func backoff(attempts int) int64 {
+	delay := int64(INITIAL_BACKOFF)
+	for i := 1; i < attempts && delay < MAX_BACKOFF; i++ {
+		delay *= 2
+	}
+	if delay > MAX_BACKOFF {
+		delay = MAX_BACKOFF
+	}
+	return delay
+}
+
+func getLimit(name string, defaultValue int) int {
+	prop, err := configDbExecutor.GetProperty(name)
+	if err != nil {
+		return defaultValue
+	}
+
+	value, ok := prop[VALUE].(string)
+	if !ok {
+		return defaultValue
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return defaultValue
+	}
+	return limit
+}
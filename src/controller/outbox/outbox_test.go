@@ -0,0 +1,332 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package outbox
+
+import (
+	"commons/errors"
+	configmocks "db/bolt/configuration/mocks"
+	dbmocks "db/bolt/outbox/mocks"
+	gomock "github.com/golang/mock/gomock"
+	msgmocks "messenger/mocks"
+	"testing"
+	"time"
+)
+
+const (
+	APP_ID    = "test_app_id"
+	OTHER_KEY = "ping"
+	POST      = "POST"
+	TEST_URL  = "http://127.0.0.1:48099/api/v1/notification/events"
+	BODY_STR  = "{\"event\":\"test_event\"}"
+	ID_1      = "00000000000000000001"
+	ID_2      = "00000000000000000002"
+	ID_3      = "00000000000000000003"
+	NOW       = 1500000000
+)
+
+var (
+	maxSizeProp = map[string]interface{}{
+		"name":     MAX_SIZE,
+		"value":    "2",
+		"readOnly": false,
+	}
+	notFoundError = errors.NotFound{}
+	unknownError  = errors.Unknown{}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(NOW, 0) }
+}
+
+func makeMessage(id string, key string, attempts int, nextAttempt int64, timestamp int64) map[string]interface{} {
+	return map[string]interface{}{
+		ID:           id,
+		KEY:          key,
+		METHOD:       POST,
+		URL:          TEST_URL,
+		BODY:         BODY_STR,
+		ATTEMPTS:     attempts,
+		NEXT_ATTEMPT: nextAttempt,
+		LAST_ERROR:   "",
+		TIMESTAMP:    timestamp,
+	}
+}
+
+func TestCalledEnqueue_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(nil, nil),
+		configMockObj.EXPECT().GetProperty(MAX_SIZE).Return(nil, notFoundError),
+		dbMockObj.EXPECT().InsertMessage(APP_ID, POST, TEST_URL, []byte(BODY_STR)).Return(nil, nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+
+	err := Executor{}.Enqueue(APP_ID, POST, TEST_URL, []byte(BODY_STR))
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledEnqueueWhenQueueIsFull_ExpectOldestMessageDropped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 0, NOW, NOW),
+		makeMessage(ID_2, APP_ID, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_SIZE).Return(maxSizeProp, nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_1).Return(nil),
+		dbMockObj.EXPECT().InsertMessage(APP_ID, POST, TEST_URL, []byte(BODY_STR)).Return(nil, nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+
+	err := Executor{}.Enqueue(APP_ID, POST, TEST_URL, []byte(BODY_STR))
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledEnqueueLatest_ExpectPendingMessagesOfKeyReplaced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, OTHER_KEY, 0, NOW, NOW),
+		makeMessage(ID_2, APP_ID, 0, NOW, NOW),
+		makeMessage(ID_3, OTHER_KEY, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_1).Return(nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_3).Return(nil),
+		configMockObj.EXPECT().GetProperty(MAX_SIZE).Return(maxSizeProp, nil),
+		dbMockObj.EXPECT().InsertMessage(OTHER_KEY, POST, TEST_URL, []byte(BODY_STR)).Return(nil, nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+
+	err := Executor{}.EnqueueLatest(OTHER_KEY, POST, TEST_URL, []byte(BODY_STR))
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledEnqueueWhenInsertMessageFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(nil, nil),
+		configMockObj.EXPECT().GetProperty(MAX_SIZE).Return(nil, notFoundError),
+		dbMockObj.EXPECT().InsertMessage(APP_ID, POST, TEST_URL, []byte(BODY_STR)).Return(nil, unknownError),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+
+	err := Executor{}.Enqueue(APP_ID, POST, TEST_URL, []byte(BODY_STR))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unknown", err)
+	case errors.Unknown:
+	}
+}
+
+func TestCalledGetStatus_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_AGE).Return(nil, notFoundError),
+		configMockObj.EXPECT().GetProperty(MAX_SIZE).Return(maxSizeProp, nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+
+	res, err := Executor{}.GetStatus()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[PENDING] != 1 || res[MAX_AGE] != DEFAULT_MAX_AGE || res[MAX_SIZE] != 2 {
+		t.Errorf("Unexpected status: %v", res)
+	}
+
+	pending := res[MESSAGES].([]map[string]interface{})
+	if _, exists := pending[0][BODY]; exists {
+		t.Errorf("Unexpected body in status: %v", pending[0])
+	}
+}
+
+func TestCalledDeliver_ExpectMessagesDeliveredInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	msgMockObj := msgmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 0, NOW, NOW),
+		makeMessage(ID_2, APP_ID, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_AGE).Return(nil, notFoundError),
+		msgMockObj.EXPECT().SendHttpRequest(POST, TEST_URL, []byte(BODY_STR)).Return(200, "", nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_1).Return(nil),
+		msgMockObj.EXPECT().SendHttpRequest(POST, TEST_URL, []byte(BODY_STR)).Return(200, "", nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_2).Return(nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+	httpExecutor = msgMockObj
+
+	deliver()
+}
+
+func TestCalledDeliverWhenSendFailed_ExpectLaterMessagesOfSameKeyHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	msgMockObj := msgmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 2, NOW, NOW),
+		makeMessage(ID_2, APP_ID, 0, NOW, NOW),
+		makeMessage(ID_3, OTHER_KEY, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_AGE).Return(nil, notFoundError),
+		msgMockObj.EXPECT().SendHttpRequest(POST, TEST_URL, []byte(BODY_STR)).Return(500, "", unknownError),
+		dbMockObj.EXPECT().UpdateMessage(ID_1, 3, int64(NOW+4), gomock.Any()).Return(nil),
+		msgMockObj.EXPECT().SendHttpRequest(POST, TEST_URL, []byte(BODY_STR)).Return(200, "", nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_3).Return(nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+	httpExecutor = msgMockObj
+
+	deliver()
+}
+
+func TestCalledDeliverWithExpiredAndWaitingMessages_ExpectNoRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	msgMockObj := msgmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 5, NOW, NOW-DEFAULT_MAX_AGE-1),
+		makeMessage(ID_2, APP_ID, 1, NOW+1, NOW),
+		makeMessage(ID_3, APP_ID, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_AGE).Return(nil, notFoundError),
+		dbMockObj.EXPECT().DeleteMessage(ID_1).Return(nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+	httpExecutor = msgMockObj
+
+	deliver()
+}
+
+func TestCalledDeliverWhenRequestRejected_ExpectMessageDropped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbMockObj := dbmocks.NewMockCommand(ctrl)
+	configMockObj := configmocks.NewMockCommand(ctrl)
+	msgMockObj := msgmocks.NewMockCommand(ctrl)
+
+	messages := []map[string]interface{}{
+		makeMessage(ID_1, APP_ID, 0, NOW, NOW),
+	}
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().GetMessages().Return(messages, nil),
+		configMockObj.EXPECT().GetProperty(MAX_AGE).Return(nil, notFoundError),
+		msgMockObj.EXPECT().SendHttpRequest(POST, TEST_URL, []byte(BODY_STR)).Return(400, "", nil),
+		dbMockObj.EXPECT().DeleteMessage(ID_1).Return(nil),
+	)
+
+	dbExecutor = dbMockObj
+	configDbExecutor = configMockObj
+	httpExecutor = msgMockObj
+
+	deliver()
+}
+
+func TestCalledBackoff_ExpectExponentialDelayWithLimit(t *testing.T) {
+	expected := map[int]int64{1: 1, 2: 2, 3: 4, 4: 8, 9: 256, 10: MAX_BACKOFF, 100: MAX_BACKOFF}
+	for attempts, delay := range expected {
+		if ret := backoff(attempts); ret != delay {
+			t.Errorf("Expected backoff(%d) : %d, actual : %d", attempts, delay, ret)
+		}
+	}
+}
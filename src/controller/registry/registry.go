@@ -0,0 +1,218 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package registry provides management of private registry credentials
+// and selection of them by registry host of an image.
+package registry
+
+import (
+	"commons/errors"
+	"commons/logger"
+	registryDB "db/bolt/registry"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	ID         = "id"
+	HOST       = "host"
+	USERNAME   = "username"
+	PASSWORD   = "password"
+	REGISTRIES = "registries"
+
+	// Registry host of images which don't specify it.
+	DEFAULT_REGISTRY = "docker.io"
+)
+
+type Command interface {
+	// AddRegistry adds credentials of a registry described by body.
+	AddRegistry(body string) (map[string]interface{}, error)
+
+	// GetRegistries returns all of registries without passwords.
+	GetRegistries() (map[string]interface{}, error)
+
+	// GetRegistry returns a registry without password.
+	GetRegistry(id string) (map[string]interface{}, error)
+
+	// UpdateRegistry replaces credentials of a registry with body.
+	UpdateRegistry(id string, body string) (map[string]interface{}, error)
+
+	// DeleteRegistry deletes a registry.
+	DeleteRegistry(id string) error
+
+	// FindCredential returns credentials for the registry an image belongs to.
+	FindCredential(image string) (map[string]interface{}, error)
+}
+
+type Executor struct{}
+
+var dbExecutor registryDB.Command
+
+// Aliases of the default registry used in docker configurations.
+var defaultRegistryAliases = map[string]bool{
+	"index.docker.io":         true,
+	"registry-1.docker.io":    true,
+	"registry.hub.docker.com": true,
+}
+
+func init() {
+	dbExecutor = registryDB.Executor{}
+}
+
+// AddRegistry adds credentials of a registry.
+// body should have host, username and password.
+// host is a registry address like "192.168.0.10:5000" or "docker.io",
+// a scheme and a path in it are ignored.
+func (Executor) AddRegistry(body string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyMap, err := parseBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := bodyMap[HOST].(string)
+	username, _ := bodyMap[USERNAME].(string)
+	password, _ := bodyMap[PASSWORD].(string)
+
+	host = NormalizeHost(host)
+	if len(host) == 0 || len(username) == 0 || len(password) == 0 {
+		return nil, errors.InvalidParam{Msg: "host, username and password are required"}
+	}
+
+	registry, err := dbExecutor.InsertRegistry(host, username, password)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (Executor) GetRegistries() (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	registries, err := dbExecutor.GetRegistries()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[REGISTRIES] = registries
+	return res, nil
+}
+
+func (Executor) GetRegistry(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	registry, err := dbExecutor.GetRegistry(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return registry, nil
+}
+
+// UpdateRegistry replaces username and password of a registry.
+// host of a registry can't be changed.
+func (Executor) UpdateRegistry(id string, body string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	bodyMap, err := parseBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	username, _ := bodyMap[USERNAME].(string)
+	password, _ := bodyMap[PASSWORD].(string)
+	if len(username) == 0 || len(password) == 0 {
+		return nil, errors.InvalidParam{Msg: "username and password are required"}
+	}
+
+	registry, err := dbExecutor.UpdateRegistry(id, username, password)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (Executor) DeleteRegistry(id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	err := dbExecutor.DeleteRegistry(id)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+	return err
+}
+
+// FindCredential returns host, username and password of the registry
+// which an image is pulled from.
+// if no credentials are registered for the registry, return NotFound error.
+func (Executor) FindCredential(image string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return dbExecutor.GetCredential(GetRegistryHost(image))
+}
+
+func parseBody(body string) (map[string]interface{}, error) {
+	bodyMap := make(map[string]interface{})
+	err := json.Unmarshal([]byte(body), &bodyMap)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "Unmarshalling Failed"}
+	}
+	return bodyMap, nil
+}
+
+// GetRegistryHost returns registry host of an image in the same way as docker does.
+// the first component of the name is a host if it has "." or ":" or is "localhost",
+// otherwise the image is in the default registry.
+func GetRegistryHost(image string) string {
+	idx := strings.Index(image, "/")
+	if idx == -1 {
+		return DEFAULT_REGISTRY
+	}
+
+	first := image[:idx]
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return DEFAULT_REGISTRY
+	}
+	return NormalizeHost(first)
+}
+
+// NormalizeHost returns host of a registry address without scheme and path,
+// aliases of the default registry are changed to it.
+func NormalizeHost(address string) string {
+	host := strings.ToLower(strings.TrimSpace(address))
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	if idx := strings.Index(host, "/"); idx != -1 {
+		host = host[:idx]
+	}
+
+	if defaultRegistryAliases[host] {
+		return DEFAULT_REGISTRY
+	}
+	return host
+}
@@ -0,0 +1,175 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package registry
+
+import (
+	"commons/errors"
+	dbmocks "db/bolt/registry/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+const (
+	registryId = "test_registry_id"
+	host       = "test_url:5000"
+	username   = "test_user"
+	password   = "test_password"
+)
+
+var (
+	registry = map[string]interface{}{
+		"id":        registryId,
+		"host":      host,
+		"username":  username,
+		"timestamp": int64(1),
+	}
+	credential = map[string]interface{}{
+		"host":     host,
+		"username": username,
+		"password": password,
+	}
+	notFoundError = errors.NotFound{}
+)
+
+func TestCalledAddRegistry_ExpectNormalizedHostInserted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().InsertRegistry(host, username, password).Return(registry, nil)
+	dbExecutor = dbExecutorMockObj
+
+	body := `{"host":"https://TEST_URL:5000/v2/","username":"` + username + `","password":"` + password + `"}`
+	res, err := Executor{}.AddRegistry(body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(registry, res) {
+		t.Errorf("Expected res: %v, actual res: %v", registry, res)
+	}
+}
+
+func TestCalledAddRegistryWithInvalidBody_ExpectErrorReturn(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected error
+	}{
+		"InvalidJSON":   {"{", errors.InvalidJSON{}},
+		"EmptyHost":     {`{"username":"user","password":"pass"}`, errors.InvalidParam{}},
+		"EmptyPassword": {`{"host":"docker.io","username":"user"}`, errors.InvalidParam{}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Executor{}.AddRegistry(test.body)
+			if reflect.TypeOf(err) != reflect.TypeOf(test.expected) {
+				t.Errorf("Expected err: %v, actual err: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCalledGetRegistries_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetRegistries().Return([]map[string]interface{}{registry}, nil)
+	dbExecutor = dbExecutorMockObj
+
+	res, err := Executor{}.GetRegistries()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{REGISTRIES: []map[string]interface{}{registry}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+}
+
+func TestCalledUpdateRegistry_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().UpdateRegistry(registryId, username, password).Return(registry, nil)
+	dbExecutor = dbExecutorMockObj
+
+	body := `{"username":"` + username + `","password":"` + password + `"}`
+	_, err := Executor{}.UpdateRegistry(registryId, body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteRegistryWhenDBFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().DeleteRegistry(registryId).Return(notFoundError)
+	dbExecutor = dbExecutorMockObj
+
+	err := Executor{}.DeleteRegistry(registryId)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledFindCredential_ExpectLookedUpByRegistryHost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbExecutorMockObj := dbmocks.NewMockCommand(ctrl)
+	dbExecutorMockObj.EXPECT().GetCredential(host).Return(credential, nil)
+	dbExecutor = dbExecutorMockObj
+
+	res, err := Executor{}.FindCredential(host + "/test:1.0")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(credential, res) {
+		t.Errorf("Expected res: %v, actual res: %v", credential, res)
+	}
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"ubuntu":                            DEFAULT_REGISTRY,
+		"library/ubuntu:16.04":              DEFAULT_REGISTRY,
+		"index.docker.io/library/ubuntu":    DEFAULT_REGISTRY,
+		"localhost/test":                    "localhost",
+		"localhost:5000/test:1.0":           "localhost:5000",
+		"Registry.Example.com/team/app:1.0": "registry.example.com",
+		"test_url:5000/test@sha256:1234":    "test_url:5000",
+	}
+
+	for image, expected := range tests {
+		if host := GetRegistryHost(image); host != expected {
+			t.Errorf("Expected host of %s: %s, actual host: %s", image, expected, host)
+		}
+	}
+}
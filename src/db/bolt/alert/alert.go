@@ -0,0 +1,276 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alert
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"crypto/rand"
+	. "db/bolt/wrapper"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Interface of Alert rule model's operations.
+type Command interface {
+	// InsertRule adds a new alert rule.
+	InsertRule(target string, app_id string, service string, metric string, operator string, threshold float64, duration int64) (map[string]interface{}, error)
+
+	// GetRules returns all of alert rules in the order they were inserted.
+	GetRules() ([]map[string]interface{}, error)
+
+	// GetRule returns an alert rule.
+	GetRule(id string) (map[string]interface{}, error)
+
+	// UpdateRuleState updates evaluation state of an alert rule.
+	UpdateRuleState(id string, state string, since int64) error
+
+	// DeleteRule deletes an alert rule.
+	DeleteRule(id string) error
+}
+
+const (
+	BUCKET_NAME = "alert"
+	STATE_OK    = "ok"
+	ID_LENGTH   = 12
+)
+
+type Rule struct {
+	ID        string  `json:"id"`
+	Target    string  `json:"target"`
+	AppID     string  `json:"appid"`
+	Service   string  `json:"service"`
+	Metric    string  `json:"metric"`
+	Operator  string  `json:"operator"`
+	Threshold float64 `json:"threshold"`
+	Duration  int64   `json:"for"`
+	State     string  `json:"state"`
+	Since     int64   `json:"since"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+type Executor struct {
+}
+
+var db Database
+var now = time.Now
+
+func init() {
+	db = NewBoltDB(BUCKET_NAME)
+}
+
+// Convert to map by object of struct Rule.
+// will return Rule information as map.
+func (rule Rule) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        rule.ID,
+		"target":    rule.Target,
+		"appid":     rule.AppID,
+		"service":   rule.Service,
+		"metric":    rule.Metric,
+		"operator":  rule.Operator,
+		"threshold": rule.Threshold,
+		"for":       rule.Duration,
+		"state":     rule.State,
+		"since":     rule.Since,
+		"timestamp": rule.Timestamp,
+	}
+}
+
+func (rule Rule) encode() ([]byte, error) {
+	encoded, err := json.Marshal(rule)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return encoded, nil
+}
+
+func decode(data []byte) (*Rule, error) {
+	var rule *Rule
+	err := json.Unmarshal(data, &rule)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return rule, nil
+}
+
+// Add a new alert rule to alert collection.
+// a new rule starts at ok state.
+// if succeed to add, return rule information as map.
+// otherwise, return error.
+func (Executor) InsertRule(target string, app_id string, service string, metric string, operator string, threshold float64, duration int64) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(target) == 0 || len(metric) == 0 || len(operator) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : target, metric or operator is empty."}
+		return nil, err
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	rule := Rule{
+		ID:        id,
+		Target:    target,
+		AppID:     app_id,
+		Service:   service,
+		Metric:    metric,
+		Operator:  operator,
+		Threshold: threshold,
+		Duration:  duration,
+		State:     STATE_OK,
+		Timestamp: now().Unix(),
+	}
+
+	encoded, err := rule.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Put([]byte(id), encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule.convertToMap(), nil
+}
+
+// Getting all of alert rules.
+// if succeed to get, return list of rules sorted by inserted time.
+// otherwise, return error.
+func (Executor) GetRules() ([]map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	values, err := db.List()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*Rule, 0)
+	for _, value := range values {
+		rule, err := decode([]byte(value.(string)))
+		if err != nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Timestamp != rules[j].Timestamp {
+			return rules[i].Timestamp < rules[j].Timestamp
+		}
+		return rules[i].ID < rules[j].ID
+	})
+
+	result := make([]map[string]interface{}, 0)
+	for _, rule := range rules {
+		result = append(result, rule.convertToMap())
+	}
+	return result, nil
+}
+
+// Getting an alert rule by id.
+// if succeed to get, return rule information as map.
+// otherwise, return error.
+func (Executor) GetRule(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return nil, err
+	}
+
+	value, err := db.Get([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := decode(value)
+	if err != nil {
+		return nil, err
+	}
+	return rule.convertToMap(), nil
+}
+
+// Updating evaluation state of an alert rule.
+// since is the time when the condition of the rule became true.
+// if succeed to update, return error as nil.
+// otherwise, return error.
+func (Executor) UpdateRuleState(id string, state string, since int64) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return err
+	}
+
+	value, err := db.Get([]byte(id))
+	if err != nil {
+		return err
+	}
+
+	rule, err := decode(value)
+	if err != nil {
+		return err
+	}
+
+	rule.State = state
+	rule.Since = since
+
+	encoded, err := rule.encode()
+	if err != nil {
+		return err
+	}
+	return db.Put([]byte(id), encoded)
+}
+
+// Deleting an alert rule by id.
+// if succeed to delete, return error as nil.
+// otherwise, return error.
+func (Executor) DeleteRule(id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return err
+	}
+
+	_, err := db.Get([]byte(id))
+	if err != nil {
+		return err
+	}
+	return db.Delete([]byte(id))
+}
+
+func generateID() (string, error) {
+	bytes := make([]byte, ID_LENGTH)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", errors.Unknown{Msg: err.Error()}
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,326 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package alert
+
+import (
+	"commons/errors"
+	dbmocks "db/bolt/wrapper/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	TARGET          = "host"
+	METRIC          = "cpu"
+	OPERATOR        = ">"
+	THRESHOLD       = 90.0
+	DURATION        = 120
+	TIMESTAMP       = 1500000000
+	RULE_1_ID       = "000000000000000000000001"
+	RULE_2_ID       = "000000000000000000000002"
+	RULE_1_JSON     = "{\"id\":\"000000000000000000000001\",\"target\":\"host\",\"appid\":\"\",\"service\":\"\",\"metric\":\"cpu\",\"operator\":\">\",\"threshold\":90,\"for\":120,\"state\":\"ok\",\"since\":0,\"timestamp\":1}"
+	RULE_2_JSON     = "{\"id\":\"000000000000000000000002\",\"target\":\"service\",\"appid\":\"test_app_id\",\"service\":\"web\",\"metric\":\"mem\",\"operator\":\">=\",\"threshold\":1024,\"for\":0,\"state\":\"firing\",\"since\":5,\"timestamp\":2}"
+	DUMMY_ERROR_MSG = "dummy_errors"
+)
+
+var (
+	rules = map[string]interface{}{
+		RULE_2_ID: RULE_2_JSON,
+		RULE_1_ID: RULE_1_JSON,
+	}
+	rule1 = map[string]interface{}{
+		"id":        RULE_1_ID,
+		"target":    "host",
+		"appid":     "",
+		"service":   "",
+		"metric":    "cpu",
+		"operator":  ">",
+		"threshold": 90.0,
+		"for":       int64(120),
+		"state":     "ok",
+		"since":     int64(0),
+		"timestamp": int64(1),
+	}
+	rule2 = map[string]interface{}{
+		"id":        RULE_2_ID,
+		"target":    "service",
+		"appid":     "test_app_id",
+		"service":   "web",
+		"metric":    "mem",
+		"operator":  ">=",
+		"threshold": 1024.0,
+		"for":       int64(0),
+		"state":     "firing",
+		"since":     int64(5),
+		"timestamp": int64(2),
+	}
+	dummy_error = errors.NotFound{Msg: DUMMY_ERROR_MSG}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(TIMESTAMP, 0) }
+}
+
+func TestCalledInsertRule_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.InsertRule(TARGET, "", "", METRIC, OPERATOR, THRESHOLD, DURATION)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if len(res["id"].(string)) != ID_LENGTH*2 {
+		t.Errorf("Unexpected id length: %s", res["id"])
+	}
+
+	expectedRes := map[string]interface{}{
+		"id":        res["id"],
+		"target":    TARGET,
+		"appid":     "",
+		"service":   "",
+		"metric":    METRIC,
+		"operator":  OPERATOR,
+		"threshold": THRESHOLD,
+		"for":       int64(DURATION),
+		"state":     STATE_OK,
+		"since":     int64(0),
+		"timestamp": int64(TIMESTAMP),
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledInsertRuleWithEmptyMetric_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.InsertRule(TARGET, "", "", "", OPERATOR, THRESHOLD, DURATION)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidParam", err.Error())
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledInsertRuleWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.InsertRule(TARGET, "", "", METRIC, OPERATOR, THRESHOLD, DURATION)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetRules_ExpectSortedByTimestamp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(rules, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetRules()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := []map[string]interface{}{rule1, rule2}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetRulesWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.GetRules()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetRule_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(RULE_2_ID)).Return([]byte(RULE_2_JSON), nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetRule(RULE_2_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(rule2, res) {
+		t.Errorf("Expected res: %v, actual res: %v", rule2, res)
+	}
+}
+
+func TestCalledGetRuleWithEmptyId_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.GetRule("")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidParam", err.Error())
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledUpdateRuleState_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	expected := "{\"id\":\"000000000000000000000001\",\"target\":\"host\",\"appid\":\"\",\"service\":\"\",\"metric\":\"cpu\",\"operator\":\"\\u003e\",\"threshold\":90,\"for\":120,\"state\":\"pending\",\"since\":10,\"timestamp\":1}"
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(RULE_1_ID)).Return([]byte(RULE_1_JSON), nil),
+		dbMockObj.EXPECT().Put([]byte(RULE_1_ID), []byte(expected)).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.UpdateRuleState(RULE_1_ID, "pending", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUpdateRuleStateWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(RULE_1_ID)).Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.UpdateRuleState(RULE_1_ID, "pending", 10)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeleteRule_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(RULE_1_ID)).Return([]byte(RULE_1_JSON), nil),
+		dbMockObj.EXPECT().Delete([]byte(RULE_1_ID)).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.DeleteRule(RULE_1_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteRuleWhenRuleNotExist_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(RULE_1_ID)).Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.DeleteRule(RULE_1_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
@@ -29,6 +29,9 @@ type Command interface {
 	// SetProperty updates configuration sets.
 	SetProperty(property map[string]interface{}) error
 
+	// SetProperties updates several configuration sets in a single transaction.
+	SetProperties(properties []map[string]interface{}) error
+
 	// GetProperty returns a single configuration property specified by name parameter.
 	GetProperty(name string) (map[string]interface{}, error)
 
@@ -89,7 +92,33 @@ func (Executor) SetProperty(property map[string]interface{}) error {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
-	value, err := db.Get([]byte(property["name"].(string)))
+	return db.Update(func(tx Tx) error {
+		return putProperty(tx.Bucket(BUCKET_NAME), property)
+	})
+}
+
+// SetProperties updates maps of configuration in a single transaction,
+// so that none of them is updated if one of them fails.
+// if succeed to update, returns an error as nil.
+// otherwise, return error.
+func (Executor) SetProperties(properties []map[string]interface{}) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(BUCKET_NAME)
+		for _, property := range properties {
+			err := putProperty(bucket, property)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func putProperty(bucket Bucket, property map[string]interface{}) error {
+	value, err := bucket.Get([]byte(property["name"].(string)))
 	if err != nil {
 		switch err.(type) {
 		default:
@@ -106,11 +135,7 @@ func (Executor) SetProperty(property map[string]interface{}) error {
 				return err
 			}
 
-			err = db.Put([]byte(property["name"].(string)), encoded)
-			if err != nil {
-				return err
-			}
-			return nil
+			return bucket.Put([]byte(property["name"].(string)), encoded)
 		}
 	}
 
@@ -125,7 +150,7 @@ func (Executor) SetProperty(property map[string]interface{}) error {
 		return err
 	}
 
-	return db.Put([]byte(property["name"].(string)), encoded)
+	return bucket.Put([]byte(property["name"].(string)), encoded)
 }
 
 // GetProperty returns a single configuration property specified by name parameter.
@@ -18,6 +18,7 @@ package configuration
 
 import (
 	"commons/errors"
+	"db/bolt/wrapper"
 	dbmocks "db/bolt/wrapper/mocks"
 	gomock "github.com/golang/mock/gomock"
 	"reflect"
@@ -40,6 +41,15 @@ var (
 	}
 )
 
+// Expects an update transaction of which bucket is served by dbMockObj.
+func expectUpdate(mockCtrl *gomock.Controller, dbMockObj *dbmocks.MockDatabase) *gomock.Call {
+	txMockObj := dbmocks.NewMockTx(mockCtrl)
+	txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(dbMockObj).AnyTimes()
+	return dbMockObj.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(wrapper.Tx) error) error {
+		return fn(txMockObj)
+	})
+}
+
 func TestCalledSetProperty_ExpectSuccess(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -47,6 +57,7 @@ func TestCalledSetProperty_ExpectSuccess(t *testing.T) {
 	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(PROP_NAME)).Return(nil, dummy_error),
 		dbMockObj.EXPECT().Put([]byte(PROP_NAME), gomock.Any()).Return(nil),
 	)
@@ -68,6 +79,7 @@ func TestCalledSetPropertyWhenAlreadyExistsInDB_ExpectSuccess(t *testing.T) {
 	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(PROP_NAME)).Return([]byte(PROP_JSON), nil),
 		dbMockObj.EXPECT().Put([]byte(PROP_NAME), gomock.Any()).Return(nil),
 	)
@@ -82,6 +94,36 @@ func TestCalledSetPropertyWhenAlreadyExistsInDB_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestCalledSetPropertiesWhenOneOfThemFailed_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+	other := map[string]interface{}{
+		"name":     "other",
+		"value":    PROP_VALUE,
+		"readOnly": false,
+	}
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().Get([]byte(PROP_NAME)).Return([]byte(PROP_JSON), nil),
+		dbMockObj.EXPECT().Put([]byte(PROP_NAME), gomock.Any()).Return(nil),
+		dbMockObj.EXPECT().Get([]byte("other")).Return(nil, errors.Unknown{}),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.SetProperties([]map[string]interface{}{property, other})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unknown", err)
+	case errors.Unknown:
+	}
+}
+
 func TestCalledGetProperty_ExpectSuccess(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
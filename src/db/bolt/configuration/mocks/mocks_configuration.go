@@ -61,6 +61,18 @@ func (mr *MockCommandMockRecorder) SetProperty(property interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProperty", reflect.TypeOf((*MockCommand)(nil).SetProperty), property)
 }
 
+// SetProperties mocks base method
+func (m *MockCommand) SetProperties(properties []map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "SetProperties", properties)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProperties indicates an expected call of SetProperties
+func (mr *MockCommandMockRecorder) SetProperties(properties interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProperties", reflect.TypeOf((*MockCommand)(nil).SetProperties), properties)
+}
+
 // GetProperty mocks base method
 func (m *MockCommand) GetProperty(name string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetProperty", name)
@@ -27,6 +27,7 @@ import (
 type Command interface {
 	InsertEvent(eventId, appId, imageName string) (map[string]interface{}, error)
 	GetEvents(appId, imageName string) ([]map[string]interface{}, error)
+	GetEventList() ([]map[string]interface{}, error)
 	DeleteEvent(eventId string) error
 }
 
@@ -77,31 +78,38 @@ func decode(data []byte) (*Event, error) {
 }
 
 func (e Executor) InsertEvent(eventId, appId, imageName string) (map[string]interface{}, error) {
-	value, err := db.Get([]byte(eventId))
-	if err == nil {
-		event, err := decode(value)
+	var result map[string]interface{}
+	err := db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(BUCKET_NAME)
+		value, err := bucket.Get([]byte(eventId))
 		if err == nil {
-			return event.convertToMap(), errors.AlreadyReported{Msg: eventId}
+			event, err := decode(value)
+			if err == nil {
+				result = event.convertToMap()
+				return errors.AlreadyReported{Msg: eventId}
+			}
 		}
-	}
 
-	event := Event{
-		ID:        eventId,
-		AppID:     appId,
-		ImageName: imageName,
-	}
+		event := Event{
+			ID:        eventId,
+			AppID:     appId,
+			ImageName: imageName,
+		}
 
-	encoded, err := event.encode()
-	if err != nil {
-		return nil, err
-	}
+		encoded, err := event.encode()
+		if err != nil {
+			return err
+		}
 
-	err = db.Put([]byte(eventId), encoded)
-	if err != nil {
-		return nil, err
-	}
+		err = bucket.Put([]byte(eventId), encoded)
+		if err != nil {
+			return err
+		}
 
-	return event.convertToMap(), nil
+		result = event.convertToMap()
+		return nil
+	})
+	return result, err
 }
 
 func (Executor) GetEvents(appId, imageName string) ([]map[string]interface{}, error) {
@@ -123,6 +131,46 @@ func (Executor) GetEvents(appId, imageName string) ([]map[string]interface{}, er
 	return result, nil
 }
 
+// Getting all of events regardless of apps and images they are subscribed for.
+func (Executor) GetEventList() ([]map[string]interface{}, error) {
+	events, err := db.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, value := range events {
+		event, err := decode([]byte(value.(string)))
+		if err != nil {
+			continue
+		}
+		result = append(result, event.convertToMap())
+	}
+	return result, nil
+}
+
 func (Executor) DeleteEvent(eventId string) error {
 	return db.Delete([]byte(eventId))
 }
+
+// Deleting events subscribed for the app specified by appId in transaction tx.
+// events subscribed for all apps are not deleted.
+func DeleteAppEvents(tx Tx, appId string) error {
+	bucket := tx.Bucket(BUCKET_NAME)
+	events, err := bucket.List()
+	if err != nil {
+		return err
+	}
+
+	for _, value := range events {
+		event, err := decode([]byte(value.(string)))
+		if err != nil || event.AppID != appId {
+			continue
+		}
+		err = bucket.Delete([]byte(event.ID))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package event
 
 import (
 	"commons/errors"
+	"db/bolt/wrapper"
 	dbmocks "db/bolt/wrapper/mocks"
 	gomock "github.com/golang/mock/gomock"
 	"reflect"
@@ -41,6 +42,15 @@ var (
 	dummy_error = errors.NotFound{DUMMY_ERROR_MSG}
 )
 
+// Expects an update transaction of which bucket is served by dbMockObj.
+func expectUpdate(mockCtrl *gomock.Controller, dbMockObj *dbmocks.MockDatabase) *gomock.Call {
+	txMockObj := dbmocks.NewMockTx(mockCtrl)
+	txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(dbMockObj).AnyTimes()
+	return dbMockObj.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(wrapper.Tx) error) error {
+		return fn(txMockObj)
+	})
+}
+
 func TestCalledInsertEvent_ExpectSuccess(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -48,6 +58,7 @@ func TestCalledInsertEvent_ExpectSuccess(t *testing.T) {
 	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(EVENTID)).Return(nil, dummy_error),
 		dbMockObj.EXPECT().Put([]byte(EVENTID), gomock.Any()).Return(nil),
 	)
@@ -69,6 +80,7 @@ func TestCalledInsertEventWhenAlreadyExistsInDB_ExpectSuccess(t *testing.T) {
 	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(EVENTID)).Return([]byte(EVENT_JSON), nil),
 	)
 
@@ -95,6 +107,7 @@ func TestCalledInsertEventWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(EVENTID)).Return(nil, dummy_error),
 		dbMockObj.EXPECT().Put([]byte(EVENTID), gomock.Any()).Return(dummy_error),
 	)
@@ -168,6 +181,34 @@ func TestCalledGetEventsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
+func TestCalledGetEventList_ExpectAllEventsReturned(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	returnedEvents := map[string]interface{}{
+		EVENTID: EVENT_JSON,
+	}
+	expectedRes := []map[string]interface{}{event}
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(returnedEvents, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+	res, err := executor.GetEventList()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
 func TestCalledDeleteEvent_ExpectSuccess(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -186,3 +227,29 @@ func TestCalledDeleteEvent_ExpectSuccess(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestCalledDeleteAppEvents_ExpectOnlyEventsOfAppDeleted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	returnedEvents := map[string]interface{}{
+		EVENTID:        EVENT_JSON,
+		"other_event":  "{\"id\":\"other_event\",\"appid\":\"other_app_id\",\"imagename\":\"\"}",
+		"global_event": "{\"id\":\"global_event\",\"appid\":\"\",\"imagename\":\"\"}",
+	}
+
+	bucketMockObj := dbmocks.NewMockDatabase(mockCtrl)
+	txMockObj := dbmocks.NewMockTx(mockCtrl)
+
+	gomock.InOrder(
+		txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(bucketMockObj),
+		bucketMockObj.EXPECT().List().Return(returnedEvents, nil),
+		bucketMockObj.EXPECT().Delete([]byte(EVENTID)).Return(nil),
+	)
+
+	err := DeleteAppEvents(txMockObj, APPID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
@@ -75,6 +75,19 @@ func (mr *MockCommandMockRecorder) GetEvents(appId, imageName interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockCommand)(nil).GetEvents), appId, imageName)
 }
 
+// GetEventList mocks base method
+func (m *MockCommand) GetEventList() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetEventList")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventList indicates an expected call of GetEventList
+func (mr *MockCommandMockRecorder) GetEventList() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventList", reflect.TypeOf((*MockCommand)(nil).GetEventList))
+}
+
 // DeleteEvent mocks base method
 func (m *MockCommand) DeleteEvent(eventId string) error {
 	ret := m.ctrl.Call(m, "DeleteEvent", eventId)
@@ -0,0 +1,256 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package history
+
+import (
+	"commons/errors"
+	"commons/logger"
+	. "db/bolt/wrapper"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Interface of History model's operations.
+type Command interface {
+	// InsertRevision appends a new description revision of target app.
+	InsertRevision(app_id string, description string, operation string, digests map[string]string) (map[string]interface{}, error)
+
+	// GetRevisions returns all of revisions of target app in ascending order.
+	GetRevisions(app_id string) ([]map[string]interface{}, error)
+
+	// GetRevision returns a revision of target app.
+	GetRevision(app_id string, revision int) (map[string]interface{}, error)
+
+	// DeleteRevisions deletes all of revisions of target app.
+	DeleteRevisions(app_id string) error
+}
+
+const (
+	BUCKET_NAME = "history"
+	KEY_SEP     = "/"
+)
+
+type Revision struct {
+	AppID       string            `json:"appid"`
+	Revision    int               `json:"revision"`
+	Description string            `json:"description"`
+	Digests     map[string]string `json:"digests"`
+	Operation   string            `json:"operation"`
+	Timestamp   int64             `json:"timestamp"`
+}
+
+type Executor struct {
+}
+
+var db Database
+var now = time.Now
+
+func init() {
+	db = NewBoltDB(BUCKET_NAME)
+}
+
+// Convert to map by object of struct Revision.
+// will return Revision information as map.
+func (rev Revision) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"appid":       rev.AppID,
+		"revision":    rev.Revision,
+		"description": rev.Description,
+		"digests":     rev.Digests,
+		"operation":   rev.Operation,
+		"timestamp":   rev.Timestamp,
+	}
+}
+
+func (rev Revision) encode() ([]byte, error) {
+	encoded, err := json.Marshal(rev)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return encoded, nil
+}
+
+func decode(data []byte) (*Revision, error) {
+	var rev *Revision
+	err := json.Unmarshal(data, &rev)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return rev, nil
+}
+
+// Add a new revision to history collection.
+// revision number starts at 1 and increases per app.
+// if succeed to add, return revision information as map.
+// otherwise, return error.
+func (Executor) InsertRevision(app_id string, description string, operation string, digests map[string]string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
+		return nil, err
+	}
+
+	// The last revision is read and the next one is put in a single transaction
+	// so that concurrent operations don't get the same revision number.
+	var rev Revision
+	err := db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(BUCKET_NAME)
+		revisions, err := listRevisions(bucket, app_id)
+		if err != nil {
+			return err
+		}
+
+		last := 0
+		if len(revisions) != 0 {
+			last = revisions[len(revisions)-1].Revision
+		}
+
+		rev = Revision{
+			AppID:       app_id,
+			Revision:    last + 1,
+			Description: description,
+			Digests:     digests,
+			Operation:   operation,
+			Timestamp:   now().Unix(),
+		}
+
+		encoded, err := rev.encode()
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(makeKey(app_id, rev.Revision)), encoded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rev.convertToMap(), nil
+}
+
+// Getting all of revisions by app_id.
+// if succeed to get, return list of revisions sorted by revision number.
+// otherwise, return error.
+func (Executor) GetRevisions(app_id string) ([]map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
+		return nil, err
+	}
+
+	revisions, err := getRevisions(app_id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, rev := range revisions {
+		result = append(result, rev.convertToMap())
+	}
+	return result, nil
+}
+
+// Getting a revision by app_id and revision number.
+// if succeed to get, return revision information as map.
+// otherwise, return error.
+func (Executor) GetRevision(app_id string, revision int) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
+		return nil, err
+	}
+
+	value, err := db.Get([]byte(makeKey(app_id, revision)))
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := decode(value)
+	if err != nil {
+		return nil, err
+	}
+	return rev.convertToMap(), nil
+}
+
+// Deleting all of revisions by app_id.
+// if succeed to delete, return error as nil.
+// otherwise, return error.
+func (Executor) DeleteRevisions(app_id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
+		return err
+	}
+
+	revisions, err := getRevisions(app_id)
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revisions {
+		err = db.Delete([]byte(makeKey(app_id, rev.Revision)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getRevisions(app_id string) ([]*Revision, error) {
+	return listRevisions(db, app_id)
+}
+
+func listRevisions(bucket Bucket, app_id string) ([]*Revision, error) {
+	values, err := bucket.List()
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*Revision, 0)
+	for key, value := range values {
+		if !strings.HasPrefix(key, app_id+KEY_SEP) {
+			continue
+		}
+		rev, err := decode([]byte(value.(string)))
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// Key is made of app_id and zero-padded revision number
+// so that revisions of an app are adjacent in the bucket.
+func makeKey(app_id string, revision int) string {
+	return app_id + KEY_SEP + fmt.Sprintf("%08d", revision)
+}
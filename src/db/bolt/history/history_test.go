@@ -0,0 +1,251 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package history
+
+import (
+	"commons/errors"
+	"db/bolt/wrapper"
+	dbmocks "db/bolt/wrapper/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	APPID           = "test_app_id"
+	OTHER_APPID     = "other_app_id"
+	DESCRIPTION     = "{\"services\":{\"test_service\":{\"image\":\"test_image:1.0\"}}}"
+	OPERATION       = "deploy"
+	TIMESTAMP       = 1500000000
+	REVISION_1_KEY  = APPID + "/00000001"
+	REVISION_2_KEY  = APPID + "/00000002"
+	REVISION_1_JSON = "{\"appid\":\"test_app_id\",\"revision\":1,\"description\":\"desc1\",\"digests\":{},\"operation\":\"deploy\",\"timestamp\":1}"
+	REVISION_2_JSON = "{\"appid\":\"test_app_id\",\"revision\":2,\"description\":\"desc2\",\"digests\":{},\"operation\":\"update\",\"timestamp\":2}"
+	OTHER_JSON      = "{\"appid\":\"other_app_id\",\"revision\":5,\"description\":\"desc\",\"digests\":{},\"operation\":\"deploy\",\"timestamp\":3}"
+	DUMMY_ERROR_MSG = "dummy_errors"
+)
+
+var (
+	digests = map[string]string{
+		"test_image:1.0": "test_image@sha256:1234",
+	}
+	revisions = map[string]interface{}{
+		REVISION_2_KEY:            REVISION_2_JSON,
+		OTHER_APPID + "/00000005": OTHER_JSON,
+		REVISION_1_KEY:            REVISION_1_JSON,
+	}
+	dummy_error = errors.NotFound{Msg: DUMMY_ERROR_MSG}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(TIMESTAMP, 0) }
+}
+
+// Expects an update transaction of which bucket is served by dbMockObj.
+func expectUpdate(mockCtrl *gomock.Controller, dbMockObj *dbmocks.MockDatabase) *gomock.Call {
+	txMockObj := dbmocks.NewMockTx(mockCtrl)
+	txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(dbMockObj).AnyTimes()
+	return dbMockObj.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(wrapper.Tx) error) error {
+		return fn(txMockObj)
+	})
+}
+
+func TestCalledInsertRevision_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().List().Return(revisions, nil),
+		dbMockObj.EXPECT().Put([]byte(APPID+"/00000003"), gomock.Any()).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.InsertRevision(APPID, DESCRIPTION, OPERATION, digests)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{
+		"appid":       APPID,
+		"revision":    3,
+		"description": DESCRIPTION,
+		"digests":     digests,
+		"operation":   OPERATION,
+		"timestamp":   int64(TIMESTAMP),
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledInsertRevisionWithEmptyAppId_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.InsertRevision("", DESCRIPTION, OPERATION, digests)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledInsertRevisionWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().List().Return(nil, nil),
+		dbMockObj.EXPECT().Put([]byte(REVISION_1_KEY), gomock.Any()).Return(dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.InsertRevision(APPID, DESCRIPTION, OPERATION, digests)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetRevisions_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(revisions, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetRevisions(APPID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if len(res) != 2 || res[0]["revision"] != 1 || res[1]["revision"] != 2 {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetRevisionsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.GetRevisions(APPID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetRevision_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(REVISION_2_KEY)).Return([]byte(REVISION_2_JSON), nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetRevision(APPID, 2)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res["description"] != "desc2" || res["operation"] != "update" {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetRevisionWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(REVISION_2_KEY)).Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.GetRevision(APPID, 2)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeleteRevisions_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(revisions, nil),
+		dbMockObj.EXPECT().Delete([]byte(REVISION_1_KEY)).Return(nil),
+		dbMockObj.EXPECT().Delete([]byte(REVISION_2_KEY)).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.DeleteRevisions(APPID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
@@ -0,0 +1,238 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package outbox
+
+import (
+	"commons/errors"
+	"commons/logger"
+	. "db/bolt/wrapper"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Interface of Outbox model's operations.
+type Command interface {
+	// InsertMessage appends a new outbound message to the end of the queue.
+	InsertMessage(key string, method string, url string, body []byte) (map[string]interface{}, error)
+
+	// GetMessages returns all of queued messages in the order they were inserted.
+	GetMessages() ([]map[string]interface{}, error)
+
+	// UpdateMessage updates delivery state of a queued message.
+	UpdateMessage(id string, attempts int, next_attempt int64, last_error string) error
+
+	// DeleteMessage deletes a queued message.
+	DeleteMessage(id string) error
+}
+
+const (
+	BUCKET_NAME = "outbox"
+)
+
+type Message struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Body        string `json:"body"`
+	Attempts    int    `json:"attempts"`
+	NextAttempt int64  `json:"nextattempt"`
+	LastError   string `json:"lasterror"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+type Executor struct {
+}
+
+var db Database
+var now = time.Now
+
+// lastSeq keeps message ids strictly increasing within a process
+// even if the clock does not advance between two insertions.
+var lastSeq int64
+var seqMutex = &sync.Mutex{}
+
+func init() {
+	db = NewBoltDB(BUCKET_NAME)
+}
+
+// Convert to map by object of struct Message.
+// will return Message information as map.
+func (msg Message) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          msg.ID,
+		"key":         msg.Key,
+		"method":      msg.Method,
+		"url":         msg.URL,
+		"body":        msg.Body,
+		"attempts":    msg.Attempts,
+		"nextattempt": msg.NextAttempt,
+		"lasterror":   msg.LastError,
+		"timestamp":   msg.Timestamp,
+	}
+}
+
+func (msg Message) encode() ([]byte, error) {
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return encoded, nil
+}
+
+func decode(data []byte) (*Message, error) {
+	var msg *Message
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return msg, nil
+}
+
+// Add a new message to outbox collection.
+// if succeed to add, return message information as map.
+// otherwise, return error.
+func (Executor) InsertMessage(key string, method string, url string, body []byte) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(method) == 0 || len(url) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : method or url is empty."}
+		return nil, err
+	}
+
+	current := now()
+	msg := Message{
+		ID:          makeID(current),
+		Key:         key,
+		Method:      method,
+		URL:         url,
+		Body:        string(body),
+		Attempts:    0,
+		NextAttempt: current.Unix(),
+		Timestamp:   current.Unix(),
+	}
+
+	encoded, err := msg.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Put([]byte(msg.ID), encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.convertToMap(), nil
+}
+
+// Getting all of messages.
+// if succeed to get, return list of messages sorted by id.
+// otherwise, return error.
+func (Executor) GetMessages() ([]map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	values, err := db.List()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0)
+	for _, value := range values {
+		msg, err := decode([]byte(value.(string)))
+		if err != nil {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+
+	result := make([]map[string]interface{}, 0)
+	for _, msg := range messages {
+		result = append(result, msg.convertToMap())
+	}
+	return result, nil
+}
+
+// Updating delivery state of a message.
+// if succeed to update, return error as nil.
+// otherwise, return error.
+func (Executor) UpdateMessage(id string, attempts int, next_attempt int64, last_error string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return err
+	}
+
+	value, err := db.Get([]byte(id))
+	if err != nil {
+		return err
+	}
+
+	msg, err := decode(value)
+	if err != nil {
+		return err
+	}
+
+	msg.Attempts = attempts
+	msg.NextAttempt = next_attempt
+	msg.LastError = last_error
+
+	encoded, err := msg.encode()
+	if err != nil {
+		return err
+	}
+	return db.Put([]byte(id), encoded)
+}
+
+// Deleting a message by id.
+// if succeed to delete, return error as nil.
+// otherwise, return error.
+func (Executor) DeleteMessage(id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return err
+	}
+
+	return db.Delete([]byte(id))
+}
+
+// Id is made of zero-padded nanoseconds
+// so that messages are kept in insertion order in the bucket.
+func makeID(current time.Time) string {
+	seqMutex.Lock()
+	defer seqMutex.Unlock()
+
+	seq := current.UnixNano()
+	if seq <= lastSeq {
+		seq = lastSeq + 1
+	}
+	lastSeq = seq
+	return fmt.Sprintf("%020d", seq)
+}
@@ -0,0 +1,257 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package outbox
+
+import (
+	"commons/errors"
+	dbmocks "db/bolt/wrapper/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	KEY             = "test_app_id"
+	METHOD          = "POST"
+	URL             = "http://127.0.0.1:48099/api/v1/notification/events"
+	BODY            = "{\"event\":\"test_event\"}"
+	TIMESTAMP       = 1500000000
+	MESSAGE_1_ID    = "00000000000000000001"
+	MESSAGE_2_ID    = "00000000000000000002"
+	MESSAGE_1_JSON  = "{\"id\":\"00000000000000000001\",\"key\":\"test_app_id\",\"method\":\"POST\",\"url\":\"test_url\",\"body\":\"body1\",\"attempts\":0,\"nextattempt\":1,\"lasterror\":\"\",\"timestamp\":1}"
+	MESSAGE_2_JSON  = "{\"id\":\"00000000000000000002\",\"key\":\"ping\",\"method\":\"POST\",\"url\":\"test_url\",\"body\":\"body2\",\"attempts\":2,\"nextattempt\":5,\"lasterror\":\"error\",\"timestamp\":2}"
+	DUMMY_ERROR_MSG = "dummy_errors"
+)
+
+var (
+	messages = map[string]interface{}{
+		MESSAGE_2_ID: MESSAGE_2_JSON,
+		MESSAGE_1_ID: MESSAGE_1_JSON,
+	}
+	dummy_error = errors.NotFound{Msg: DUMMY_ERROR_MSG}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(TIMESTAMP, 0) }
+}
+
+func TestCalledInsertMessage_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.InsertMessage(KEY, METHOD, URL, []byte(BODY))
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{
+		"id":          res["id"],
+		"key":         KEY,
+		"method":      METHOD,
+		"url":         URL,
+		"body":        BODY,
+		"attempts":    0,
+		"nextattempt": int64(TIMESTAMP),
+		"lasterror":   "",
+		"timestamp":   int64(TIMESTAMP),
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledInsertMessageTwiceAtSameTime_ExpectIncreasingId(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil),
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	first, _ := executor.InsertMessage(KEY, METHOD, URL, []byte(BODY))
+	second, _ := executor.InsertMessage(KEY, METHOD, URL, []byte(BODY))
+
+	if first["id"].(string) >= second["id"].(string) {
+		t.Errorf("Expected %s to be less than %s", first["id"], second["id"])
+	}
+}
+
+func TestCalledInsertMessageWithEmptyUrl_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.InsertMessage(KEY, METHOD, "", []byte(BODY))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledInsertMessageWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Return(dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.InsertMessage(KEY, METHOD, URL, []byte(BODY))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetMessages_ExpectSortedList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(messages, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetMessages()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if len(res) != 2 {
+		t.Fatalf("Expected 2 messages, actual %d", len(res))
+	}
+
+	if res[0]["id"] != MESSAGE_1_ID || res[1]["id"] != MESSAGE_2_ID {
+		t.Errorf("Expected ascending order, actual %v", res)
+	}
+}
+
+func TestCalledUpdateMessage_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	expected, _ := Message{
+		ID:          MESSAGE_1_ID,
+		Key:         KEY,
+		Method:      METHOD,
+		URL:         "test_url",
+		Body:        "body1",
+		Attempts:    1,
+		NextAttempt: 10,
+		LastError:   DUMMY_ERROR_MSG,
+		Timestamp:   1,
+	}.encode()
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(MESSAGE_1_ID)).Return([]byte(MESSAGE_1_JSON), nil),
+		dbMockObj.EXPECT().Put([]byte(MESSAGE_1_ID), expected).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.UpdateMessage(MESSAGE_1_ID, 1, 10, DUMMY_ERROR_MSG)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUpdateMessageWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(MESSAGE_1_ID)).Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.UpdateMessage(MESSAGE_1_ID, 1, 10, DUMMY_ERROR_MSG)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeleteMessage_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Delete([]byte(MESSAGE_1_ID)).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.DeleteMessage(MESSAGE_1_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteMessageWithEmptyId_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	err := executor.DeleteMessage("")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
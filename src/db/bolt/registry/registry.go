@@ -0,0 +1,489 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package registry provides persistence of private registry credentials.
+// records are encrypted with AES-GCM before stored, the key is kept
+// in a file beside the database so that the database alone doesn't expose them.
+package registry
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	. "db/bolt/wrapper"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Interface of Registry model's operations.
+type Command interface {
+	// InsertRegistry adds credentials of a registry.
+	InsertRegistry(host string, username string, password string) (map[string]interface{}, error)
+
+	// GetRegistries returns all of registries without passwords.
+	GetRegistries() ([]map[string]interface{}, error)
+
+	// GetRegistry returns a registry without password.
+	GetRegistry(id string) (map[string]interface{}, error)
+
+	// GetCredential returns credentials of a registry host with password.
+	GetCredential(host string) (map[string]interface{}, error)
+
+	// UpdateRegistry replaces credentials of a registry.
+	UpdateRegistry(id string, username string, password string) (map[string]interface{}, error)
+
+	// DeleteRegistry deletes a registry.
+	DeleteRegistry(id string) error
+
+	// ExportRegistries returns encrypted records of all registries
+	// with the key which they are encrypted with.
+	ExportRegistries() ([][]byte, []byte, error)
+
+	// ImportRegistries stores credentials of records encrypted with the key,
+	// and returns hosts of imported registries.
+	ImportRegistries(records [][]byte, key []byte) ([]string, error)
+}
+
+const (
+	BUCKET_NAME = "registry"
+	ID_LENGTH   = 12
+	KEY_LENGTH  = 32
+	KEY_FILE    = "registry.key"
+	KEY_MODE    = 0600
+)
+
+type Registry struct {
+	ID        string `json:"id"`
+	Host      string `json:"host"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type Executor struct {
+}
+
+var db Database
+var now = time.Now
+var loadKey = loadOrCreateKey
+var keyPath = getKeyPath
+
+func init() {
+	db = NewBoltDB(BUCKET_NAME)
+}
+
+// Convert to map by object of struct Registry.
+// password is never included.
+func (registry Registry) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        registry.ID,
+		"host":      registry.Host,
+		"username":  registry.Username,
+		"timestamp": registry.Timestamp,
+	}
+}
+
+// Encode a registry to JSON and encrypt it.
+func (registry Registry) encode() ([]byte, error) {
+	encoded, err := json.Marshal(registry)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, errors.Unknown{Msg: err.Error()}
+	}
+	return gcm.Seal(nonce, nonce, encoded, nil), nil
+}
+
+// Decrypt data and decode it to a registry.
+func decode(data []byte) (*Registry, error) {
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	return decodeWithKey(data, key)
+}
+
+// Decrypt data with the key and decode it to a registry.
+func decodeWithKey(data []byte, key []byte) (*Registry, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.InvalidJSON{Msg: "encrypted data is too short"}
+	}
+	decrypted, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: "decryption failed : " + err.Error()}
+	}
+
+	var registry *Registry
+	err = json.Unmarshal(decrypted, &registry)
+	if err != nil {
+		return nil, errors.InvalidJSON{Msg: err.Error()}
+	}
+	return registry, nil
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Unknown{Msg: err.Error()}
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Unknown{Msg: err.Error()}
+	}
+	return gcm, nil
+}
+
+// Returns the path of the key file beside the database,
+// which is known after the path of database is given by environments or flags.
+func getKeyPath() string {
+	return filepath.Join(filepath.Dir(Path()), KEY_FILE)
+}
+
+// Read the encryption key from the key file beside the database.
+// a new key is generated if the file doesn't exist.
+func loadOrCreateKey() ([]byte, error) {
+	key, err := ioutil.ReadFile(keyPath())
+	if err == nil {
+		if len(key) != KEY_LENGTH {
+			return nil, errors.IOError{Msg: "invalid key length of " + keyPath()}
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.IOError{Msg: err.Error()}
+	}
+
+	key = make([]byte, KEY_LENGTH)
+	_, err = io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, errors.Unknown{Msg: err.Error()}
+	}
+	err = ioutil.WriteFile(keyPath(), key, KEY_MODE)
+	if err != nil {
+		return nil, errors.IOError{Msg: err.Error()}
+	}
+	return key, nil
+}
+
+// Add credentials of a registry to registry collection.
+// a host can have only one credentials.
+// if succeed to add, return registry information as map.
+// otherwise, return error.
+func (Executor) InsertRegistry(host string, username string, password string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(host) == 0 || len(username) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : host or username is empty."}
+		return nil, err
+	}
+
+	registries, err := getRegistries()
+	if err != nil {
+		return nil, err
+	}
+	for _, registry := range registries {
+		if registry.Host == host {
+			return nil, errors.AlreadyReported{Msg: host + " is already registered"}
+		}
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := Registry{
+		ID:        id,
+		Host:      host,
+		Username:  username,
+		Password:  password,
+		Timestamp: now().Unix(),
+	}
+
+	encoded, err := registry.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Put([]byte(id), encoded)
+	if err != nil {
+		return nil, err
+	}
+	return registry.convertToMap(), nil
+}
+
+// Getting all of registries.
+// if succeed to get, return list of registries sorted by inserted time.
+// otherwise, return error.
+func (Executor) GetRegistries() ([]map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	registries, err := getRegistries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, registry := range registries {
+		result = append(result, registry.convertToMap())
+	}
+	return result, nil
+}
+
+// Getting a registry by id.
+// if succeed to get, return registry information as map.
+// otherwise, return error.
+func (Executor) GetRegistry(id string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	registry, err := getRegistry(id)
+	if err != nil {
+		return nil, err
+	}
+	return registry.convertToMap(), nil
+}
+
+// Getting credentials of a registry by host.
+// if succeed to get, return host, username and password as map.
+// otherwise, return error.
+func (Executor) GetCredential(host string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	registries, err := getRegistries()
+	if err != nil {
+		return nil, err
+	}
+	for _, registry := range registries {
+		if registry.Host == host {
+			return map[string]interface{}{
+				"host":     registry.Host,
+				"username": registry.Username,
+				"password": registry.Password,
+			}, nil
+		}
+	}
+	return nil, errors.NotFound{Msg: "credentials of " + host + " does not exist"}
+}
+
+// Updating credentials of a registry.
+// if succeed to update, return registry information as map.
+// otherwise, return error.
+func (Executor) UpdateRegistry(id string, username string, password string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(username) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : username is empty."}
+		return nil, err
+	}
+
+	registry, err := getRegistry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	registry.Username = username
+	registry.Password = password
+
+	encoded, err := registry.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Put([]byte(id), encoded)
+	if err != nil {
+		return nil, err
+	}
+	return registry.convertToMap(), nil
+}
+
+// Deleting a registry by id.
+// if succeed to delete, return error as nil.
+// otherwise, return error.
+func (Executor) DeleteRegistry(id string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return err
+	}
+
+	_, err := db.Get([]byte(id))
+	if err != nil {
+		return err
+	}
+	return db.Delete([]byte(id))
+}
+
+// Exporting encrypted records of all registries with the encryption key,
+// so that they can be imported on another node.
+// if succeed to export, return records and the key.
+// otherwise, return error.
+func (Executor) ExportRegistries() ([][]byte, []byte, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	key, err := loadKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := db.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]string, 0)
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	records := make([][]byte, 0)
+	for _, id := range ids {
+		records = append(records, []byte(values[id].(string)))
+	}
+	return records, key, nil
+}
+
+// Importing records exported from another node with its key.
+// records are encrypted again with the key of this node, and credentials
+// of a host already registered are replaced.
+// records which can't be decrypted are left out.
+// if succeed to import, return hosts of imported registries.
+// otherwise, return error.
+func (Executor) ImportRegistries(records [][]byte, key []byte) ([]string, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(key) != KEY_LENGTH {
+		return nil, errors.InvalidParam{Msg: "Invalid param error : invalid key length."}
+	}
+
+	registries, err := getRegistries()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string)
+	for _, registry := range registries {
+		ids[registry.Host] = registry.ID
+	}
+
+	hosts := make([]string, 0)
+	for _, record := range records {
+		registry, err := decodeWithKey(record, key)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		if id, exists := ids[registry.Host]; exists {
+			registry.ID = id
+		}
+
+		encoded, err := registry.encode()
+		if err != nil {
+			return nil, err
+		}
+
+		err = db.Put([]byte(registry.ID), encoded)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, registry.Host)
+	}
+	return hosts, nil
+}
+
+func getRegistry(id string) (*Registry, error) {
+	if len(id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : id is empty."}
+		return nil, err
+	}
+
+	value, err := db.Get([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+	return decode(value)
+}
+
+// Returns all of registries sorted by inserted time.
+// records which can't be decrypted are left out.
+func getRegistries() ([]*Registry, error) {
+	values, err := db.List()
+	if err != nil {
+		return nil, err
+	}
+
+	registries := make([]*Registry, 0)
+	for _, value := range values {
+		registry, err := decode([]byte(value.(string)))
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			continue
+		}
+		registries = append(registries, registry)
+	}
+
+	sort.Slice(registries, func(i, j int) bool {
+		if registries[i].Timestamp != registries[j].Timestamp {
+			return registries[i].Timestamp < registries[j].Timestamp
+		}
+		return registries[i].ID < registries[j].ID
+	})
+	return registries, nil
+}
+
+func generateID() (string, error) {
+	bytes := make([]byte, ID_LENGTH)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", errors.Unknown{Msg: err.Error()}
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,447 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package registry
+
+import (
+	"bytes"
+	"commons/errors"
+	dbmocks "db/bolt/wrapper/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	HOST            = "test_url:5000"
+	USERNAME        = "test_user"
+	PASSWORD        = "test_password"
+	TIMESTAMP       = 1500000000
+	REGISTRY_1_ID   = "000000000000000000000001"
+	REGISTRY_2_ID   = "000000000000000000000002"
+	DUMMY_ERROR_MSG = "dummy_errors"
+)
+
+var (
+	testKey     = bytes.Repeat([]byte{1}, KEY_LENGTH)
+	dummy_error = errors.NotFound{Msg: DUMMY_ERROR_MSG}
+	registry1   = Registry{ID: REGISTRY_1_ID, Host: HOST, Username: USERNAME, Password: PASSWORD, Timestamp: 1}
+	registry2   = Registry{ID: REGISTRY_2_ID, Host: "docker.io", Username: "other_user", Password: "other_password", Timestamp: 2}
+)
+
+func init() {
+	now = func() time.Time { return time.Unix(TIMESTAMP, 0) }
+	loadKey = func() ([]byte, error) { return testKey, nil }
+}
+
+func encrypt(t *testing.T, registry Registry) string {
+	encoded, err := registry.encode()
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	return string(encoded)
+}
+
+func TestCalledInsertRegistry_ExpectEncryptedRecordStored(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	var stored []byte
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{}, nil),
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(key []byte, value []byte) {
+			stored = value
+		}).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.InsertRegistry(HOST, USERNAME, PASSWORD)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{
+		"id":        res["id"],
+		"host":      HOST,
+		"username":  USERNAME,
+		"timestamp": int64(TIMESTAMP),
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+
+	if bytes.Contains(stored, []byte(PASSWORD)) || bytes.Contains(stored, []byte(USERNAME)) {
+		t.Errorf("Credentials are stored in plain text")
+	}
+	registry, err := decode(stored)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	} else if registry.Password != PASSWORD {
+		t.Errorf("Expected password: %s, actual password: %s", PASSWORD, registry.Password)
+	}
+}
+
+func TestCalledInsertRegistryWithEmptyHost_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.InsertRegistry("", USERNAME, PASSWORD)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledInsertRegistryWithRegisteredHost_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{REGISTRY_1_ID: encrypt(t, registry1)}, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.InsertRegistry(HOST, USERNAME, PASSWORD)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "AlreadyReported", err)
+	case errors.AlreadyReported:
+	}
+}
+
+func TestCalledGetRegistries_ExpectSortedWithoutPassword(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{
+			REGISTRY_2_ID: encrypt(t, registry2),
+			REGISTRY_1_ID: encrypt(t, registry1),
+			"broken":      "broken",
+		}, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetRegistries()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := []map[string]interface{}{registry1.convertToMap(), registry2.convertToMap()}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+	for _, registry := range res {
+		if _, exists := registry["password"]; exists {
+			t.Errorf("Unexpected password in %v", registry)
+		}
+	}
+}
+
+func TestCalledGetRegistryWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(REGISTRY_1_ID)).Return(nil, dummy_error),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.GetRegistry(REGISTRY_1_ID)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetCredential_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{
+			REGISTRY_1_ID: encrypt(t, registry1),
+			REGISTRY_2_ID: encrypt(t, registry2),
+		}, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.GetCredential(HOST)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expectedRes := map[string]interface{}{
+		"host":     HOST,
+		"username": USERNAME,
+		"password": PASSWORD,
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetCredentialWithUnknownHost_ExpectErrorReturn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{REGISTRY_2_ID: encrypt(t, registry2)}, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	_, err := executor.GetCredential(HOST)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledUpdateRegistry_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	newPassword := "new_password"
+	var stored []byte
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(REGISTRY_1_ID)).Return([]byte(encrypt(t, registry1)), nil),
+		dbMockObj.EXPECT().Put([]byte(REGISTRY_1_ID), gomock.Any()).Do(func(key []byte, value []byte) {
+			stored = value
+		}).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	res, err := executor.UpdateRegistry(REGISTRY_1_ID, USERNAME, newPassword)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(registry1.convertToMap(), res) {
+		t.Errorf("Expected res: %v, actual res: %v", registry1.convertToMap(), res)
+	}
+
+	registry, err := decode(stored)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	} else if registry.Password != newPassword {
+		t.Errorf("Expected password: %s, actual password: %s", newPassword, registry.Password)
+	}
+}
+
+func TestCalledDeleteRegistry_ExpectSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		dbMockObj.EXPECT().Get([]byte(REGISTRY_1_ID)).Return([]byte(encrypt(t, registry1)), nil),
+		dbMockObj.EXPECT().Delete([]byte(REGISTRY_1_ID)).Return(nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	err := executor.DeleteRegistry(REGISTRY_1_ID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledExportRegistries_ExpectRecordsAndKeyReturned(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	encrypted1, encrypted2 := encrypt(t, registry1), encrypt(t, registry2)
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{
+			REGISTRY_2_ID: encrypted2,
+			REGISTRY_1_ID: encrypted1,
+		}, nil),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	records, key, err := executor.ExportRegistries()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expectedRecords := [][]byte{[]byte(encrypted1), []byte(encrypted2)}
+	if !reflect.DeepEqual(expectedRecords, records) {
+		t.Errorf("Expected records: %v, actual records: %v", expectedRecords, records)
+	}
+	if !bytes.Equal(testKey, key) {
+		t.Errorf("Expected key: %v, actual key: %v", testKey, key)
+	}
+}
+
+func TestCalledImportRegistries_ExpectEncryptedWithOwnKeyAndRegisteredHostReplaced(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	dbMockObj := dbmocks.NewMockDatabase(mockCtrl)
+
+	otherKey := bytes.Repeat([]byte{2}, KEY_LENGTH)
+	loadKey = func() ([]byte, error) { return otherKey, nil }
+	exported1 := encrypt(t, Registry{ID: "exported", Host: HOST, Username: "new_user", Password: "new_password"})
+	exported2 := encrypt(t, registry2)
+	loadKey = func() ([]byte, error) { return testKey, nil }
+
+	stored := make(map[string][]byte)
+	gomock.InOrder(
+		dbMockObj.EXPECT().List().Return(map[string]interface{}{REGISTRY_1_ID: encrypt(t, registry1)}, nil),
+		dbMockObj.EXPECT().Put(gomock.Any(), gomock.Any()).Do(func(key []byte, value []byte) {
+			stored[string(key)] = value
+		}).Return(nil).Times(2),
+	)
+
+	db = dbMockObj
+	executor := Executor{}
+
+	hosts, err := executor.ImportRegistries([][]byte{[]byte(exported1), []byte("broken"), []byte(exported2)}, otherKey)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expectedHosts := []string{HOST, registry2.Host}
+	if !reflect.DeepEqual(expectedHosts, hosts) {
+		t.Errorf("Expected hosts: %v, actual hosts: %v", expectedHosts, hosts)
+	}
+
+	registry, err := decode(stored[REGISTRY_1_ID])
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	if registry.Username != "new_user" || registry.Password != "new_password" {
+		t.Errorf("Expected credentials are replaced, actual registry: %v", registry)
+	}
+	registry, err = decode(stored[REGISTRY_2_ID])
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(registry2, *registry) {
+		t.Errorf("Expected registry: %v, actual registry: %v", registry2, *registry)
+	}
+}
+
+func TestCalledImportRegistriesWithInvalidKey_ExpectErrorReturn(t *testing.T) {
+	executor := Executor{}
+
+	_, err := executor.ImportRegistries([][]byte{}, []byte("short"))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestDecodeWithWrongKey_ExpectErrorReturn(t *testing.T) {
+	encrypted := encrypt(t, registry1)
+
+	loadKey = func() ([]byte, error) { return bytes.Repeat([]byte{2}, KEY_LENGTH), nil }
+	defer func() { loadKey = func() ([]byte, error) { return testKey, nil } }()
+
+	_, err := decode([]byte(encrypted))
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestLoadOrCreateKey_ExpectSameKeyReturned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	origin := keyPath
+	keyPath = func() string { return filepath.Join(dir, KEY_FILE) }
+	defer func() { keyPath = origin }()
+
+	created, err := loadOrCreateKey()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if len(created) != KEY_LENGTH {
+		t.Errorf("Unexpected key length: %d", len(created))
+	}
+
+	info, err := os.Stat(keyPath())
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	} else if info.Mode().Perm() != KEY_MODE {
+		t.Errorf("Unexpected key file mode: %v", info.Mode())
+	}
+
+	loaded, err := loadOrCreateKey()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !bytes.Equal(created, loaded) {
+		t.Errorf("Expected the same key to be loaded")
+	}
+}
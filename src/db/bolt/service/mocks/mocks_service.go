@@ -45,6 +45,19 @@ func (mr *MockCommandMockRecorder) InsertComposeFile(description, state interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertComposeFile", reflect.TypeOf((*MockCommand)(nil).InsertComposeFile), description, state)
 }
 
+// InsertRecoveredApp mocks base method
+func (m *MockCommand) InsertRecoveredApp(app_id, description, state string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "InsertRecoveredApp", app_id, description, state)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRecoveredApp indicates an expected call of InsertRecoveredApp
+func (mr *MockCommandMockRecorder) InsertRecoveredApp(app_id, description, state interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecoveredApp", reflect.TypeOf((*MockCommand)(nil).InsertRecoveredApp), app_id, description, state)
+}
+
 // GetAppList mocks base method
 func (m *MockCommand) GetAppList() ([]map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAppList")
@@ -118,3 +131,27 @@ func (m *MockCommand) UpdateAppEvent(app_id, repo, tag, event string) error {
 func (mr *MockCommandMockRecorder) UpdateAppEvent(app_id, repo, tag, event interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppEvent", reflect.TypeOf((*MockCommand)(nil).UpdateAppEvent), app_id, repo, tag, event)
 }
+
+// UpdateAppPolicy mocks base method
+func (m *MockCommand) UpdateAppPolicy(app_id string, policy map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "UpdateAppPolicy", app_id, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppPolicy indicates an expected call of UpdateAppPolicy
+func (mr *MockCommandMockRecorder) UpdateAppPolicy(app_id, policy interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppPolicy", reflect.TypeOf((*MockCommand)(nil).UpdateAppPolicy), app_id, policy)
+}
+
+// UpdateAppReplicas mocks base method
+func (m *MockCommand) UpdateAppReplicas(app_id, service string, replicas int) error {
+	ret := m.ctrl.Call(m, "UpdateAppReplicas", app_id, service, replicas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppReplicas indicates an expected call of UpdateAppReplicas
+func (mr *MockCommandMockRecorder) UpdateAppReplicas(app_id, service, replicas interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppReplicas", reflect.TypeOf((*MockCommand)(nil).UpdateAppReplicas), app_id, service, replicas)
+}
@@ -21,6 +21,7 @@ import (
 	"commons/errors"
 	"commons/logger"
 	"crypto/sha1"
+	"db/bolt/event"
 	. "db/bolt/wrapper"
 	"encoding/hex"
 	"encoding/json"
@@ -33,6 +34,9 @@ type Command interface {
 	// InsertComposeFile insert docker-compose file for new service.
 	InsertComposeFile(description string, state string) (map[string]interface{}, error)
 
+	// InsertRecoveredApp inserts app rebuilt from containers of which original description is lost.
+	InsertRecoveredApp(app_id string, description string, state string) (map[string]interface{}, error)
+
 	// GetAppList returns all of app's IDs.
 	GetAppList() ([]map[string]interface{}, error)
 
@@ -50,20 +54,34 @@ type Command interface {
 
 	// UpdateAppEvent updates the last received event from docker registry.
 	UpdateAppEvent(app_id string, repo string, tag string, event string) error
+
+	// UpdateAppPolicy updates how changes of app's images are applied.
+	UpdateAppPolicy(app_id string, policy map[string]interface{}) error
+
+	// UpdateAppReplicas updates the number of containers of app's service.
+	UpdateAppReplicas(app_id string, service string, replicas int) error
 }
 
 const (
 	BUCKET_NAME    = "service"
 	SERVICES_FIELD = "services"
 	IMAGE_FIELD    = "image"
+	POLICY_FIELD   = "updatepolicy"
+	REPLICAS_FIELD = "replicas"
 	EVENT_NONE     = "none"
 )
 
 type App struct {
 	ID          string                   `json:"id"`
 	Description string                   `json:"description"`
-	State       string                   `json:"state"`
+	State       string                   `json:"state"` // running, exited, partially exited, paused, updating or rolledback
 	Images      []map[string]interface{} `json:"images"`
+
+	// Update policy of the app, nil if changes are applied manually.
+	UpdatePolicy map[string]interface{} `json:"updatepolicy,omitempty"`
+
+	// Number of containers of services scaled, nil if none of services is scaled.
+	Replicas map[string]int `json:"replicas,omitempty"`
 }
 
 type Executor struct {
@@ -78,12 +96,19 @@ func init() {
 // Convert to map by object of struct App.
 // will return App information as map.
 func (app App) convertToMap() map[string]interface{} {
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"id":          app.ID,
 		"description": app.Description,
 		"state":       app.State,
 		"images":      app.Images,
 	}
+	if app.UpdatePolicy != nil {
+		result[POLICY_FIELD] = app.UpdatePolicy
+	}
+	if app.Replicas != nil {
+		result[REPLICAS_FIELD] = app.Replicas
+	}
+	return result
 }
 
 func (app App) encode() ([]byte, error) {
@@ -115,37 +140,61 @@ func (Executor) InsertComposeFile(description string, state string) (map[string]
 		return nil, err
 	}
 
-	value, err := db.Get([]byte(id))
-	if err == nil {
-		app, err := decode(value)
-		if err == nil {
-			return app.convertToMap(), errors.AlreadyReported{Msg: id}
-		}
-	}
+	return insertApp(id, description, state)
+}
+
+// Inserting app rebuilt from containers after the database was recovered.
+// app_id is the compose project of containers, which is not the hash of
+// the rebuilt description since the original description is lost.
+// if succeed to insert, return app information.
+// if app_id already exists, return AlreadyReported error.
+func (Executor) InsertRecoveredApp(app_id string, description string, state string) (map[string]interface{}, error) {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	return insertApp(app_id, description, state)
+}
 
+// Inserts app of which id is given, unless it already exists.
+func insertApp(id string, description string, state string) (map[string]interface{}, error) {
 	images, err := getImageNames([]byte(description))
 	if err != nil {
 		return nil, err
 	}
 
-	installedApp := App{
-		ID:          id,
-		Description: description,
-		State:       state,
-		Images:      images,
-	}
+	var result map[string]interface{}
+	err = db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(BUCKET_NAME)
+		value, err := bucket.Get([]byte(id))
+		if err == nil {
+			app, err := decode(value)
+			if err == nil {
+				result = app.convertToMap()
+				return errors.AlreadyReported{Msg: id}
+			}
+		}
 
-	encoded, err := installedApp.encode()
-	if err != nil {
-		return nil, err
-	}
+		installedApp := App{
+			ID:          id,
+			Description: description,
+			State:       state,
+			Images:      images,
+		}
 
-	err = db.Put([]byte(id), encoded)
-	if err != nil {
-		return nil, err
-	}
+		encoded, err := installedApp.encode()
+		if err != nil {
+			return err
+		}
 
-	return installedApp.convertToMap(), nil
+		err = bucket.Put([]byte(id), encoded)
+		if err != nil {
+			return err
+		}
+
+		result = installedApp.convertToMap()
+		return nil
+	})
+	return result, err
 }
 
 // Getting all of app informations.
@@ -217,26 +266,14 @@ func (Executor) UpdateAppInfo(app_id string, description string) error {
 		return err
 	}
 
-	value, err := db.Get([]byte(app_id))
-	if err != nil {
-		return err
-	}
-
-	app, err := decode(value)
-	if err != nil {
-		return err
-	}
-
-	app.Description = description
-	encoded, err := app.encode()
-	if err != nil {
-		return err
-	}
-
-	return db.Put([]byte(app_id), encoded)
+	return updateApp(app_id, func(app *App) error {
+		app.Description = description
+		return nil
+	})
 }
 
 // Deleting app collection by app_id.
+// events subscribed for the app are deleted together.
 // if succeed to delete, return error as nil.
 // otherwise, return error.
 func (Executor) DeleteApp(app_id string) error {
@@ -248,7 +285,13 @@ func (Executor) DeleteApp(app_id string) error {
 		return err
 	}
 
-	return db.Delete([]byte(app_id))
+	return db.Update(func(tx Tx) error {
+		err := tx.Bucket(BUCKET_NAME).Delete([]byte(app_id))
+		if err != nil {
+			return err
+		}
+		return event.DeleteAppEvents(tx, app_id)
+	})
 }
 
 // Updating app state by app_id.
@@ -267,68 +310,110 @@ func (Executor) UpdateAppState(app_id string, state string) error {
 		return err
 	}
 
-	value, err := db.Get([]byte(app_id))
-	if err != nil {
-		return err
-	}
+	return updateApp(app_id, func(app *App) error {
+		app.State = state
+		return nil
+	})
+}
 
-	app, err := decode(value)
-	if err != nil {
-		return err
-	}
+func (Executor) UpdateAppEvent(app_id string, repo string, tag string, event string) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
 
-	app.State = state
-	encoded, err := app.encode()
-	if err != nil {
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{"Invalid param error : app_id is empty."}
 		return err
 	}
 
-	return db.Put([]byte(app_id), encoded)
+	return updateApp(app_id, func(app *App) error {
+		if len(app.Images) == 0 {
+			return errors.NotFound{Msg: "There is no matching image"}
+		}
+
+		// Find image specified by repo parameter.
+		for index, image := range app.Images {
+			if strings.Compare(image["name"].(string), repo) == 0 {
+				// If event type is none, delete 'changes' field.
+				if event == EVENT_NONE {
+					delete(app.Images[index], "changes")
+				} else {
+					newEvent := make(map[string]interface{})
+					newEvent["tag"] = tag
+					newEvent["status"] = event
+					app.Images[index]["changes"] = newEvent
+				}
+			}
+		}
+		return nil
+	})
 }
 
-func (Executor) UpdateAppEvent(app_id string, repo string, tag string, event string) error {
+// Updating update policy of app by app_id.
+// if succeed to update policy, return error as nil.
+// otherwise, return error.
+func (Executor) UpdateAppPolicy(app_id string, policy map[string]interface{}) error {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
 	if len(app_id) == 0 {
-		err := errors.InvalidParam{"Invalid param error : app_id is empty."}
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
 		return err
 	}
 
-	value, err := db.Get([]byte(app_id))
-	if err != nil {
-		return err
-	}
+	return updateApp(app_id, func(app *App) error {
+		app.UpdatePolicy = policy
+		return nil
+	})
+}
 
-	app, err := decode(value)
-	if err != nil {
+// Updating the number of containers of a service of app by app_id.
+// if succeed to update replicas, return error as nil.
+// otherwise, return error.
+func (Executor) UpdateAppReplicas(app_id string, service string, replicas int) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if len(app_id) == 0 {
+		err := errors.InvalidParam{Msg: "Invalid param error : app_id is empty."}
 		return err
 	}
 
-	// Find image specified by repo parameter.
-	for index, image := range app.Images {
-		if strings.Compare(image["name"].(string), repo) == 0 {
-			// If event type is none, delete 'changes' field.
-			if event == EVENT_NONE {
-				delete(app.Images[index], "changes")
-			} else {
-				newEvent := make(map[string]interface{})
-				newEvent["tag"] = tag
-				newEvent["status"] = event
-				app.Images[index]["changes"] = newEvent
-			}
+	return updateApp(app_id, func(app *App) error {
+		if app.Replicas == nil {
+			app.Replicas = make(map[string]int)
 		}
+		app.Replicas[service] = replicas
+		return nil
+	})
+}
 
-		// Save the changes to database.
-		encoded, err := app.encode()
+// Reads app by app_id, modifies it by update and writes it back
+// in a single transaction, so that concurrent updates are not lost.
+func updateApp(app_id string, update func(app *App) error) error {
+	return db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(BUCKET_NAME)
+		value, err := bucket.Get([]byte(app_id))
 		if err != nil {
 			return err
 		}
 
-		return db.Put([]byte(app_id), encoded)
-	}
+		app, err := decode(value)
+		if err != nil {
+			return err
+		}
+
+		err = update(app)
+		if err != nil {
+			return err
+		}
+
+		encoded, err := app.encode()
+		if err != nil {
+			return err
+		}
 
-	return errors.NotFound{Msg: "There is no matching image"}
+		return bucket.Put([]byte(app_id), encoded)
+	})
 }
 
 // Generating app_id using hash of description
@@ -18,6 +18,8 @@ package service
 
 import (
 	"commons/errors"
+	"db/bolt/event"
+	"db/bolt/wrapper"
 	"db/bolt/wrapper/mocks"
 	"encoding/json"
 	gomock "github.com/golang/mock/gomock"
@@ -54,8 +56,20 @@ var (
 		"images":      []map[string]interface{}{image},
 		"state":       VALID_STATE,
 	}
+	POLICY = map[string]interface{}{
+		"mode": "immediate",
+	}
 )
 
+// Expects an update transaction of which bucket is served by dbMockObj.
+func expectUpdate(mockCtrl *gomock.Controller, dbMockObj *mocks.MockDatabase) *gomock.Call {
+	txMockObj := mocks.NewMockTx(mockCtrl)
+	txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(dbMockObj).AnyTimes()
+	return dbMockObj.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(wrapper.Tx) error) error {
+		return fn(txMockObj)
+	})
+}
+
 func TestCalled_InsertComposeFile_WithEmptyDescription_ExpectErrorReturn(t *testing.T) {
 	dbExecutor := Executor{}
 
@@ -118,6 +132,7 @@ func TestCalled_InsertComposeFile_WithValidDescription_ExpectSuccess(t *testing.
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(nil, dummy_error),
 		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Return(nil),
 	)
@@ -151,6 +166,7 @@ func TestCalled_InsertComposeFile_WhenAlreadyExistsInDB_ExpectSuccess(t *testing
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
 	)
 	db = dbMockObj
@@ -169,6 +185,40 @@ func TestCalled_InsertComposeFile_WhenAlreadyExistsInDB_ExpectSuccess(t *testing
 	}
 }
 
+func TestCalled_InsertRecoveredApp_ExpectInsertedWithGivenId(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const RECOVERED_APPID = "recovered_app_id"
+
+	dbMockObj := mocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().Get([]byte(RECOVERED_APPID)).Return(nil, dummy_error),
+		dbMockObj.EXPECT().Put([]byte(RECOVERED_APPID), gomock.Any()).Return(nil),
+	)
+	db = dbMockObj
+	dbExecutor := Executor{}
+
+	expectedRes := map[string]interface{}{
+		"id":          RECOVERED_APPID,
+		"description": VALID_DESCRIPTION,
+		"images":      []map[string]interface{}{image},
+		"state":       VALID_STATE,
+	}
+
+	res, err := dbExecutor.InsertRecoveredApp(RECOVERED_APPID, VALID_DESCRIPTION, VALID_STATE)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
 func TestCalled_GetAppList_ExpectErrorReturn(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -318,6 +368,7 @@ func TestCalled_UpdateAppInfo_WhenDBHasNotMatchedApp_ExpectErrorReturn(t *testin
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(nil, dummy_error),
 	)
 
@@ -346,6 +397,7 @@ func TestCalled_UpdateAppInfo_WhenDBHasMatchedApp_ExpectErrorReturn(t *testing.T
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
 		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Return(nil),
 	)
@@ -382,6 +434,7 @@ func TestCalled_UpdateAppState_WhenDBHasNotMatchedApp_ExpectErrorReturn(t *testi
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(nil, dummy_error),
 	)
 
@@ -410,6 +463,7 @@ func TestCalled_UpdateAppState_WhenDBHasMatchedApp_ExpectErrorReturn(t *testing.
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
 		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Return(nil),
 	)
@@ -446,6 +500,7 @@ func TestCalled_UpdateAppEVENT_WhenDBHasNotMatchedApp_ExpectErrorReturn(t *testi
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(nil, dummy_error),
 	)
 
@@ -474,6 +529,7 @@ func TestCalled_UpdateAppEVENT_WhenDBHasMatchedApp_ExpectErrorReturn(t *testing.
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
 
 	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
 		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
 		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Return(nil),
 	)
@@ -487,6 +543,93 @@ func TestCalled_UpdateAppEVENT_WhenDBHasMatchedApp_ExpectErrorReturn(t *testing.
 	}
 }
 
+func TestCalled_UpdateAppPolicy_WithInvalidAppID_ExpectErrorReturn(t *testing.T) {
+	dbExecutor := Executor{}
+
+	err := dbExecutor.UpdateAppPolicy(INVALID_APPID, POLICY)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParamError", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalled_UpdateAppPolicy_WhenDBHasMatchedApp_ExpectPolicyReturnedByGetApp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	returnedService, _ := json.Marshal(service)
+	var updatedService []byte
+
+	dbMockObj := mocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
+		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Do(func(key []byte, value []byte) {
+			updatedService = value
+		}).Return(nil),
+	)
+
+	db = dbMockObj
+	dbExecutor := Executor{}
+	err := dbExecutor.UpdateAppPolicy(VALID_APPID, POLICY)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(updatedService, nil)
+
+	res, err := dbExecutor.GetApp(VALID_APPID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !reflect.DeepEqual(res[POLICY_FIELD], POLICY) {
+		t.Errorf("Expected policy: %v, actual policy: %v", POLICY, res[POLICY_FIELD])
+	}
+}
+
+func TestCalled_UpdateAppReplicas_WhenDBHasMatchedApp_ExpectReplicasReturnedByGetApp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	returnedService, _ := json.Marshal(service)
+	var updatedService []byte
+
+	dbMockObj := mocks.NewMockDatabase(mockCtrl)
+
+	gomock.InOrder(
+		expectUpdate(mockCtrl, dbMockObj),
+		dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return([]byte(returnedService), nil),
+		dbMockObj.EXPECT().Put([]byte(VALID_APPID), gomock.Any()).Do(func(key []byte, value []byte) {
+			updatedService = value
+		}).Return(nil),
+	)
+
+	db = dbMockObj
+	dbExecutor := Executor{}
+	err := dbExecutor.UpdateAppReplicas(VALID_APPID, "web", 3)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	dbMockObj.EXPECT().Get([]byte(VALID_APPID)).Return(updatedService, nil)
+
+	res, err := dbExecutor.GetApp(VALID_APPID)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]int{"web": 3}
+	if !reflect.DeepEqual(res[REPLICAS_FIELD], expected) {
+		t.Errorf("Expected replicas: %v, actual replicas: %v", expected, res[REPLICAS_FIELD])
+	}
+}
+
 func TestCalled_DeleteApp_WithInvlaidAppID_ExpectErrorReturn(t *testing.T) {
 	dbExecutor := Executor{}
 	err := dbExecutor.DeleteApp(INVALID_APPID)
@@ -507,9 +650,22 @@ func TestCalled_DeleteApp_WhenDBHasNotMatchedApp_ExpectErrorReturn(t *testing.T)
 	defer mockCtrl.Finish()
 
 	dbMockObj := mocks.NewMockDatabase(mockCtrl)
+	eventBucketMockObj := mocks.NewMockDatabase(mockCtrl)
+	txMockObj := mocks.NewMockTx(mockCtrl)
+
+	returnedEvents := map[string]interface{}{
+		"test_event_id": "{\"id\":\"test_event_id\",\"appid\":\"" + VALID_APPID + "\",\"imagename\":\"\"}",
+	}
 
 	gomock.InOrder(
+		dbMockObj.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(wrapper.Tx) error) error {
+			return fn(txMockObj)
+		}),
+		txMockObj.EXPECT().Bucket(BUCKET_NAME).Return(dbMockObj),
 		dbMockObj.EXPECT().Delete([]byte(VALID_APPID)).Return(nil),
+		txMockObj.EXPECT().Bucket(event.BUCKET_NAME).Return(eventBucketMockObj),
+		eventBucketMockObj.EXPECT().List().Return(returnedEvents, nil),
+		eventBucketMockObj.EXPECT().Delete([]byte("test_event_id")).Return(nil),
 	)
 
 	db = dbMockObj
@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"github.com/boltdb/bolt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Bucket holding metadata of database like the schema version.
+	META_BUCKET        = "meta"
+	SCHEMA_VERSION_KEY = "schemaversion"
+
+	// Environment variable which makes migrations run without being committed,
+	// if it is set to "true". the database is not served in dry-run.
+	MIGRATION_DRY_RUN = "DB_MIGRATION_DRY_RUN"
+)
+
+// Migration upgrades buckets stored by an older schema version to Version.
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(tx Tx) error
+}
+
+// Migrations ordered by version, which are run when the database is opened.
+// a migration should be added to the end of this list with the next version
+// whenever the shape of a stored value changes, and it should never be
+// modified once released since devices in the field may have run it already.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "initial schema of service, event and configuration buckets",
+		Migrate:     func(tx Tx) error { return nil },
+	},
+}
+
+// Error returned by the transaction of dry-run to roll back migrations.
+type dryRunError struct{}
+
+func (dryRunError) Error() string {
+	return "dry-run of migrations"
+}
+
+// Runs migrations newer than the schema version stored in meta bucket
+// in a single transaction, and updates the schema version.
+// if dryRun is true, changes of the migrations are rolled back.
+// if succeed to migrate, returns versions of run migrations.
+// otherwise, returns error and nothing is changed.
+func migrate(conn *bolt.DB, dryRun bool) ([]int, error) {
+	migrated := make([]int, 0)
+	err := conn.Update(func(btx *bolt.Tx) error {
+		tx := boltTx{tx: btx}
+		meta := tx.Bucket(META_BUCKET)
+
+		version, err := getSchemaVersion(meta)
+		if err != nil {
+			return err
+		}
+
+		latest := 0
+		if len(migrations) != 0 {
+			latest = migrations[len(migrations)-1].Version
+		}
+		if version > latest {
+			return errors.DBOperationError{Msg: "schema version " + strconv.Itoa(version) +
+				" is newer than supported version " + strconv.Itoa(latest)}
+		}
+
+		for _, migration := range migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			logger.Logging(logger.INFO, "migrate schema to version", strconv.Itoa(migration.Version), migration.Description)
+			err = migration.Migrate(tx)
+			if err != nil {
+				logger.Logging(logger.ERROR, "failed to migrate schema to version", strconv.Itoa(migration.Version), err.Error())
+				return err
+			}
+			version = migration.Version
+			migrated = append(migrated, version)
+		}
+
+		err = meta.Put([]byte(SCHEMA_VERSION_KEY), []byte(strconv.Itoa(version)))
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return dryRunError{}
+		}
+		return nil
+	})
+
+	switch err.(type) {
+	case nil:
+	case dryRunError:
+		logger.Logging(logger.INFO, "migrations are rolled back by dry-run")
+	default:
+		return nil, err
+	}
+	return migrated, nil
+}
+
+// Returns schema version stored in meta bucket,
+// database which has no schema version is regarded as version 0.
+func getSchemaVersion(meta Bucket) (int, error) {
+	value, err := meta.Get([]byte(SCHEMA_VERSION_KEY))
+	if err != nil {
+		switch err.(type) {
+		default:
+			return 0, err
+		case errors.NotFound:
+			return 0, nil
+		}
+	}
+
+	version, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, errors.DBOperationError{Msg: "invalid schema version : " + string(value)}
+	}
+	return version, nil
+}
+
+// DryRunMigrations runs migrations newer than the schema version of the database
+// and rolls them back, so that they can be tried before the node is upgraded.
+// the database is opened only for the dry-run and closed before it returns.
+// if succeed to run, returns versions of migrations which would be run.
+// otherwise, returns error.
+func DryRunMigrations() ([]int, error) {
+	handle.Lock()
+	defer handle.Unlock()
+
+	conn, err := openChecked(dbPath)
+	if err != nil {
+		if _, ok := err.(corruptError); ok {
+			return nil, errors.DBOperationError{Msg: err.Error()}
+		}
+		return nil, err
+	}
+	defer conn.Close()
+
+	return migrate(conn, true)
+}
+
+// IsMigrationDryRun returns whether migrations are run only by DryRunMigrations.
+func IsMigrationDryRun() bool {
+	return isMigrationDryRun()
+}
+
+func isMigrationDryRun() bool {
+	return strings.EqualFold(os.Getenv(MIGRATION_DRY_RUN), "true")
+}
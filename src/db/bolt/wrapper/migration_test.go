@@ -0,0 +1,179 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"commons/errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Uses migrations which put the version of each run migration to BUCKET.
+func setTestMigrations(versions ...int) func() {
+	origMigrations := migrations
+	migrations = make([]Migration, 0)
+	for _, version := range versions {
+		key := []byte(KEY + string(rune('0'+version)))
+		migrations = append(migrations, Migration{
+			Version: version,
+			Migrate: func(tx Tx) error {
+				return tx.Bucket(BUCKET).Put(key, []byte(VALUE))
+			},
+		})
+	}
+	return func() {
+		migrations = origMigrations
+	}
+}
+
+func getTestSchemaVersion(t *testing.T) int {
+	var version int
+	err := NewBoltDB(META_BUCKET).View(func(tx Tx) error {
+		var err error
+		version, err = getSchemaVersion(tx.Bucket(META_BUCKET))
+		return err
+	})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	return version
+}
+
+func TestOpenWithoutSchemaVersion_ExpectAllMigrationsRun(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1, 2)()
+
+	res, err := NewBoltDB(BUCKET).List()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{KEY + "1": VALUE, KEY + "2": VALUE}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+	if version := getTestSchemaVersion(t); version != 2 {
+		t.Errorf("Expected version: 2, actual version: %d", version)
+	}
+}
+
+func TestOpenWithOldSchemaVersion_ExpectOnlyNewerMigrationsRun(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1)()
+
+	NewBoltDB(BUCKET).Delete([]byte(KEY + "1"))
+	Close()
+	setTestMigrations(1, 2, 3)
+
+	res, err := NewBoltDB(BUCKET).List()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{KEY + "2": VALUE, KEY + "3": VALUE}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+	if version := getTestSchemaVersion(t); version != 3 {
+		t.Errorf("Expected version: 3, actual version: %d", version)
+	}
+}
+
+func TestOpenWithDryRun_ExpectDatabaseNotServed(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1, 2)()
+
+	os.Setenv(MIGRATION_DRY_RUN, "true")
+	defer os.Unsetenv(MIGRATION_DRY_RUN)
+
+	_, err := NewBoltDB(BUCKET).List()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
+func TestCalledDryRunMigrations_ExpectMigrationsReportedAndRolledBack(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1)()
+
+	NewBoltDB(BUCKET).Delete([]byte(KEY + "1"))
+	Close()
+	setTestMigrations(1, 2, 3)
+
+	for i := 0; i < 2; i++ {
+		versions, err := DryRunMigrations()
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+		if expected := []int{2, 3}; !reflect.DeepEqual(expected, versions) {
+			t.Errorf("Expected versions: %v, actual versions: %v", expected, versions)
+		}
+	}
+}
+
+func TestOpenWhenMigrationFailed_ExpectNothingChanged(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1)()
+
+	migrations = append(migrations, Migration{
+		Version: 2,
+		Migrate: func(tx Tx) error { return errors.InvalidJSON{} },
+	})
+
+	_, err := NewBoltDB(BUCKET).List()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+
+	migrations = migrations[:1]
+	migrations[0].Migrate = func(tx Tx) error { return nil }
+
+	res, err := NewBoltDB(BUCKET).List()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if len(res) != 0 {
+		t.Errorf("Expected empty res, actual res: %v", res)
+	}
+}
+
+func TestOpenWithNewerSchemaVersion_ExpectErrorReturn(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestMigrations(1, 2)()
+
+	NewBoltDB(BUCKET).List()
+	Close()
+	migrations = migrations[:1]
+
+	_, err := NewBoltDB(BUCKET).List()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
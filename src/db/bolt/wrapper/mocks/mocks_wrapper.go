@@ -21,6 +21,7 @@
 package mocks
 
 import (
+	wrapper "db/bolt/wrapper"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -97,3 +98,62 @@ func (m *MockDatabase) Delete(key []byte) error {
 func (mr *MockDatabaseMockRecorder) Delete(key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDatabase)(nil).Delete), key)
 }
+
+// View mocks base method
+func (m *MockDatabase) View(fn func(wrapper.Tx) error) error {
+	ret := m.ctrl.Call(m, "View", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// View indicates an expected call of View
+func (mr *MockDatabaseMockRecorder) View(fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "View", reflect.TypeOf((*MockDatabase)(nil).View), fn)
+}
+
+// Update mocks base method
+func (m *MockDatabase) Update(fn func(wrapper.Tx) error) error {
+	ret := m.ctrl.Call(m, "Update", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockDatabaseMockRecorder) Update(fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDatabase)(nil).Update), fn)
+}
+
+// MockTx is a mock of Tx interface
+type MockTx struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxMockRecorder
+}
+
+// MockTxMockRecorder is the mock recorder for MockTx
+type MockTxMockRecorder struct {
+	mock *MockTx
+}
+
+// NewMockTx creates a new mock instance
+func NewMockTx(ctrl *gomock.Controller) *MockTx {
+	mock := &MockTx{ctrl: ctrl}
+	mock.recorder = &MockTxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTx) EXPECT() *MockTxMockRecorder {
+	return m.recorder
+}
+
+// Bucket mocks base method
+func (m *MockTx) Bucket(name string) wrapper.Bucket {
+	ret := m.ctrl.Call(m, "Bucket", name)
+	ret0, _ := ret[0].(wrapper.Bucket)
+	return ret0
+}
+
+// Bucket indicates an expected call of Bucket
+func (mr *MockTxMockRecorder) Bucket(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bucket", reflect.TypeOf((*MockTx)(nil).Bucket), name)
+}
@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"encoding/json"
+	"fmt"
+	"github.com/boltdb/bolt"
+	"os"
+	"time"
+)
+
+const (
+	// Key of the record of the last recovery in META_BUCKET.
+	RECOVERY_KEY = "recovery"
+
+	// Corrupt database is renamed to its path followed by this suffix and time.
+	CORRUPT_SUFFIX = ".corrupt-"
+
+	RECOVERY_TIME   = "time"
+	RECOVERY_REASON = "reason"
+	CORRUPT_FILE    = "corruptfile"
+)
+
+// Error returned when the database file is corrupt.
+type corruptError struct {
+	reason string
+}
+
+func (e corruptError) Error() string {
+	return "database is corrupt : " + e.reason
+}
+
+var now = time.Now
+
+// Opens the database file and checks integrity of all pages in it.
+// bolt panics on some kinds of corrupt pages while opening, which is reported as corruptError too.
+func openChecked(path string) (conn *bolt.DB, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if conn != nil {
+				conn.Close()
+			}
+			conn, err = nil, corruptError{reason: fmt.Sprint(r)}
+		}
+	}()
+
+	conn, err = bolt.Open(path, PORT, &bolt.Options{Timeout: OPEN_TIMEOUT})
+	switch err {
+	case nil:
+	case bolt.ErrInvalid, bolt.ErrVersionMismatch, bolt.ErrChecksum:
+		return nil, corruptError{reason: err.Error()}
+	default:
+		return nil, errors.DBConnectionError{Msg: err.Error()}
+	}
+
+	err = check(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Returns corruptError with the first error found by the integrity check.
+func check(conn *bolt.DB) error {
+	var errs []error
+	err := conn.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return corruptError{reason: err.Error()}
+	}
+	if len(errs) != 0 {
+		return corruptError{reason: fmt.Sprintf("%s (%d errors found)", errs[0].Error(), len(errs))}
+	}
+	return nil
+}
+
+// Moves the corrupt database aside and creates a new one,
+// in which the recovery is recorded.
+func recoverCorruptDB(reason string) (*bolt.DB, error) {
+	logger.Logging(logger.ERROR, reason)
+
+	corruptFile := dbPath + CORRUPT_SUFFIX + now().UTC().Format("20060102T150405Z")
+	err := os.Rename(dbPath, corruptFile)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return nil, errors.DBConnectionError{Msg: reason}
+	}
+	logger.Logging(logger.INFO, "corrupt database is moved to", corruptFile)
+
+	conn, err := bolt.Open(dbPath, PORT, &bolt.Options{Timeout: OPEN_TIMEOUT})
+	if err != nil {
+		return nil, errors.DBConnectionError{Msg: err.Error()}
+	}
+
+	record := map[string]interface{}{
+		RECOVERY_TIME:   now().UTC().Format(time.RFC3339),
+		RECOVERY_REASON: reason,
+		CORRUPT_FILE:    corruptFile,
+	}
+	err = conn.Update(func(tx *bolt.Tx) error {
+		return putRecovery(boltTx{tx: tx}.Bucket(META_BUCKET), record)
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// GetRecovery returns the record of the last recovery of corrupt database,
+// NotFound error is returned if the database has never been recovered.
+func GetRecovery() (map[string]interface{}, error) {
+	conn, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	var record map[string]interface{}
+	err = conn.View(func(tx *bolt.Tx) error {
+		value, err := boltTx{tx: tx}.Bucket(META_BUCKET).Get([]byte(RECOVERY_KEY))
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(value, &record)
+		if err != nil {
+			return errors.InvalidJSON{Msg: err.Error()}
+		}
+		return nil
+	})
+	return record, err
+}
+
+// SetRecovery replaces the record of the last recovery of corrupt database,
+// which is used to add what is rebuilt after the recovery.
+func SetRecovery(record map[string]interface{}) error {
+	conn, err := open()
+	if err != nil {
+		return err
+	}
+	return conn.Update(func(tx *bolt.Tx) error {
+		return putRecovery(boltTx{tx: tx}.Bucket(META_BUCKET), record)
+	})
+}
+
+func putRecovery(meta Bucket, record map[string]interface{}) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return errors.InvalidJSON{Msg: err.Error()}
+	}
+	return meta.Put([]byte(RECOVERY_KEY), value)
+}
@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"bytes"
+	"commons/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const TEST_TIME = "2018-01-02T03:04:05Z"
+
+func setTestTime(t *testing.T) func() {
+	testTime, err := time.Parse(time.RFC3339, TEST_TIME)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	now = func() time.Time { return testTime }
+	return func() {
+		now = time.Now
+	}
+}
+
+func TestGetDBPath(t *testing.T) {
+	defer os.Unsetenv(DATA_DIR)
+	defer os.Unsetenv(DB_PATH)
+
+	tests := map[string]struct {
+		dataDir  string
+		path     string
+		args     []string
+		expected string
+	}{
+		"Default":     {"", "", nil, PATH},
+		"DataDir":     {"/test/data", "", nil, "/test/data/data.db"},
+		"DBPath":      {"", "/test/db/test.db", nil, "/test/db/test.db"},
+		"DBPathWins":  {"/test/data", "/test/db/test.db", nil, "/test/db/test.db"},
+		"DataDirFlag": {"", "", []string{"-data-dir", "/test/flag"}, "/test/flag/data.db"},
+		"DBPathFlag":  {"", "", []string{"--db-path=/test/flag/test.db"}, "/test/flag/test.db"},
+		"FlagWins":    {"", "/test/db/test.db", []string{"-data-dir=/test/flag"}, "/test/flag/data.db"},
+		"UnknownFlag": {"/test/data", "", []string{"-unknown"}, "/test/data/data.db"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(DATA_DIR, test.dataDir)
+			os.Setenv(DB_PATH, test.path)
+
+			if res := getDBPath(test.args); res != test.expected {
+				t.Errorf("Expected res: %s, actual res: %s", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestOpenWhenDataDirDoesNotExist_ExpectDataDirCreated(t *testing.T) {
+	defer setTestDB(t)()
+
+	dbPath = filepath.Join(filepath.Dir(dbPath), "data", "data.db")
+
+	err := NewBoltDB(BUCKET).Put([]byte(KEY), []byte(VALUE))
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		t.Errorf("Expected database file created, actual err: %s", err.Error())
+	}
+}
+
+func TestOpenWhenDBIsNotRecovered_ExpectNotFoundRecovery(t *testing.T) {
+	defer setTestDB(t)()
+
+	_, err := GetRecovery()
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestOpenWhenDBIsCorrupt_ExpectDBMovedAsideAndRecovered(t *testing.T) {
+	defer setTestDB(t)()
+	defer setTestTime(t)()
+
+	garbage := bytes.Repeat([]byte("corrupt"), 4096)
+	err := ioutil.WriteFile(dbPath, garbage, 0600)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	db := NewBoltDB(BUCKET)
+	err = db.Put([]byte(KEY), []byte(VALUE))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	corruptFile := dbPath + CORRUPT_SUFFIX + "20180102T030405Z"
+	moved, err := ioutil.ReadFile(corruptFile)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !bytes.Equal(garbage, moved) {
+		t.Errorf("Expected corrupt database moved to %s", corruptFile)
+	}
+
+	record, err := GetRecovery()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if record[RECOVERY_TIME] != TEST_TIME || record[CORRUPT_FILE] != corruptFile {
+		t.Errorf("Unexpected recovery: %v", record)
+	}
+	if reason, _ := record[RECOVERY_REASON].(string); len(reason) == 0 {
+		t.Errorf("Expected reason of recovery, actual recovery: %v", record)
+	}
+
+	if version := getTestSchemaVersion(t); version != migrations[len(migrations)-1].Version {
+		t.Errorf("Expected recovered database migrated, actual version: %d", version)
+	}
+}
+
+func TestSetRecovery_ExpectRecordReplaced(t *testing.T) {
+	defer setTestDB(t)()
+
+	record := map[string]interface{}{RECOVERY_TIME: TEST_TIME, "apps": []interface{}{"app"}}
+	err := SetRecovery(record)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	res, err := GetRecovery()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if res[RECOVERY_TIME] != TEST_TIME || len(res["apps"].([]interface{})) != 1 {
+		t.Errorf("Expected res: %v, actual res: %v", record, res)
+	}
+}
@@ -18,126 +18,297 @@ package wrapper
 
 import (
 	"commons/errors"
+	"flag"
 	"github.com/boltdb/bolt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 )
 
 const (
 	PATH = "/data/db/data.db"
 	PORT = 0600
+
+	// Environments to change where database is stored.
+	// DB_PATH is the path of database file, which takes precedence over
+	// DATA_DIR, the directory in which the database file is created.
+	DATA_DIR = "DATA_DIR"
+	DB_PATH  = "DB_PATH"
+	DB_FILE  = "data.db"
+
+	// Flags of the same meanings, which take precedence over environments.
+	DATA_DIR_FLAG = "data-dir"
+	DB_PATH_FLAG  = "db-path"
+
+	// Time to wait for the file lock of database held by another process.
+	OPEN_TIMEOUT = 5 * time.Second
 )
 
 type (
+	// Bucket is a collection of key/value pairs.
+	Bucket interface {
+		Get(key []byte) ([]byte, error)
+		Put(key []byte, value []byte) error
+		List() (map[string]interface{}, error)
+		Delete(key []byte) error
+	}
+
+	// Tx is a transaction which can access several buckets.
+	Tx interface {
+		// Bucket returns the bucket specified by name in the transaction.
+		// a bucket which doesn't exist is created when a value is put in it.
+		Bucket(name string) Bucket
+	}
+
+	// Database is the bucket of a model, of which each operation
+	// is done in its own transaction.
+	// View and Update run several operations in a single transaction.
 	Database interface {
 		Get(key []byte) ([]byte, error)
 		Put(key []byte, value []byte) error
 		List() (map[string]interface{}, error)
 		Delete(key []byte) error
+
+		// View runs fn in a read-only transaction.
+		View(fn func(tx Tx) error) error
+
+		// Update runs fn in a read-write transaction.
+		// changes are committed only if fn returns nil, otherwise rolled back.
+		Update(fn func(tx Tx) error) error
 	}
 
 	BoltDB struct {
 		bucketname string
-		boltdb     *bolt.DB
+	}
+
+	boltTx struct {
+		tx *bolt.Tx
+	}
+
+	boltBucket struct {
+		tx   *bolt.Tx
+		name string
 	}
 )
 
+// Flags are parsed here instead of main,
+// since the database is opened by init of controllers before main runs.
+var dbPath = getDBPath(os.Args[1:])
+
+// Handle of database shared by all models.
+// it is opened on the first use and kept open until Close is called,
+// and migrations are run when it is opened.
+var handle struct {
+	sync.Mutex
+	db *bolt.DB
+}
+
 func NewBoltDB(bucketname string) Database {
 	return &BoltDB{bucketname: bucketname}
 }
 
-func (db *BoltDB) dbOpen() error {
-	conn, err := bolt.Open(PATH, PORT, nil)
+// Returns the shared handle, opening the database if it is not opened yet.
+func open() (*bolt.DB, error) {
+	handle.Lock()
+	defer handle.Unlock()
+
+	if handle.db != nil {
+		return handle.db, nil
+	}
+
+	// The database on the old schema should not be served while
+	// migrations are only tried by DryRunMigrations.
+	if isMigrationDryRun() {
+		return nil, errors.DBOperationError{Msg: "database is not served in dry-run of migrations"}
+	}
+
+	err := os.MkdirAll(filepath.Dir(dbPath), 0700)
 	if err != nil {
-		return errors.DBConnectionError{Msg: err.Error()}
+		return nil, errors.DBConnectionError{Msg: err.Error()}
 	}
-	db.boltdb = conn
-	return nil
-}
 
-func (db *BoltDB) dbClose() {
-	db.boltdb.Close()
-}
+	// A corrupt database is moved aside and a new one is created,
+	// otherwise every operation would fail until it is removed by hand.
+	conn, err := openChecked(dbPath)
+	if _, ok := err.(corruptError); ok {
+		conn, err = recoverCorruptDB(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
 
-func (db *BoltDB) Get(key []byte) ([]byte, error) {
-	err := db.dbOpen()
+	// Buckets are upgraded to the latest schema before they are used.
+	_, err = migrate(conn, false)
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
-	defer db.dbClose()
+	handle.db = conn
+	return conn, nil
+}
 
-	var data []byte
-	err = db.boltdb.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(db.bucketname))
-		if bucket == nil {
-			return errors.NotFound{Msg: string(key[:]) + " does not exist"}
-		}
+// Path returns the path of database file.
+func Path() string {
+	return dbPath
+}
 
-		v := bucket.Get(key)
-		if len(v) == 0 {
-			return errors.NotFound{Msg: string(key[:]) + " does not exist"}
-		}
-		data = make([]byte, len(v))
-		copy(data, v)
+// Returns the path of database file given by flags in args or environments.
+// flags take precedence over environments, and in each of them,
+// the path of database file takes precedence over the data directory.
+func getDBPath(args []string) string {
+	flags := flag.NewFlagSet(DB_PATH_FLAG, flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	dataDir := flags.String(DATA_DIR_FLAG, "", "directory in which the database file is created")
+	path := flags.String(DB_PATH_FLAG, "", "path of the database file")
+	flags.Parse(args)
+
+	if len(*path) == 0 && len(*dataDir) == 0 {
+		*path, *dataDir = os.Getenv(DB_PATH), os.Getenv(DATA_DIR)
+	}
+
+	if len(*path) != 0 {
+		return *path
+	}
+	if len(*dataDir) != 0 {
+		return filepath.Join(*dataDir, DB_FILE)
+	}
+	return PATH
+}
+
+// Close closes the shared handle of database.
+// it should be called before the process exits.
+func Close() error {
+	handle.Lock()
+	defer handle.Unlock()
+
+	if handle.db == nil {
 		return nil
-	})
-	return data, err
+	}
+
+	err := handle.db.Close()
+	handle.db = nil
+	if err != nil {
+		return errors.DBOperationError{Msg: err.Error()}
+	}
+	return nil
 }
 
-func (db *BoltDB) Put(key []byte, value []byte) error {
-	err := db.dbOpen()
+func (db *BoltDB) View(fn func(tx Tx) error) error {
+	conn, err := open()
 	if err != nil {
 		return err
 	}
-	defer db.dbClose()
 
-	return db.boltdb.Update(func(tx *bolt.Tx) error {
-		bucket, err := tx.CreateBucketIfNotExists([]byte(db.bucketname))
-		if err != nil {
-			return errors.DBOperationError{Msg: err.Error()}
-		}
-		return bucket.Put(key, value)
+	return conn.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
 	})
 }
 
-func (db BoltDB) List() (map[string]interface{}, error) {
-	err := db.dbOpen()
+func (db *BoltDB) Update(fn func(tx Tx) error) error {
+	conn, err := open()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer db.dbClose()
 
-	data := make(map[string]interface{})
-	err = db.boltdb.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(db.bucketname))
-		if bucket != nil {
-			c := bucket.Cursor()
-			for k, v := c.First(); k != nil; k, v = c.Next() {
-				data[string(k)] = string(v)
-			}
-		}
-		return nil
+	return conn.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx: tx})
+	})
+}
+
+func (db *BoltDB) Get(key []byte) ([]byte, error) {
+	var data []byte
+	err := db.View(func(tx Tx) error {
+		var err error
+		data, err = tx.Bucket(db.bucketname).Get(key)
+		return err
 	})
+	return data, err
+}
 
+func (db *BoltDB) Put(key []byte, value []byte) error {
+	return db.Update(func(tx Tx) error {
+		return tx.Bucket(db.bucketname).Put(key, value)
+	})
+}
+
+func (db *BoltDB) List() (map[string]interface{}, error) {
+	var data map[string]interface{}
+	err := db.View(func(tx Tx) error {
+		var err error
+		data, err = tx.Bucket(db.bucketname).List()
+		return err
+	})
 	return data, err
 }
 
 func (db *BoltDB) Delete(key []byte) error {
-	err := db.dbOpen()
+	return db.Update(func(tx Tx) error {
+		return tx.Bucket(db.bucketname).Delete(key)
+	})
+}
+
+func (t boltTx) Bucket(name string) Bucket {
+	return boltBucket{tx: t.tx, name: name}
+}
+
+func (b boltBucket) Get(key []byte) ([]byte, error) {
+	bucket := b.tx.Bucket([]byte(b.name))
+	if bucket == nil {
+		return nil, errors.NotFound{Msg: string(key[:]) + " does not exist"}
+	}
+
+	v := bucket.Get(key)
+	if len(v) == 0 {
+		return nil, errors.NotFound{Msg: string(key[:]) + " does not exist"}
+	}
+
+	// Value is valid only in the transaction.
+	data := make([]byte, len(v))
+	copy(data, v)
+	return data, nil
+}
+
+func (b boltBucket) Put(key []byte, value []byte) error {
+	bucket, err := b.tx.CreateBucketIfNotExists([]byte(b.name))
 	if err != nil {
-		return err
+		return errors.DBOperationError{Msg: err.Error()}
 	}
-	defer db.dbClose()
 
-	return db.boltdb.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(db.bucketname))
-		if bucket == nil {
-			return errors.NotFound{Msg: string(key[:]) + " does not exist"}
-		}
+	err = bucket.Put(key, value)
+	if err != nil {
+		return errors.DBOperationError{Msg: err.Error()}
+	}
+	return nil
+}
 
-		v := bucket.Get(key)
-		if len(v) == 0 {
-			return errors.NotFound{Msg: string(key[:]) + " does not exist"}
+func (b boltBucket) List() (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	bucket := b.tx.Bucket([]byte(b.name))
+	if bucket != nil {
+		c := bucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			data[string(k)] = string(v)
 		}
+	}
+	return data, nil
+}
 
-		return bucket.Delete(key)
-	})
+func (b boltBucket) Delete(key []byte) error {
+	bucket := b.tx.Bucket([]byte(b.name))
+	if bucket == nil {
+		return errors.NotFound{Msg: string(key[:]) + " does not exist"}
+	}
+
+	v := bucket.Get(key)
+	if len(v) == 0 {
+		return errors.NotFound{Msg: string(key[:]) + " does not exist"}
+	}
+
+	err := bucket.Delete(key)
+	if err != nil {
+		return errors.DBOperationError{Msg: err.Error()}
+	}
+	return nil
 }
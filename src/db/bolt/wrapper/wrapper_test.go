@@ -0,0 +1,194 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package wrapper
+
+import (
+	"commons/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	BUCKET       = "test_bucket"
+	OTHER_BUCKET = "test_other_bucket"
+	KEY          = "test_key"
+	VALUE        = "test_value"
+)
+
+// Uses a database in a temporary directory, which is removed by the returned function.
+func setTestDB(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "wrapper")
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	dbPath = filepath.Join(dir, "data.db")
+	return func() {
+		Close()
+		dbPath = PATH
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPutAndGet_ExpectValueReturned(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+
+	err := db.Put([]byte(KEY), []byte(VALUE))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	res, err := db.Get([]byte(KEY))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if string(res) != VALUE {
+		t.Errorf("Expected res: %s, actual res: %s", VALUE, res)
+	}
+
+	list, err := db.List()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	expected := map[string]interface{}{KEY: VALUE}
+	if !reflect.DeepEqual(expected, list) {
+		t.Errorf("Expected list: %v, actual list: %v", expected, list)
+	}
+}
+
+func TestGetAndDeleteWithUnknownKey_ExpectNotFoundReturned(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+
+	_, err := db.Get([]byte(KEY))
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+
+	err = db.Delete([]byte(KEY))
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestUpdateOverSeveralBuckets_ExpectAllChangesCommitted(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+	other := NewBoltDB(OTHER_BUCKET)
+
+	err := other.Put([]byte(KEY), []byte(VALUE))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	err = db.Update(func(tx Tx) error {
+		value, err := tx.Bucket(OTHER_BUCKET).Get([]byte(KEY))
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(BUCKET).Put([]byte(KEY), value)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(OTHER_BUCKET).Delete([]byte(KEY))
+	})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	res, err := db.Get([]byte(KEY))
+	if err != nil || string(res) != VALUE {
+		t.Errorf("Expected res: %s, actual res: %s, err: %v", VALUE, res, err)
+	}
+	_, err = other.Get([]byte(KEY))
+	if err == nil {
+		t.Errorf("Expected key of other bucket is deleted")
+	}
+}
+
+func TestUpdateWhenFnFailed_ExpectChangesRolledBack(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+
+	err := db.Update(func(tx Tx) error {
+		err := tx.Bucket(BUCKET).Put([]byte(KEY), []byte(VALUE))
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(OTHER_BUCKET).Delete([]byte(KEY))
+	})
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+
+	_, err = db.Get([]byte(KEY))
+	if err == nil {
+		t.Errorf("Expected put value is rolled back")
+	}
+}
+
+func TestPutInView_ExpectErrorReturn(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+
+	err := db.View(func(tx Tx) error {
+		return tx.Bucket(BUCKET).Put([]byte(KEY), []byte(VALUE))
+	})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
+func TestCloseAndReopen_ExpectValuePersisted(t *testing.T) {
+	defer setTestDB(t)()
+
+	db := NewBoltDB(BUCKET)
+
+	err := db.Put([]byte(KEY), []byte(VALUE))
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	err = Close()
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	res, err := db.Get([]byte(KEY))
+	if err != nil || string(res) != VALUE {
+		t.Errorf("Expected res: %s, actual res: %s, err: %v", VALUE, res, err)
+	}
+}
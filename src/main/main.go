@@ -19,10 +19,36 @@ package main
 import (
 	"api"
 	"commons/logger"
+	"controller/deployment"
+	"controller/gc"
+	"controller/monitoring/alerts"
+	"controller/monitoring/resource"
+	"controller/outbox"
+	"db/bolt/wrapper"
+	"fmt"
+	"os"
 )
 
 func main() {
+	// The node exits after reporting the dry-run of migrations,
+	// since the database on the old schema should not be served.
+	if wrapper.IsMigrationDryRun() {
+		versions, err := wrapper.DryRunMigrations()
+		if err != nil {
+			logger.Logging(logger.ERROR, "dry-run of migrations failed :", err.Error())
+			os.Exit(1)
+		}
+		logger.Logging(logger.INFO, "dry-run of migrations succeeded, versions to migrate :", fmt.Sprint(versions))
+		os.Exit(0)
+	}
+
 	logger.Logging(logger.DEBUG, "Start Pharos Node")
+	resource.StartSampler()
+	alerts.StartEvaluator()
+	gc.StartCollector()
+	outbox.StartDelivery()
+	deployment.StartUpdateScheduler()
 	api.RunNodeWebServer("0.0.0.0", 48098)
+	wrapper.Close()
 	logger.Logging(logger.DEBUG, "Stop Pharos Node")
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"commons/errors"
 	"commons/logger"
+	"commons/util"
 	"io/ioutil"
 	"net/http"
 )
@@ -12,11 +13,17 @@ type httpWrapper interface {
 	DoWrapper(req *http.Request) (*http.Response, error)
 }
 
-type httpClient struct{}
+type httpClient struct {
+	client *http.Client
+	err    error
+}
 
-// DoWrapper is a wrapper around DefaultClient.Do.
-func (httpClient) DoWrapper(req *http.Request) (*http.Response, error) {
-	return http.DefaultClient.Do(req)
+// DoWrapper is a wrapper around Client.Do.
+func (c httpClient) DoWrapper(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.client.Do(req)
 }
 
 type Command interface {
@@ -29,8 +36,30 @@ type Executor struct {
 
 func NewExecutor() *Executor {
 	return &Executor{
-		client: httpClient{},
+		client: newHttpClient(),
+	}
+}
+
+// newHttpClient makes a client which trusts only the CA of Pharos Anchor
+// if it is given, otherwise the client is the same as DefaultClient.
+// if TLS configuration is invalid, every request fails rather than
+// falling back to an unverified connection.
+func newHttpClient() httpClient {
+	config, err := util.MakeAnchorTLSConfig()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return httpClient{err: err}
+	}
+
+	if config == nil {
+		return httpClient{client: http.DefaultClient}
+	}
+
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: config,
 	}
+	return httpClient{client: &http.Client{Transport: transport}}
 }
 
 // sendHttpRequest creates a new request and sends it to target device.
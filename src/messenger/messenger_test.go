@@ -18,11 +18,13 @@ package messenger
 
 import (
 	"bytes"
+	"commons/util"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"io/ioutil"
 	msgmocks "messenger/mocks"
 	"net/http"
+	"os"
 	"testing"
 )
 
@@ -89,3 +91,15 @@ func TestCalledSendHttpRequestWhenFailedToSendHttpRequest_ExpectErrorReturn(t *t
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
+
+func TestCalledSendHttpRequestWithInvalidAnchorCA_ExpectErrorReturn(t *testing.T) {
+	os.Setenv(util.ANCHOR_CA_FILE, "/not/exist/ca.pem")
+	messengerObj := NewExecutor()
+	os.Unsetenv(util.ANCHOR_CA_FILE)
+
+	_, _, err := messengerObj.SendHttpRequest("POST", "https://127.0.0.1/test/url")
+
+	if err == nil {
+		t.Errorf("Expected err : IOError, actual err : nil")
+	}
+}
@@ -13,7 +13,7 @@ go get github.com/boltdb/bolt
 
 rm -rf $GOPATH/src/github.com/docker/distribution/vendor/github.com/opencontainers
 
-pkg_list=("api" "api/common" "api/deployment" "api/health" "api/monitoring/resource" "api/configuration" "api/notification" "api/notification/apps" "commons/errors" "commons/logger" "commons/url" "commons/util" "controller/deployment" "controller/dockercontroller" "controller/health" "controller/monitoring/resource" "controller/monitoring/apps" "controller/configuration" "controller/shellcommand" "controller/monitoring/apps" "controller/notification/apps" "db/bolt/event" "db/bolt/configuration" "db/bolt/service" "messenger")
+pkg_list=("api" "api/common" "api/deployment" "api/health" "api/monitoring/resource" "api/monitoring/apps" "api/monitoring/alerts" "api/configuration" "api/notification" "api/notification/apps" "api/notification/outbox" "api/registry" "api/backup" "api/job" "commons/errors" "commons/logger" "commons/url" "commons/util" "controller/deployment" "controller/dockercontroller" "controller/health" "controller/gc" "controller/backup" "controller/job" "controller/imagepolicy" "controller/monitoring/resource" "controller/monitoring/apps" "controller/monitoring/alerts" "controller/auth" "controller/configuration" "controller/shellcommand" "controller/monitoring/apps" "controller/notification/apps" "controller/notification/alerts" "controller/outbox" "controller/registry" "db/bolt/event" "db/bolt/configuration" "db/bolt/service" "db/bolt/history" "db/bolt/outbox" "db/bolt/alert" "db/bolt/registry" "db/bolt/wrapper" "messenger")
 
 function func_cleanup(){
     rm *.out *.test